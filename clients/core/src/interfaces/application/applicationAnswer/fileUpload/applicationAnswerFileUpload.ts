@@ -1,3 +1,12 @@
+export interface ApplicationAnswerFileVersion {
+  fileID: string
+  version: number
+  fileName: string
+  fileSize: number
+  uploadedAt: string
+  downloadUrl: string
+}
+
 export interface ApplicationAnswerFileUpload {
   id: string
   applicationQuestionID: string
@@ -6,4 +15,8 @@ export interface ApplicationAnswerFileUpload {
   fileSize: number
   uploadedAt: string
   downloadUrl: string
+  previewUrl?: string
+  version?: number
+  // files the applicant replaced, the newest first
+  versions?: ApplicationAnswerFileVersion[]
 }
@@ -48,7 +48,7 @@ export const ApplicationAnswersTable = ({
   const [downloadingFileId, setDownloadingFileId] = useState<string | null>(null)
   const sortedQuestions = [...questions].sort((a, b) => a.orderNum - b.orderNum)
 
-  const handleDownload = async (fileAnswer: ApplicationAnswerFileUpload) => {
+  const handleDownload = async (fileAnswer: { fileID: string; fileName: string }) => {
     if (!coursePhaseId || !fileAnswer.fileID) {
       return
     }
@@ -121,6 +121,13 @@ export const ApplicationAnswersTable = ({
                       {isFileUpload ? (
                         fileAnswer ? (
                           <div className='flex flex-col gap-2'>
+                            {fileAnswer.previewUrl && (
+                              <img
+                                src={fileAnswer.previewUrl}
+                                alt={`Preview of ${fileAnswer.fileName}`}
+                                className='max-h-40 w-fit rounded border object-contain'
+                              />
+                            )}
                             <div>
                               <div className='font-medium'>{fileAnswer.fileName}</div>
                               <div className='text-xs text-muted-foreground'>
@@ -140,6 +147,33 @@ export const ApplicationAnswersTable = ({
                               <Download className='mr-2 h-4 w-4' />
                               Download
                             </Button>
+                            {fileAnswer.versions && fileAnswer.versions.length > 0 && (
+                              <div className='space-y-1'>
+                                <div className='text-xs font-medium text-muted-foreground'>
+                                  Previous versions
+                                </div>
+                                {fileAnswer.versions.map((version) => (
+                                  <div
+                                    key={version.fileID}
+                                    className='flex items-center justify-between gap-2 text-xs'
+                                  >
+                                    <span>
+                                      v{version.version} · {version.fileName} ·{' '}
+                                      {formatFileSize(version.fileSize)} ·{' '}
+                                      {new Date(version.uploadedAt).toLocaleDateString()}
+                                    </span>
+                                    <Button
+                                      variant='ghost'
+                                      size='sm'
+                                      onClick={() => handleDownload(version)}
+                                      disabled={!coursePhaseId || downloadingFileId === version.fileID}
+                                    >
+                                      <Download className='h-4 w-4' />
+                                    </Button>
+                                  </div>
+                                ))}
+                              </div>
+                            )}
                           </div>
                         ) : (
                           <span className='text-muted-foreground'>No file uploaded</span>
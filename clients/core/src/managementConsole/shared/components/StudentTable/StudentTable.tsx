@@ -3,7 +3,20 @@ import {
   StudentWithCourses,
 } from '@core/network/queries/getStudentsWithCourses'
 import { ColumnDef } from '@tanstack/react-table'
-import { PromptTable, RowAction, TableFilter } from '@tumaet/prompt-ui-components'
+import { keepPreviousData, useQuery } from '@tanstack/react-query'
+import {
+  Button,
+  Input,
+  PromptTable,
+  RowAction,
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue,
+  TableFilter,
+} from '@tumaet/prompt-ui-components'
+import { useCourseStore } from '@tumaet/prompt-shared-state'
 import { useCallback, useEffect, useMemo, useState } from 'react'
 import { useNavigate } from 'react-router-dom'
 import { studentTableColumns } from './studentTableColumns'
@@ -11,10 +24,39 @@ import { getStudentTableFilters } from './studentTableFilters'
 import { getStudentTableActions } from './studentTableActions'
 import { useStudentStore } from '../../store/student.store'
 
-export const StudentTable = () => {
-  const [studentsWithCourses, setStudentsWithCourses] = useState<Array<StudentWithCourses>>([])
+const PAGE_SIZE = 50
+const ALL_COURSES = 'all'
 
+export const StudentTable = () => {
   const { upsertStudents } = useStudentStore()
+  const { courses } = useCourseStore()
+
+  const [searchInput, setSearchInput] = useState('')
+  const [search, setSearch] = useState('')
+  const [courseID, setCourseID] = useState('')
+  // the cursors of the pages before the current one, the first page has no cursor
+  const [cursors, setCursors] = useState<Array<string | undefined>>([undefined])
+  const cursor = cursors[cursors.length - 1]
+
+  // the search is sent once the user stopped typing
+  useEffect(() => {
+    const timeout = setTimeout(() => {
+      setSearch(searchInput.trim())
+      setCursors([undefined])
+    }, 300)
+    return () => clearTimeout(timeout)
+  }, [searchInput])
+
+  const { data: page, isFetching } = useQuery({
+    queryKey: ['studentsWithCourses', search, courseID, cursor],
+    queryFn: () => getStudentsWithCourses({ search, courseID, cursor, limit: PAGE_SIZE }),
+    placeholderData: keepPreviousData,
+  })
+  const studentsWithCourses = useMemo(() => page?.students ?? [], [page])
+
+  useEffect(() => {
+    upsertStudents(studentsWithCourses)
+  }, [studentsWithCourses, upsertStudents])
 
   const navigate = useNavigate()
   const openStudent = useCallback(
@@ -22,15 +64,6 @@ export const StudentTable = () => {
     [navigate],
   )
 
-  useEffect(() => {
-    const fetchStudents = async () => {
-      const s = await getStudentsWithCourses()
-      setStudentsWithCourses(s)
-      upsertStudents(s)
-    }
-    fetchStudents()
-  }, [upsertStudents])
-
   const columns: ColumnDef<StudentWithCourses>[] = useMemo(() => studentTableColumns, [])
 
   const filters: TableFilter[] = useMemo(
@@ -42,8 +75,38 @@ export const StudentTable = () => {
     () => getStudentTableActions({ openStudent }),
     [openStudent],
   )
+
+  const selectCourse = (value: string) => {
+    setCourseID(value === ALL_COURSES ? '' : value)
+    setCursors([undefined])
+  }
+
+  const firstStudent = (cursors.length - 1) * PAGE_SIZE + 1
+  const lastStudent = firstStudent + studentsWithCourses.length - 1
+
   return (
     <div className='flex flex-col gap-3 w-full'>
+      <div className='flex flex-wrap items-center gap-2'>
+        <Input
+          className='max-w-sm'
+          placeholder='Search by name, email, matriculation number or login'
+          value={searchInput}
+          onChange={(event) => setSearchInput(event.target.value)}
+        />
+        <Select value={courseID || ALL_COURSES} onValueChange={selectCourse}>
+          <SelectTrigger className='w-56'>
+            <SelectValue placeholder='Course' />
+          </SelectTrigger>
+          <SelectContent>
+            <SelectItem value={ALL_COURSES}>All courses</SelectItem>
+            {courses.map((course) => (
+              <SelectItem key={course.id} value={course.id}>
+                {course.name} ({course.semesterTag})
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      </div>
       <PromptTable
         data={studentsWithCourses}
         columns={columns}
@@ -51,6 +114,29 @@ export const StudentTable = () => {
         actions={actions}
         onRowClick={openStudent}
       />
+      <div className='flex items-center justify-end gap-2 text-sm text-muted-foreground'>
+        {page && page.totalCount > 0 && (
+          <span>
+            {firstStudent}-{lastStudent} of {page.totalCount}
+          </span>
+        )}
+        <Button
+          variant='outline'
+          size='sm'
+          disabled={cursors.length === 1 || isFetching}
+          onClick={() => setCursors((previous) => previous.slice(0, -1))}
+        >
+          Previous
+        </Button>
+        <Button
+          variant='outline'
+          size='sm'
+          disabled={!page?.nextCursor || isFetching}
+          onClick={() => setCursors((previous) => [...previous, page?.nextCursor])}
+        >
+          Next
+        </Button>
+      </div>
     </div>
   )
 }
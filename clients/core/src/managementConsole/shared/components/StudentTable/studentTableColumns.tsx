@@ -61,12 +61,6 @@ export const studentTableColumns: ColumnDef<StudentWithCourses>[] = [
         ))}
       </div>
     ),
-
-    filterFn: (row: Row<StudentWithCourses>, _columnId, filterValue: string[]) => {
-      if (!filterValue?.length) return true
-
-      return row.original.courses.some((course) => filterValue.includes(course.courseName))
-    },
   },
   {
     id: 'noteTags',
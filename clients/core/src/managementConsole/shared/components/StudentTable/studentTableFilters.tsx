@@ -6,55 +6,16 @@ import {
   DropdownMenuSubTrigger,
   TableFilter,
 } from '@tumaet/prompt-ui-components'
-import { StudentCoursePreview } from './components/StudentCoursePreview'
 import { InstructorNoteTag } from '../InstructorNote/InstructorNoteTag'
 import { NoteTagColor } from '../../interfaces/InstructorNote'
 
+// the courses are filtered on the server, the tags only on the loaded page
 export function getStudentTableFilters(studentsWithCourses: StudentWithCourses[]): TableFilter[] {
   const tagOptions = Array.from(
     new Map(studentsWithCourses.flatMap((s) => s.noteTags).map((t) => [t.id, t])).values(),
   )
 
   return [
-    {
-      type: 'custom',
-      id: 'courses',
-      label: 'Course',
-      render: ({ column }) => {
-        const selected = (column.getFilterValue() as string[]) ?? []
-
-        const courseOptions = Array.from(
-          new Map(
-            studentsWithCourses
-              .flatMap((s) => s.courses)
-              .filter((c) => c.courseName)
-              .map((c) => [c.courseName, c]),
-          ).values(),
-        )
-
-        return (
-          <DropdownMenuSub>
-            <DropdownMenuSubTrigger>Course</DropdownMenuSubTrigger>
-            <DropdownMenuSubContent>
-              {courseOptions.map((course) => (
-                <DropdownMenuCheckboxItem
-                  key={course.courseId}
-                  checked={selected.includes(course.courseName)}
-                  onCheckedChange={(checked) => {
-                    const next = checked
-                      ? [...selected, course.courseName]
-                      : selected.filter((c) => c !== course.courseName)
-                    column.setFilterValue(next.length === 0 ? undefined : next)
-                  }}
-                >
-                  <StudentCoursePreview studentCourseParticipation={course} />
-                </DropdownMenuCheckboxItem>
-              ))}
-            </DropdownMenuSubContent>
-          </DropdownMenuSub>
-        )
-      },
-    },
     {
       type: 'custom',
       id: 'noteTags',
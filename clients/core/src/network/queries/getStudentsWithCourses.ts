@@ -24,10 +24,30 @@ export interface StudentWithCourses {
   noteTags: StudentNoteTag[]
 }
 
-export const getStudentsWithCourses = async (): Promise<StudentWithCourses[]> => {
+export interface StudentsWithCoursesPage {
+  students: StudentWithCourses[]
+  totalCount: number
+  nextCursor?: string
+}
+
+// search and course are filtered on the server, which returns one page of the matching students
+export interface StudentsWithCoursesQuery {
+  search?: string
+  courseID?: string
+  cursor?: string
+  limit?: number
+}
+
+export const getStudentsWithCourses = async ({
+  search,
+  courseID,
+  cursor,
+  limit = 50,
+}: StudentsWithCoursesQuery): Promise<StudentsWithCoursesPage> => {
   try {
     return (
       await axiosInstance.get('/api/students/with-courses', {
+        params: { q: search || undefined, courseID: courseID || undefined, cursor, limit },
         headers: {
           'Content-Type': 'application/json-path+json',
         },
@@ -3,7 +3,10 @@ import { axiosInstance } from '@/network/configService'
 
 export const searchStudents = async (searchString: string): Promise<Student[]> => {
   try {
-    return (await axiosInstance.get(`/api/students/search/${searchString}`)).data
+    const response = await axiosInstance.get('/api/students/search', {
+      params: { q: searchString },
+    })
+    return response.data.students
   } catch (err) {
     console.error(err)
     throw err
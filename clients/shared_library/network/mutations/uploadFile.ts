@@ -16,17 +16,110 @@ export interface FileResponse {
   contentType: string
   sizeBytes: number
   storageKey: string
+  storageProvider: string
   downloadUrl: string
+  previewUrl?: string
   uploadedByUserId: string
   uploadedByEmail?: string
   applicationId?: string
   coursePhaseId?: string
   description?: string
   tags?: string[]
+  contentSha256?: string
+  version: number
+  replacedFileId?: string
+  encrypted: boolean
   createdAt: string
   updatedAt: string
 }
 
+// Files of this size are uploaded in parts, so a flaky connection only needs to retry the failed part
+const MULTIPART_UPLOAD_THRESHOLD_BYTES = 64 * 1024 * 1024
+const MULTIPART_PART_RETRIES = 3
+
+interface MultipartUpload {
+  storageKey: string
+  storageProvider: string
+  uploadId: string
+  partSize: number
+  partCount: number
+}
+
+// uploadFileInParts returns undefined if the storage does not support multipart uploads
+const uploadFileInParts = async (
+  basePath: string,
+  contentType: string,
+  params: FileUploadParams,
+): Promise<FileResponse | undefined> => {
+  let upload: MultipartUpload
+  try {
+    const initiateResponse = await axiosInstance.post(`${basePath}/files/multipart`, {
+      filename: params.file.name,
+      contentType,
+      sizeBytes: params.file.size,
+      description: params.description,
+      tags: params.tags,
+    })
+    upload = initiateResponse.data
+  } catch (err) {
+    if (axios.isAxiosError(err) && err.response?.status === 501) {
+      return undefined
+    }
+    throw err
+  }
+
+  const { storageKey, uploadId, partSize, partCount } = upload
+  try {
+    const parts: { partNumber: number; etag: string }[] = []
+    let uploadedBytes = 0
+    for (let partNumber = 1; partNumber <= partCount; partNumber++) {
+      const chunk = params.file.slice((partNumber - 1) * partSize, partNumber * partSize)
+
+      for (let attempt = 1; ; attempt++) {
+        try {
+          // the URL is requested for every attempt, it may have expired during a slow retry
+          const partsResponse = await axiosInstance.post(`${basePath}/files/multipart/parts`, {
+            storageKey,
+            uploadId,
+            partNumbers: [partNumber],
+          })
+          const partResponse = await axios.put(partsResponse.data.parts[0].uploadUrl, chunk, {
+            onUploadProgress: (event) =>
+              params.onUploadProgress?.({
+                ...event,
+                loaded: uploadedBytes + event.loaded,
+                total: params.file.size,
+              }),
+          })
+          parts.push({ partNumber, etag: partResponse.headers['etag'] })
+          break
+        } catch (err) {
+          if (attempt >= MULTIPART_PART_RETRIES) {
+            throw err
+          }
+        }
+      }
+      uploadedBytes += chunk.size
+    }
+
+    const completeResponse = await axiosInstance.post(`${basePath}/files/multipart/complete`, {
+      storageKey,
+      uploadId,
+      parts,
+      originalFilename: params.file.name,
+      contentType,
+      description: params.description,
+      tags: params.tags,
+    })
+    return completeResponse.data
+  } catch (err) {
+    await axiosInstance
+      .post(`${basePath}/files/multipart/abort`, { storageKey, uploadId })
+      .catch(() => undefined)
+    throw err
+  }
+}
+
 export const uploadFile = async (params: FileUploadParams): Promise<FileResponse> => {
   const contentType = params.file.type || 'application/octet-stream'
 
@@ -40,9 +133,17 @@ export const uploadFile = async (params: FileUploadParams): Promise<FileResponse
       ? `/api/apply/authenticated/${params.coursePhaseId}`
       : `/api/apply/${params.coursePhaseId}`
 
+    if (params.file.size >= MULTIPART_UPLOAD_THRESHOLD_BYTES) {
+      const multipartFile = await uploadFileInParts(basePath, contentType, params)
+      if (multipartFile) {
+        return multipartFile
+      }
+    }
+
     const presignResponse = await axiosInstance.post(`${basePath}/files/presign`, {
       filename: params.file.name,
       contentType,
+      sizeBytes: params.file.size,
       description: params.description,
       tags: params.tags,
     })
@@ -0,0 +1,40 @@
+package analyticsDTO
+
+import (
+	"time"
+
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+type CourseAnalytics struct {
+	Funnel             CourseFunnel       `json:"funnel"`
+	Demographics       Demographics       `json:"demographics"`
+	RepeatParticipants RepeatParticipants `json:"repeatParticipants"`
+	// the analytics are cached, this is the time they were computed
+	ComputedAt time.Time `json:"computedAt"`
+}
+
+// SemesterAnalytics combines all courses with the semester tag, students taking part in several courses are counted once
+type SemesterAnalytics struct {
+	SemesterTag        string             `json:"semesterTag"`
+	Courses            []CourseFunnel     `json:"courses"`
+	Demographics       Demographics       `json:"demographics"`
+	RepeatParticipants RepeatParticipants `json:"repeatParticipants"`
+	ComputedAt         time.Time          `json:"computedAt"`
+}
+
+type SemesterOverview struct {
+	SemesterTag  string `json:"semesterTag"`
+	Courses      int64  `json:"courses"`
+	Participants int64  `json:"participants"`
+	Accepted     int64  `json:"accepted"`
+}
+
+func GetSemesterOverviewDTOFromDBModel(row db.GetSemesterOverviewRow) SemesterOverview {
+	return SemesterOverview{
+		SemesterTag:  row.SemesterTag,
+		Courses:      row.Courses,
+		Participants: row.Participants,
+		Accepted:     row.Accepted,
+	}
+}
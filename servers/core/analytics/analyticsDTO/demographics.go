@@ -0,0 +1,23 @@
+package analyticsDTO
+
+// DemographicGroup counts the students with the same value, an empty value means the students did not provide it
+type DemographicGroup struct {
+	Value        string `json:"value"`
+	Participants int64  `json:"participants"`
+	Accepted     int64  `json:"accepted"`
+}
+
+type Demographics struct {
+	Gender       []DemographicGroup `json:"gender"`
+	Nationality  []DemographicGroup `json:"nationality"`
+	StudyProgram []DemographicGroup `json:"studyProgram"`
+	StudyDegree  []DemographicGroup `json:"studyDegree"`
+}
+
+type RepeatParticipants struct {
+	Participants int64 `json:"participants"`
+	// students that take part in more than one of the courses
+	MultiCourseParticipants int64 `json:"multiCourseParticipants"`
+	// students that already took part in a course that started earlier
+	ReturningParticipants int64 `json:"returningParticipants"`
+}
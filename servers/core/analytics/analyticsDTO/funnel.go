@@ -0,0 +1,43 @@
+package analyticsDTO
+
+import (
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+type PhaseFunnelStep struct {
+	CoursePhaseID   uuid.UUID `json:"coursePhaseID"`
+	Name            string    `json:"name"`
+	CoursePhaseType string    `json:"coursePhaseType"`
+	SequenceOrder   int32     `json:"sequenceOrder"`
+	Participants    int64     `json:"participants"`
+	Passed          int64     `json:"passed"`
+	Failed          int64     `json:"failed"`
+	NotAssessed     int64     `json:"notAssessed"`
+}
+
+// CourseFunnel follows the participants of a course through its phases
+type CourseFunnel struct {
+	CourseID   uuid.UUID `json:"courseID"`
+	CourseName string    `json:"courseName"`
+	// participants of the initial phase, usually the application phase
+	Applied int64 `json:"applied"`
+	// participants that passed the initial phase
+	Accepted int64 `json:"accepted"`
+	// participants that passed the last phase
+	Completed int64             `json:"completed"`
+	Phases    []PhaseFunnelStep `json:"phases"`
+}
+
+func GetPhaseFunnelStepDTOFromDBModel(row db.GetCoursePhaseFunnelsRow) PhaseFunnelStep {
+	return PhaseFunnelStep{
+		CoursePhaseID:   row.CoursePhaseID,
+		Name:            row.CoursePhaseName.String,
+		CoursePhaseType: row.CoursePhaseTypeName,
+		SequenceOrder:   row.SequenceOrder,
+		Participants:    row.Participants,
+		Passed:          row.Passed,
+		Failed:          row.Failed,
+		NotAssessed:     row.NotAssessed,
+	}
+}
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"sync"
+	"time"
+
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// analyticsCacheMaxEntries bounds the memory of the cache, it is cleared when it grows beyond
+const analyticsCacheMaxEntries = 1000
+
+// analyticsCache keeps computed analytics for some time, as they aggregate all participations of the courses
+type analyticsCache struct {
+	mu      sync.Mutex
+	entries map[string]analyticsCacheEntry
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+type analyticsCacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+func newAnalyticsCache(ttl time.Duration) *analyticsCache {
+	return &analyticsCache{
+		entries: make(map[string]analyticsCacheEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (c *analyticsCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[key]
+	if !found || !c.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *analyticsCache) set(key string, value any) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= analyticsCacheMaxEntries {
+		c.entries = make(map[string]analyticsCacheEntry)
+	}
+	c.entries[key] = analyticsCacheEntry{value: value, expiresAt: c.now().Add(c.ttl)}
+}
+
+// getCached returns the cached value of the key, or computes and caches it if it is missing, expired or a refresh is requested
+func getCached[T any](cache *analyticsCache, key string, refresh bool, compute func() (T, error)) (T, error) {
+	if !refresh {
+		if value, found := cache.get(key); found {
+			return value.(T), nil
+		}
+	}
+
+	value, err := compute()
+	if err != nil {
+		return value, err
+	}
+	cache.set(key, value)
+	return value, nil
+}
+
+// analyticsCacheTTL is the time computed analytics are reused, ANALYTICS_CACHE_TTL=0 disables the cache
+func analyticsCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(sdkUtils.GetEnv("ANALYTICS_CACHE_TTL", "10m"))
+	if err != nil || ttl < 0 {
+		log.Warn("Invalid ANALYTICS_CACHE_TTL, using 10 minutes")
+		return 10 * time.Minute
+	}
+	return ttl
+}
@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsCacheExpiry(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	cache := newAnalyticsCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.set("semesters", 1)
+	value, found := cache.get("semesters")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+
+	now = now.Add(time.Minute)
+	_, found = cache.get("semesters")
+	assert.False(t, found, "entries expire after the ttl")
+}
+
+func TestAnalyticsCacheDisabled(t *testing.T) {
+	cache := newAnalyticsCache(0)
+	cache.set("semesters", 1)
+
+	_, found := cache.get("semesters")
+	assert.False(t, found)
+}
+
+func TestGetCached(t *testing.T) {
+	cache := newAnalyticsCache(time.Minute)
+	computations := 0
+	compute := func() (int, error) {
+		computations++
+		return computations, nil
+	}
+
+	value, err := getCached(cache, "course", false, compute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	value, err = getCached(cache, "course", false, compute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, value, "the cached value is reused")
+
+	value, err = getCached(cache, "course", true, compute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value, "a refresh recomputes the value")
+
+	value, err = getCached(cache, "course", false, compute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, value, "the refreshed value is cached")
+}
+
+func TestGetCachedDoesNotCacheErrors(t *testing.T) {
+	cache := newAnalyticsCache(time.Minute)
+
+	_, err := getCached(cache, "course", false, func() (int, error) { return 0, errors.New("query failed") })
+	assert.Error(t, err)
+
+	_, found := cache.get("course")
+	assert.False(t, found)
+}
+
+func TestAnalyticsCacheTTL(t *testing.T) {
+	t.Setenv("ANALYTICS_CACHE_TTL", "30s")
+	assert.Equal(t, 30*time.Second, analyticsCacheTTL())
+
+	t.Setenv("ANALYTICS_CACHE_TTL", "0")
+	assert.Equal(t, time.Duration(0), analyticsCacheTTL())
+
+	t.Setenv("ANALYTICS_CACHE_TTL", "soon")
+	assert.Equal(t, 10*time.Minute, analyticsCacheTTL())
+}
@@ -0,0 +1,23 @@
+package analytics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+)
+
+func InitAnalyticsModule(api *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) {
+	setupAnalyticsRouter(api, keycloakTokenVerifier.KeycloakMiddleware, permissionValidation.CheckAccessControlByRole, checkAccessControlByIDWrapper)
+	AnalyticsServiceSingleton = &AnalyticsService{
+		queries: queries,
+		conn:    conn,
+		cache:   newAnalyticsCache(analyticsCacheTTL()),
+	}
+}
+
+// initializes the handler func with CheckCoursePermissions
+func checkAccessControlByIDWrapper(allowedRoles ...string) gin.HandlerFunc {
+	return permissionValidation.CheckAccessControlByID(permissionValidation.CheckCoursePermission, "uuid", allowedRoles...)
+}
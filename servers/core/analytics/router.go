@@ -0,0 +1,127 @@
+package analytics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/utils"
+)
+
+func setupAnalyticsRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionRoleMiddleware, permissionIDMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+	analyticsRouter := router.Group("/analytics", authMiddleware())
+	analyticsRouter.GET("/semesters", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getSemesterOverview)
+	analyticsRouter.GET("/semesters/:semesterTag", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getSemesterAnalytics)
+	// the demographics of a single course can identify participants, editors do not see them
+	analyticsRouter.GET("/courses/:uuid", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getCourseAnalytics)
+}
+
+// getSemesterOverview godoc
+// @Summary Get the semester overview
+// @Description Get the number of courses, participants and accepted participants per semester
+// @Tags analytics
+// @Produce json
+// @Param refresh query bool false "Recompute the statistics instead of using the cache"
+// @Success 200 {object} []analyticsDTO.SemesterOverview
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /analytics/semesters [get]
+func getSemesterOverview(c *gin.Context) {
+	refresh, err := parseRefresh(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	semesters, err := GetSemesterOverview(c, refresh)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, semesters)
+}
+
+// getSemesterAnalytics godoc
+// @Summary Get the analytics of a semester
+// @Description Get the funnels of all courses of a semester and the demographics and repeat participants across these courses
+// @Tags analytics
+// @Produce json
+// @Param semesterTag path string true "Semester tag"
+// @Param refresh query bool false "Recompute the statistics instead of using the cache"
+// @Success 200 {object} analyticsDTO.SemesterAnalytics
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /analytics/semesters/{semesterTag} [get]
+func getSemesterAnalytics(c *gin.Context) {
+	refresh, err := parseRefresh(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	analytics, err := GetSemesterAnalytics(c, c.Param("semesterTag"), refresh)
+	if errors.Is(err, ErrSemesterNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, analytics)
+}
+
+// getCourseAnalytics godoc
+// @Summary Get the analytics of a course
+// @Description Get the funnel, the demographics and the repeat participants of a course
+// @Tags analytics
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param refresh query bool false "Recompute the statistics instead of using the cache"
+// @Success 200 {object} analyticsDTO.CourseAnalytics
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /analytics/courses/{uuid} [get]
+func getCourseAnalytics(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	refresh, err := parseRefresh(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	analytics, err := GetCourseAnalytics(c, courseID, refresh)
+	if errors.Is(err, ErrCourseNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, analytics)
+}
+
+func parseRefresh(c *gin.Context) (bool, error) {
+	refresh, err := strconv.ParseBool(c.DefaultQuery("refresh", "false"))
+	if err != nil {
+		return false, errors.New("refresh must be a boolean")
+	}
+	return refresh, nil
+}
+
+func handleError(c *gin.Context, statusCode int, err error) {
+	c.JSON(statusCode, utils.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,195 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prompt-edu/prompt/servers/core/analytics/analyticsDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+type AnalyticsService struct {
+	queries db.Queries
+	conn    *pgxpool.Pool
+	cache   *analyticsCache
+}
+
+var AnalyticsServiceSingleton *AnalyticsService
+
+var (
+	ErrCourseNotFound   = errors.New("course not found")
+	ErrSemesterNotFound = errors.New("no courses with this semester tag")
+)
+
+type courseRef struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func GetSemesterOverview(ctx context.Context, refresh bool) ([]analyticsDTO.SemesterOverview, error) {
+	return getCached(AnalyticsServiceSingleton.cache, "semesters", refresh, func() ([]analyticsDTO.SemesterOverview, error) {
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		defer cancel()
+
+		rows, err := AnalyticsServiceSingleton.queries.GetSemesterOverview(ctxWithTimeout)
+		if err != nil {
+			log.Error(err)
+			return nil, errors.New("could not get the semester overview")
+		}
+
+		semesters := make([]analyticsDTO.SemesterOverview, 0, len(rows))
+		for _, row := range rows {
+			semesters = append(semesters, analyticsDTO.GetSemesterOverviewDTOFromDBModel(row))
+		}
+		return semesters, nil
+	})
+}
+
+func GetCourseAnalytics(ctx context.Context, courseID uuid.UUID, refresh bool) (analyticsDTO.CourseAnalytics, error) {
+	return getCached(AnalyticsServiceSingleton.cache, "course:"+courseID.String(), refresh, func() (analyticsDTO.CourseAnalytics, error) {
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		defer cancel()
+
+		course, err := AnalyticsServiceSingleton.queries.GetCourse(ctxWithTimeout, courseID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return analyticsDTO.CourseAnalytics{}, ErrCourseNotFound
+		}
+		if err != nil {
+			log.Error(err)
+			return analyticsDTO.CourseAnalytics{}, errors.New("could not get the course")
+		}
+
+		courses := []courseRef{{ID: course.ID, Name: course.Name}}
+		funnels, demographics, repeatParticipants, err := computeAnalytics(ctxWithTimeout, courses)
+		if err != nil {
+			return analyticsDTO.CourseAnalytics{}, err
+		}
+
+		return analyticsDTO.CourseAnalytics{
+			Funnel:             funnels[0],
+			Demographics:       demographics,
+			RepeatParticipants: repeatParticipants,
+			ComputedAt:         time.Now(),
+		}, nil
+	})
+}
+
+func GetSemesterAnalytics(ctx context.Context, semesterTag string, refresh bool) (analyticsDTO.SemesterAnalytics, error) {
+	return getCached(AnalyticsServiceSingleton.cache, "semester:"+semesterTag, refresh, func() (analyticsDTO.SemesterAnalytics, error) {
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		defer cancel()
+
+		rows, err := AnalyticsServiceSingleton.queries.GetCoursesBySemesterTag(ctxWithTimeout, semesterTag)
+		if err != nil {
+			log.Error(err)
+			return analyticsDTO.SemesterAnalytics{}, errors.New("could not get the courses of the semester")
+		}
+		if len(rows) == 0 {
+			return analyticsDTO.SemesterAnalytics{}, ErrSemesterNotFound
+		}
+
+		courses := make([]courseRef, 0, len(rows))
+		for _, row := range rows {
+			courses = append(courses, courseRef{ID: row.ID, Name: row.Name})
+		}
+
+		funnels, demographics, repeatParticipants, err := computeAnalytics(ctxWithTimeout, courses)
+		if err != nil {
+			return analyticsDTO.SemesterAnalytics{}, err
+		}
+
+		return analyticsDTO.SemesterAnalytics{
+			SemesterTag:        semesterTag,
+			Courses:            funnels,
+			Demographics:       demographics,
+			RepeatParticipants: repeatParticipants,
+			ComputedAt:         time.Now(),
+		}, nil
+	})
+}
+
+func computeAnalytics(ctx context.Context, courses []courseRef) ([]analyticsDTO.CourseFunnel, analyticsDTO.Demographics, analyticsDTO.RepeatParticipants, error) {
+	courseIDs := make([]uuid.UUID, 0, len(courses))
+	for _, course := range courses {
+		courseIDs = append(courseIDs, course.ID)
+	}
+
+	phaseRows, err := AnalyticsServiceSingleton.queries.GetCoursePhaseFunnels(ctx, courseIDs)
+	if err != nil {
+		log.Error(err)
+		return nil, analyticsDTO.Demographics{}, analyticsDTO.RepeatParticipants{}, errors.New("could not get the course phase funnels")
+	}
+
+	demographicRows, err := AnalyticsServiceSingleton.queries.GetStudentDemographics(ctx, courseIDs)
+	if err != nil {
+		log.Error(err)
+		return nil, analyticsDTO.Demographics{}, analyticsDTO.RepeatParticipants{}, errors.New("could not get the demographics")
+	}
+
+	repeatRow, err := AnalyticsServiceSingleton.queries.GetRepeatParticipantStatistics(ctx, courseIDs)
+	if err != nil {
+		log.Error(err)
+		return nil, analyticsDTO.Demographics{}, analyticsDTO.RepeatParticipants{}, errors.New("could not get the repeat participants")
+	}
+
+	repeatParticipants := analyticsDTO.RepeatParticipants{
+		Participants:            repeatRow.Participants,
+		MultiCourseParticipants: repeatRow.MultiCourseParticipants,
+		ReturningParticipants:   repeatRow.ReturningParticipants,
+	}
+	return buildCourseFunnels(courses, phaseRows), buildDemographics(demographicRows), repeatParticipants, nil
+}
+
+// buildCourseFunnels returns a funnel for every course, in the order of the courses, with the phases in sequence order
+func buildCourseFunnels(courses []courseRef, phaseRows []db.GetCoursePhaseFunnelsRow) []analyticsDTO.CourseFunnel {
+	phasesByCourse := make(map[uuid.UUID][]analyticsDTO.PhaseFunnelStep)
+	for _, row := range phaseRows {
+		phasesByCourse[row.CourseID] = append(phasesByCourse[row.CourseID], analyticsDTO.GetPhaseFunnelStepDTOFromDBModel(row))
+	}
+
+	funnels := make([]analyticsDTO.CourseFunnel, 0, len(courses))
+	for _, course := range courses {
+		funnel := analyticsDTO.CourseFunnel{
+			CourseID:   course.ID,
+			CourseName: course.Name,
+			Phases:     make([]analyticsDTO.PhaseFunnelStep, 0),
+		}
+		if phases := phasesByCourse[course.ID]; len(phases) > 0 {
+			funnel.Phases = phases
+			funnel.Applied = phases[0].Participants
+			funnel.Accepted = phases[0].Passed
+			funnel.Completed = phases[len(phases)-1].Passed
+		}
+		funnels = append(funnels, funnel)
+	}
+	return funnels
+}
+
+func buildDemographics(rows []db.GetStudentDemographicsRow) analyticsDTO.Demographics {
+	demographics := analyticsDTO.Demographics{
+		Gender:       make([]analyticsDTO.DemographicGroup, 0),
+		Nationality:  make([]analyticsDTO.DemographicGroup, 0),
+		StudyProgram: make([]analyticsDTO.DemographicGroup, 0),
+		StudyDegree:  make([]analyticsDTO.DemographicGroup, 0),
+	}
+
+	for _, row := range rows {
+		group := analyticsDTO.DemographicGroup{Value: row.Value, Participants: row.Participants, Accepted: row.Accepted}
+		switch row.Dimension {
+		case "gender":
+			demographics.Gender = append(demographics.Gender, group)
+		case "nationality":
+			demographics.Nationality = append(demographics.Nationality, group)
+		case "studyProgram":
+			demographics.StudyProgram = append(demographics.StudyProgram, group)
+		case "studyDegree":
+			demographics.StudyDegree = append(demographics.StudyDegree, group)
+		}
+	}
+	return demographics
+}
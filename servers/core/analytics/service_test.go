@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prompt-edu/prompt/servers/core/analytics/analyticsDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCourseFunnels(t *testing.T) {
+	iPraktikum := courseRef{ID: uuid.New(), Name: "iPraktikum"}
+	emptyCourse := courseRef{ID: uuid.New(), Name: "Empty Course"}
+	applicationPhaseID := uuid.New()
+	teamPhaseID := uuid.New()
+
+	funnels := buildCourseFunnels([]courseRef{iPraktikum, emptyCourse}, []db.GetCoursePhaseFunnelsRow{
+		{
+			CourseID:            iPraktikum.ID,
+			CoursePhaseID:       applicationPhaseID,
+			CoursePhaseName:     pgtype.Text{String: "Application", Valid: true},
+			CoursePhaseTypeName: "Application",
+			SequenceOrder:       1,
+			Participants:        120,
+			Passed:              40,
+			Failed:              70,
+			NotAssessed:         10,
+		},
+		{
+			CourseID:            iPraktikum.ID,
+			CoursePhaseID:       teamPhaseID,
+			CoursePhaseName:     pgtype.Text{String: "Team Allocation", Valid: true},
+			CoursePhaseTypeName: "Team Allocation",
+			SequenceOrder:       2,
+			Participants:        40,
+			Passed:              38,
+			NotAssessed:         2,
+		},
+	})
+	require.Len(t, funnels, 2)
+
+	assert.Equal(t, iPraktikum.ID, funnels[0].CourseID)
+	assert.Equal(t, int64(120), funnels[0].Applied)
+	assert.Equal(t, int64(40), funnels[0].Accepted)
+	assert.Equal(t, int64(38), funnels[0].Completed)
+	require.Len(t, funnels[0].Phases, 2)
+	assert.Equal(t, applicationPhaseID, funnels[0].Phases[0].CoursePhaseID)
+	assert.Equal(t, "Team Allocation", funnels[0].Phases[1].Name)
+
+	assert.Equal(t, analyticsDTO.CourseFunnel{
+		CourseID:   emptyCourse.ID,
+		CourseName: "Empty Course",
+		Phases:     []analyticsDTO.PhaseFunnelStep{},
+	}, funnels[1], "courses without phases have an empty funnel")
+}
+
+func TestBuildDemographics(t *testing.T) {
+	demographics := buildDemographics([]db.GetStudentDemographicsRow{
+		{Dimension: "gender", Value: "female", Participants: 12, Accepted: 5},
+		{Dimension: "gender", Value: "male", Participants: 30, Accepted: 9},
+		{Dimension: "nationality", Value: "DE", Participants: 25, Accepted: 8},
+		{Dimension: "studyDegree", Value: "master", Participants: 42, Accepted: 14},
+		{Dimension: "studyProgram", Value: "", Participants: 42, Accepted: 14},
+	})
+
+	assert.Equal(t, []analyticsDTO.DemographicGroup{
+		{Value: "female", Participants: 12, Accepted: 5},
+		{Value: "male", Participants: 30, Accepted: 9},
+	}, demographics.Gender)
+	assert.Equal(t, []analyticsDTO.DemographicGroup{{Value: "DE", Participants: 25, Accepted: 8}}, demographics.Nationality)
+	assert.Equal(t, []analyticsDTO.DemographicGroup{{Value: "master", Participants: 42, Accepted: 14}}, demographics.StudyDegree)
+	assert.Equal(t, []analyticsDTO.DemographicGroup{{Value: "", Participants: 42, Accepted: 14}}, demographics.StudyProgram)
+
+	empty := buildDemographics(nil)
+	assert.NotNil(t, empty.Gender, "dimensions are serialized as empty lists")
+	assert.Empty(t, empty.Gender)
+}
@@ -16,6 +16,22 @@ type AnswerFileUpload struct {
 	FileSize              int64     `json:"fileSize"`
 	UploadedAt            time.Time `json:"uploadedAt"`
 	DownloadURL           string    `json:"downloadUrl"`
+	PreviewURL            string    `json:"previewUrl,omitempty"`
+	Version               int32     `json:"version,omitempty"`
+	// ScanStatus tells why quarantined files have no download URL
+	ScanStatus db.FileScanStatus `json:"scanStatus,omitempty"`
+	// Versions are the files this answer had before, the newest first. They are only listed for lecturers and admins.
+	Versions []AnswerFileVersion `json:"versions,omitempty"`
+}
+
+// AnswerFileVersion is a file that was replaced by a later upload of the applicant
+type AnswerFileVersion struct {
+	FileID      uuid.UUID `json:"fileID"`
+	Version     int32     `json:"version"`
+	FileName    string    `json:"fileName"`
+	FileSize    int64     `json:"fileSize"`
+	UploadedAt  time.Time `json:"uploadedAt"`
+	DownloadURL string    `json:"downloadUrl"`
 }
 
 func (a AnswerFileUpload) GetDBModel() db.ApplicationAnswerFileUpload {
@@ -0,0 +1,28 @@
+package applicationDTO
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+type ApplicationDraft struct {
+	CoursePhaseID uuid.UUID       `json:"coursePhaseID"`
+	Application   PostApplication `json:"application"`
+	LastModified  time.Time       `json:"lastModified"`
+}
+
+func GetApplicationDraftDTOFromDBModel(model db.ApplicationDraft) (ApplicationDraft, error) {
+	var application PostApplication
+	if err := json.Unmarshal(model.Application, &application); err != nil {
+		return ApplicationDraft{}, err
+	}
+
+	return ApplicationDraft{
+		CoursePhaseID: model.CoursePhaseID,
+		Application:   application,
+		LastModified:  model.LastModified.Time,
+	}, nil
+}
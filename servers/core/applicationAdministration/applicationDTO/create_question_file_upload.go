@@ -16,6 +16,10 @@ type CreateQuestionFileUpload struct {
 	OrderNum                 int         `json:"orderNum"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessibleForOtherPhases" swaggertype:"boolean"`
 	AccessKey                pgtype.Text `json:"accessKey" swaggertype:"string"`
+	// nil if the question is always displayed
+	DisplayCondition *QuestionCondition `json:"displayCondition"`
+	// answers are hidden from editors during a blind review
+	IsIdentifying bool `json:"isIdentifying"`
 }
 
 func (a CreateQuestionFileUpload) GetDBModel() db.CreateApplicationQuestionFileUploadParams {
@@ -44,5 +48,7 @@ func (a CreateQuestionFileUpload) GetDBModel() db.CreateApplicationQuestionFileU
 		OrderNum:                 int32(a.OrderNum),
 		AccessibleForOtherPhases: a.AccessibleForOtherPhases.Bool,
 		AccessKey:                a.AccessKey,
+		DisplayCondition:         GetDisplayConditionDBModel(a.DisplayCondition),
+		IsIdentifying:            a.IsIdentifying,
 	}
 }
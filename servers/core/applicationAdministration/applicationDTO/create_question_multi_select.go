@@ -20,6 +20,10 @@ type CreateQuestionMultiSelect struct {
 	// using pgtype as this allows for optional values
 	AccessibleForOtherPhases pgtype.Bool `json:"accessibleForOtherPhases" swaggertype:"boolean"`
 	AccessKey                pgtype.Text `json:"accessKey" swaggertype:"string"`
+	// nil if the question is always displayed
+	DisplayCondition *QuestionCondition `json:"displayCondition"`
+	// answers are hidden from editors during a blind review
+	IsIdentifying bool `json:"isIdentifying"`
 }
 
 func (a CreateQuestionMultiSelect) GetDBModel() db.CreateApplicationQuestionMultiSelectParams {
@@ -36,6 +40,8 @@ func (a CreateQuestionMultiSelect) GetDBModel() db.CreateApplicationQuestionMult
 		OrderNum:                 pgtype.Int4{Int32: int32(a.OrderNum), Valid: true},
 		AccessibleForOtherPhases: a.AccessibleForOtherPhases,
 		AccessKey:                a.AccessKey,
+		DisplayCondition:         GetDisplayConditionDBModel(a.DisplayCondition),
+		IsIdentifying:            a.IsIdentifying,
 	}
 
 }
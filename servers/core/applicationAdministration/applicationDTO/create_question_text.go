@@ -19,6 +19,10 @@ type CreateQuestionText struct {
 	// using pgtype as this allows for optional values
 	AccessibleForOtherPhases pgtype.Bool `json:"accessibleForOtherPhases" swaggertype:"boolean"`
 	AccessKey                pgtype.Text `json:"accessKey" swaggertype:"string"`
+	// nil if the question is always displayed
+	DisplayCondition *QuestionCondition `json:"displayCondition"`
+	// answers are hidden from editors during a blind review
+	IsIdentifying bool `json:"isIdentifying"`
 }
 
 func (a CreateQuestionText) GetDBModel() db.CreateApplicationQuestionTextParams {
@@ -34,5 +38,7 @@ func (a CreateQuestionText) GetDBModel() db.CreateApplicationQuestionTextParams
 		OrderNum:                 pgtype.Int4{Int32: int32(a.OrderNum), Valid: true},
 		AccessibleForOtherPhases: a.AccessibleForOtherPhases,
 		AccessKey:                a.AccessKey,
+		DisplayCondition:         GetDisplayConditionDBModel(a.DisplayCondition),
+		IsIdentifying:            a.IsIdentifying,
 	}
 }
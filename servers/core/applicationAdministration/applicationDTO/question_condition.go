@@ -0,0 +1,104 @@
+package applicationDTO
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type ConditionOperator string
+
+const (
+	ConditionOperatorAnd ConditionOperator = "and"
+	ConditionOperatorOr  ConditionOperator = "or"
+)
+
+type ConditionComparator string
+
+const (
+	ConditionComparatorEquals        ConditionComparator = "equals"
+	ConditionComparatorNotEquals     ConditionComparator = "notEquals"
+	ConditionComparatorContains      ConditionComparator = "contains"
+	ConditionComparatorNotContains   ConditionComparator = "notContains"
+	ConditionComparatorIsAnswered    ConditionComparator = "isAnswered"
+	ConditionComparatorIsNotAnswered ConditionComparator = "isNotAnswered"
+)
+
+// QuestionCondition is a node of a display condition tree.
+// A node is either a group (Operator and Conditions set) combining its children,
+// or a leaf (QuestionID and Comparator set) comparing the answer of another question of the same form.
+// QuestionID is a pointer so that groups are serialized without a question reference.
+type QuestionCondition struct {
+	Operator   ConditionOperator   `json:"operator,omitempty" enums:"and,or"`
+	Conditions []QuestionCondition `json:"conditions,omitempty"`
+	QuestionID *uuid.UUID          `json:"questionID,omitempty"`
+	Comparator ConditionComparator `json:"comparator,omitempty" enums:"equals,notEquals,contains,notContains,isAnswered,isNotAnswered"`
+	Value      string              `json:"value,omitempty"`
+}
+
+func (c QuestionCondition) IsGroup() bool {
+	return c.Operator != ""
+}
+
+// ReferencedQuestionIDs returns the ids of all questions the condition tree depends on.
+func (c QuestionCondition) ReferencedQuestionIDs() []uuid.UUID {
+	if !c.IsGroup() {
+		if c.QuestionID == nil {
+			return nil
+		}
+		return []uuid.UUID{*c.QuestionID}
+	}
+
+	questionIDs := make([]uuid.UUID, 0, len(c.Conditions))
+	for _, condition := range c.Conditions {
+		questionIDs = append(questionIDs, condition.ReferencedQuestionIDs()...)
+	}
+	return questionIDs
+}
+
+// RemapQuestionIDs returns a copy of the condition tree with all question references replaced
+// according to the mapping. References without a mapping entry are kept.
+func (c QuestionCondition) RemapQuestionIDs(questionIDMapping map[uuid.UUID]uuid.UUID) QuestionCondition {
+	remapped := c
+	if c.QuestionID != nil {
+		if newID, ok := questionIDMapping[*c.QuestionID]; ok {
+			remapped.QuestionID = &newID
+		}
+	}
+
+	if c.Conditions != nil {
+		remapped.Conditions = make([]QuestionCondition, 0, len(c.Conditions))
+		for _, condition := range c.Conditions {
+			remapped.Conditions = append(remapped.Conditions, condition.RemapQuestionIDs(questionIDMapping))
+		}
+	}
+	return remapped
+}
+
+func GetDisplayConditionDBModel(condition *QuestionCondition) []byte {
+	// a question without condition is always displayed
+	if condition == nil {
+		return nil
+	}
+
+	conditionBytes, err := json.Marshal(condition)
+	if err != nil {
+		log.Error("failed to marshal display condition: ", err)
+		return nil
+	}
+	return conditionBytes
+}
+
+func GetDisplayConditionDTOFromDBModel(conditionBytes []byte) *QuestionCondition {
+	if len(conditionBytes) == 0 {
+		return nil
+	}
+
+	var condition QuestionCondition
+	if err := json.Unmarshal(conditionBytes, &condition); err != nil {
+		log.Error("failed to parse display condition stored in database: ", err)
+		return nil
+	}
+	return &condition
+}
@@ -17,6 +17,10 @@ type QuestionFileUpload struct {
 	OrderNum                 int         `json:"orderNum"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessibleForOtherPhases" swaggertype:"boolean"`
 	AccessKey                pgtype.Text `json:"accessKey" swaggertype:"string"`
+	// nil if the question is always displayed
+	DisplayCondition *QuestionCondition `json:"displayCondition"`
+	// answers are hidden from editors during a blind review
+	IsIdentifying bool `json:"isIdentifying"`
 }
 
 func (a QuestionFileUpload) GetDBModel() db.UpdateApplicationQuestionFileUploadParams {
@@ -45,6 +49,8 @@ func (a QuestionFileUpload) GetDBModel() db.UpdateApplicationQuestionFileUploadP
 		OrderNum:                 int32(a.OrderNum),
 		AccessibleForOtherPhases: a.AccessibleForOtherPhases.Bool,
 		AccessKey:                a.AccessKey,
+		DisplayCondition:         GetDisplayConditionDBModel(a.DisplayCondition),
+		IsIdentifying:            a.IsIdentifying,
 	}
 }
 
@@ -75,5 +81,7 @@ func GetQuestionFileUploadDTOFromDBModel(question db.ApplicationQuestionFileUplo
 		OrderNum:                 int(question.OrderNum),
 		AccessibleForOtherPhases: pgtype.Bool{Bool: question.AccessibleForOtherPhases, Valid: true},
 		AccessKey:                question.AccessKey,
+		DisplayCondition:         GetDisplayConditionDTOFromDBModel(question.DisplayCondition),
+		IsIdentifying:            question.IsIdentifying,
 	}
 }
@@ -21,6 +21,10 @@ type QuestionMultiSelect struct {
 	// using pgtype as this allows for optional values
 	AccessibleForOtherPhases pgtype.Bool `json:"accessibleForOtherPhases" swaggertype:"boolean"`
 	AccessKey                pgtype.Text `json:"accessKey" swaggertype:"string"`
+	// nil if the question is always displayed
+	DisplayCondition *QuestionCondition `json:"displayCondition"`
+	// answers are hidden from editors during a blind review
+	IsIdentifying bool `json:"isIdentifying"`
 }
 
 func (a QuestionMultiSelect) GetDBModel() db.UpdateApplicationQuestionMultiSelectParams {
@@ -37,6 +41,8 @@ func (a QuestionMultiSelect) GetDBModel() db.UpdateApplicationQuestionMultiSelec
 		OrderNum:                 pgtype.Int4{Int32: int32(a.OrderNum), Valid: true},
 		AccessibleForOtherPhases: a.AccessibleForOtherPhases,
 		AccessKey:                a.AccessKey,
+		DisplayCondition:         GetDisplayConditionDBModel(a.DisplayCondition),
+		IsIdentifying:            a.IsIdentifying,
 	}
 
 }
@@ -56,5 +62,7 @@ func GetQuestionMultiSelectDTOFromDBModel(applicationQuestionMultiSelect db.Appl
 		OrderNum:                 int(applicationQuestionMultiSelect.OrderNum.Int32),
 		AccessibleForOtherPhases: applicationQuestionMultiSelect.AccessibleForOtherPhases,
 		AccessKey:                applicationQuestionMultiSelect.AccessKey,
+		DisplayCondition:         GetDisplayConditionDTOFromDBModel(applicationQuestionMultiSelect.DisplayCondition),
+		IsIdentifying:            applicationQuestionMultiSelect.IsIdentifying,
 	}
 }
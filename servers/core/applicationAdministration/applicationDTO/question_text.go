@@ -20,6 +20,10 @@ type QuestionText struct {
 	// using pgtype as this allows for optional values
 	AccessibleForOtherPhases pgtype.Bool `json:"accessibleForOtherPhases" swaggertype:"boolean"`
 	AccessKey                pgtype.Text `json:"accessKey" swaggertype:"string"`
+	// nil if the question is always displayed
+	DisplayCondition *QuestionCondition `json:"displayCondition"`
+	// answers are hidden from editors during a blind review
+	IsIdentifying bool `json:"isIdentifying"`
 }
 
 func (a QuestionText) GetDBModel() db.UpdateApplicationQuestionTextParams {
@@ -35,6 +39,8 @@ func (a QuestionText) GetDBModel() db.UpdateApplicationQuestionTextParams {
 		OrderNum:                 pgtype.Int4{Int32: int32(a.OrderNum), Valid: true},
 		AccessibleForOtherPhases: a.AccessibleForOtherPhases,
 		AccessKey:                a.AccessKey,
+		DisplayCondition:         GetDisplayConditionDBModel(a.DisplayCondition),
+		IsIdentifying:            a.IsIdentifying,
 	}
 }
 
@@ -52,5 +58,7 @@ func GetQuestionTextDTOFromDBModel(applicationQuestionText db.ApplicationQuestio
 		OrderNum:                 int(applicationQuestionText.OrderNum.Int32),
 		AccessibleForOtherPhases: applicationQuestionText.AccessibleForOtherPhases,
 		AccessKey:                applicationQuestionText.AccessKey,
+		DisplayCondition:         GetDisplayConditionDTOFromDBModel(applicationQuestionText.DisplayCondition),
+		IsIdentifying:            applicationQuestionText.IsIdentifying,
 	}
 }
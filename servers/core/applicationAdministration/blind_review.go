@@ -0,0 +1,127 @@
+package applicationAdministration
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prompt-edu/prompt/servers/core/applicationAdministration/applicationDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	log "github.com/sirupsen/logrus"
+)
+
+// blindReview describes what a reviewer is allowed to see of the applicants of a phase.
+// The zero value shows everything.
+type blindReview struct {
+	enabled                bool
+	revealScored           bool
+	identifyingQuestionIDs map[uuid.UUID]bool
+}
+
+// isBlindReviewer reports whether the identity of applicants is hidden from the user during a blind review.
+// Only editors review blindly, admins and lecturers of the course or the phase always see the identity.
+func isBlindReviewer(ctx context.Context, userRolesMap map[string]bool, courseTokenIdentifier string, coursePhaseID uuid.UUID) (bool, error) {
+	isLecturer, err := permissionValidation.HasCoursePhaseRole(ctx, userRolesMap, courseTokenIdentifier, coursePhaseID, permissionValidation.PromptAdmin, permissionValidation.CourseLecturer)
+	if err != nil {
+		return false, err
+	}
+	return !isLecturer, nil
+}
+
+func getBlindReview(ctx context.Context, coursePhaseID uuid.UUID, blindReviewer bool) (blindReview, error) {
+	if !blindReviewer {
+		return blindReview{}, nil
+	}
+
+	settings, err := ApplicationServiceSingleton.queries.GetApplicationBlindReviewSettings(ctx, coursePhaseID)
+	if err != nil {
+		log.Error(err)
+		return blindReview{}, errors.New("could not get the blind review settings")
+	}
+
+	if !settings.BlindReview {
+		return blindReview{}, nil
+	}
+
+	questionIDs, err := ApplicationServiceSingleton.queries.GetIdentifyingApplicationQuestionIDs(ctx, coursePhaseID)
+	if err != nil {
+		log.Error(err)
+		return blindReview{}, errors.New("could not get the identifying questions")
+	}
+
+	identifyingQuestionIDs := make(map[uuid.UUID]bool, len(questionIDs))
+	for _, questionID := range questionIDs {
+		identifyingQuestionIDs[questionID] = true
+	}
+
+	return blindReview{
+		enabled:                true,
+		revealScored:           settings.BlindReviewRevealed,
+		identifyingQuestionIDs: identifyingQuestionIDs,
+	}, nil
+}
+
+// hidesIdentity reports whether an application with the given score has to be anonymized.
+// Once the lecturer revealed the review, only applications that were not scored yet stay anonymous.
+func (b blindReview) hidesIdentity(score pgtype.Int4) bool {
+	return b.enabled && !(b.revealScored && score.Valid)
+}
+
+func redactStudent(student *studentDTO.Student) {
+	student.FirstName = ""
+	student.LastName = ""
+	student.Email = ""
+	student.MatriculationNumber = ""
+	student.UniversityLogin = ""
+	student.Gender = ""
+	student.Nationality = ""
+}
+
+func (b blindReview) redactApplication(application *applicationDTO.Application) {
+	if application.Student != nil {
+		redactStudent(application.Student)
+	}
+
+	answersText := make([]applicationDTO.AnswerText, 0, len(application.AnswersText))
+	for _, answer := range application.AnswersText {
+		if !b.identifyingQuestionIDs[answer.ApplicationQuestionID] {
+			answersText = append(answersText, answer)
+		}
+	}
+	application.AnswersText = answersText
+
+	answersMultiSelect := make([]applicationDTO.AnswerMultiSelect, 0, len(application.AnswersMultiSelect))
+	for _, answer := range application.AnswersMultiSelect {
+		if !b.identifyingQuestionIDs[answer.ApplicationQuestionID] {
+			answersMultiSelect = append(answersMultiSelect, answer)
+		}
+	}
+	application.AnswersMultiSelect = answersMultiSelect
+
+	answersFileUpload := make([]applicationDTO.AnswerFileUpload, 0, len(application.AnswersFileUpload))
+	for _, answer := range application.AnswersFileUpload {
+		if !b.identifyingQuestionIDs[answer.ApplicationQuestionID] {
+			// original filenames often contain the name of the applicant
+			answer.FileName = ""
+			answer.Versions = nil
+			answersFileUpload = append(answersFileUpload, answer)
+		}
+	}
+	application.AnswersFileUpload = answersFileUpload
+}
+
+// RevealBlindReview lifts the anonymity of all scored applications of the phase for the editors.
+func RevealBlindReview(ctx context.Context, coursePhaseID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	err := ApplicationServiceSingleton.queries.RevealApplicationBlindReview(ctxWithTimeout, coursePhaseID)
+	if err != nil {
+		log.Error(err)
+		return errors.New("could not reveal the blind review")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package applicationAdministration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prompt-edu/prompt/servers/core/applicationAdministration/applicationDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBlindReviewer(t *testing.T) {
+	coursePhaseID := uuid.New()
+	for userRoles, expected := range map[string]bool{
+		"ios24245-iPraktikum-Editor":   true,
+		"ios24245-iPraktikum-Lecturer": false,
+		"PROMPT_Admin":                 false,
+		"other-course-Lecturer":        true,
+	} {
+		// roles without custom course roles are resolved without a query
+		blindReviewer, err := isBlindReviewer(context.Background(), map[string]bool{userRoles: true}, "ios24245-iPraktikum", coursePhaseID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, blindReviewer, userRoles)
+	}
+}
+
+func TestBlindReviewHidesIdentity(t *testing.T) {
+	scored := pgtype.Int4{Int32: 7, Valid: true}
+	unscored := pgtype.Int4{}
+
+	assert.False(t, blindReview{}.hidesIdentity(unscored))
+
+	review := blindReview{enabled: true}
+	assert.True(t, review.hidesIdentity(scored))
+	assert.True(t, review.hidesIdentity(unscored))
+
+	// after revealing, only unscored applications stay anonymous
+	review.revealScored = true
+	assert.False(t, review.hidesIdentity(scored))
+	assert.True(t, review.hidesIdentity(unscored))
+}
+
+func TestBlindReviewRedactApplication(t *testing.T) {
+	identifyingQuestionID := uuid.New()
+	otherQuestionID := uuid.New()
+
+	application := applicationDTO.Application{
+		Student: &studentDTO.Student{
+			ID:                  uuid.New(),
+			FirstName:           "Jane",
+			LastName:            "Doe",
+			Email:               "jane@example.com",
+			MatriculationNumber: "03700001",
+			UniversityLogin:     "ge12abc",
+			Gender:              db.GenderFemale,
+			Nationality:         "DE",
+			StudyProgram:        "Informatics",
+		},
+		AnswersText: []applicationDTO.AnswerText{
+			{ApplicationQuestionID: identifyingQuestionID, Answer: "https://github.com/janedoe"},
+			{ApplicationQuestionID: otherQuestionID, Answer: "I like building apps"},
+		},
+		AnswersMultiSelect: []applicationDTO.AnswerMultiSelect{
+			{ApplicationQuestionID: otherQuestionID, Answer: []string{"iOS"}},
+		},
+		AnswersFileUpload: []applicationDTO.AnswerFileUpload{
+			{ApplicationQuestionID: identifyingQuestionID},
+			{
+				ApplicationQuestionID: otherQuestionID,
+				FileName:              "Jane_Doe_Transcript.pdf",
+				Versions:              []applicationDTO.AnswerFileVersion{{FileName: "Jane_Doe_Transcript_old.pdf"}},
+			},
+		},
+	}
+
+	review := blindReview{enabled: true, identifyingQuestionIDs: map[uuid.UUID]bool{identifyingQuestionID: true}}
+	review.redactApplication(&application)
+
+	assert.Empty(t, application.Student.FirstName)
+	assert.Empty(t, application.Student.LastName)
+	assert.Empty(t, application.Student.Email)
+	assert.Empty(t, application.Student.MatriculationNumber)
+	assert.Empty(t, application.Student.UniversityLogin)
+	assert.Empty(t, application.Student.Gender)
+	assert.Empty(t, application.Student.Nationality)
+	assert.NotEqual(t, uuid.Nil, application.Student.ID, "the id is kept to refer to the application")
+	assert.Equal(t, "Informatics", application.Student.StudyProgram)
+
+	assert.Len(t, application.AnswersText, 1)
+	assert.Equal(t, otherQuestionID, application.AnswersText[0].ApplicationQuestionID)
+	assert.Len(t, application.AnswersMultiSelect, 1)
+	if assert.Len(t, application.AnswersFileUpload, 1) {
+		assert.Equal(t, otherQuestionID, application.AnswersFileUpload[0].ApplicationQuestionID)
+		assert.Empty(t, application.AnswersFileUpload[0].FileName, "original filenames may contain the name")
+		assert.Empty(t, application.AnswersFileUpload[0].Versions)
+	}
+}
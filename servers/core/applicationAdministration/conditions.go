@@ -0,0 +1,365 @@
+package applicationAdministration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/applicationAdministration/applicationDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+type questionKind int
+
+const (
+	questionKindText questionKind = iota
+	questionKindMultiSelect
+	questionKindFileUpload
+)
+
+// conditionalQuestion bundles what is needed to validate and evaluate the display condition of a question.
+type conditionalQuestion struct {
+	kind      questionKind
+	condition *applicationDTO.QuestionCondition
+}
+
+func getConditionalQuestions(questionsText []db.ApplicationQuestionText, questionsMultiSelect []db.ApplicationQuestionMultiSelect, questionsFileUpload []db.ApplicationQuestionFileUpload) map[uuid.UUID]conditionalQuestion {
+	questions := make(map[uuid.UUID]conditionalQuestion, len(questionsText)+len(questionsMultiSelect)+len(questionsFileUpload))
+	for _, question := range questionsText {
+		questions[question.ID] = conditionalQuestion{kind: questionKindText, condition: applicationDTO.GetDisplayConditionDTOFromDBModel(question.DisplayCondition)}
+	}
+	for _, question := range questionsMultiSelect {
+		questions[question.ID] = conditionalQuestion{kind: questionKindMultiSelect, condition: applicationDTO.GetDisplayConditionDTOFromDBModel(question.DisplayCondition)}
+	}
+	for _, question := range questionsFileUpload {
+		questions[question.ID] = conditionalQuestion{kind: questionKindFileUpload, condition: applicationDTO.GetDisplayConditionDTOFromDBModel(question.DisplayCondition)}
+	}
+	return questions
+}
+
+// validateDisplayConditions checks the conditions of the form as it looks after an update.
+// The questions map must contain every question of the form, already reflecting the update.
+func validateDisplayConditions(questions map[uuid.UUID]conditionalQuestion, createdConditions []*applicationDTO.QuestionCondition) error {
+	for questionID, question := range questions {
+		if question.condition == nil {
+			continue
+		}
+		if err := validateCondition(*question.condition, questionID, questions); err != nil {
+			return err
+		}
+	}
+
+	// newly created questions cannot be referenced yet, so they can never be part of a cycle
+	for _, condition := range createdConditions {
+		if condition == nil {
+			continue
+		}
+		if err := validateCondition(*condition, uuid.Nil, questions); err != nil {
+			return err
+		}
+	}
+
+	return checkForConditionCycles(questions)
+}
+
+func validateCondition(condition applicationDTO.QuestionCondition, questionID uuid.UUID, questions map[uuid.UUID]conditionalQuestion) error {
+	if condition.IsGroup() {
+		if condition.Operator != applicationDTO.ConditionOperatorAnd && condition.Operator != applicationDTO.ConditionOperatorOr {
+			return fmt.Errorf("invalid condition operator %q", condition.Operator)
+		}
+		if condition.QuestionID != nil || condition.Comparator != "" {
+			return errors.New("a condition group cannot reference a question")
+		}
+		if len(condition.Conditions) == 0 {
+			return errors.New("a condition group must contain at least one condition")
+		}
+		for _, child := range condition.Conditions {
+			if err := validateCondition(child, questionID, questions); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(condition.Conditions) > 0 {
+		return errors.New("a condition referencing a question cannot contain nested conditions")
+	}
+	if condition.QuestionID == nil {
+		return errors.New("a condition must either reference a question or be a condition group")
+	}
+	if *condition.QuestionID == questionID {
+		return errors.New("a question cannot depend on itself")
+	}
+	referencedQuestion, exists := questions[*condition.QuestionID]
+	if !exists {
+		return fmt.Errorf("condition references question %s which does not belong to this application form", *condition.QuestionID)
+	}
+
+	switch condition.Comparator {
+	case applicationDTO.ConditionComparatorIsAnswered, applicationDTO.ConditionComparatorIsNotAnswered:
+		return nil
+	case applicationDTO.ConditionComparatorEquals, applicationDTO.ConditionComparatorNotEquals,
+		applicationDTO.ConditionComparatorContains, applicationDTO.ConditionComparatorNotContains:
+		if referencedQuestion.kind == questionKindFileUpload {
+			return errors.New("file upload questions can only be checked for being answered")
+		}
+		if condition.Value == "" {
+			return fmt.Errorf("condition comparator %q requires a value", condition.Comparator)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid condition comparator %q", condition.Comparator)
+	}
+}
+
+func checkForConditionCycles(questions map[uuid.UUID]conditionalQuestion) error {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[uuid.UUID]int, len(questions))
+
+	var visit func(questionID uuid.UUID) error
+	visit = func(questionID uuid.UUID) error {
+		switch state[questionID] {
+		case inProgress:
+			return errors.New("display conditions must not depend on each other in a cycle")
+		case done:
+			return nil
+		}
+
+		state[questionID] = inProgress
+		if condition := questions[questionID].condition; condition != nil {
+			for _, referencedID := range condition.ReferencedQuestionIDs() {
+				if err := visit(referencedID); err != nil {
+					return err
+				}
+			}
+		}
+		state[questionID] = done
+		return nil
+	}
+
+	for questionID := range questions {
+		if err := visit(questionID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conditionEvaluator decides which questions of a form are displayed for a given application.
+// A question is displayed if it has no condition or its condition holds. Answers to hidden
+// questions are treated as missing, so hiding a question also hides its dependents.
+type conditionEvaluator struct {
+	questions          map[uuid.UUID]conditionalQuestion
+	answersText        map[uuid.UUID]string
+	answersMultiSelect map[uuid.UUID][]string
+	answersFileUpload  map[uuid.UUID]uuid.UUID
+	visible            map[uuid.UUID]bool
+	evaluating         map[uuid.UUID]bool
+}
+
+func newConditionEvaluator(questions map[uuid.UUID]conditionalQuestion, application applicationDTO.PostApplication) *conditionEvaluator {
+	evaluator := &conditionEvaluator{
+		questions:          questions,
+		answersText:        make(map[uuid.UUID]string, len(application.AnswersText)),
+		answersMultiSelect: make(map[uuid.UUID][]string, len(application.AnswersMultiSelect)),
+		answersFileUpload:  make(map[uuid.UUID]uuid.UUID, len(application.AnswersFileUpload)),
+		visible:            make(map[uuid.UUID]bool, len(questions)),
+		evaluating:         make(map[uuid.UUID]bool),
+	}
+
+	for _, answer := range application.AnswersText {
+		evaluator.answersText[answer.ApplicationQuestionID] = answer.Answer
+	}
+	for _, answer := range application.AnswersMultiSelect {
+		evaluator.answersMultiSelect[answer.ApplicationQuestionID] = answer.Answer
+	}
+	for _, answer := range application.AnswersFileUpload {
+		evaluator.answersFileUpload[answer.ApplicationQuestionID] = answer.FileID
+	}
+	return evaluator
+}
+
+func (e *conditionEvaluator) isVisible(questionID uuid.UUID) bool {
+	if visible, evaluated := e.visible[questionID]; evaluated {
+		return visible
+	}
+	// cycles are rejected when saving the form, this only guards against inconsistent data
+	if e.evaluating[questionID] {
+		return false
+	}
+
+	question, exists := e.questions[questionID]
+	if !exists {
+		return false
+	}
+
+	e.evaluating[questionID] = true
+	visible := question.condition == nil || e.evaluate(*question.condition)
+	delete(e.evaluating, questionID)
+
+	e.visible[questionID] = visible
+	return visible
+}
+
+func (e *conditionEvaluator) evaluate(condition applicationDTO.QuestionCondition) bool {
+	switch condition.Operator {
+	case applicationDTO.ConditionOperatorAnd:
+		for _, child := range condition.Conditions {
+			if !e.evaluate(child) {
+				return false
+			}
+		}
+		return true
+	case applicationDTO.ConditionOperatorOr:
+		for _, child := range condition.Conditions {
+			if e.evaluate(child) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if condition.QuestionID == nil {
+		return false
+	}
+	questionID := *condition.QuestionID
+
+	switch condition.Comparator {
+	case applicationDTO.ConditionComparatorIsAnswered:
+		return e.isAnswered(questionID)
+	case applicationDTO.ConditionComparatorIsNotAnswered:
+		return !e.isAnswered(questionID)
+	case applicationDTO.ConditionComparatorEquals:
+		return e.answerEquals(questionID, condition.Value)
+	case applicationDTO.ConditionComparatorNotEquals:
+		return !e.answerEquals(questionID, condition.Value)
+	case applicationDTO.ConditionComparatorContains:
+		return e.answerContains(questionID, condition.Value)
+	case applicationDTO.ConditionComparatorNotContains:
+		return !e.answerContains(questionID, condition.Value)
+	default:
+		return false
+	}
+}
+
+func (e *conditionEvaluator) isAnswered(questionID uuid.UUID) bool {
+	if !e.isVisible(questionID) {
+		return false
+	}
+
+	switch e.questions[questionID].kind {
+	case questionKindText:
+		return strings.TrimSpace(e.answersText[questionID]) != ""
+	case questionKindMultiSelect:
+		return len(e.answersMultiSelect[questionID]) > 0
+	case questionKindFileUpload:
+		return e.answersFileUpload[questionID] != uuid.Nil
+	default:
+		return false
+	}
+}
+
+// answerEquals compares a text answer or a single selection case-insensitively with the value.
+func (e *conditionEvaluator) answerEquals(questionID uuid.UUID, value string) bool {
+	if !e.isAnswered(questionID) {
+		return false
+	}
+
+	switch e.questions[questionID].kind {
+	case questionKindText:
+		return strings.EqualFold(strings.TrimSpace(e.answersText[questionID]), strings.TrimSpace(value))
+	case questionKindMultiSelect:
+		selection := e.answersMultiSelect[questionID]
+		return len(selection) == 1 && strings.EqualFold(selection[0], value)
+	default:
+		return false
+	}
+}
+
+// answerContains checks if a text answer contains the value or if the value is one of the selected options.
+func (e *conditionEvaluator) answerContains(questionID uuid.UUID, value string) bool {
+	if !e.isAnswered(questionID) {
+		return false
+	}
+
+	switch e.questions[questionID].kind {
+	case questionKindText:
+		return strings.Contains(strings.ToLower(e.answersText[questionID]), strings.ToLower(value))
+	case questionKindMultiSelect:
+		for _, selection := range e.answersMultiSelect[questionID] {
+			if strings.EqualFold(selection, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// filterHiddenQuestions removes all questions and answers that are hidden by a display condition,
+// so that hidden required questions do not block the submission.
+func filterHiddenQuestions(
+	questionsText []db.ApplicationQuestionText,
+	questionsMultiSelect []db.ApplicationQuestionMultiSelect,
+	questionsFileUpload []db.ApplicationQuestionFileUpload,
+	application applicationDTO.PostApplication,
+) ([]db.ApplicationQuestionText, []db.ApplicationQuestionMultiSelect, []db.ApplicationQuestionFileUpload, applicationDTO.PostApplication) {
+	evaluator := newConditionEvaluator(getConditionalQuestions(questionsText, questionsMultiSelect, questionsFileUpload), application)
+
+	visibleQuestionsText := make([]db.ApplicationQuestionText, 0, len(questionsText))
+	for _, question := range questionsText {
+		if evaluator.isVisible(question.ID) {
+			visibleQuestionsText = append(visibleQuestionsText, question)
+		}
+	}
+
+	visibleQuestionsMultiSelect := make([]db.ApplicationQuestionMultiSelect, 0, len(questionsMultiSelect))
+	for _, question := range questionsMultiSelect {
+		if evaluator.isVisible(question.ID) {
+			visibleQuestionsMultiSelect = append(visibleQuestionsMultiSelect, question)
+		}
+	}
+
+	visibleQuestionsFileUpload := make([]db.ApplicationQuestionFileUpload, 0, len(questionsFileUpload))
+	for _, question := range questionsFileUpload {
+		if evaluator.isVisible(question.ID) {
+			visibleQuestionsFileUpload = append(visibleQuestionsFileUpload, question)
+		}
+	}
+
+	// answers to unknown questions are kept, so that the validation still rejects them
+	isHidden := func(questionID uuid.UUID) bool {
+		_, exists := evaluator.questions[questionID]
+		return exists && !evaluator.isVisible(questionID)
+	}
+
+	filteredApplication := application
+	filteredApplication.AnswersText = make([]applicationDTO.CreateAnswerText, 0, len(application.AnswersText))
+	for _, answer := range application.AnswersText {
+		if !isHidden(answer.ApplicationQuestionID) {
+			filteredApplication.AnswersText = append(filteredApplication.AnswersText, answer)
+		}
+	}
+
+	filteredApplication.AnswersMultiSelect = make([]applicationDTO.CreateAnswerMultiSelect, 0, len(application.AnswersMultiSelect))
+	for _, answer := range application.AnswersMultiSelect {
+		if !isHidden(answer.ApplicationQuestionID) {
+			filteredApplication.AnswersMultiSelect = append(filteredApplication.AnswersMultiSelect, answer)
+		}
+	}
+
+	filteredApplication.AnswersFileUpload = make([]applicationDTO.CreateAnswerFileUpload, 0, len(application.AnswersFileUpload))
+	for _, answer := range application.AnswersFileUpload {
+		if !isHidden(answer.ApplicationQuestionID) {
+			filteredApplication.AnswersFileUpload = append(filteredApplication.AnswersFileUpload, answer)
+		}
+	}
+
+	return visibleQuestionsText, visibleQuestionsMultiSelect, visibleQuestionsFileUpload, filteredApplication
+}
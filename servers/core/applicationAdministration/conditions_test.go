@@ -0,0 +1,169 @@
+package applicationAdministration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prompt-edu/prompt/servers/core/applicationAdministration/applicationDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustMarshalCondition(t *testing.T, condition applicationDTO.QuestionCondition) []byte {
+	conditionBytes, err := json.Marshal(condition)
+	assert.NoError(t, err)
+	return conditionBytes
+}
+
+func TestFilterHiddenQuestions_HiddenRequiredQuestionIsSkipped(t *testing.T) {
+	devicesQuestionID := uuid.New()
+	iOSQuestionID := uuid.New()
+
+	questionsMultiSelect := []db.ApplicationQuestionMultiSelect{
+		{
+			ID:        devicesQuestionID,
+			MinSelect: pgtype.Int4{Int32: 0, Valid: true},
+			MaxSelect: pgtype.Int4{Int32: 2, Valid: true},
+			Options:   []string{"iOS", "Android"},
+		},
+	}
+	questionsText := []db.ApplicationQuestionText{
+		{
+			ID:            iOSQuestionID,
+			IsRequired:    pgtype.Bool{Bool: true, Valid: true},
+			AllowedLength: pgtype.Int4{Int32: 100, Valid: true},
+			DisplayCondition: mustMarshalCondition(t, applicationDTO.QuestionCondition{
+				QuestionID: &devicesQuestionID,
+				Comparator: applicationDTO.ConditionComparatorContains,
+				Value:      "iOS",
+			}),
+		},
+	}
+	application := applicationDTO.PostApplication{
+		AnswersMultiSelect: []applicationDTO.CreateAnswerMultiSelect{
+			{ApplicationQuestionID: devicesQuestionID, Answer: []string{"Android"}},
+		},
+		AnswersText: []applicationDTO.CreateAnswerText{
+			{ApplicationQuestionID: iOSQuestionID, Answer: "stale answer"},
+		},
+	}
+
+	visibleText, visibleMultiSelect, _, filteredApplication := filterHiddenQuestions(questionsText, questionsMultiSelect, nil, application)
+	assert.Empty(t, visibleText)
+	assert.Len(t, visibleMultiSelect, 1)
+	assert.Empty(t, filteredApplication.AnswersText)
+	assert.NoError(t, validateTextAnswers(visibleText, filteredApplication.AnswersText))
+
+	// selecting iOS displays the question, so the required check applies again
+	application.AnswersMultiSelect[0].Answer = []string{"iOS"}
+	application.AnswersText = nil
+	visibleText, _, _, filteredApplication = filterHiddenQuestions(questionsText, questionsMultiSelect, nil, application)
+	assert.Len(t, visibleText, 1)
+	assert.Error(t, validateTextAnswers(visibleText, filteredApplication.AnswersText))
+}
+
+func TestFilterHiddenQuestions_GroupAndTransitiveConditions(t *testing.T) {
+	firstQuestionID := uuid.New()
+	secondQuestionID := uuid.New()
+	thirdQuestionID := uuid.New()
+
+	questionsText := []db.ApplicationQuestionText{
+		{ID: firstQuestionID},
+		{
+			ID: secondQuestionID,
+			DisplayCondition: mustMarshalCondition(t, applicationDTO.QuestionCondition{
+				Operator: applicationDTO.ConditionOperatorOr,
+				Conditions: []applicationDTO.QuestionCondition{
+					{QuestionID: &firstQuestionID, Comparator: applicationDTO.ConditionComparatorEquals, Value: "yes"},
+					{QuestionID: &firstQuestionID, Comparator: applicationDTO.ConditionComparatorEquals, Value: "maybe"},
+				},
+			}),
+		},
+		{
+			ID: thirdQuestionID,
+			DisplayCondition: mustMarshalCondition(t, applicationDTO.QuestionCondition{
+				QuestionID: &secondQuestionID,
+				Comparator: applicationDTO.ConditionComparatorIsNotAnswered,
+			}),
+		},
+	}
+	application := applicationDTO.PostApplication{
+		AnswersText: []applicationDTO.CreateAnswerText{
+			{ApplicationQuestionID: firstQuestionID, Answer: "No"},
+			{ApplicationQuestionID: secondQuestionID, Answer: "answer to a hidden question"},
+		},
+	}
+
+	// the second question is hidden, so its answer counts as missing for the third question
+	visibleText, _, _, filteredApplication := filterHiddenQuestions(questionsText, nil, nil, application)
+	assert.Equal(t, []uuid.UUID{firstQuestionID, thirdQuestionID}, []uuid.UUID{visibleText[0].ID, visibleText[1].ID})
+	assert.Len(t, filteredApplication.AnswersText, 1)
+}
+
+func TestValidateDisplayConditions(t *testing.T) {
+	textQuestionID := uuid.New()
+	fileUploadQuestionID := uuid.New()
+
+	questions := map[uuid.UUID]conditionalQuestion{
+		textQuestionID:       {kind: questionKindText},
+		fileUploadQuestionID: {kind: questionKindFileUpload},
+	}
+
+	valid := &applicationDTO.QuestionCondition{QuestionID: &fileUploadQuestionID, Comparator: applicationDTO.ConditionComparatorIsAnswered}
+	assert.NoError(t, validateDisplayConditions(questions, []*applicationDTO.QuestionCondition{valid, nil}))
+
+	unknownQuestionID := uuid.New()
+	unknownQuestion := &applicationDTO.QuestionCondition{QuestionID: &unknownQuestionID, Comparator: applicationDTO.ConditionComparatorIsAnswered}
+	assert.Error(t, validateDisplayConditions(questions, []*applicationDTO.QuestionCondition{unknownQuestion}))
+
+	fileUploadContains := &applicationDTO.QuestionCondition{QuestionID: &fileUploadQuestionID, Comparator: applicationDTO.ConditionComparatorContains, Value: "pdf"}
+	assert.Error(t, validateDisplayConditions(questions, []*applicationDTO.QuestionCondition{fileUploadContains}))
+
+	missingValue := &applicationDTO.QuestionCondition{QuestionID: &textQuestionID, Comparator: applicationDTO.ConditionComparatorEquals}
+	assert.Error(t, validateDisplayConditions(questions, []*applicationDTO.QuestionCondition{missingValue}))
+
+	emptyGroup := &applicationDTO.QuestionCondition{Operator: applicationDTO.ConditionOperatorAnd}
+	assert.Error(t, validateDisplayConditions(questions, []*applicationDTO.QuestionCondition{emptyGroup}))
+
+	missingQuestion := &applicationDTO.QuestionCondition{Comparator: applicationDTO.ConditionComparatorIsAnswered}
+	assert.Error(t, validateDisplayConditions(questions, []*applicationDTO.QuestionCondition{missingQuestion}))
+}
+
+func TestDisplayConditionGroupHasNoQuestionID(t *testing.T) {
+	questionID := uuid.New()
+	group := applicationDTO.QuestionCondition{
+		Operator: applicationDTO.ConditionOperatorOr,
+		Conditions: []applicationDTO.QuestionCondition{
+			{QuestionID: &questionID, Comparator: applicationDTO.ConditionComparatorIsAnswered},
+		},
+	}
+
+	var serialized map[string]interface{}
+	assert.NoError(t, json.Unmarshal(mustMarshalCondition(t, group), &serialized))
+	assert.NotContains(t, serialized, "questionID")
+
+	leaf := serialized["conditions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, questionID.String(), leaf["questionID"])
+}
+
+func TestValidateDisplayConditions_Cycle(t *testing.T) {
+	firstQuestionID := uuid.New()
+	secondQuestionID := uuid.New()
+
+	questions := map[uuid.UUID]conditionalQuestion{
+		firstQuestionID: {
+			kind:      questionKindText,
+			condition: &applicationDTO.QuestionCondition{QuestionID: &secondQuestionID, Comparator: applicationDTO.ConditionComparatorIsAnswered},
+		},
+		secondQuestionID: {
+			kind:      questionKindText,
+			condition: &applicationDTO.QuestionCondition{QuestionID: &firstQuestionID, Comparator: applicationDTO.ConditionComparatorIsAnswered},
+		},
+	}
+
+	err := validateDisplayConditions(questions, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cycle")
+}
@@ -0,0 +1,231 @@
+package applicationAdministration
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// archivedFile is an uploaded application file together with its path inside the archive.
+type archivedFile struct {
+	fileID uuid.UUID
+	path   string
+}
+
+type openFileFunc func(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, error)
+
+// downloadApplicationFiles godoc
+// @Summary Download uploaded application files
+// @Description Streams a zip archive with the uploaded application files of a course phase, grouped by student and named by question. Editors only get anonymized files during a blind review.
+// @Tags applications
+// @Produce application/zip
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param questionID query []string false "Only include the answers to these file upload questions"
+// @Param courseParticipationID query []string false "Only include the files of these applicants"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /applications/{coursePhaseID}/files/archive [get]
+func downloadApplicationFiles(c *gin.Context) {
+	coursePhaseID, ok := parseCoursePhaseID(c)
+	if !ok {
+		return
+	}
+
+	questionIDs, err := parseUUIDs(c.QueryArray("questionID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid question ID"))
+		return
+	}
+
+	courseParticipationIDs, err := parseUUIDs(c.QueryArray("courseParticipationID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course participation ID"))
+		return
+	}
+
+	blindReviewer, err := isBlindReviewerFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	files, err := GetApplicationFilesForArchive(c, coursePhaseID, questionIDs, courseParticipationIDs, blindReviewer)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(files) == 0 {
+		handleError(c, http.StatusNotFound, errors.New("no uploaded files found"))
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"application-files-%s.zip\"", coursePhaseID))
+	c.Status(http.StatusOK)
+
+	err = writeApplicationFilesArchive(c.Request.Context(), c.Writer, files, downloadStoredFile)
+	if err != nil {
+		// the status is already sent, so the client only notices the truncated archive
+		log.WithError(err).WithField("coursePhaseId", coursePhaseID).Error("Failed to stream application files archive")
+		c.Abort()
+	}
+}
+
+// GetApplicationFilesForArchive returns the uploaded files of a course phase and their paths in the archive.
+// Empty filters include all files. For blind reviewers the paths do not contain the names of anonymized applicants,
+// and the answers to identifying questions are left out.
+func GetApplicationFilesForArchive(ctx context.Context, coursePhaseID uuid.UUID, questionIDs, courseParticipationIDs []uuid.UUID, blindReviewer bool) ([]archivedFile, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	review, err := getBlindReview(ctxWithTimeout, coursePhaseID, blindReviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	uploads, err := ApplicationServiceSingleton.queries.GetApplicationFileUploadsForCoursePhase(ctxWithTimeout, db.GetApplicationFileUploadsForCoursePhaseParams{
+		CoursePhaseID:          coursePhaseID,
+		QuestionIds:            questionIDs,
+		CourseParticipationIds: courseParticipationIDs,
+	})
+	if err != nil {
+		log.Error(err)
+		return nil, errors.New("could not get the uploaded files")
+	}
+
+	return buildArchivedFiles(uploads, review), nil
+}
+
+func buildArchivedFiles(uploads []db.GetApplicationFileUploadsForCoursePhaseRow, review blindReview) []archivedFile {
+	folders := make(map[uuid.UUID]string)
+	usedFolders := make(map[string]bool)
+	files := make([]archivedFile, 0, len(uploads))
+
+	for _, upload := range uploads {
+		hidesIdentity := review.hidesIdentity(upload.Score)
+		if hidesIdentity && review.identifyingQuestionIDs[upload.ApplicationQuestionID] {
+			continue
+		}
+
+		folder, ok := folders[upload.CourseParticipationID]
+		if !ok {
+			folder = archiveName(strings.TrimSpace(upload.LastName.String + " " + upload.FirstName.String))
+			if hidesIdentity || folder == "" {
+				folder = "applicant " + upload.CourseParticipationID.String()[:8]
+			}
+			// students with the same name get separate folders
+			name := folder
+			for suffix := 2; usedFolders[folder]; suffix++ {
+				folder = fmt.Sprintf("%s (%d)", name, suffix)
+			}
+			folders[upload.CourseParticipationID] = folder
+			usedFolders[folder] = true
+		}
+
+		// the original filename of an anonymized applicant often contains the name
+		filename := archiveName(upload.QuestionTitle)
+		if filename == "" {
+			filename = "file"
+		}
+		if hidesIdentity {
+			if extension := archiveName(strings.TrimPrefix(filepath.Ext(upload.OriginalFilename), ".")); extension != "" {
+				filename += "." + extension
+			}
+		} else if originalFilename := archiveName(upload.OriginalFilename); originalFilename != "" {
+			filename += " - " + originalFilename
+		}
+
+		files = append(files, archivedFile{
+			fileID: upload.FileID,
+			path:   folder + "/" + filename,
+		})
+	}
+	return files
+}
+
+// writeApplicationFilesArchive streams the files one by one into a zip archive,
+// so that only a single file is read from the storage at a time.
+func writeApplicationFilesArchive(ctx context.Context, w io.Writer, files []archivedFile, openFile openFileFunc) error {
+	archive := zip.NewWriter(w)
+	flusher, canFlush := w.(http.Flusher)
+
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		reader, err := openFile(ctx, file.fileID)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", file.fileID, err)
+		}
+
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.path,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			reader.Close()
+			return err
+		}
+
+		_, err = io.Copy(entry, reader)
+		reader.Close()
+		if err != nil {
+			return fmt.Errorf("failed to archive file %s: %w", file.fileID, err)
+		}
+
+		if canFlush {
+			if err := archive.Flush(); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+	}
+
+	return archive.Close()
+}
+
+func downloadStoredFile(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, error) {
+	reader, _, err := storage.StorageServiceSingleton.DownloadFile(ctx, fileID)
+	return reader, err
+}
+
+// archiveName keeps the name readable while removing characters that are not portable in zip entries.
+func archiveName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" -_.()", r) {
+			return r
+		}
+		return '_'
+	}, name)
+	return strings.Trim(sanitized, " .")
+}
+
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
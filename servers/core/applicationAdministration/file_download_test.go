@@ -0,0 +1,100 @@
+package applicationAdministration
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFileUpload(courseParticipationID, questionID uuid.UUID, questionTitle, originalFilename, firstName, lastName string) db.GetApplicationFileUploadsForCoursePhaseRow {
+	return db.GetApplicationFileUploadsForCoursePhaseRow{
+		FileID:                uuid.New(),
+		CourseParticipationID: courseParticipationID,
+		ApplicationQuestionID: questionID,
+		QuestionTitle:         questionTitle,
+		OriginalFilename:      originalFilename,
+		FirstName:             pgtype.Text{String: firstName, Valid: true},
+		LastName:              pgtype.Text{String: lastName, Valid: true},
+	}
+}
+
+func TestBuildArchivedFiles(t *testing.T) {
+	cvQuestionID, portfolioQuestionID := uuid.New(), uuid.New()
+	janeID := uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000001")
+	otherJaneID := uuid.MustParse("b1b2c3d4-0000-4000-8000-000000000002")
+
+	uploads := []db.GetApplicationFileUploadsForCoursePhaseRow{
+		newFileUpload(janeID, cvQuestionID, "CV", "jane_doe_cv.pdf", "Jane", "Doe"),
+		newFileUpload(janeID, portfolioQuestionID, "Portfolio / Projects", "../portfolio.zip", "Jane", "Doe"),
+		newFileUpload(otherJaneID, cvQuestionID, "CV", "cv.pdf", "Jane", "Doe"),
+	}
+
+	files := buildArchivedFiles(uploads, blindReview{})
+	assert.Len(t, files, 3)
+	assert.Equal(t, "Doe Jane/CV - jane_doe_cv.pdf", files[0].path)
+	assert.Equal(t, "Doe Jane/Portfolio _ Projects - _portfolio.zip", files[1].path)
+	assert.Equal(t, "Doe Jane (2)/CV - cv.pdf", files[2].path, "students with the same name get separate folders")
+	assert.Equal(t, uploads[0].FileID, files[0].fileID)
+}
+
+func TestBuildArchivedFiles_BlindReview(t *testing.T) {
+	cvQuestionID, githubQuestionID := uuid.New(), uuid.New()
+	courseParticipationID := uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000001")
+
+	uploads := []db.GetApplicationFileUploadsForCoursePhaseRow{
+		newFileUpload(courseParticipationID, cvQuestionID, "CV", "jane_doe_cv.pdf", "Jane", "Doe"),
+		newFileUpload(courseParticipationID, githubQuestionID, "GitHub Export", "janedoe.zip", "Jane", "Doe"),
+	}
+
+	review := blindReview{enabled: true, identifyingQuestionIDs: map[uuid.UUID]bool{githubQuestionID: true}}
+	files := buildArchivedFiles(uploads, review)
+	assert.Len(t, files, 1, "answers to identifying questions are left out")
+	assert.Equal(t, "applicant a1b2c3d4/CV.pdf", files[0].path)
+}
+
+func TestWriteApplicationFilesArchive(t *testing.T) {
+	contents := map[uuid.UUID]string{uuid.New(): "first file", uuid.New(): "second file"}
+	var files []archivedFile
+	for fileID := range contents {
+		files = append(files, archivedFile{fileID: fileID, path: "Doe Jane/" + fileID.String()})
+	}
+
+	openFile := func(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(contents[fileID])), nil
+	}
+
+	var buffer bytes.Buffer
+	err := writeApplicationFilesArchive(context.Background(), &buffer, files, openFile)
+	assert.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, archive.File, 2)
+	for _, entry := range archive.File {
+		fileID := uuid.MustParse(strings.TrimPrefix(entry.Name, "Doe Jane/"))
+		reader, err := entry.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		reader.Close()
+		assert.Equal(t, contents[fileID], string(content))
+	}
+}
+
+func TestWriteApplicationFilesArchive_OpenError(t *testing.T) {
+	openFile := func(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, error) {
+		return nil, errors.New("storage unavailable")
+	}
+
+	err := writeApplicationFilesArchive(context.Background(), io.Discard, []archivedFile{{fileID: uuid.New(), path: "a/b.pdf"}}, openFile)
+	assert.Error(t, err)
+}
@@ -2,7 +2,6 @@ package applicationAdministration
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,6 +15,7 @@ const externalUploaderID = "external"
 type applicationPresignUploadRequest struct {
 	Filename    string `json:"filename"`
 	ContentType string `json:"contentType"`
+	SizeBytes   int64  `json:"sizeBytes"`
 	Description string `json:"description"`
 	Tags        string `json:"tags"`
 }
@@ -38,6 +38,7 @@ type applicationCompleteUploadRequest struct {
 // @Param body body applicationPresignUploadRequest true "Presign request"
 // @Success 200 {object} storage.PresignUploadResponse
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /apply/{coursePhaseID}/files/presign [post]
 func presignApplicationUploadExternal(c *gin.Context) {
@@ -58,12 +59,17 @@ func presignApplicationUploadExternal(c *gin.Context) {
 	response, err := storage.StorageServiceSingleton.PresignUpload(c.Request.Context(), storage.PresignUploadRequest{
 		Filename:      body.Filename,
 		ContentType:   body.ContentType,
+		SizeBytes:     body.SizeBytes,
 		CoursePhaseID: &coursePhaseID,
 		Description:   body.Description,
-		Tags:          parseTags(body.Tags),
+		Tags:          storage.ParseTags(body.Tags),
 	})
 	if err != nil {
 		log.WithError(err).Error("Failed to presign external upload")
+		if status := storage.RejectedUploadStatus(err); status != 0 {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate upload URL"})
 		return
 	}
@@ -81,6 +87,7 @@ func presignApplicationUploadExternal(c *gin.Context) {
 // @Param body body applicationCompleteUploadRequest true "Complete request"
 // @Success 201 {object} storage.FileResponse
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /apply/{coursePhaseID}/files/complete [post]
 func completeApplicationUploadExternal(c *gin.Context) {
@@ -104,10 +111,14 @@ func completeApplicationUploadExternal(c *gin.Context) {
 		ContentType:      body.ContentType,
 		CoursePhaseID:    &coursePhaseID,
 		Description:      body.Description,
-		Tags:             parseTags(body.Tags),
+		Tags:             storage.ParseTags(body.Tags),
 	}, externalUploaderID, "")
 	if err != nil {
 		log.WithError(err).Error("Failed to complete external upload")
+		if status := storage.RejectedUploadStatus(err); status != 0 {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete upload"})
 		return
 	}
@@ -127,6 +138,7 @@ func completeApplicationUploadExternal(c *gin.Context) {
 // @Success 200 {object} storage.PresignUploadResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /apply/authenticated/{coursePhaseID}/files/presign [post]
 func presignApplicationUploadAuthenticated(c *gin.Context) {
@@ -153,12 +165,17 @@ func presignApplicationUploadAuthenticated(c *gin.Context) {
 	response, err := storage.StorageServiceSingleton.PresignUpload(c.Request.Context(), storage.PresignUploadRequest{
 		Filename:      body.Filename,
 		ContentType:   body.ContentType,
+		SizeBytes:     body.SizeBytes,
 		CoursePhaseID: &coursePhaseID,
 		Description:   body.Description,
-		Tags:          parseTags(body.Tags),
+		Tags:          storage.ParseTags(body.Tags),
 	})
 	if err != nil {
 		log.WithError(err).Error("Failed to presign authenticated upload")
+		if status := storage.RejectedUploadStatus(err); status != 0 {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -178,6 +195,7 @@ func presignApplicationUploadAuthenticated(c *gin.Context) {
 // @Success 201 {object} storage.FileResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /apply/authenticated/{coursePhaseID}/files/complete [post]
 func completeApplicationUploadAuthenticated(c *gin.Context) {
@@ -215,10 +233,14 @@ func completeApplicationUploadAuthenticated(c *gin.Context) {
 		ContentType:      body.ContentType,
 		CoursePhaseID:    &coursePhaseID,
 		Description:      body.Description,
-		Tags:             parseTags(body.Tags),
+		Tags:             storage.ParseTags(body.Tags),
 	}, userID, email)
 	if err != nil {
 		log.WithError(err).Error("Failed to complete authenticated upload")
+		if status := storage.RejectedUploadStatus(err); status != 0 {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -295,6 +317,7 @@ func deleteApplicationFileAuthenticated(c *gin.Context) {
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /applications/{coursePhaseID}/files/{fileId}/download-url [get]
 func getApplicationFileDownloadURL(c *gin.Context) {
@@ -320,6 +343,11 @@ func getApplicationFileDownloadURL(c *gin.Context) {
 		return
 	}
 
+	if fileResponse.ScanStatus != db.FileScanStatusClean {
+		c.JSON(http.StatusConflict, gin.H{"error": storage.ErrFileQuarantined.Error(), "scanStatus": fileResponse.ScanStatus})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"downloadUrl": fileResponse.DownloadURL})
 }
 
@@ -341,21 +369,6 @@ func getUserID(c *gin.Context) (string, bool) {
 	return userIDStr, ok
 }
 
-func parseTags(tags string) []string {
-	if tags == "" {
-		return nil
-	}
-	parts := strings.Split(tags, ",")
-	result := make([]string, 0, len(parts))
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part != "" {
-			result = append(result, part)
-		}
-	}
-	return result
-}
-
 func ensureOpenApplicationPhase(c *gin.Context, coursePhaseID uuid.UUID) bool {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(c.Request.Context())
 	defer cancel()
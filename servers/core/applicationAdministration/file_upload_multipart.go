@@ -0,0 +1,322 @@
+package applicationAdministration
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+type applicationMultipartPartsRequest struct {
+	StorageKey  string  `json:"storageKey"`
+	UploadID    string  `json:"uploadId"`
+	PartNumbers []int32 `json:"partNumbers"`
+}
+
+type applicationCompleteMultipartUploadRequest struct {
+	applicationCompleteUploadRequest
+	UploadID string                 `json:"uploadId"`
+	Parts    []storage.UploadedPart `json:"parts"`
+}
+
+type applicationAbortMultipartUploadRequest struct {
+	StorageKey string `json:"storageKey"`
+	UploadID   string `json:"uploadId"`
+}
+
+type applicationMultipartPartsResponse struct {
+	Parts []storage.UploadPartURL `json:"parts"`
+}
+
+// applicationUploader identifies who uploads a file to an application phase
+type applicationUploader struct {
+	coursePhaseID uuid.UUID
+	userID        string
+	email         string
+}
+
+// initiateApplicationMultipartUploadExternal godoc
+// @Summary Start a multipart upload (external)
+// @Description Starts an upload in parts for large files, the parts are uploaded directly to storage (external applicants)
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationPresignUploadRequest true "Upload request, sizeBytes is required"
+// @Success 200 {object} storage.InitiateMultipartUploadResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/{coursePhaseID}/files/multipart [post]
+func initiateApplicationMultipartUploadExternal(c *gin.Context) {
+	if uploader, ok := getExternalApplicationUploader(c); ok {
+		initiateApplicationMultipartUpload(c, uploader)
+	}
+}
+
+// presignApplicationUploadPartsExternal godoc
+// @Summary Create upload URLs for parts of a multipart upload (external)
+// @Description Returns presigned URLs for the requested parts, parts can be requested again to retry them (external applicants)
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationMultipartPartsRequest true "Parts request"
+// @Success 200 {object} applicationMultipartPartsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/{coursePhaseID}/files/multipart/parts [post]
+func presignApplicationUploadPartsExternal(c *gin.Context) {
+	if uploader, ok := getExternalApplicationUploader(c); ok {
+		presignApplicationUploadParts(c, uploader)
+	}
+}
+
+// completeApplicationMultipartUploadExternal godoc
+// @Summary Complete a multipart upload (external)
+// @Description Assembles the uploaded parts and registers the file (external applicants)
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationCompleteMultipartUploadRequest true "Complete request"
+// @Success 201 {object} storage.FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/{coursePhaseID}/files/multipart/complete [post]
+func completeApplicationMultipartUploadExternal(c *gin.Context) {
+	if uploader, ok := getExternalApplicationUploader(c); ok {
+		completeApplicationMultipartUpload(c, uploader)
+	}
+}
+
+// abortApplicationMultipartUploadExternal godoc
+// @Summary Abort a multipart upload (external)
+// @Description Discards an unfinished multipart upload and its uploaded parts (external applicants)
+// @Tags applications
+// @Accept json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationAbortMultipartUploadRequest true "Abort request"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/{coursePhaseID}/files/multipart/abort [post]
+func abortApplicationMultipartUploadExternal(c *gin.Context) {
+	if uploader, ok := getExternalApplicationUploader(c); ok {
+		abortApplicationMultipartUpload(c, uploader)
+	}
+}
+
+// initiateApplicationMultipartUploadAuthenticated godoc
+// @Summary Start a multipart upload (authenticated)
+// @Description Starts an upload in parts for large files, the parts are uploaded directly to storage (authenticated applicants)
+// @Tags applications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationPresignUploadRequest true "Upload request, sizeBytes is required"
+// @Success 200 {object} storage.InitiateMultipartUploadResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/files/multipart [post]
+func initiateApplicationMultipartUploadAuthenticated(c *gin.Context) {
+	if uploader, ok := getAuthenticatedApplicationUploader(c); ok {
+		initiateApplicationMultipartUpload(c, uploader)
+	}
+}
+
+// presignApplicationUploadPartsAuthenticated godoc
+// @Summary Create upload URLs for parts of a multipart upload (authenticated)
+// @Description Returns presigned URLs for the requested parts, parts can be requested again to retry them (authenticated applicants)
+// @Tags applications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationMultipartPartsRequest true "Parts request"
+// @Success 200 {object} applicationMultipartPartsResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/files/multipart/parts [post]
+func presignApplicationUploadPartsAuthenticated(c *gin.Context) {
+	if uploader, ok := getAuthenticatedApplicationUploader(c); ok {
+		presignApplicationUploadParts(c, uploader)
+	}
+}
+
+// completeApplicationMultipartUploadAuthenticated godoc
+// @Summary Complete a multipart upload (authenticated)
+// @Description Assembles the uploaded parts and registers the file (authenticated applicants)
+// @Tags applications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationCompleteMultipartUploadRequest true "Complete request"
+// @Success 201 {object} storage.FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/files/multipart/complete [post]
+func completeApplicationMultipartUploadAuthenticated(c *gin.Context) {
+	if uploader, ok := getAuthenticatedApplicationUploader(c); ok {
+		completeApplicationMultipartUpload(c, uploader)
+	}
+}
+
+// abortApplicationMultipartUploadAuthenticated godoc
+// @Summary Abort a multipart upload (authenticated)
+// @Description Discards an unfinished multipart upload and its uploaded parts (authenticated applicants)
+// @Tags applications
+// @Security BearerAuth
+// @Accept json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param body body applicationAbortMultipartUploadRequest true "Abort request"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 501 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/files/multipart/abort [post]
+func abortApplicationMultipartUploadAuthenticated(c *gin.Context) {
+	if uploader, ok := getAuthenticatedApplicationUploader(c); ok {
+		abortApplicationMultipartUpload(c, uploader)
+	}
+}
+
+func initiateApplicationMultipartUpload(c *gin.Context, uploader applicationUploader) {
+	var body applicationPresignUploadRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	response, err := storage.StorageServiceSingleton.InitiateMultipartUpload(c.Request.Context(), storage.PresignUploadRequest{
+		Filename:      body.Filename,
+		ContentType:   body.ContentType,
+		SizeBytes:     body.SizeBytes,
+		CoursePhaseID: &uploader.coursePhaseID,
+		Description:   body.Description,
+		Tags:          storage.ParseTags(body.Tags),
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to initiate multipart upload")
+		respondMultipartUploadError(c, err, "failed to initiate upload")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func presignApplicationUploadParts(c *gin.Context, uploader applicationUploader) {
+	var body applicationMultipartPartsRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	parts, err := storage.StorageServiceSingleton.PresignUploadParts(c.Request.Context(), &uploader.coursePhaseID, body.StorageKey, body.UploadID, body.PartNumbers)
+	if err != nil {
+		log.WithError(err).Error("Failed to presign upload parts")
+		respondMultipartUploadError(c, err, "failed to generate upload URLs")
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationMultipartPartsResponse{Parts: parts})
+}
+
+func completeApplicationMultipartUpload(c *gin.Context, uploader applicationUploader) {
+	var body applicationCompleteMultipartUploadRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	fileResponse, err := storage.StorageServiceSingleton.CompleteMultipartUpload(c.Request.Context(), storage.CompleteMultipartUploadRequest{
+		CreateFileFromStorageKeyRequest: storage.CreateFileFromStorageKeyRequest{
+			StorageKey:       body.StorageKey,
+			OriginalFilename: body.OriginalFilename,
+			ContentType:      body.ContentType,
+			CoursePhaseID:    &uploader.coursePhaseID,
+			Description:      body.Description,
+			Tags:             storage.ParseTags(body.Tags),
+		},
+		UploadID: body.UploadID,
+		Parts:    body.Parts,
+	}, uploader.userID, uploader.email)
+	if err != nil {
+		log.WithError(err).Error("Failed to complete multipart upload")
+		respondMultipartUploadError(c, err, "failed to complete upload")
+		return
+	}
+
+	c.JSON(http.StatusCreated, fileResponse)
+}
+
+func abortApplicationMultipartUpload(c *gin.Context, uploader applicationUploader) {
+	var body applicationAbortMultipartUploadRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+		return
+	}
+
+	if err := storage.StorageServiceSingleton.AbortMultipartUpload(c.Request.Context(), &uploader.coursePhaseID, body.StorageKey, body.UploadID); err != nil {
+		log.WithError(err).Error("Failed to abort multipart upload")
+		respondMultipartUploadError(c, err, "failed to abort upload")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func getExternalApplicationUploader(c *gin.Context) (applicationUploader, bool) {
+	coursePhaseID, ok := parseCoursePhaseID(c)
+	if !ok || !ensureOpenApplicationPhase(c, coursePhaseID) {
+		return applicationUploader{}, false
+	}
+	return applicationUploader{coursePhaseID: coursePhaseID, userID: externalUploaderID}, true
+}
+
+func getAuthenticatedApplicationUploader(c *gin.Context) (applicationUploader, bool) {
+	userID, ok := getUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return applicationUploader{}, false
+	}
+
+	coursePhaseID, ok := parseCoursePhaseID(c)
+	if !ok || !ensureOpenApplicationPhase(c, coursePhaseID) {
+		return applicationUploader{}, false
+	}
+
+	email := ""
+	if emailVal, exists := c.Get("userEmail"); exists {
+		if emailStr, ok := emailVal.(string); ok {
+			email = emailStr
+		}
+	}
+	return applicationUploader{coursePhaseID: coursePhaseID, userID: userID, email: email}, true
+}
+
+func respondMultipartUploadError(c *gin.Context, err error, message string) {
+	if status := storage.RejectedUploadStatus(err); status != 0 {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -3,14 +3,18 @@ package applicationAdministration
 import (
 	"errors"
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/prompt-edu/prompt/servers/core/applicationAdministration/applicationDTO"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation/coursePhaseParticipationDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/mailing"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/student"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -24,20 +28,22 @@ func setupApplicationRouter(router *gin.RouterGroup, authMiddleware func() gin.H
 	application := router.Group("/applications", authMiddleware())
 
 	// Application Form Endpoints
-	application.GET("/:coursePhaseID/form", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getApplicationForm)
+	application.GET("/:coursePhaseID/form", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewApplications), getApplicationForm)
 	application.PUT("/:coursePhaseID/form", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateApplicationForm)
 	application.GET("/:coursePhaseID/score", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getAdditionalScores)
 	application.POST("/:coursePhaseID/score", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), uploadAdditionalScore)
-	application.PUT("/:coursePhaseID/assessment", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateApplicationsStatus)
+	application.PUT("/:coursePhaseID/assessment", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CanAssess), updateApplicationsStatus)
 
 	application.POST("/:coursePhaseID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), postApplicationManual)
 	application.DELETE("/:coursePhaseID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), deleteApplications)
-	application.GET("/:coursePhaseID/files/:fileId/download-url", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getApplicationFileDownloadURL)
+	application.GET("/:coursePhaseID/files/archive", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewApplications), downloadApplicationFiles)
+	application.GET("/:coursePhaseID/files/:fileId/download-url", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewApplications), getApplicationFileDownloadURL)
 
-	application.GET("/:coursePhaseID/:courseParticipationID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getApplicationByCPID)
-	application.PUT("/:coursePhaseID/:courseParticipationID/assessment", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateApplicationAssessment)
+	application.GET("/:coursePhaseID/:courseParticipationID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewApplications), getApplicationByCPID)
+	application.PUT("/:coursePhaseID/:courseParticipationID/assessment", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CanAssess), updateApplicationAssessment)
 
-	application.GET("/:coursePhaseID/participations", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getAllApplicationParticipations)
+	application.GET("/:coursePhaseID/participations", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewApplications), getAllApplicationParticipations)
+	application.PUT("/:coursePhaseID/blind-review/reveal", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), revealBlindReview)
 
 	// Apply Endpoints - No Authentication needed
 	apply := router.Group("/apply")
@@ -46,12 +52,23 @@ func setupApplicationRouter(router *gin.RouterGroup, authMiddleware func() gin.H
 	apply.POST("/:coursePhaseID", postApplicationExtern)
 	apply.POST("/:coursePhaseID/files/presign", presignApplicationUploadExternal)
 	apply.POST("/:coursePhaseID/files/complete", completeApplicationUploadExternal)
+	apply.POST("/:coursePhaseID/files/multipart", initiateApplicationMultipartUploadExternal)
+	apply.POST("/:coursePhaseID/files/multipart/parts", presignApplicationUploadPartsExternal)
+	apply.POST("/:coursePhaseID/files/multipart/complete", completeApplicationMultipartUploadExternal)
+	apply.POST("/:coursePhaseID/files/multipart/abort", abortApplicationMultipartUploadExternal)
 
 	applyAuthenticated := router.Group("/apply/authenticated", applicationMiddleware())
 	applyAuthenticated.GET("/:coursePhaseID", getApplicationAuthenticated)
 	applyAuthenticated.POST("/:coursePhaseID", postApplicationAuthenticated)
+	applyAuthenticated.GET("/:coursePhaseID/draft", getApplicationDraft)
+	applyAuthenticated.PUT("/:coursePhaseID/draft", saveApplicationDraft)
+	applyAuthenticated.DELETE("/:coursePhaseID/draft", deleteApplicationDraft)
 	applyAuthenticated.POST("/:coursePhaseID/files/presign", presignApplicationUploadAuthenticated)
 	applyAuthenticated.POST("/:coursePhaseID/files/complete", completeApplicationUploadAuthenticated)
+	applyAuthenticated.POST("/:coursePhaseID/files/multipart", initiateApplicationMultipartUploadAuthenticated)
+	applyAuthenticated.POST("/:coursePhaseID/files/multipart/parts", presignApplicationUploadPartsAuthenticated)
+	applyAuthenticated.POST("/:coursePhaseID/files/multipart/complete", completeApplicationMultipartUploadAuthenticated)
+	applyAuthenticated.POST("/:coursePhaseID/files/multipart/abort", abortApplicationMultipartUploadAuthenticated)
 	applyAuthenticated.DELETE("/:coursePhaseID/files/:fileId", deleteApplicationFileAuthenticated)
 
 }
@@ -246,14 +263,14 @@ func postApplicationManual(c *gin.Context) {
 		return
 	}
 
-	err = validateApplicationManualAdd(c, coursePhaseId, application)
+	application, err = validateApplicationManualAdd(c, coursePhaseId, application)
 	if err != nil {
 		log.Error(err)
 		handleError(c, http.StatusBadRequest, err)
 		return
 	}
 
-	courseParticipationID, err := PostApplicationAuthenticatedStudent(c, coursePhaseId, application)
+	courseParticipationID, err := PostApplicationAuthenticatedStudent(c, coursePhaseId, application, student.GetProfileChangeAuthor(c, db.StudentProfileChangeSourceApplication))
 	if err != nil {
 		log.Error(err)
 		if errors.Is(err, ErrAlreadyApplied) {
@@ -302,7 +319,7 @@ func postApplicationExtern(c *gin.Context) {
 		return
 	}
 
-	err = validateApplication(c, coursePhaseId, application, false)
+	application, err = validateApplication(c, coursePhaseId, application, false)
 	if err != nil {
 		log.Error(err)
 		handleError(c, http.StatusBadRequest, err)
@@ -337,7 +354,7 @@ func postApplicationExtern(c *gin.Context) {
 
 // postApplicationAuthenticated godoc
 // @Summary Post an application (authenticated)
-// @Description Post an application for a student (authenticated)
+// @Description Post an application for a student (authenticated). The posted application is authoritative: a saved draft is not merged into it but discarded, so clients send the complete application including the answers loaded from the draft.
 // @Tags applications
 // @Accept json
 // @Produce json
@@ -371,7 +388,7 @@ func postApplicationAuthenticated(c *gin.Context) {
 		return
 	}
 
-	err = validateApplication(c, coursePhaseId, application, true)
+	application, err = validateApplication(c, coursePhaseId, application, true)
 	if err != nil {
 		log.Error(err)
 		handleError(c, http.StatusBadRequest, err)
@@ -390,7 +407,7 @@ func postApplicationAuthenticated(c *gin.Context) {
 		application.Student.LastName = lastName
 	}
 
-	courseParticipationID, err := PostApplicationAuthenticatedStudent(c, coursePhaseId, application)
+	courseParticipationID, err := PostApplicationAuthenticatedStudent(c, coursePhaseId, application, student.GetProfileChangeAuthor(c, db.StudentProfileChangeSourceApplication))
 	if err != nil {
 		log.Error(err)
 		handleError(c, http.StatusInternalServerError, errors.New("could not post application"))
@@ -408,9 +425,130 @@ func postApplicationAuthenticated(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"message": "application posted", "confirmationMailSent": confirmationMailSent})
 }
 
+// getApplicationDraft godoc
+// @Summary Get application draft (authenticated)
+// @Description Get the partially filled application of the authenticated user for a course phase
+// @Tags applications
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Success 200 {object} applicationDTO.ApplicationDraft
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/draft [get]
+func getApplicationDraft(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	universityLogin := c.GetString("universityLogin")
+	if universityLogin == "" {
+		handleError(c, http.StatusUnauthorized, errors.New("no university login found"))
+		return
+	}
+
+	draft, err := GetApplicationDraft(c, coursePhaseID, universityLogin)
+	if err != nil {
+		if errors.Is(err, ErrNoApplicationDraft) {
+			handleError(c, http.StatusNotFound, err)
+			return
+		}
+		log.Error(err)
+		handleError(c, http.StatusInternalServerError, errors.New("could not get application draft"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, draft)
+}
+
+// saveApplicationDraft godoc
+// @Summary Save application draft (authenticated)
+// @Description Store the partially filled application of the authenticated user. Required questions are not enforced until the final submission. The draft replaces the previous draft and is discarded when the application is submitted, it is not merged into the submission.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param application body applicationDTO.PostApplication true "Partially filled application"
+// @Success 200 {object} applicationDTO.ApplicationDraft
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/draft [put]
+func saveApplicationDraft(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	universityLogin := c.GetString("universityLogin")
+	if universityLogin == "" {
+		handleError(c, http.StatusUnauthorized, errors.New("no university login found"))
+		return
+	}
+
+	var application applicationDTO.PostApplication
+	if err := c.BindJSON(&application); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = validateApplicationDraft(c, coursePhaseID, application)
+	if err != nil {
+		log.Error(err)
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	draft, err := SaveApplicationDraft(c, coursePhaseID, universityLogin, application)
+	if err != nil {
+		log.Error(err)
+		handleError(c, http.StatusInternalServerError, errors.New("could not save application draft"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, draft)
+}
+
+// deleteApplicationDraft godoc
+// @Summary Discard application draft (authenticated)
+// @Description Delete the partially filled application of the authenticated user for a course phase
+// @Tags applications
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /apply/authenticated/{coursePhaseID}/draft [delete]
+func deleteApplicationDraft(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	universityLogin := c.GetString("universityLogin")
+	if universityLogin == "" {
+		handleError(c, http.StatusUnauthorized, errors.New("no university login found"))
+		return
+	}
+
+	err = DeleteApplicationDraft(c, coursePhaseID, universityLogin)
+	if err != nil {
+		log.Error(err)
+		handleError(c, http.StatusInternalServerError, errors.New("could not delete application draft"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "application draft deleted"})
+}
+
 // getApplicationByCPID godoc
 // @Summary Get application by course phase and participation ID
-// @Description Get an application by course phase ID and course participation ID
+// @Description Get an application by course phase ID and course participation ID. Editors only see an anonymized application during a blind review.
 // @Tags applications
 // @Produce json
 // @Param coursePhaseID path string true "Course Phase UUID"
@@ -433,7 +571,13 @@ func getApplicationByCPID(c *gin.Context) {
 		return
 	}
 
-	application, err := GetApplicationByCPID(c, coursePhaseId, courseParticipationID)
+	blindReviewer, err := isBlindReviewerFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	application, err := GetApplicationByCPID(c, coursePhaseId, courseParticipationID, blindReviewer)
 	if err != nil {
 		log.Error(err)
 		if errors.Is(err, ErrNotFound) {
@@ -449,10 +593,16 @@ func getApplicationByCPID(c *gin.Context) {
 
 // getAllApplicationParticipations godoc
 // @Summary Get all application participations
-// @Description Get all participations for a course phase
+// @Description Get the participations for a course phase, all of them unless a limit is given. Editors only see anonymized applicants during a blind review and cannot sort them by identity. The total count is sent in the X-Total-Count header, the next page in the Link header. Query parameters metadata.<key>=<value> filter on the restricted data.
 // @Tags applications
 // @Produce json
 // @Param coursePhaseID path string true "Course Phase UUID"
+// @Param limit query int false "Maximum number of applications, at most 500"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Param sortBy query string false "Sort field, by default lastName or score during a blind review" Enums(lastName, firstName, email, score)
+// @Param sortOrder query string false "Sort order" Enums(asc, desc)
+// @Param passStatus query string false "Only applications with this pass status" Enums(passed, failed, not_assessed)
+// @Param fields query string false "Comma-separated application fields to return, e.g. courseParticipationID,student.lastName,score"
 // @Success 200 {array} applicationDTO.ApplicationParticipation
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
@@ -464,14 +614,67 @@ func getAllApplicationParticipations(c *gin.Context) {
 		return
 	}
 
-	applications, err := GetAllApplicationParticipations(c, coursePhaseId)
+	blindReviewer, err := isBlindReviewerFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	params, err := listQuery.Parse(c, listQuery.Options{
+		SortFields:       append(slices.Clone(identitySortFields), "score"),
+		PassStatusFilter: true,
+		MetadataFilter:   true,
+	})
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	applications, total, nextCursor, err := GetAllApplicationParticipations(c, coursePhaseId, blindReviewer, params)
+	if errors.Is(err, ErrIdentitySortDuringBlindReview) {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
 	if err != nil {
 		log.Error(err)
 		handleError(c, http.StatusInternalServerError, errors.New("could not get applications"))
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, applications)
+	projectedApplications, err := listQuery.Project(applications, params.Fields)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	listQuery.SetHeaders(c, total, nextCursor)
+	c.IndentedJSON(http.StatusOK, projectedApplications)
+}
+
+// revealBlindReview godoc
+// @Summary Reveal a blind review
+// @Description Show the identity of all scored applicants to the editors of a phase under blind review
+// @Tags applications
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /applications/{coursePhaseID}/blind-review/reveal [put]
+func revealBlindReview(c *gin.Context) {
+	coursePhaseId, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = RevealBlindReview(c, coursePhaseId)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "blind review revealed"})
 }
 
 // updateApplicationAssessment godoc
@@ -663,6 +866,32 @@ func deleteApplications(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "applications deleted"})
 }
 
+func isBlindReviewerFromContext(c *gin.Context) (bool, error) {
+	userRoles, exists := c.Get("userRoles")
+	if !exists {
+		log.Error("userRoles not found in context")
+		return false, errors.New("could not get the user roles")
+	}
+
+	userRolesMap, ok := userRoles.(map[string]bool)
+	if !ok {
+		log.Error("invalid roles format in context")
+		return false, errors.New("could not get the user roles")
+	}
+
+	coursePhaseID, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		return false, errors.New("invalid course phase id")
+	}
+
+	blindReviewer, err := isBlindReviewer(c, userRolesMap, c.GetString("courseTokenIdentifier"), coursePhaseID)
+	if err != nil {
+		log.Error(err)
+		return false, errors.New("could not check the role of the user")
+	}
+	return blindReviewer, nil
+}
+
 func handleError(c *gin.Context, statusCode int, err error) {
 	c.JSON(statusCode, utils.ErrorResponse{
 		Error: err.Error(),
@@ -3,8 +3,10 @@ package applicationAdministration
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -16,8 +18,11 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation/coursePhaseParticipationDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/storage"
 	"github.com/prompt-edu/prompt/servers/core/student"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -32,8 +37,15 @@ var ApplicationServiceSingleton *ApplicationService
 var ErrNotFound = errors.New("application was not found")
 var ErrAlreadyApplied = errors.New("application already exists")
 var ErrStudentDetailsDoNotMatch = errors.New("student details do not match")
+var ErrNoApplicationDraft = errors.New("no application draft exists")
+var ErrIdentitySortDuringBlindReview = errors.New("applications cannot be sorted by the identity of the applicants during a blind review")
 
-func buildFileUploadAnswerDTOs(ctx context.Context, answers []db.ApplicationAnswerFileUpload, includeDownloadURL bool) []applicationDTO.AnswerFileUpload {
+// sorting by these fields would reveal the order of the names to blind reviewers
+var identitySortFields = []string{"lastName", "firstName", "email"}
+
+// buildFileUploadAnswerDTOs adds the file metadata to the answers.
+// Prior versions of the files are only listed for lecturers and admins.
+func buildFileUploadAnswerDTOs(ctx context.Context, answers []db.ApplicationAnswerFileUpload, includeDownloadURL bool, includeVersions bool) []applicationDTO.AnswerFileUpload {
 	answerDTOs := make([]applicationDTO.AnswerFileUpload, 0, len(answers))
 	for _, answer := range answers {
 		dto := applicationDTO.AnswerFileUpload{
@@ -52,6 +64,9 @@ func buildFileUploadAnswerDTOs(ctx context.Context, answers []db.ApplicationAnsw
 				dto.FileSize = file.SizeBytes
 				dto.UploadedAt = file.CreatedAt
 				dto.DownloadURL = file.DownloadURL
+				dto.PreviewURL = file.PreviewURL
+				dto.Version = file.Version
+				dto.ScanStatus = file.ScanStatus
 			}
 		} else {
 			file, err := ApplicationServiceSingleton.queries.GetFileByID(ctx, answer.FileID)
@@ -61,28 +76,51 @@ func buildFileUploadAnswerDTOs(ctx context.Context, answers []db.ApplicationAnsw
 				dto.FileName = file.OriginalFilename
 				dto.FileSize = file.SizeBytes
 				dto.UploadedAt = file.CreatedAt.Time
+				dto.Version = file.Version
+				dto.ScanStatus = file.ScanStatus
 			}
 		}
 
+		if includeVersions {
+			dto.Versions = buildFileVersionDTOs(ctx, answer.FileID)
+		}
+
 		answerDTOs = append(answerDTOs, dto)
 	}
 
 	return answerDTOs
 }
 
-// createOrReplaceFileUploadAnswer creates or updates a file upload answer and returns a stale file id for cleanup after commit.
-func createOrReplaceFileUploadAnswer(ctx context.Context, qtx *db.Queries, answer applicationDTO.CreateAnswerFileUpload, courseParticipationID uuid.UUID) (*uuid.UUID, error) {
-	return upsertFileUploadAnswer(ctx, qtx, answer, courseParticipationID)
+// buildFileVersionDTOs lists the files an answer had before, a failure only hides the prior versions
+func buildFileVersionDTOs(ctx context.Context, fileID uuid.UUID) []applicationDTO.AnswerFileVersion {
+	versions, err := storage.StorageServiceSingleton.GetFileVersions(ctx, fileID)
+	if err != nil {
+		log.WithError(err).WithField("fileId", fileID).Warn("Failed to load prior file versions for answer")
+		return nil
+	}
+
+	versionDTOs := make([]applicationDTO.AnswerFileVersion, 0, len(versions))
+	for _, version := range versions {
+		versionDTOs = append(versionDTOs, applicationDTO.AnswerFileVersion{
+			FileID:      version.ID,
+			Version:     version.Version,
+			FileName:    version.OriginalFilename,
+			FileSize:    version.SizeBytes,
+			UploadedAt:  version.CreatedAt,
+			DownloadURL: version.DownloadURL,
+		})
+	}
+	return versionDTOs
 }
 
-// createOrOverwriteFileUploadAnswer creates or updates a file upload answer and returns a stale file id for cleanup after commit.
-func createOrOverwriteFileUploadAnswer(ctx context.Context, qtx *db.Queries, answer applicationDTO.CreateAnswerFileUpload, courseParticipationID uuid.UUID) (*uuid.UUID, error) {
+// createOrOverwriteFileUploadAnswer creates or updates a file upload answer, a replaced file is kept as prior version of the new one.
+func createOrOverwriteFileUploadAnswer(ctx context.Context, qtx *db.Queries, answer applicationDTO.CreateAnswerFileUpload, courseParticipationID uuid.UUID) error {
 	return upsertFileUploadAnswer(ctx, qtx, answer, courseParticipationID)
 }
 
-func upsertFileUploadAnswer(ctx context.Context, qtx *db.Queries, answer applicationDTO.CreateAnswerFileUpload, courseParticipationID uuid.UUID) (*uuid.UUID, error) {
+func upsertFileUploadAnswer(ctx context.Context, qtx *db.Queries, answer applicationDTO.CreateAnswerFileUpload, courseParticipationID uuid.UUID) error {
 	if answer.FileID == uuid.Nil {
-		return nil, nil
+		return nil
 	}
 
 	// Check if there's an existing file upload answer for this question
@@ -91,50 +129,29 @@ func upsertFileUploadAnswer(ctx context.Context, qtx *db.Queries, answer applica
 		CourseParticipationID: courseParticipationID,
 	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, err
-	}
-
-	var oldFileID *uuid.UUID
-	if err == nil {
-		if existingAnswer.FileID != answer.FileID {
-			existingFileID := existingAnswer.FileID
-			oldFileID = &existingFileID
-		}
+		return err
 	}
+	replacedFile := err == nil && existingAnswer.FileID != answer.FileID
 
 	// Create or overwrite the answer in the same transaction.
 	answerDBModel := answer.GetDBModel()
 	answerDBModel.ID = uuid.New()
 	answerDBModel.CourseParticipationID = courseParticipationID
 	if err := qtx.CreateOrOverwriteApplicationAnswerFileUpload(ctx, db.CreateOrOverwriteApplicationAnswerFileUploadParams(answerDBModel)); err != nil {
-		return nil, err
-	}
-
-	return oldFileID, nil
-}
-
-func cleanupReplacedFiles(ctx context.Context, fileIDs []uuid.UUID) {
-	if len(fileIDs) == 0 {
-		return
-	}
-	if storage.StorageServiceSingleton == nil {
-		return
+		return err
 	}
 
-	seenFileIDs := make(map[uuid.UUID]struct{}, len(fileIDs))
-	for _, fileID := range fileIDs {
-		if fileID == uuid.Nil {
-			continue
-		}
-		if _, seen := seenFileIDs[fileID]; seen {
-			continue
-		}
-		seenFileIDs[fileID] = struct{}{}
-
-		if err := storage.StorageServiceSingleton.DeleteFile(ctx, fileID, true); err != nil {
-			log.WithError(err).WithField("fileId", fileID).Warn("Failed to delete replaced file after transaction commit")
+	// the replaced file stays available to lecturers as prior version
+	if replacedFile {
+		if _, err := qtx.LinkFileVersion(ctx, db.LinkFileVersionParams{
+			PreviousVersionID: existingAnswer.FileID,
+			ID:                answer.FileID,
+		}); err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
 
 func GetApplicationForm(ctx context.Context, coursePhaseID uuid.UUID) (applicationDTO.Form, error) {
@@ -421,17 +438,12 @@ func PostApplicationExtern(ctx context.Context, coursePhaseID uuid.UUID, applica
 		}
 	}
 
-	replacedFileIDs := make([]uuid.UUID, 0, len(application.AnswersFileUpload))
 	for _, answer := range application.AnswersFileUpload {
-		var oldFileID *uuid.UUID
-		oldFileID, err = createOrReplaceFileUploadAnswer(ctx, qtx, answer, cPhaseParticipation.CourseParticipationID)
+		err = createOrOverwriteFileUploadAnswer(ctx, qtx, answer, cPhaseParticipation.CourseParticipationID)
 		if err != nil {
 			log.Error(err)
 			return uuid.Nil, errors.New("could not save the application answers")
 		}
-		if oldFileID != nil {
-			replacedFileIDs = append(replacedFileIDs, *oldFileID)
-		}
 	}
 
 	// Set Application To Passed if feature is turned on
@@ -457,8 +469,8 @@ func PostApplicationExtern(ctx context.Context, coursePhaseID uuid.UUID, applica
 		log.Error(err)
 		return uuid.Nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-
-	cleanupReplacedFiles(ctx, replacedFileIDs)
+	// the applicant may have got the student role of the course
+	keycloakTokenVerifier.InvalidateRoleCache()
 
 	return cPhaseParticipation.CourseParticipationID, nil
 }
@@ -532,7 +544,7 @@ func GetApplicationAuthenticatedByMatriculationNumberAndUniversityLogin(ctx cont
 			Student:            &studentObj,
 			AnswersText:        applicationDTO.GetAnswersTextDTOFromDBModels(answersText),
 			AnswersMultiSelect: applicationDTO.GetAnswersMultiSelectDTOFromDBModels(answersMultiSelect),
-			AnswersFileUpload:  buildFileUploadAnswerDTOs(ctxWithTimeout, answersFileUpload, true),
+			AnswersFileUpload:  buildFileUploadAnswerDTOs(ctxWithTimeout, answersFileUpload, true, false),
 		}, nil
 
 	} else {
@@ -548,7 +560,7 @@ func GetApplicationAuthenticatedByMatriculationNumberAndUniversityLogin(ctx cont
 
 }
 
-func PostApplicationAuthenticatedStudent(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication) (uuid.UUID, error) {
+func PostApplicationAuthenticatedStudent(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication, changedBy studentDTO.ProfileChangeAuthor) (uuid.UUID, error) {
 	tx, err := ApplicationServiceSingleton.conn.Begin(ctx)
 	if err != nil {
 		return uuid.Nil, err
@@ -557,7 +569,7 @@ func PostApplicationAuthenticatedStudent(ctx context.Context, coursePhaseID uuid
 	qtx := ApplicationServiceSingleton.queries.WithTx(tx)
 
 	// 1. Update student details
-	studentObj, err := student.CreateOrUpdateStudent(ctx, qtx, application.Student)
+	studentObj, err := student.CreateOrUpdateStudent(ctx, qtx, application.Student, changedBy)
 	if err != nil {
 		log.Error(err)
 		return uuid.Nil, errors.New("could not save the student")
@@ -605,17 +617,12 @@ func PostApplicationAuthenticatedStudent(ctx context.Context, coursePhaseID uuid
 		}
 	}
 
-	replacedFileIDs := make([]uuid.UUID, 0, len(application.AnswersFileUpload))
 	for _, answer := range application.AnswersFileUpload {
-		var oldFileID *uuid.UUID
-		oldFileID, err = createOrOverwriteFileUploadAnswer(ctx, qtx, answer, cPhaseParticipation.CourseParticipationID)
+		err = createOrOverwriteFileUploadAnswer(ctx, qtx, answer, cPhaseParticipation.CourseParticipationID)
 		if err != nil {
 			log.Error(err)
 			return uuid.Nil, errors.New("could not save the application answers")
 		}
-		if oldFileID != nil {
-			replacedFileIDs = append(replacedFileIDs, *oldFileID)
-		}
 	}
 
 	// 4. Set Application To Passed if feature is turned on
@@ -637,19 +644,95 @@ func PostApplicationAuthenticatedStudent(ctx context.Context, coursePhaseID uuid
 		return uuid.Nil, errors.New("could not save the application answers")
 	}
 
+	// 5. The submitted application replaces the draft. It is authoritative, answers only stored in the draft are not merged into it,
+	// since the applicant may have cleared them in the form.
+	err = qtx.DeleteApplicationDraft(ctx, db.DeleteApplicationDraftParams{
+		CoursePhaseID:   coursePhaseID,
+		UniversityLogin: application.Student.UniversityLogin,
+	})
+	if err != nil {
+		log.Error(err)
+		return uuid.Nil, errors.New("could not delete the application draft")
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		log.Error(err)
 		return uuid.Nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-
-	cleanupReplacedFiles(ctx, replacedFileIDs)
+	// the applicant may have got the student role of the course
+	keycloakTokenVerifier.InvalidateRoleCache()
 
 	return cPhaseParticipation.CourseParticipationID, nil
 
 }
 
+func GetApplicationDraft(ctx context.Context, coursePhaseID uuid.UUID, universityLogin string) (applicationDTO.ApplicationDraft, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	draft, err := ApplicationServiceSingleton.queries.GetApplicationDraft(ctxWithTimeout, db.GetApplicationDraftParams{
+		CoursePhaseID:   coursePhaseID,
+		UniversityLogin: universityLogin,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return applicationDTO.ApplicationDraft{}, ErrNoApplicationDraft
+	}
+	if err != nil {
+		log.Error(err)
+		return applicationDTO.ApplicationDraft{}, errors.New("could not get the application draft")
+	}
+
+	draftDTO, err := applicationDTO.GetApplicationDraftDTOFromDBModel(draft)
+	if err != nil {
+		log.Error("could not parse stored application draft: ", err)
+		return applicationDTO.ApplicationDraft{}, errors.New("could not get the application draft")
+	}
+	return draftDTO, nil
+}
+
+func SaveApplicationDraft(ctx context.Context, coursePhaseID uuid.UUID, universityLogin string, application applicationDTO.PostApplication) (applicationDTO.ApplicationDraft, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	applicationBytes, err := json.Marshal(application)
+	if err != nil {
+		log.Error(err)
+		return applicationDTO.ApplicationDraft{}, errors.New("could not save the application draft")
+	}
+
+	draft, err := ApplicationServiceSingleton.queries.UpsertApplicationDraft(ctxWithTimeout, db.UpsertApplicationDraftParams{
+		CoursePhaseID:   coursePhaseID,
+		UniversityLogin: universityLogin,
+		Application:     applicationBytes,
+	})
+	if err != nil {
+		log.Error(err)
+		return applicationDTO.ApplicationDraft{}, errors.New("could not save the application draft")
+	}
+
+	return applicationDTO.GetApplicationDraftDTOFromDBModel(draft)
+}
+
+func DeleteApplicationDraft(ctx context.Context, coursePhaseID uuid.UUID, universityLogin string) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	err := ApplicationServiceSingleton.queries.DeleteApplicationDraft(ctxWithTimeout, db.DeleteApplicationDraftParams{
+		CoursePhaseID:   coursePhaseID,
+		UniversityLogin: universityLogin,
+	})
+	if err != nil {
+		log.Error(err)
+		return errors.New("could not delete the application draft")
+	}
+	return nil
+}
+
 // TODO update
-func GetApplicationByCPID(ctx context.Context, coursePhaseID uuid.UUID, courseParticipationID uuid.UUID) (applicationDTO.Application, error) {
+// GetApplicationByCPID returns the application of a course participation.
+// For blind reviewers the identity of the applicant is hidden while the phase is under blind review,
+// and only the other users, the lecturers and admins, see the prior versions of uploaded files.
+func GetApplicationByCPID(ctx context.Context, coursePhaseID uuid.UUID, courseParticipationID uuid.UUID, blindReviewer bool) (applicationDTO.Application, error) {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
 	defer cancel()
 
@@ -696,37 +779,95 @@ func GetApplicationByCPID(ctx context.Context, coursePhaseID uuid.UUID, coursePa
 		return applicationDTO.Application{}, errors.New("could not get application answers")
 	}
 
-	return applicationDTO.Application{
+	application := applicationDTO.Application{
 		ID:                 courseParticipationID,
 		Status:             applicationDTO.StatusApplied,
 		Student:            &studentObj,
 		AnswersText:        applicationDTO.GetAnswersTextDTOFromDBModels(answersText),
 		AnswersMultiSelect: applicationDTO.GetAnswersMultiSelectDTOFromDBModels(answersMultiSelect),
-		AnswersFileUpload:  buildFileUploadAnswerDTOs(ctxWithTimeout, answersFileUpload, false),
-	}, nil
+		AnswersFileUpload:  buildFileUploadAnswerDTOs(ctxWithTimeout, answersFileUpload, false, !blindReviewer),
+	}
+
+	review, err := getBlindReview(ctxWithTimeout, coursePhaseID, blindReviewer)
+	if err != nil {
+		return applicationDTO.Application{}, err
+	}
+
+	if review.enabled {
+		score, err := ApplicationServiceSingleton.queries.GetApplicationAssessmentScore(ctxWithTimeout, db.GetApplicationAssessmentScoreParams{
+			CoursePhaseID:         coursePhaseID,
+			CourseParticipationID: courseParticipationID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Error(err)
+			return applicationDTO.Application{}, errors.New("could not get application assessment")
+		}
+		if review.hidesIdentity(score) {
+			review.redactApplication(&application)
+		}
+	}
+
+	return application, nil
 }
 
-func GetAllApplicationParticipations(ctx context.Context, coursePhaseID uuid.UUID) ([]applicationDTO.ApplicationParticipation, error) {
+// GetAllApplicationParticipations returns a page of the applications of the phase matching the list parameters,
+// the total number of matching applications and the cursor of the next page.
+// For blind reviewers the identity of the applicants is hidden while the phase is under blind review,
+// they cannot sort by identity and get the applications sorted by score by default.
+func GetAllApplicationParticipations(ctx context.Context, coursePhaseID uuid.UUID, blindReviewer bool, params listQuery.Params) ([]applicationDTO.ApplicationParticipation, int64, string, error) {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
 	defer cancel()
 
-	applicationParticipations, err := ApplicationServiceSingleton.queries.GetAllApplicationParticipations(ctxWithTimeout, coursePhaseID)
+	review, err := getBlindReview(ctxWithTimeout, coursePhaseID, blindReviewer)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	sortBy := params.SortByOrDefault("lastName")
+	if review.enabled {
+		if slices.Contains(identitySortFields, params.SortBy) {
+			return nil, 0, "", ErrIdentitySortDuringBlindReview
+		}
+		sortBy = params.SortByOrDefault("score")
+	}
+
+	applicationParticipations, err := ApplicationServiceSingleton.queries.GetAllApplicationParticipations(ctxWithTimeout, db.GetAllApplicationParticipationsParams{
+		SortBy:        sortBy,
+		CoursePhaseID: coursePhaseID,
+		PassStatus:    params.PassStatusFilter(),
+		Metadata:      params.MetadataFilter(),
+		CursorID:      params.CursorID(),
+		Descending:    params.Descending,
+		CursorKey:     params.CursorKey(),
+		PageSize:      params.PageSize(),
+	})
 	if err != nil {
 		log.Error(err)
-		return nil, errors.New("could not get application participations")
+		return nil, 0, "", errors.New("could not get application participations")
 	}
 
+	var total int64
+	if len(applicationParticipations) > 0 {
+		total = applicationParticipations[0].TotalCount
+	}
+	applicationParticipations, nextCursor := listQuery.TrimPage(params, applicationParticipations, func(row db.GetAllApplicationParticipationsRow) (string, uuid.UUID) {
+		return row.SortKey, row.CourseParticipationID
+	})
+
 	applicationParticipationsDTO := make([]applicationDTO.ApplicationParticipation, 0, len(applicationParticipations))
 	for _, applicationParticipation := range applicationParticipations {
 		application, err := applicationDTO.GetAllCPPsForCoursePhaseDTOFromDBModel(applicationParticipation)
 		if err != nil {
 			log.Error(err)
-			return nil, errors.New("could not get application participations")
+			return nil, 0, "", errors.New("could not get application participations")
+		}
+		if review.hidesIdentity(application.Score) {
+			redactStudent(&application.Student)
 		}
 		applicationParticipationsDTO = append(applicationParticipationsDTO, application)
 	}
 
-	return applicationParticipationsDTO, nil
+	return applicationParticipationsDTO, total, nextCursor, nil
 }
 
 func UpdateApplicationAssessment(ctx context.Context, coursePhaseID uuid.UUID, courseParticipationID uuid.UUID, assessment applicationDTO.PutAssessment) error {
@@ -893,5 +1034,7 @@ func DeleteApplications(ctx context.Context, coursePhaseID uuid.UUID, coursePart
 		log.Error(err)
 		return errors.New("could not delete applications")
 	}
+	// the applicants lose the student role of the course
+	keycloakTokenVerifier.InvalidateRoleCache()
 	return nil
 }
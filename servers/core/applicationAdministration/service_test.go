@@ -17,6 +17,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/coursePhase"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/meta"
 	"github.com/prompt-edu/prompt/servers/core/student"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
@@ -363,7 +364,7 @@ func (suite *ApplicationAdminServiceTestSuite) TestPostApplicationAuthenticatedS
 		},
 	}
 
-	_, err := PostApplicationAuthenticatedStudent(suite.ctx, coursePhaseID, application)
+	_, err := PostApplicationAuthenticatedStudent(suite.ctx, coursePhaseID, application, studentDTO.ProfileChangeAuthor{Source: db.StudentProfileChangeSourceApplication})
 	assert.NoError(suite.T(), err)
 }
 
@@ -386,10 +387,81 @@ func (suite *ApplicationAdminServiceTestSuite) TestPostApplicationAuthenticatedS
 	}
 
 	// Apply with existing email but updated details
-	_, err := PostApplicationAuthenticatedStudent(suite.ctx, coursePhaseID, application)
+	_, err := PostApplicationAuthenticatedStudent(suite.ctx, coursePhaseID, application, studentDTO.ProfileChangeAuthor{Source: db.StudentProfileChangeSourceApplication})
 	assert.NoError(suite.T(), err)
 }
 
+func (suite *ApplicationAdminServiceTestSuite) TestApplicationDraft_SaveGetAndSubmit() {
+	coursePhaseID := uuid.MustParse("4179d58a-d00d-4fa7-94a5-397bc69fab02")
+	universityLogin := "dr12aft"
+
+	_, err := GetApplicationDraft(suite.ctx, coursePhaseID, universityLogin)
+	assert.ErrorIs(suite.T(), err, ErrNoApplicationDraft)
+
+	application := applicationDTO.PostApplication{
+		Student: studentDTO.CreateStudent{
+			FirstName:            "Draft",
+			LastName:             "Student",
+			Email:                "draftStudent@example.com",
+			HasUniversityAccount: true,
+			MatriculationNumber:  "03712345",
+			UniversityLogin:      universityLogin,
+			Gender:               db.GenderFemale,
+			Nationality:          "DE",
+			CurrentSemester:      pgtype.Int4{Valid: true, Int32: 1},
+			StudyProgram:         "Computer Science",
+			StudyDegree:          "bachelor",
+		},
+		AnswersText: []applicationDTO.CreateAnswerText{
+			{
+				ApplicationQuestionID: uuid.MustParse("a6a04042-95d1-4765-8592-caf9560c8c3c"),
+				Answer:                "First thoughts",
+			},
+		},
+	}
+
+	saved, err := SaveApplicationDraft(suite.ctx, coursePhaseID, universityLogin, application)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), saved.LastModified.IsZero())
+
+	// saving again overwrites the previous draft
+	application.AnswersText[0].Answer = "Refined thoughts"
+	_, err = SaveApplicationDraft(suite.ctx, coursePhaseID, universityLogin, application)
+	assert.NoError(suite.T(), err)
+
+	draft, err := GetApplicationDraft(suite.ctx, coursePhaseID, universityLogin)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), coursePhaseID, draft.CoursePhaseID)
+	assert.Equal(suite.T(), "Refined thoughts", draft.Application.AnswersText[0].Answer)
+
+	application.AnswersMultiSelect = []applicationDTO.CreateAnswerMultiSelect{
+		{
+			ApplicationQuestionID: uuid.MustParse("383a9590-fba2-4e6b-a32b-88895d55fb9b"),
+			Answer:                []string{"Option1"},
+		},
+	}
+	_, err = PostApplicationAuthenticatedStudent(suite.ctx, coursePhaseID, application, studentDTO.ProfileChangeAuthor{Source: db.StudentProfileChangeSourceApplication})
+	assert.NoError(suite.T(), err)
+
+	// submitting the application removes the draft
+	_, err = GetApplicationDraft(suite.ctx, coursePhaseID, universityLogin)
+	assert.ErrorIs(suite.T(), err, ErrNoApplicationDraft)
+}
+
+func (suite *ApplicationAdminServiceTestSuite) TestDeleteApplicationDraft() {
+	coursePhaseID := uuid.MustParse("4179d58a-d00d-4fa7-94a5-397bc69fab02")
+	universityLogin := "de12let"
+
+	_, err := SaveApplicationDraft(suite.ctx, coursePhaseID, universityLogin, applicationDTO.PostApplication{})
+	assert.NoError(suite.T(), err)
+
+	err = DeleteApplicationDraft(suite.ctx, coursePhaseID, universityLogin)
+	assert.NoError(suite.T(), err)
+
+	_, err = GetApplicationDraft(suite.ctx, coursePhaseID, universityLogin)
+	assert.ErrorIs(suite.T(), err, ErrNoApplicationDraft)
+}
+
 func (suite *ApplicationAdminServiceTestSuite) TestUpdateApplicationAssessment_Success() {
 	coursePhaseID := uuid.MustParse("4179d58a-d00d-4fa7-94a5-397bc69fab02")
 	courseParticipationID := uuid.MustParse("82d7efae-d545-4cc5-9b94-5d0ee1e50d25")
@@ -407,7 +479,7 @@ func (suite *ApplicationAdminServiceTestSuite) TestUpdateApplicationAssessment_S
 	assert.NoError(suite.T(), err)
 
 	// Verify that the assessment was updated
-	participations, err := GetAllApplicationParticipations(suite.ctx, coursePhaseID)
+	participations, _, _, err := GetAllApplicationParticipations(suite.ctx, coursePhaseID, false, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	for _, participation := range participations {
 		if participation.CourseParticipationID == courseParticipationID {
@@ -449,7 +521,7 @@ func (suite *ApplicationAdminServiceTestSuite) TestUploadAdditionalScore_Success
 	err := UploadAdditionalScore(suite.ctx, coursePhaseID, additionalScore)
 	assert.NoError(suite.T(), err)
 
-	participations, err := GetAllApplicationParticipations(suite.ctx, coursePhaseID)
+	participations, _, _, err := GetAllApplicationParticipations(suite.ctx, coursePhaseID, false, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	for _, participation := range participations {
 		if participation.CourseParticipationID == uuid.MustParse("82d7efae-d545-4cc5-9b94-5d0ee1e50d25") {
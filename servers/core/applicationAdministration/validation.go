@@ -143,7 +143,45 @@ func validateUpdateForm(ctx context.Context, coursePhaseID uuid.UUID, updateForm
 		}
 	}
 
-	return nil
+	// 4. Conditions: Only reference questions of the updated form and contain no cycles
+	return validateUpdatedDisplayConditions(applicationQuestionsText, applicationQuestionsMultiSelect, applicationQuestionsFileUpload, updateForm)
+}
+
+func validateUpdatedDisplayConditions(questionsText []db.ApplicationQuestionText, questionsMultiSelect []db.ApplicationQuestionMultiSelect, questionsFileUpload []db.ApplicationQuestionFileUpload, updateForm applicationDTO.UpdateForm) error {
+	questions := getConditionalQuestions(questionsText, questionsMultiSelect, questionsFileUpload)
+
+	for _, questionID := range updateForm.DeleteQuestionsText {
+		delete(questions, questionID)
+	}
+	for _, questionID := range updateForm.DeleteQuestionsMultiSelect {
+		delete(questions, questionID)
+	}
+	for _, questionID := range updateForm.DeleteQuestionsFileUpload {
+		delete(questions, questionID)
+	}
+
+	for _, question := range updateForm.UpdateQuestionsText {
+		questions[question.ID] = conditionalQuestion{kind: questionKindText, condition: question.DisplayCondition}
+	}
+	for _, question := range updateForm.UpdateQuestionsMultiSelect {
+		questions[question.ID] = conditionalQuestion{kind: questionKindMultiSelect, condition: question.DisplayCondition}
+	}
+	for _, question := range updateForm.UpdateQuestionsFileUpload {
+		questions[question.ID] = conditionalQuestion{kind: questionKindFileUpload, condition: question.DisplayCondition}
+	}
+
+	createdConditions := make([]*applicationDTO.QuestionCondition, 0, len(updateForm.CreateQuestionsText)+len(updateForm.CreateQuestionsMultiSelect)+len(updateForm.CreateQuestionsFileUpload))
+	for _, question := range updateForm.CreateQuestionsText {
+		createdConditions = append(createdConditions, question.DisplayCondition)
+	}
+	for _, question := range updateForm.CreateQuestionsMultiSelect {
+		createdConditions = append(createdConditions, question.DisplayCondition)
+	}
+	for _, question := range updateForm.CreateQuestionsFileUpload {
+		createdConditions = append(createdConditions, question.DisplayCondition)
+	}
+
+	return validateDisplayConditions(questions, createdConditions)
 }
 
 func validateQuestionText(title, validationRegex string, allowedLength int, accessibleForOtherPhases pgtype.Bool, accessKey pgtype.Text) error {
@@ -257,7 +295,8 @@ func validateExportSettings(accessibleForOtherPhases pgtype.Bool, accessKey pgty
 	return nil
 }
 
-func validateApplicationManualAdd(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication) error {
+// validateApplicationManualAdd validates an application added by a lecturer, which is accepted after the deadline
+func validateApplicationManualAdd(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication) (applicationDTO.PostApplication, error) {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
 	defer cancel()
 
@@ -266,16 +305,17 @@ func validateApplicationManualAdd(ctx context.Context, coursePhaseID uuid.UUID,
 	isApplicationPhase, err := ApplicationServiceSingleton.queries.CheckIfCoursePhaseIsApplicationPhase(ctxWithTimeout, coursePhaseID)
 	if err != nil {
 		log.Error("could not validate application: ", err)
-		return errors.New("could not validate the application")
+		return applicationDTO.PostApplication{}, errors.New("could not validate the application")
 	}
 	if !isApplicationPhase {
-		return errors.New("course phase is not an application phase")
+		return applicationDTO.PostApplication{}, errors.New("course phase is not an application phase")
 	}
 
 	return validateAnswers(ctx, coursePhaseID, application)
 }
 
-func validateApplication(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication, authenticatedRoute bool) error {
+// validateApplication validates a submitted application and returns the application to save
+func validateApplication(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication, authenticatedRoute bool) (applicationDTO.PostApplication, error) {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
 	defer cancel()
 
@@ -283,60 +323,148 @@ func validateApplication(ctx context.Context, coursePhaseID uuid.UUID, applicati
 	applicationDetails, err := ApplicationServiceSingleton.queries.CheckIfCoursePhaseIsOpenApplicationPhase(ctxWithTimeout, coursePhaseID)
 	if err != nil {
 		log.Error("could not validate application: ", err)
-		return errors.New("could not validate the application. the application deadline might have passed")
+		return applicationDTO.PostApplication{}, errors.New("could not validate the application. the application deadline might have passed")
 	}
 	if !applicationDetails.IsApplication {
-		return errors.New("course phase is not an application phase")
+		return applicationDTO.PostApplication{}, errors.New("course phase is not an application phase")
 	}
 
 	if !authenticatedRoute && applicationDetails.UniversityLoginAvailable && application.Student.HasUniversityAccount {
-		return errors.New("student with university data MUST log in to apply")
+		return applicationDTO.PostApplication{}, errors.New("student with university data MUST log in to apply")
 	}
 
 	return validateAnswers(ctx, coursePhaseID, application)
 }
 
-func validateAnswers(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication) error {
+// validateApplicationDraft checks a partially filled application. Unlike a submission,
+// missing required answers and student details are accepted.
+func validateApplicationDraft(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	applicationDetails, err := ApplicationServiceSingleton.queries.CheckIfCoursePhaseIsOpenApplicationPhase(ctxWithTimeout, coursePhaseID)
+	if err != nil {
+		log.Error("could not validate application draft: ", err)
+		return errors.New("could not validate the application draft. the application deadline might have passed")
+	}
+	if !applicationDetails.IsApplication {
+		return errors.New("course phase is not an application phase")
+	}
+
+	applicationQuestionsText, err := ApplicationServiceSingleton.queries.GetApplicationQuestionsTextForCoursePhase(ctxWithTimeout, coursePhaseID)
+	if err != nil {
+		log.Error("could not validate application draft: ", err)
+		return errors.New("could not validate the application draft")
+	}
+
+	applicationQuestionsMultiSelect, err := ApplicationServiceSingleton.queries.GetApplicationQuestionsMultiSelectForCoursePhase(ctxWithTimeout, coursePhaseID)
+	if err != nil {
+		log.Error("could not validate application draft: ", err)
+		return errors.New("could not validate the application draft")
+	}
+
+	applicationQuestionsFileUpload, err := ApplicationServiceSingleton.queries.GetApplicationQuestionsFileUploadForCoursePhase(ctxWithTimeout, coursePhaseID)
+	if err != nil {
+		log.Error("could not validate application draft: ", err)
+		return errors.New("could not validate the application draft")
+	}
+
+	return validateDraftAnswers(applicationQuestionsText, applicationQuestionsMultiSelect, applicationQuestionsFileUpload, application)
+}
+
+func validateDraftAnswers(textQuestions []db.ApplicationQuestionText, multiSelectQuestions []db.ApplicationQuestionMultiSelect, fileUploadQuestions []db.ApplicationQuestionFileUpload, application applicationDTO.PostApplication) error {
+	textQuestionMap := make(map[uuid.UUID]db.ApplicationQuestionText, len(textQuestions))
+	for _, question := range textQuestions {
+		textQuestionMap[question.ID] = question
+	}
+	for _, answer := range application.AnswersText {
+		question, exists := textQuestionMap[answer.ApplicationQuestionID]
+		if !exists {
+			return fmt.Errorf("answer to question %s does not belong to this course", answer.ApplicationQuestionID)
+		}
+		if utf8.RuneCountInString(answer.Answer) > int(question.AllowedLength.Int32) {
+			return fmt.Errorf("answer to question %s exceeds allowed length of %d", question.ID, question.AllowedLength.Int32)
+		}
+	}
+
+	multiSelectQuestionMap := make(map[uuid.UUID]db.ApplicationQuestionMultiSelect, len(multiSelectQuestions))
+	for _, question := range multiSelectQuestions {
+		multiSelectQuestionMap[question.ID] = question
+	}
+	for _, answer := range application.AnswersMultiSelect {
+		question, exists := multiSelectQuestionMap[answer.ApplicationQuestionID]
+		if !exists {
+			return fmt.Errorf("answer to question %s does not belong to this course", answer.ApplicationQuestionID)
+		}
+		if len(answer.Answer) > int(question.MaxSelect.Int32) {
+			return fmt.Errorf("answer to question %s does not meet selection requirements", question.ID)
+		}
+		for _, selection := range answer.Answer {
+			if !contains(question.Options, selection) {
+				return fmt.Errorf("invalid selection %s for question %s", selection, question.ID)
+			}
+		}
+	}
+
+	fileUploadQuestionMap := make(map[uuid.UUID]db.ApplicationQuestionFileUpload, len(fileUploadQuestions))
+	for _, question := range fileUploadQuestions {
+		fileUploadQuestionMap[question.ID] = question
+	}
+	for _, answer := range application.AnswersFileUpload {
+		if _, exists := fileUploadQuestionMap[answer.ApplicationQuestionID]; !exists {
+			return fmt.Errorf("answer to question %s does not belong to this course", answer.ApplicationQuestionID)
+		}
+	}
+
+	return nil
+}
+
+// validateAnswers validates the student and the answers to the visible questions.
+// The returned application no longer contains answers to questions hidden by their display condition, it is the one to save.
+func validateAnswers(ctx context.Context, coursePhaseID uuid.UUID, application applicationDTO.PostApplication) (applicationDTO.PostApplication, error) {
 	// 1. Check that the student is valid
 	err := student.Validate(application.Student)
 	if err != nil {
-		return errors.New("invalid student")
+		return applicationDTO.PostApplication{}, errors.New("invalid student")
 	}
 
 	// 2. Get all questions for the course phase
 	applicationQuestionsText, err := ApplicationServiceSingleton.queries.GetApplicationQuestionsTextForCoursePhase(ctx, coursePhaseID)
 	if err != nil {
 		log.Error("could not validate application: ", err)
-		return errors.New("could not validate the application")
+		return applicationDTO.PostApplication{}, errors.New("could not validate the application")
 	}
 
 	applicationQuestionsMultiSelect, err := ApplicationServiceSingleton.queries.GetApplicationQuestionsMultiSelectForCoursePhase(ctx, coursePhaseID)
 	if err != nil {
-		return errors.New("could not validate the application")
+		return applicationDTO.PostApplication{}, errors.New("could not validate the application")
 	}
 
 	applicationQuestionsFileUpload, err := ApplicationServiceSingleton.queries.GetApplicationQuestionsFileUploadForCoursePhase(ctx, coursePhaseID)
 	if err != nil {
-		return errors.New("could not validate the application")
+		return applicationDTO.PostApplication{}, errors.New("could not validate the application")
 	}
 
-	// 3. Validate all the answers
+	// 3. Skip questions hidden by their display condition
+	applicationQuestionsText, applicationQuestionsMultiSelect, applicationQuestionsFileUpload, application = filterHiddenQuestions(applicationQuestionsText, applicationQuestionsMultiSelect, applicationQuestionsFileUpload, application)
+
+	// 4. Validate all the answers
 	err = validateTextAnswers(applicationQuestionsText, application.AnswersText)
 	if err != nil {
-		return err
+		return applicationDTO.PostApplication{}, err
 	}
 
 	err = validateMultiSelectAnswers(applicationQuestionsMultiSelect, application.AnswersMultiSelect)
 	if err != nil {
-		return err
+		return applicationDTO.PostApplication{}, err
 	}
 
 	err = validateFileUploadAnswers(applicationQuestionsFileUpload, application.AnswersFileUpload)
 	if err != nil {
-		return err
+		return applicationDTO.PostApplication{}, err
 	}
 
-	return nil
+	return application, nil
 }
 
 func validateFileUploadAnswers(fileUploadQuestions []db.ApplicationQuestionFileUpload, fileUploadAnswers []applicationDTO.CreateAnswerFileUpload) error {
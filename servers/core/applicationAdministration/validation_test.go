@@ -168,7 +168,7 @@ func (suite *ApplicationAdminValidationTestSuite) TestValidateApplication_Invali
 		AnswersMultiSelect: []applicationDTO.CreateAnswerMultiSelect{},
 	}
 
-	err := validateApplication(suite.ctx, coursePhaseID, application, false)
+	_, err := validateApplication(suite.ctx, coursePhaseID, application, false)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "invalid student", err.Error())
 }
@@ -196,7 +196,7 @@ func (suite *ApplicationAdminValidationTestSuite) TestValidateApplication_Invali
 		},
 	}
 
-	err := validateApplication(suite.ctx, coursePhaseID, application, false)
+	_, err := validateApplication(suite.ctx, coursePhaseID, application, false)
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "required question")
 }
@@ -382,6 +382,55 @@ func TestValidateMultiSelectAnswers_InvalidQuestionID(t *testing.T) {
 	assert.Contains(t, err.Error(), "answer to question d1e74f8b-9f7f-4b87-94a5-1234567890ab does not belong to this course")
 }
 
+func TestValidateDraftAnswers_MissingRequiredAnswersAccepted(t *testing.T) {
+	textQuestionID := uuid.MustParse("a6a04042-95d1-4765-8592-caf9560c8c3c")
+	multiSelectQuestionID := uuid.MustParse("383a9590-fba2-4e6b-a32b-88895d55fb9b")
+	textQuestions := []db.ApplicationQuestionText{
+		{
+			ID:            textQuestionID,
+			IsRequired:    pgtype.Bool{Bool: true, Valid: true},
+			AllowedLength: pgtype.Int4{Int32: 10, Valid: true},
+		},
+	}
+	multiSelectQuestions := []db.ApplicationQuestionMultiSelect{
+		{
+			ID:         multiSelectQuestionID,
+			IsRequired: pgtype.Bool{Bool: true, Valid: true},
+			MinSelect:  pgtype.Int4{Int32: 1, Valid: true},
+			MaxSelect:  pgtype.Int4{Int32: 1, Valid: true},
+			Options:    []string{"Option1", "Option2"},
+		},
+	}
+
+	// an empty draft is valid even though all questions are required
+	err := validateDraftAnswers(textQuestions, multiSelectQuestions, nil, applicationDTO.PostApplication{})
+	assert.NoError(t, err)
+
+	err = validateDraftAnswers(textQuestions, multiSelectQuestions, nil, applicationDTO.PostApplication{
+		AnswersText: []applicationDTO.CreateAnswerText{
+			{ApplicationQuestionID: textQuestionID, Answer: "this answer is too long"},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds allowed length")
+
+	err = validateDraftAnswers(textQuestions, multiSelectQuestions, nil, applicationDTO.PostApplication{
+		AnswersMultiSelect: []applicationDTO.CreateAnswerMultiSelect{
+			{ApplicationQuestionID: multiSelectQuestionID, Answer: []string{"Option3"}},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid selection")
+
+	err = validateDraftAnswers(textQuestions, multiSelectQuestions, nil, applicationDTO.PostApplication{
+		AnswersFileUpload: []applicationDTO.CreateAnswerFileUpload{
+			{ApplicationQuestionID: uuid.New(), FileID: uuid.New()},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not belong to this course")
+}
+
 func (suite *ApplicationAdminValidationTestSuite) TestValidateUpdateAssessment_Success() {
 	coursePhaseID := uuid.MustParse("4179d58a-d00d-4fa7-94a5-397bc69fab02")
 	courseParticipationID := uuid.MustParse("82d7efae-d545-4cc5-9b94-5d0ee1e50d25")
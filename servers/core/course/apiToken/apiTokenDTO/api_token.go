@@ -0,0 +1,50 @@
+package apiTokenDTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+type APIToken struct {
+	ID uuid.UUID `json:"id"`
+	// TokenPrefix is the beginning of the token, to recognize it without revealing it
+	TokenPrefix    string     `json:"tokenPrefix"`
+	Name           string     `json:"name"`
+	CourseID       uuid.UUID  `json:"courseID"`
+	Roles          []string   `json:"roles"`
+	CreatedByEmail string     `json:"createdByEmail"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// CreatedAPIToken is the only response that contains the token, it cannot be retrieved later
+type CreatedAPIToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+func GetAPITokenDTOFromDBModel(model db.ApiToken) APIToken {
+	apiToken := APIToken{
+		ID:             model.ID,
+		TokenPrefix:    model.TokenPrefix,
+		Name:           model.Name,
+		CourseID:       model.CourseID,
+		Roles:          model.Roles,
+		CreatedByEmail: model.CreatedByEmail,
+		CreatedAt:      model.CreatedAt.Time,
+		ExpiresAt:      model.ExpiresAt.Time,
+	}
+	if model.RevokedAt.Valid {
+		revokedAt := model.RevokedAt.Time
+		apiToken.RevokedAt = &revokedAt
+	}
+	if model.LastUsedAt.Valid {
+		lastUsedAt := model.LastUsedAt.Time
+		apiToken.LastUsedAt = &lastUsedAt
+	}
+	return apiToken
+}
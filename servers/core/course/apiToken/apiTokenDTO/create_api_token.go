@@ -0,0 +1,8 @@
+package apiTokenDTO
+
+type CreateAPIToken struct {
+	Name string `json:"name"`
+	// Roles are course roles, i.e. Lecturer or Editor
+	Roles         []string `json:"roles"`
+	ExpiresInDays int32    `json:"expiresInDays"`
+}
@@ -0,0 +1,22 @@
+package apiToken
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+)
+
+func InitAPITokenModule(routerGroup *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) {
+	setupAPITokenRouter(routerGroup, keycloakTokenVerifier.KeycloakMiddleware, checkAccessControlByIDWrapper)
+	APITokenServiceSingleton = &APITokenService{
+		queries: queries,
+		conn:    conn,
+	}
+}
+
+// initializes the handler func with CheckCoursePermissions
+func checkAccessControlByIDWrapper(allowedRoles ...string) gin.HandlerFunc {
+	return permissionValidation.CheckAccessControlByID(permissionValidation.CheckCoursePermission, "uuid", allowedRoles...)
+}
@@ -0,0 +1,140 @@
+package apiToken
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/course/apiToken/apiTokenDTO"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/utils"
+)
+
+var errAPITokenManagement = errors.New("API tokens cannot manage API tokens, sign in as a lecturer")
+
+// setupAPITokenRouter sets up the API token endpoints
+// @Summary API Token Endpoints
+// @Description Endpoints for managing the API tokens of scripts and service accounts
+// @Tags api_tokens
+// @Security BearerAuth
+func setupAPITokenRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionIDMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+	// a leaked token must not be able to create new tokens, so only users manage tokens
+	apiTokens := router.Group("/courses/:uuid/api-tokens", authMiddleware(), rejectAPITokens())
+	apiTokens.GET("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getAPITokens)
+	apiTokens.POST("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), createAPIToken)
+	apiTokens.DELETE("/:token-uuid", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), revokeAPIToken)
+}
+
+func rejectAPITokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if keycloakTokenVerifier.IsAPITokenRequest(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, utils.ErrorResponse{Error: errAPITokenManagement.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// getAPITokens godoc
+// @Summary Get the API tokens of a course
+// @Description Get all API tokens of a course including revoked and expired ones, without the tokens themselves
+// @Tags api_tokens
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Success 200 {array} apiTokenDTO.APIToken
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/api-tokens [get]
+func getAPITokens(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	apiTokens, err := GetAPITokens(c, courseID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, apiTokens)
+}
+
+// createAPIToken godoc
+// @Summary Create an API token
+// @Description Create an API token that acts with the given roles in the course. The token is only returned once.
+// @Tags api_tokens
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param apiToken body apiTokenDTO.CreateAPIToken true "Name, roles and lifetime of the token"
+// @Success 201 {object} apiTokenDTO.CreatedAPIToken
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/api-tokens [post]
+func createAPIToken(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var request apiTokenDTO.CreateAPIToken
+	if err := c.BindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateCreateAPIToken(request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	apiToken, err := CreateAPIToken(c, courseID, request, c.GetString(keycloakTokenVerifier.CtxUserID), c.GetString(keycloakTokenVerifier.CtxUserEmail))
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, apiToken)
+}
+
+// revokeAPIToken godoc
+// @Summary Revoke an API token
+// @Description Revoke an API token of a course, requests with the token are rejected afterwards
+// @Tags api_tokens
+// @Param uuid path string true "Course UUID"
+// @Param token-uuid path string true "API token UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/api-tokens/{token-uuid} [delete]
+func revokeAPIToken(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	tokenID, err := uuid.Parse(c.Param("token-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := RevokeAPIToken(c, courseID, tokenID); err != nil {
+		if errors.Is(err, ErrAPITokenNotFound) {
+			handleError(c, http.StatusNotFound, err)
+			return
+		}
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func handleError(c *gin.Context, statusCode int, err error) {
+	c.JSON(statusCode, utils.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,121 @@
+package apiToken
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prompt-edu/prompt/servers/core/course/apiToken/apiTokenDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	apiTokenRandomBytes = 32
+	// apiTokenDisplayLength is the length of the prefix stored to recognize a token, e.g. prompt_Ab3dE
+	apiTokenDisplayLength = len(keycloakTokenVerifier.APITokenPrefix) + 5
+)
+
+var ErrAPITokenNotFound = errors.New("the API token does not exist or is already revoked")
+
+type APITokenService struct {
+	queries db.Queries
+	conn    *pgxpool.Pool
+}
+
+var APITokenServiceSingleton *APITokenService
+
+func GetAPITokens(ctx context.Context, courseID uuid.UUID) ([]apiTokenDTO.APIToken, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	apiTokens, err := APITokenServiceSingleton.queries.GetAPITokensByCourse(ctxWithTimeout, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoAPITokens := make([]apiTokenDTO.APIToken, 0, len(apiTokens))
+	for _, apiToken := range apiTokens {
+		dtoAPITokens = append(dtoAPITokens, apiTokenDTO.GetAPITokenDTOFromDBModel(apiToken))
+	}
+	return dtoAPITokens, nil
+}
+
+// CreateAPIToken stores the hash of a new token, the token itself is only part of the response
+func CreateAPIToken(ctx context.Context, courseID uuid.UUID, request apiTokenDTO.CreateAPIToken, userID, userEmail string) (apiTokenDTO.CreatedAPIToken, error) {
+	token, err := generateAPIToken()
+	if err != nil {
+		return apiTokenDTO.CreatedAPIToken{}, err
+	}
+
+	roles := slices.Clone(request.Roles)
+	slices.Sort(roles)
+	roles = slices.Compact(roles)
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	apiToken, err := APITokenServiceSingleton.queries.CreateAPIToken(ctxWithTimeout, db.CreateAPITokenParams{
+		ID:              uuid.New(),
+		Name:            strings.TrimSpace(request.Name),
+		TokenHash:       keycloakTokenVerifier.HashAPIToken(token),
+		TokenPrefix:     token[:apiTokenDisplayLength],
+		CourseID:        courseID,
+		Roles:           roles,
+		CreatedByUserID: userID,
+		CreatedByEmail:  userEmail,
+		ExpiresInDays:   request.ExpiresInDays,
+	})
+	if err != nil {
+		return apiTokenDTO.CreatedAPIToken{}, err
+	}
+
+	log.WithFields(log.Fields{
+		"apiTokenId": apiToken.ID,
+		"courseId":   courseID,
+		"roles":      roles,
+		"createdBy":  userID,
+	}).Info("API token created")
+
+	return apiTokenDTO.CreatedAPIToken{
+		APIToken: apiTokenDTO.GetAPITokenDTOFromDBModel(apiToken),
+		Token:    token,
+	}, nil
+}
+
+// RevokeAPIToken rejects all further requests with the token, the record is kept for the audit of its last use
+func RevokeAPIToken(ctx context.Context, courseID, tokenID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	revoked, err := APITokenServiceSingleton.queries.RevokeAPIToken(ctxWithTimeout, db.RevokeAPITokenParams{
+		ID:       tokenID,
+		CourseID: courseID,
+	})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrAPITokenNotFound
+	}
+
+	log.WithFields(log.Fields{
+		"apiTokenId": tokenID,
+		"courseId":   courseID,
+	}).Info("API token revoked")
+	return nil
+}
+
+func generateAPIToken() (string, error) {
+	randomBytes := make([]byte, apiTokenRandomBytes)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return keycloakTokenVerifier.APITokenPrefix + base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
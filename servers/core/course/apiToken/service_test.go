@@ -0,0 +1,105 @@
+package apiToken
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	"github.com/prompt-edu/prompt/servers/core/course/apiToken/apiTokenDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type APITokenServiceTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	cleanup         func()
+	apiTokenService APITokenService
+}
+
+func (suite *APITokenServiceTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	testDB, cleanup, err := sdkTestUtils.SetupTestDB(suite.ctx, "../../database_dumps/copy_course_test.sql", func(conn *pgxpool.Pool) *db.Queries { return db.New(conn) })
+	if err != nil {
+		log.Fatalf("Failed to set up test database: %v", err)
+	}
+
+	suite.cleanup = cleanup
+	suite.apiTokenService = APITokenService{
+		queries: *testDB.Queries,
+		conn:    testDB.Conn,
+	}
+	APITokenServiceSingleton = &suite.apiTokenService
+}
+
+func (suite *APITokenServiceTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *APITokenServiceTestSuite) TestCreateAPIToken() {
+	courseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88dd")
+	created, err := CreateAPIToken(suite.ctx, courseID, apiTokenDTO.CreateAPIToken{
+		Name:          " Nightly export ",
+		Roles:         []string{"Lecturer", "Editor", "Lecturer"},
+		ExpiresInDays: 30,
+	}, uuid.NewString(), "lecturer@example.com")
+	assert.NoError(suite.T(), err)
+
+	assert.True(suite.T(), keycloakTokenVerifier.IsAPIToken(created.Token))
+	assert.True(suite.T(), strings.HasPrefix(created.Token, created.TokenPrefix))
+	assert.Equal(suite.T(), "Nightly export", created.Name)
+	assert.Equal(suite.T(), []string{"Editor", "Lecturer"}, created.Roles)
+	assert.WithinDuration(suite.T(), created.CreatedAt.Add(30*24*time.Hour), created.ExpiresAt, time.Minute)
+	assert.Nil(suite.T(), created.LastUsedAt)
+
+	// only the hash is stored, and it resolves to the roles of the course
+	activeToken, err := suite.apiTokenService.queries.GetActiveAPITokenByHash(suite.ctx, keycloakTokenVerifier.HashAPIToken(created.Token))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), created.ID, activeToken.ID)
+	assert.Equal(suite.T(), "ss25-Master Test", activeToken.CourseIdentifier)
+
+	apiTokens, err := GetAPITokens(suite.ctx, courseID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), apiTokens, 1)
+	assert.Equal(suite.T(), created.ID, apiTokens[0].ID)
+}
+
+func (suite *APITokenServiceTestSuite) TestRevokeAPIToken() {
+	courseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88ee")
+	created, err := CreateAPIToken(suite.ctx, courseID, apiTokenDTO.CreateAPIToken{
+		Name:          "Registration sync",
+		Roles:         []string{"Editor"},
+		ExpiresInDays: 1,
+	}, uuid.NewString(), "lecturer@example.com")
+	assert.NoError(suite.T(), err)
+
+	// tokens of other courses cannot be revoked through this course
+	err = RevokeAPIToken(suite.ctx, uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88dd"), created.ID)
+	assert.ErrorIs(suite.T(), err, ErrAPITokenNotFound)
+
+	err = RevokeAPIToken(suite.ctx, courseID, created.ID)
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.apiTokenService.queries.GetActiveAPITokenByHash(suite.ctx, keycloakTokenVerifier.HashAPIToken(created.Token))
+	assert.Error(suite.T(), err, "revoked tokens must not authenticate")
+
+	err = RevokeAPIToken(suite.ctx, courseID, created.ID)
+	assert.ErrorIs(suite.T(), err, ErrAPITokenNotFound)
+
+	apiTokens, err := GetAPITokens(suite.ctx, courseID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), apiTokens, 1)
+	assert.NotNil(suite.T(), apiTokens[0].RevokedAt)
+}
+
+func TestAPITokenServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APITokenServiceTestSuite))
+}
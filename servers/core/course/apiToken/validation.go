@@ -0,0 +1,43 @@
+package apiToken
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prompt-edu/prompt/servers/core/course/apiToken/apiTokenDTO"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+)
+
+const (
+	maxAPITokenNameLength   = 100
+	maxAPITokenLifetimeDays = 365
+)
+
+// assignableAPITokenRoles are the course roles a token can act with, tokens never act as students or administrators
+var assignableAPITokenRoles = []string{permissionValidation.CourseLecturer, permissionValidation.CourseEditor}
+
+func validateCreateAPIToken(request apiTokenDTO.CreateAPIToken) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return errors.New("validation error: the token name is required")
+	}
+	if len(name) > maxAPITokenNameLength {
+		return fmt.Errorf("validation error: the token name must not be longer than %d characters", maxAPITokenNameLength)
+	}
+
+	if len(request.Roles) == 0 {
+		return errors.New("validation error: at least one role is required")
+	}
+	for _, role := range request.Roles {
+		if !slices.Contains(assignableAPITokenRoles, role) {
+			return fmt.Errorf("validation error: unknown role %q, tokens can have the roles %s", role, strings.Join(assignableAPITokenRoles, ", "))
+		}
+	}
+
+	if request.ExpiresInDays < 1 || request.ExpiresInDays > maxAPITokenLifetimeDays {
+		return fmt.Errorf("validation error: tokens must expire within 1 to %d days", maxAPITokenLifetimeDays)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package apiToken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prompt-edu/prompt/servers/core/course/apiToken/apiTokenDTO"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateAPIToken(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         apiTokenDTO.CreateAPIToken
+		expectedError string
+	}{
+		{
+			name:          "valid token",
+			input:         apiTokenDTO.CreateAPIToken{Name: "Nightly export", Roles: []string{"Lecturer", "Editor"}, ExpiresInDays: 90},
+			expectedError: "",
+		},
+		{
+			name:          "missing name",
+			input:         apiTokenDTO.CreateAPIToken{Name: "  ", Roles: []string{"Editor"}, ExpiresInDays: 90},
+			expectedError: "validation error: the token name is required",
+		},
+		{
+			name:          "name too long",
+			input:         apiTokenDTO.CreateAPIToken{Name: strings.Repeat("a", 101), Roles: []string{"Editor"}, ExpiresInDays: 90},
+			expectedError: "validation error: the token name must not be longer than 100 characters",
+		},
+		{
+			name:          "missing roles",
+			input:         apiTokenDTO.CreateAPIToken{Name: "Registration sync", ExpiresInDays: 90},
+			expectedError: "validation error: at least one role is required",
+		},
+		{
+			name:          "student role",
+			input:         apiTokenDTO.CreateAPIToken{Name: "Registration sync", Roles: []string{"Student"}, ExpiresInDays: 90},
+			expectedError: `validation error: unknown role "Student", tokens can have the roles Lecturer, Editor`,
+		},
+		{
+			name:          "admin role",
+			input:         apiTokenDTO.CreateAPIToken{Name: "Registration sync", Roles: []string{"PROMPT_Admin"}, ExpiresInDays: 90},
+			expectedError: `validation error: unknown role "PROMPT_Admin", tokens can have the roles Lecturer, Editor`,
+		},
+		{
+			name:          "no expiry",
+			input:         apiTokenDTO.CreateAPIToken{Name: "Registration sync", Roles: []string{"Editor"}},
+			expectedError: "validation error: tokens must expire within 1 to 365 days",
+		},
+		{
+			name:          "expiry too late",
+			input:         apiTokenDTO.CreateAPIToken{Name: "Registration sync", Roles: []string{"Editor"}, ExpiresInDays: 366},
+			expectedError: "validation error: tokens must expire within 1 to 365 days",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCreateAPIToken(tt.input)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -32,6 +32,7 @@ func copyApplicationForm(c *gin.Context, qtx *db.Queries, sourceCoursePhaseID, t
 			OrderNum:                 question.OrderNum,
 			AccessibleForOtherPhases: question.AccessibleForOtherPhases,
 			AccessKey:                question.AccessKey,
+			IsIdentifying:            question.IsIdentifying,
 		})
 	}
 
@@ -50,6 +51,7 @@ func copyApplicationForm(c *gin.Context, qtx *db.Queries, sourceCoursePhaseID, t
 			OrderNum:                 question.OrderNum,
 			AccessibleForOtherPhases: question.AccessibleForOtherPhases,
 			AccessKey:                question.AccessKey,
+			IsIdentifying:            question.IsIdentifying,
 		})
 	}
 
@@ -65,6 +67,7 @@ func copyApplicationForm(c *gin.Context, qtx *db.Queries, sourceCoursePhaseID, t
 			OrderNum:                 question.OrderNum,
 			AccessibleForOtherPhases: question.AccessibleForOtherPhases,
 			AccessKey:                question.AccessKey,
+			IsIdentifying:            question.IsIdentifying,
 		})
 	}
 
@@ -80,41 +83,114 @@ func copyApplicationForm(c *gin.Context, qtx *db.Queries, sourceCoursePhaseID, t
 		UpdateQuestionsFileUpload:  []applicationDTO.QuestionFileUpload{},
 	}
 
-	if err := updateApplicationFormHelper(c, qtx, targetCoursePhaseID, form); err != nil {
+	createdIDs, err := updateApplicationFormHelper(c, qtx, targetCoursePhaseID, form)
+	if err != nil {
 		return fmt.Errorf("failed to update application form: %w", err)
 	}
+
+	if err := copyDisplayConditions(c, qtx, targetCoursePhaseID, applicationForm, createdIDs); err != nil {
+		return fmt.Errorf("failed to copy display conditions: %w", err)
+	}
 	return nil
 }
 
+// createdQuestionIDs holds the ids of the questions created by updateApplicationFormHelper
+// in the order of the create lists of the update form.
+type createdQuestionIDs struct {
+	text        []uuid.UUID
+	multiSelect []uuid.UUID
+	fileUpload  []uuid.UUID
+}
+
+// copyDisplayConditions copies the display conditions of the source form onto the newly
+// created questions. Conditions reference other questions by id, so every reference is
+// remapped to the id of the corresponding copied question.
+func copyDisplayConditions(c *gin.Context, qtx *db.Queries, targetCoursePhaseID uuid.UUID, sourceForm applicationDTO.Form, createdIDs createdQuestionIDs) error {
+	questionIDMapping := make(map[uuid.UUID]uuid.UUID)
+	for i, question := range sourceForm.QuestionsText {
+		questionIDMapping[question.ID] = createdIDs.text[i]
+	}
+	for i, question := range sourceForm.QuestionsMultiSelect {
+		questionIDMapping[question.ID] = createdIDs.multiSelect[i]
+	}
+	for i, question := range sourceForm.QuestionsFileUpload {
+		questionIDMapping[question.ID] = createdIDs.fileUpload[i]
+	}
+
+	remapCondition := func(condition *applicationDTO.QuestionCondition) *applicationDTO.QuestionCondition {
+		remapped := condition.RemapQuestionIDs(questionIDMapping)
+		return &remapped
+	}
+
+	form := applicationDTO.UpdateForm{}
+	for i, question := range sourceForm.QuestionsText {
+		if question.DisplayCondition == nil {
+			continue
+		}
+		question.ID = createdIDs.text[i]
+		question.CoursePhaseID = targetCoursePhaseID
+		question.DisplayCondition = remapCondition(question.DisplayCondition)
+		form.UpdateQuestionsText = append(form.UpdateQuestionsText, question)
+	}
+	for i, question := range sourceForm.QuestionsMultiSelect {
+		if question.DisplayCondition == nil {
+			continue
+		}
+		question.ID = createdIDs.multiSelect[i]
+		question.CoursePhaseID = targetCoursePhaseID
+		question.DisplayCondition = remapCondition(question.DisplayCondition)
+		form.UpdateQuestionsMultiSelect = append(form.UpdateQuestionsMultiSelect, question)
+	}
+	for i, question := range sourceForm.QuestionsFileUpload {
+		if question.DisplayCondition == nil {
+			continue
+		}
+		question.ID = createdIDs.fileUpload[i]
+		question.CoursePhaseID = targetCoursePhaseID
+		question.DisplayCondition = remapCondition(question.DisplayCondition)
+		form.UpdateQuestionsFileUpload = append(form.UpdateQuestionsFileUpload, question)
+	}
+
+	_, err := updateApplicationFormHelper(c, qtx, targetCoursePhaseID, form)
+	return err
+}
+
 // updateApplicationFormHelper applies updates to a course phase's application form.
-// It handles creation, deletion, and updating of text and multi-select questions.
-func updateApplicationFormHelper(c *gin.Context, qtx *db.Queries, coursePhaseId uuid.UUID, form applicationDTO.UpdateForm) error {
+// It handles creation, deletion, and updating of text and multi-select questions
+// and returns the ids of the created questions.
+func updateApplicationFormHelper(c *gin.Context, qtx *db.Queries, coursePhaseId uuid.UUID, form applicationDTO.UpdateForm) (createdQuestionIDs, error) {
+	createdIDs := createdQuestionIDs{
+		text:        make([]uuid.UUID, 0, len(form.CreateQuestionsText)),
+		multiSelect: make([]uuid.UUID, 0, len(form.CreateQuestionsMultiSelect)),
+		fileUpload:  make([]uuid.UUID, 0, len(form.CreateQuestionsFileUpload)),
+	}
+
 	isApplicationPhase, err := qtx.CheckIfCoursePhaseIsApplicationPhase(c, coursePhaseId)
 	if err != nil {
 		log.Error(err)
-		return fmt.Errorf("failed to check if course phase is application phase: %w", err)
+		return createdIDs, fmt.Errorf("failed to check if course phase is application phase: %w", err)
 	}
 
 	if !isApplicationPhase {
-		return fmt.Errorf("course phase is not an application phase")
+		return createdIDs, fmt.Errorf("course phase is not an application phase")
 	}
 
 	for _, questionID := range form.DeleteQuestionsMultiSelect {
 		if err := qtx.DeleteApplicationQuestionMultiSelect(c, questionID); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not delete multi-select question: %w", err)
+			return createdIDs, fmt.Errorf("could not delete multi-select question: %w", err)
 		}
 	}
 	for _, questionID := range form.DeleteQuestionsText {
 		if err := qtx.DeleteApplicationQuestionText(c, questionID); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not delete text question: %w", err)
+			return createdIDs, fmt.Errorf("could not delete text question: %w", err)
 		}
 	}
 	for _, questionID := range form.DeleteQuestionsFileUpload {
 		if err := qtx.DeleteApplicationQuestionFileUpload(c, questionID); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not delete file upload question: %w", err)
+			return createdIDs, fmt.Errorf("could not delete file upload question: %w", err)
 		}
 	}
 
@@ -124,8 +200,9 @@ func updateApplicationFormHelper(c *gin.Context, qtx *db.Queries, coursePhaseId
 		model.CoursePhaseID = coursePhaseId
 		if err := qtx.CreateApplicationQuestionText(c, model); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not create text question: %w", err)
+			return createdIDs, fmt.Errorf("could not create text question: %w", err)
 		}
+		createdIDs.text = append(createdIDs.text, model.ID)
 	}
 	for _, question := range form.CreateQuestionsMultiSelect {
 		model := question.GetDBModel()
@@ -133,8 +210,9 @@ func updateApplicationFormHelper(c *gin.Context, qtx *db.Queries, coursePhaseId
 		model.CoursePhaseID = coursePhaseId
 		if err := qtx.CreateApplicationQuestionMultiSelect(c, model); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not create multi-select question: %w", err)
+			return createdIDs, fmt.Errorf("could not create multi-select question: %w", err)
 		}
+		createdIDs.multiSelect = append(createdIDs.multiSelect, model.ID)
 	}
 	for _, question := range form.CreateQuestionsFileUpload {
 		model := question.GetDBModel()
@@ -142,30 +220,31 @@ func updateApplicationFormHelper(c *gin.Context, qtx *db.Queries, coursePhaseId
 		model.CoursePhaseID = coursePhaseId
 		if err := qtx.CreateApplicationQuestionFileUpload(c, model); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not create file upload question: %w", err)
+			return createdIDs, fmt.Errorf("could not create file upload question: %w", err)
 		}
+		createdIDs.fileUpload = append(createdIDs.fileUpload, model.ID)
 	}
 
 	for _, question := range form.UpdateQuestionsMultiSelect {
 		if err := qtx.UpdateApplicationQuestionMultiSelect(c, question.GetDBModel()); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not update multi-select question: %w", err)
+			return createdIDs, fmt.Errorf("could not update multi-select question: %w", err)
 		}
 	}
 	for _, question := range form.UpdateQuestionsText {
 		if err := qtx.UpdateApplicationQuestionText(c, question.GetDBModel()); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not update text question: %w", err)
+			return createdIDs, fmt.Errorf("could not update text question: %w", err)
 		}
 	}
 	for _, question := range form.UpdateQuestionsFileUpload {
 		if err := qtx.UpdateApplicationQuestionFileUpload(c, question.GetDBModel()); err != nil {
 			log.Error(err)
-			return fmt.Errorf("could not update file upload question: %w", err)
+			return createdIDs, fmt.Errorf("could not update file upload question: %w", err)
 		}
 	}
 
-	return nil
+	return createdIDs, nil
 }
 
 // getApplicationFormHelper retrieves the application form for the given course phase,
@@ -11,6 +11,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/course/copy/courseCopyDTO"
 	"github.com/prompt-edu/prompt/servers/core/course/courseDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
 	"github.com/prompt-edu/prompt/servers/core/meta"
 	log "github.com/sirupsen/logrus"
 )
@@ -136,6 +137,10 @@ func copyCourseInternal(c *gin.Context, sourceCourseID uuid.UUID, courseVariable
 		}
 	}
 
+	if err := keycloakTokenVerifier.AddCourseLecturer(c, qtx, createdCourse.ID, requesterID); err != nil {
+		return courseDTO.Course{}, err
+	}
+
 	if err := CourseCopyServiceSingleton.createCourseGroupsAndRoles(c, createdCourse.Name, createdCourse.SemesterTag.String, requesterID); err != nil {
 		log.Error("failed to create keycloak roles for course: ", err)
 		return courseDTO.Course{}, fmt.Errorf("failed to create keycloak roles/groups: %w", err)
@@ -144,6 +149,7 @@ func copyCourseInternal(c *gin.Context, sourceCourseID uuid.UUID, courseVariable
 	if err := tx.Commit(c); err != nil {
 		return courseDTO.Course{}, fmt.Errorf("failed to commit course transaction: %w", err)
 	}
+	keycloakTokenVerifier.InvalidateRoleCache()
 
 	if err := copyPhaseConfigurations(c, phaseIDMap); err != nil {
 		return courseDTO.Course{}, fmt.Errorf("failed to copy phase configurations: %w", err)
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prompt-edu/prompt/servers/core/course/courseParticipation/courseParticipationDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 )
 
@@ -59,6 +60,11 @@ func CreateCourseParticipation(ctx context.Context, transactionQueries *db.Queri
 	if err != nil {
 		return courseParticipationDTO.GetCourseParticipation{}, err
 	}
+	// the student gets the student role of the course,
+	// callers with a transaction invalidate the cache after the commit, when the participation becomes visible
+	if transactionQueries == nil {
+		keycloakTokenVerifier.InvalidateRoleCache()
+	}
 
 	return courseParticipationDTO.GetCourseParticipationDTOFromDBModel(createdParticipation), nil
 }
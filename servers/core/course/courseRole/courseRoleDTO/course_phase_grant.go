@@ -0,0 +1,21 @@
+package courseRoleDTO
+
+import (
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+// CoursePhaseGrant lists the built-in roles and permissions a course role has in a single course phase
+type CoursePhaseGrant struct {
+	CourseRoleID  uuid.UUID `json:"courseRoleID"`
+	CoursePhaseID uuid.UUID `json:"coursePhaseID"`
+	Grants        []string  `json:"grants"`
+}
+
+func GetCoursePhaseGrantDTOFromDBModel(model db.CoursePhaseGrant) CoursePhaseGrant {
+	return CoursePhaseGrant{
+		CourseRoleID:  model.CourseRoleID,
+		CoursePhaseID: model.CoursePhaseID,
+		Grants:        model.Grants,
+	}
+}
@@ -0,0 +1,24 @@
+package courseRoleDTO
+
+import (
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+type CourseRole struct {
+	ID          uuid.UUID `json:"id"`
+	CourseID    uuid.UUID `json:"courseID"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+}
+
+func GetCourseRoleDTOFromDBModel(model db.CourseRole) CourseRole {
+	return CourseRole{
+		ID:          model.ID,
+		CourseID:    model.CourseID,
+		Name:        model.Name,
+		Description: model.Description,
+		Permissions: model.Permissions,
+	}
+}
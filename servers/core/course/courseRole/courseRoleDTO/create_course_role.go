@@ -0,0 +1,7 @@
+package courseRoleDTO
+
+type CreateCourseRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,6 @@
+package courseRoleDTO
+
+// UpdateCoursePhaseGrant replaces the grants of a course role in a course phase
+type UpdateCoursePhaseGrant struct {
+	Grants []string `json:"grants"`
+}
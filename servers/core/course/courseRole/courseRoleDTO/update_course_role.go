@@ -0,0 +1,7 @@
+package courseRoleDTO
+
+// UpdateCourseRole cannot rename a role, as the name is part of its Keycloak role
+type UpdateCourseRole struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,25 @@
+package courseRole
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+)
+
+func InitCourseRoleModule(routerGroup *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) {
+	setupCourseRoleRouter(routerGroup, keycloakTokenVerifier.KeycloakMiddleware, checkAccessControlByIDWrapper)
+	CourseRoleServiceSingleton = &CourseRoleService{
+		queries:            queries,
+		conn:               conn,
+		createKeycloakRole: keycloakRealmManager.CreateCourseRoleGroup,
+		deleteKeycloakRole: keycloakRealmManager.DeleteCourseRoleGroup,
+	}
+}
+
+// initializes the handler func with CheckCoursePermissions
+func checkAccessControlByIDWrapper(allowedRoles ...string) gin.HandlerFunc {
+	return permissionValidation.CheckAccessControlByID(permissionValidation.CheckCoursePermission, "uuid", allowedRoles...)
+}
@@ -0,0 +1,353 @@
+package courseRole
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/course/courseRole/courseRoleDTO"
+	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager/keycloakRealmDTO"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/utils"
+)
+
+// setupCourseRoleRouter sets up the course role endpoints
+// @Summary Course Role Endpoints
+// @Description Endpoints for managing custom course roles with explicit permissions
+// @Tags course_roles
+// @Security BearerAuth
+func setupCourseRoleRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionIDMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+	courseRoles := router.Group("/courses/:uuid/roles", authMiddleware())
+	courseRoles.GET("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getCourseRoles)
+	courseRoles.POST("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), createCourseRole)
+	courseRoles.PUT("/:role-uuid", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateCourseRole)
+	courseRoles.DELETE("/:role-uuid", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), deleteCourseRole)
+	courseRoles.GET("/:role-uuid/members", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getCourseRoleMembers)
+	courseRoles.PUT("/:role-uuid/students", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), addStudentsToCourseRole)
+	courseRoles.GET("/:role-uuid/phases", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getCoursePhaseGrants)
+	courseRoles.PUT("/:role-uuid/phases/:phase-uuid", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateCoursePhaseGrant)
+	courseRoles.DELETE("/:role-uuid/phases/:phase-uuid", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), deleteCoursePhaseGrant)
+}
+
+// getCourseRoles godoc
+// @Summary Get the custom roles of a course
+// @Description Get all custom roles of a course with their permissions
+// @Tags course_roles
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Success 200 {array} courseRoleDTO.CourseRole
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles [get]
+func getCourseRoles(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	courseRoles, err := GetCourseRoles(c, courseID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, courseRoles)
+}
+
+// createCourseRole godoc
+// @Summary Create a custom course role
+// @Description Create a custom role with explicit permissions, together with its Keycloak group and role
+// @Tags course_roles
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param courseRole body courseRoleDTO.CreateCourseRole true "Role to create"
+// @Success 201 {object} courseRoleDTO.CourseRole
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles [post]
+func createCourseRole(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var request courseRoleDTO.CreateCourseRole
+	if err := c.BindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateCreateCourseRole(request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	courseRole, err := CreateCourseRole(c, courseID, request)
+	if err != nil {
+		if errors.Is(err, ErrDuplicateCourseRole) {
+			handleError(c, http.StatusConflict, err)
+			return
+		}
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, courseRole)
+}
+
+// updateCourseRole godoc
+// @Summary Update a custom course role
+// @Description Update the description and permissions of a custom course role
+// @Tags course_roles
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Param courseRole body courseRoleDTO.UpdateCourseRole true "New description and permissions"
+// @Success 200 {object} courseRoleDTO.CourseRole
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid} [put]
+func updateCourseRole(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var request courseRoleDTO.UpdateCourseRole
+	if err := c.BindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateUpdateCourseRole(request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	courseRole, err := UpdateCourseRole(c, courseID, courseRoleID, request)
+	if err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, courseRole)
+}
+
+// deleteCourseRole godoc
+// @Summary Delete a custom course role
+// @Description Delete a custom course role together with its Keycloak group and role
+// @Tags course_roles
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid} [delete]
+func deleteCourseRole(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := DeleteCourseRole(c, courseID, courseRoleID); err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// getCourseRoleMembers godoc
+// @Summary Get the members of a custom course role
+// @Description Get the members of the Keycloak group of a custom course role
+// @Tags course_roles
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Success 200 {object} keycloakRealmDTO.GroupMembers
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid}/members [get]
+func getCourseRoleMembers(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	members, err := GetCourseRoleMembers(c, courseID, courseRoleID)
+	if err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, members)
+}
+
+// addStudentsToCourseRole godoc
+// @Summary Add students to a custom course role
+// @Description Add students, e.g. tutors, to the Keycloak group of a custom course role
+// @Tags course_roles
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Param request body keycloakRealmDTO.AddStudentsToGroup true "Students to add"
+// @Success 200 {object} keycloakRealmDTO.AddStudentsToGroupResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid}/students [put]
+func addStudentsToCourseRole(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var request keycloakRealmDTO.AddStudentsToGroup
+	if err := c.BindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	addingReport, err := AddStudentsToCourseRole(c, courseID, courseRoleID, request.StudentsToAdd)
+	if err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, addingReport)
+}
+
+// getCoursePhaseGrants godoc
+// @Summary Get the course phase grants of a custom course role
+// @Description Get the course phases in which the role has additional roles or permissions
+// @Tags course_roles
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Success 200 {array} courseRoleDTO.CoursePhaseGrant
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid}/phases [get]
+func getCoursePhaseGrants(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	grants, err := GetCoursePhaseGrants(c, courseID, courseRoleID)
+	if err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, grants)
+}
+
+// updateCoursePhaseGrant godoc
+// @Summary Grant a custom course role access to a course phase
+// @Description Set the built-in roles (Lecturer, Editor) and permissions the role has in a single course phase
+// @Tags course_roles
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Param phase-uuid path string true "Course phase UUID"
+// @Param grant body courseRoleDTO.UpdateCoursePhaseGrant true "Grants in the course phase"
+// @Success 200 {object} courseRoleDTO.CoursePhaseGrant
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid}/phases/{phase-uuid} [put]
+func updateCoursePhaseGrant(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	coursePhaseID, err := uuid.Parse(c.Param("phase-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var request courseRoleDTO.UpdateCoursePhaseGrant
+	if err := c.BindJSON(&request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := validateUpdateCoursePhaseGrant(request); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	grant, err := UpdateCoursePhaseGrant(c, courseID, courseRoleID, coursePhaseID, request)
+	if err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, grant)
+}
+
+// deleteCoursePhaseGrant godoc
+// @Summary Revoke the access of a custom course role to a course phase
+// @Description Delete the grants of the role in the course phase, its permissions in the whole course are kept
+// @Tags course_roles
+// @Param uuid path string true "Course UUID"
+// @Param role-uuid path string true "Course role UUID"
+// @Param phase-uuid path string true "Course phase UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /courses/{uuid}/roles/{role-uuid}/phases/{phase-uuid} [delete]
+func deleteCoursePhaseGrant(c *gin.Context) {
+	courseID, courseRoleID, err := parseCourseRoleParams(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	coursePhaseID, err := uuid.Parse(c.Param("phase-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := DeleteCoursePhaseGrant(c, courseID, courseRoleID, coursePhaseID); err != nil {
+		handleCourseRoleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func parseCourseRoleParams(c *gin.Context) (uuid.UUID, uuid.UUID, error) {
+	courseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	courseRoleID, err := uuid.Parse(c.Param("role-uuid"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return courseID, courseRoleID, nil
+}
+
+func handleCourseRoleError(c *gin.Context, err error) {
+	if errors.Is(err, ErrCourseRoleNotFound) || errors.Is(err, ErrCoursePhaseNotFound) || errors.Is(err, ErrCoursePhaseGrantNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	handleError(c, http.StatusInternalServerError, err)
+}
+
+func handleError(c *gin.Context, statusCode int, err error) {
+	c.JSON(statusCode, utils.ErrorResponse{
+		Error: err.Error(),
+	})
+}
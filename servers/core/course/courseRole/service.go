@@ -0,0 +1,229 @@
+package courseRole
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	"github.com/prompt-edu/prompt/servers/core/course/courseRole/courseRoleDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager"
+	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager/keycloakRealmDTO"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrCourseRoleNotFound       = errors.New("course role not found")
+	ErrDuplicateCourseRole      = errors.New("a role with this name already exists in the course")
+	ErrCoursePhaseNotFound      = errors.New("course phase not found in the course")
+	ErrCoursePhaseGrantNotFound = errors.New("the course role has no grant for this course phase")
+)
+
+type CourseRoleService struct {
+	queries db.Queries
+	conn    *pgxpool.Pool
+	// the Keycloak group and role of a course role are created and deleted together with the role
+	createKeycloakRole func(ctx context.Context, courseID uuid.UUID, roleName string) error
+	deleteKeycloakRole func(ctx context.Context, courseID uuid.UUID, roleName string) error
+}
+
+var CourseRoleServiceSingleton *CourseRoleService
+
+func GetCourseRoles(ctx context.Context, courseID uuid.UUID) ([]courseRoleDTO.CourseRole, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	courseRoles, err := CourseRoleServiceSingleton.queries.GetCourseRolesByCourse(ctxWithTimeout, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtoCourseRoles := make([]courseRoleDTO.CourseRole, 0, len(courseRoles))
+	for _, courseRole := range courseRoles {
+		dtoCourseRoles = append(dtoCourseRoles, courseRoleDTO.GetCourseRoleDTOFromDBModel(courseRole))
+	}
+	return dtoCourseRoles, nil
+}
+
+func CreateCourseRole(ctx context.Context, courseID uuid.UUID, request courseRoleDTO.CreateCourseRole) (courseRoleDTO.CourseRole, error) {
+	// start transaction to roll back if keycloak failed
+	tx, err := CourseRoleServiceSingleton.conn.Begin(ctx)
+	if err != nil {
+		return courseRoleDTO.CourseRole{}, err
+	}
+	defer sdkUtils.DeferRollback(tx, ctx)
+	qtx := CourseRoleServiceSingleton.queries.WithTx(tx)
+
+	courseRole, err := qtx.CreateCourseRole(ctx, db.CreateCourseRoleParams{
+		ID:          uuid.New(),
+		CourseID:    courseID,
+		Name:        request.Name,
+		Description: request.Description,
+		Permissions: normalizePermissions(request.Permissions),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return courseRoleDTO.CourseRole{}, ErrDuplicateCourseRole
+		}
+		return courseRoleDTO.CourseRole{}, err
+	}
+
+	if err := CourseRoleServiceSingleton.createKeycloakRole(ctx, courseID, courseRole.Name); err != nil {
+		log.Error("Failed to create keycloak role for course role: ", err)
+		return courseRoleDTO.CourseRole{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return courseRoleDTO.CourseRole{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return courseRoleDTO.GetCourseRoleDTOFromDBModel(courseRole), nil
+}
+
+// UpdateCourseRole changes the permissions of a role, which apply to its members immediately
+func UpdateCourseRole(ctx context.Context, courseID, courseRoleID uuid.UUID, request courseRoleDTO.UpdateCourseRole) (courseRoleDTO.CourseRole, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	courseRole, err := CourseRoleServiceSingleton.queries.UpdateCourseRole(ctxWithTimeout, db.UpdateCourseRoleParams{
+		ID:          courseRoleID,
+		CourseID:    courseID,
+		Description: request.Description,
+		Permissions: normalizePermissions(request.Permissions),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return courseRoleDTO.CourseRole{}, ErrCourseRoleNotFound
+	}
+	if err != nil {
+		return courseRoleDTO.CourseRole{}, err
+	}
+	return courseRoleDTO.GetCourseRoleDTOFromDBModel(courseRole), nil
+}
+
+// DeleteCourseRole removes the role together with its Keycloak group, its members lose the permissions
+func DeleteCourseRole(ctx context.Context, courseID, courseRoleID uuid.UUID) error {
+	tx, err := CourseRoleServiceSingleton.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer sdkUtils.DeferRollback(tx, ctx)
+	qtx := CourseRoleServiceSingleton.queries.WithTx(tx)
+
+	courseRole, err := getCourseRole(ctx, qtx, courseID, courseRoleID)
+	if err != nil {
+		return err
+	}
+
+	if err := qtx.DeleteCourseRole(ctx, db.DeleteCourseRoleParams{ID: courseRoleID, CourseID: courseID}); err != nil {
+		return err
+	}
+
+	if err := CourseRoleServiceSingleton.deleteKeycloakRole(ctx, courseID, courseRole.Name); err != nil {
+		log.Error("Failed to delete keycloak role of course role: ", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func AddStudentsToCourseRole(ctx context.Context, courseID, courseRoleID uuid.UUID, studentIDs []uuid.UUID) (keycloakRealmDTO.AddStudentsToGroupResponse, error) {
+	courseRole, err := getCourseRole(ctx, &CourseRoleServiceSingleton.queries, courseID, courseRoleID)
+	if err != nil {
+		return keycloakRealmDTO.AddStudentsToGroupResponse{}, err
+	}
+	return keycloakRealmManager.AddStudentsToCourseRoleGroup(ctx, courseID, courseRole.Name, studentIDs)
+}
+
+func GetCourseRoleMembers(ctx context.Context, courseID, courseRoleID uuid.UUID) (keycloakRealmDTO.GroupMembers, error) {
+	courseRole, err := getCourseRole(ctx, &CourseRoleServiceSingleton.queries, courseID, courseRoleID)
+	if err != nil {
+		return keycloakRealmDTO.GroupMembers{}, err
+	}
+	return keycloakRealmManager.GetCourseRoleMembers(ctx, courseID, courseRole.Name)
+}
+
+// GetCoursePhaseGrants lists the course phases in which the role has additional grants
+func GetCoursePhaseGrants(ctx context.Context, courseID, courseRoleID uuid.UUID) ([]courseRoleDTO.CoursePhaseGrant, error) {
+	if _, err := getCourseRole(ctx, &CourseRoleServiceSingleton.queries, courseID, courseRoleID); err != nil {
+		return nil, err
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	grants, err := CourseRoleServiceSingleton.queries.GetCoursePhaseGrantsByCourseRole(ctxWithTimeout, db.GetCoursePhaseGrantsByCourseRoleParams{
+		CourseRoleID: courseRoleID,
+		CourseID:     courseID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtoGrants := make([]courseRoleDTO.CoursePhaseGrant, 0, len(grants))
+	for _, grant := range grants {
+		dtoGrants = append(dtoGrants, courseRoleDTO.GetCoursePhaseGrantDTOFromDBModel(grant))
+	}
+	return dtoGrants, nil
+}
+
+// UpdateCoursePhaseGrant grants the role built-in roles or permissions in a single course phase of its course
+func UpdateCoursePhaseGrant(ctx context.Context, courseID, courseRoleID, coursePhaseID uuid.UUID, request courseRoleDTO.UpdateCoursePhaseGrant) (courseRoleDTO.CoursePhaseGrant, error) {
+	if _, err := getCourseRole(ctx, &CourseRoleServiceSingleton.queries, courseID, courseRoleID); err != nil {
+		return courseRoleDTO.CoursePhaseGrant{}, err
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	grant, err := CourseRoleServiceSingleton.queries.UpsertCoursePhaseGrant(ctxWithTimeout, db.UpsertCoursePhaseGrantParams{
+		Grants:        normalizePermissions(request.Grants),
+		CourseRoleID:  courseRoleID,
+		CoursePhaseID: coursePhaseID,
+		CourseID:      courseID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return courseRoleDTO.CoursePhaseGrant{}, ErrCoursePhaseNotFound
+	}
+	if err != nil {
+		return courseRoleDTO.CoursePhaseGrant{}, err
+	}
+	return courseRoleDTO.GetCoursePhaseGrantDTOFromDBModel(grant), nil
+}
+
+// DeleteCoursePhaseGrant revokes the access of the role to the course phase, grants of the whole course are kept
+func DeleteCoursePhaseGrant(ctx context.Context, courseID, courseRoleID, coursePhaseID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	deleted, err := CourseRoleServiceSingleton.queries.DeleteCoursePhaseGrant(ctxWithTimeout, db.DeleteCoursePhaseGrantParams{
+		CourseRoleID:  courseRoleID,
+		CoursePhaseID: coursePhaseID,
+		CourseID:      courseID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrCoursePhaseGrantNotFound
+	}
+	return nil
+}
+
+func getCourseRole(ctx context.Context, queries *db.Queries, courseID, courseRoleID uuid.UUID) (db.CourseRole, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	courseRole, err := queries.GetCourseRole(ctxWithTimeout, db.GetCourseRoleParams{ID: courseRoleID, CourseID: courseID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return db.CourseRole{}, ErrCourseRoleNotFound
+	}
+	return courseRole, err
+}
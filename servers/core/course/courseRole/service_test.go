@@ -0,0 +1,198 @@
+package courseRole
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	"github.com/prompt-edu/prompt/servers/core/course/courseRole/courseRoleDTO"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CourseRoleServiceTestSuite struct {
+	suite.Suite
+	ctx               context.Context
+	cleanup           func()
+	courseRoleService CourseRoleService
+	keycloakRoles     map[string]bool
+	keycloakError     error
+}
+
+func (suite *CourseRoleServiceTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	testDB, cleanup, err := sdkTestUtils.SetupTestDB(suite.ctx, "../../database_dumps/copy_course_test.sql", func(conn *pgxpool.Pool) *db.Queries { return db.New(conn) })
+	if err != nil {
+		log.Fatalf("Failed to set up test database: %v", err)
+	}
+
+	suite.cleanup = cleanup
+	suite.keycloakRoles = map[string]bool{}
+	suite.courseRoleService = CourseRoleService{
+		queries: *testDB.Queries,
+		conn:    testDB.Conn,
+		createKeycloakRole: func(ctx context.Context, courseID uuid.UUID, roleName string) error {
+			if suite.keycloakError != nil {
+				return suite.keycloakError
+			}
+			suite.keycloakRoles[courseID.String()+"/"+roleName] = true
+			return nil
+		},
+		deleteKeycloakRole: func(ctx context.Context, courseID uuid.UUID, roleName string) error {
+			delete(suite.keycloakRoles, courseID.String()+"/"+roleName)
+			return nil
+		},
+	}
+	CourseRoleServiceSingleton = &suite.courseRoleService
+	permissionValidation.InitValidationService(*testDB.Queries, testDB.Conn)
+}
+
+func (suite *CourseRoleServiceTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *CourseRoleServiceTestSuite) TestCreateCourseRole() {
+	courseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88dd")
+	courseRole, err := CreateCourseRole(suite.ctx, courseID, courseRoleDTO.CreateCourseRole{
+		Name:        "Tutor",
+		Description: "Assesses the intro course",
+		Permissions: []string{permissionValidation.CanViewParticipants, permissionValidation.CanAssess, permissionValidation.CanAssess},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{permissionValidation.CanAssess, permissionValidation.CanViewParticipants}, courseRole.Permissions)
+	assert.True(suite.T(), suite.keycloakRoles[courseID.String()+"/Tutor"], "Expected the keycloak role to be created")
+
+	_, err = CreateCourseRole(suite.ctx, courseID, courseRoleDTO.CreateCourseRole{Name: "Tutor", Permissions: []string{permissionValidation.CanAssess}})
+	assert.ErrorIs(suite.T(), err, ErrDuplicateCourseRole)
+
+	// the permissions of the role apply to members of the Keycloak role
+	hasPermission, err := suite.courseRoleService.queries.HasCourseRolePermission(suite.ctx, db.HasCourseRolePermissionParams{
+		RoleStrings: []string{"ss25-Master Test-cr-Tutor"},
+		Permission:  permissionValidation.CanAssess,
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), hasPermission)
+
+	hasPermission, err = suite.courseRoleService.queries.HasCourseRolePermission(suite.ctx, db.HasCourseRolePermissionParams{
+		RoleStrings: []string{"ss25-Master Test-cr-Tutor"},
+		Permission:  permissionValidation.CanViewApplications,
+	})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), hasPermission)
+}
+
+func (suite *CourseRoleServiceTestSuite) TestCreateCourseRole_KeycloakFailure() {
+	courseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88dd")
+	suite.keycloakError = errors.New("keycloak unavailable")
+	defer func() { suite.keycloakError = nil }()
+
+	_, err := CreateCourseRole(suite.ctx, courseID, courseRoleDTO.CreateCourseRole{Name: "Grader", Permissions: []string{permissionValidation.CanAssess}})
+	assert.Error(suite.T(), err)
+
+	courseRoles, err := GetCourseRoles(suite.ctx, courseID)
+	assert.NoError(suite.T(), err)
+	for _, courseRole := range courseRoles {
+		assert.NotEqual(suite.T(), "Grader", courseRole.Name, "Expected the role to be rolled back")
+	}
+}
+
+func (suite *CourseRoleServiceTestSuite) TestUpdateAndDeleteCourseRole() {
+	courseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88ee")
+	courseRole, err := CreateCourseRole(suite.ctx, courseID, courseRoleDTO.CreateCourseRole{Name: "Mailer", Permissions: []string{permissionValidation.CanSendMail}})
+	assert.NoError(suite.T(), err)
+
+	updated, err := UpdateCourseRole(suite.ctx, courseID, courseRole.ID, courseRoleDTO.UpdateCourseRole{
+		Description: "Sends the status mails",
+		Permissions: []string{permissionValidation.CanSendMail, permissionValidation.CanViewApplications},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Sends the status mails", updated.Description)
+	assert.Equal(suite.T(), []string{permissionValidation.CanSendMail, permissionValidation.CanViewApplications}, updated.Permissions)
+
+	// roles cannot be changed through another course
+	otherCourseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88dd")
+	_, err = UpdateCourseRole(suite.ctx, otherCourseID, courseRole.ID, courseRoleDTO.UpdateCourseRole{Permissions: []string{permissionValidation.CanAssess}})
+	assert.ErrorIs(suite.T(), err, ErrCourseRoleNotFound)
+	assert.ErrorIs(suite.T(), DeleteCourseRole(suite.ctx, otherCourseID, courseRole.ID), ErrCourseRoleNotFound)
+
+	assert.NoError(suite.T(), DeleteCourseRole(suite.ctx, courseID, courseRole.ID))
+	assert.False(suite.T(), suite.keycloakRoles[courseID.String()+"/Mailer"], "Expected the keycloak role to be deleted")
+	assert.ErrorIs(suite.T(), DeleteCourseRole(suite.ctx, courseID, courseRole.ID), ErrCourseRoleNotFound)
+}
+
+func (suite *CourseRoleServiceTestSuite) TestCoursePhaseGrants() {
+	courseID := uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88dd")
+	interviewPhaseID := uuid.MustParse("0bf6eb6c-ff6f-40f4-af63-a005e2c8d123")
+	applicationPhaseID := uuid.MustParse("bd727106-2dc0-4c44-a804-2efde26101ae")
+
+	// a role without permissions in the whole course only gets the grants of its course phases
+	courseRole, err := CreateCourseRole(suite.ctx, courseID, courseRoleDTO.CreateCourseRole{Name: "InterviewTutor"})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), courseRole.Permissions)
+
+	grant, err := UpdateCoursePhaseGrant(suite.ctx, courseID, courseRole.ID, interviewPhaseID, courseRoleDTO.UpdateCoursePhaseGrant{
+		Grants: []string{permissionValidation.CourseEditor, permissionValidation.CanAssess, permissionValidation.CourseEditor},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{permissionValidation.CourseEditor, permissionValidation.CanAssess}, grant.Grants)
+
+	hasGrant, err := suite.courseRoleService.queries.HasCoursePhaseGrant(suite.ctx, db.HasCoursePhaseGrantParams{
+		CoursePhaseID: interviewPhaseID,
+		RoleStrings:   []string{"ss25-Master Test-cr-InterviewTutor"},
+		Grants:        []string{permissionValidation.CourseLecturer, permissionValidation.CourseEditor},
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), hasGrant)
+
+	hasGrant, err = suite.courseRoleService.queries.HasCoursePhaseGrant(suite.ctx, db.HasCoursePhaseGrantParams{
+		CoursePhaseID: applicationPhaseID,
+		RoleStrings:   []string{"ss25-Master Test-cr-InterviewTutor"},
+		Grants:        []string{permissionValidation.CourseLecturer, permissionValidation.CourseEditor},
+	})
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), hasGrant, "grants only apply in their course phase")
+
+	// handlers resolve the role of the user the same way as the permission middleware
+	userRoles := map[string]bool{"ss25-Master Test-cr-InterviewTutor": true}
+	isEditor, err := permissionValidation.HasCoursePhaseRole(suite.ctx, userRoles, "ss25-Master Test", interviewPhaseID, permissionValidation.PromptAdmin, permissionValidation.CourseEditor)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), isEditor)
+	isEditor, err = permissionValidation.HasCoursePhaseRole(suite.ctx, userRoles, "ss25-Master Test", applicationPhaseID, permissionValidation.PromptAdmin, permissionValidation.CourseEditor)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), isEditor)
+	isLecturer, err := permissionValidation.HasCoursePhaseRole(suite.ctx, userRoles, "ss25-Master Test", interviewPhaseID, permissionValidation.PromptAdmin, permissionValidation.CourseLecturer)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), isLecturer, "an editor grant does not make the user a lecturer")
+
+	// updating a grant replaces it
+	_, err = UpdateCoursePhaseGrant(suite.ctx, courseID, courseRole.ID, interviewPhaseID, courseRoleDTO.UpdateCoursePhaseGrant{
+		Grants: []string{permissionValidation.CanViewParticipants},
+	})
+	assert.NoError(suite.T(), err)
+	grants, err := GetCoursePhaseGrants(suite.ctx, courseID, courseRole.ID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), grants, 1)
+	assert.Equal(suite.T(), []string{permissionValidation.CanViewParticipants}, grants[0].Grants)
+
+	// course phases of other courses cannot be granted
+	otherCourseRole, err := CreateCourseRole(suite.ctx, uuid.MustParse("c1f8060d-7381-4b64-a6ea-5ba8e8ac88ee"), courseRoleDTO.CreateCourseRole{Name: "InterviewTutor"})
+	assert.NoError(suite.T(), err)
+	_, err = UpdateCoursePhaseGrant(suite.ctx, otherCourseRole.CourseID, otherCourseRole.ID, interviewPhaseID, courseRoleDTO.UpdateCoursePhaseGrant{
+		Grants: []string{permissionValidation.CourseEditor},
+	})
+	assert.ErrorIs(suite.T(), err, ErrCoursePhaseNotFound)
+
+	assert.NoError(suite.T(), DeleteCoursePhaseGrant(suite.ctx, courseID, courseRole.ID, interviewPhaseID))
+	assert.ErrorIs(suite.T(), DeleteCoursePhaseGrant(suite.ctx, courseID, courseRole.ID, interviewPhaseID), ErrCoursePhaseGrantNotFound)
+}
+
+func TestCourseRoleServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CourseRoleServiceTestSuite))
+}
@@ -0,0 +1,63 @@
+package courseRole
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/prompt-edu/prompt/servers/core/course/courseRole/courseRoleDTO"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+)
+
+// role names become part of Keycloak group paths and role names
+var courseRoleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,50}$`)
+
+var reservedCourseRoleNames = []string{permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CourseStudent}
+
+func validateCreateCourseRole(request courseRoleDTO.CreateCourseRole) error {
+	if !courseRoleNamePattern.MatchString(request.Name) {
+		return errors.New("validation error: the role name must consist of 1 to 50 letters, digits or underscores")
+	}
+	for _, reservedName := range reservedCourseRoleNames {
+		if strings.EqualFold(request.Name, reservedName) {
+			return fmt.Errorf("validation error: %s is a built-in course role", reservedName)
+		}
+	}
+	return validatePermissions(request.Permissions)
+}
+
+func validateUpdateCourseRole(request courseRoleDTO.UpdateCourseRole) error {
+	return validatePermissions(request.Permissions)
+}
+
+// validatePermissions allows roles without permissions, they only get the grants of their course phases
+func validatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !permissionValidation.IsCoursePermission(permission) {
+			return fmt.Errorf("validation error: unknown permission %q, available permissions are %s", permission, strings.Join(permissionValidation.CoursePermissions, ", "))
+		}
+	}
+	return nil
+}
+
+func validateUpdateCoursePhaseGrant(request courseRoleDTO.UpdateCoursePhaseGrant) error {
+	if len(request.Grants) == 0 {
+		return errors.New("validation error: at least one grant is required, delete the grant to revoke the access to the course phase")
+	}
+	for _, grant := range request.Grants {
+		if !permissionValidation.IsCoursePhaseGrant(grant) {
+			return fmt.Errorf("validation error: unknown grant %q, available grants are %s, %s, %s", grant, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, strings.Join(permissionValidation.CoursePermissions, ", "))
+		}
+	}
+	return nil
+}
+
+// normalizePermissions sorts the permissions and removes duplicates.
+// It never returns nil, as the permissions of a role must not be NULL.
+func normalizePermissions(permissions []string) []string {
+	normalized := append([]string{}, permissions...)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,98 @@
+package courseRole
+
+import (
+	"testing"
+
+	"github.com/prompt-edu/prompt/servers/core/course/courseRole/courseRoleDTO"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCreateCourseRole(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         courseRoleDTO.CreateCourseRole
+		expectedError string
+	}{
+		{
+			name:          "valid role",
+			input:         courseRoleDTO.CreateCourseRole{Name: "Tutor", Permissions: []string{"can_assess", "can_view_participants"}},
+			expectedError: "",
+		},
+		{
+			name:          "missing name",
+			input:         courseRoleDTO.CreateCourseRole{Permissions: []string{"can_assess"}},
+			expectedError: "validation error: the role name must consist of 1 to 50 letters, digits or underscores",
+		},
+		{
+			name:          "name with separator",
+			input:         courseRoleDTO.CreateCourseRole{Name: "cr-Tutor", Permissions: []string{"can_assess"}},
+			expectedError: "validation error: the role name must consist of 1 to 50 letters, digits or underscores",
+		},
+		{
+			name:          "built-in role",
+			input:         courseRoleDTO.CreateCourseRole{Name: "editor", Permissions: []string{"can_assess"}},
+			expectedError: "validation error: Editor is a built-in course role",
+		},
+		{
+			name:          "role with course phase grants only",
+			input:         courseRoleDTO.CreateCourseRole{Name: "Tutor"},
+			expectedError: "",
+		},
+		{
+			name:          "unknown permission",
+			input:         courseRoleDTO.CreateCourseRole{Name: "Tutor", Permissions: []string{"can_delete_course"}},
+			expectedError: `validation error: unknown permission "can_delete_course", available permissions are can_view_applications, can_assess, can_view_participants, can_view_notes, can_send_mail`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCreateCourseRole(tt.input)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateCoursePhaseGrant(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         courseRoleDTO.UpdateCoursePhaseGrant
+		expectedError string
+	}{
+		{
+			name:          "valid grants",
+			input:         courseRoleDTO.UpdateCoursePhaseGrant{Grants: []string{"Editor", "can_assess"}},
+			expectedError: "",
+		},
+		{
+			name:          "missing grants",
+			input:         courseRoleDTO.UpdateCoursePhaseGrant{},
+			expectedError: "validation error: at least one grant is required, delete the grant to revoke the access to the course phase",
+		},
+		{
+			name:          "student role",
+			input:         courseRoleDTO.UpdateCoursePhaseGrant{Grants: []string{"Student"}},
+			expectedError: `validation error: unknown grant "Student", available grants are Lecturer, Editor, can_view_applications, can_assess, can_view_participants, can_view_notes, can_send_mail`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUpdateCoursePhaseGrant(tt.input)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestNormalizePermissions(t *testing.T) {
+	assert.Equal(t, []string{"can_assess", "can_send_mail"}, normalizePermissions([]string{"can_send_mail", "can_assess", "can_send_mail"}))
+	assert.NotNil(t, normalizePermissions(nil))
+}
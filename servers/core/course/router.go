@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/prompt-edu/prompt/servers/core/course/courseDTO"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 	log "github.com/sirupsen/logrus"
@@ -76,10 +77,16 @@ func getOwnCourses(c *gin.Context) {
 
 // getAllCourses godoc
 // @Summary Get all courses
-// @Description Get all courses accessible to the user
+// @Description Get the courses accessible to the user, all of them unless a limit is given. The total count is sent in the X-Total-Count header, the next page in the Link header. Query parameters metadata.<key>=<value> filter on the restricted and student readable data.
 // @Tags courses
 // @Produce json
+// @Param limit query int false "Maximum number of courses, at most 500"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Param sortBy query string false "Sort field" Enums(name, semesterTag, startDate)
+// @Param sortOrder query string false "Sort order" Enums(asc, desc)
+// @Param fields query string false "Comma-separated course fields to return, the course phases are only loaded if requested, e.g. id,name,semesterTag"
 // @Success 200 {array} courseDTO.CourseWithPhases
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /courses/ [get]
@@ -92,13 +99,29 @@ func getAllCourses(c *gin.Context) {
 
 	userRoles := rolesVal.(map[string]bool)
 
-	courses, err := GetAllCourses(c, userRoles)
+	params, err := listQuery.Parse(c, listQuery.Options{
+		SortFields:     []string{"name", "semesterTag", "startDate"},
+		MetadataFilter: true,
+	})
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	courses, total, nextCursor, err := GetAllCourses(c, userRoles, params)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, courses)
+	projectedCourses, err := listQuery.Project(courses, params.Fields)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	listQuery.SetHeaders(c, total, nextCursor)
+	c.IndentedJSON(http.StatusOK, projectedCourses)
 }
 
 // getCourseByID godoc
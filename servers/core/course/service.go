@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +15,8 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/course/courseDTO"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	log "github.com/sirupsen/logrus"
 )
@@ -41,7 +44,10 @@ func GetOwnCourseIDs(ctx context.Context, matriculationNumber, universityLogin s
 	return courses, err
 }
 
-func GetAllCourses(ctx context.Context, userRoles map[string]bool) ([]courseDTO.CourseWithPhases, error) {
+// GetAllCourses returns a page of the courses the user is allowed to see that match the list parameters,
+// the total number of matching courses and the cursor of the next page.
+// The course list of a user is short, so the courses are filtered and paged after loading them.
+func GetAllCourses(ctx context.Context, userRoles map[string]bool, params listQuery.Params) ([]courseDTO.CourseWithPhases, int64, string, error) {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
 	defer cancel()
 
@@ -52,7 +58,7 @@ func GetAllCourses(ctx context.Context, userRoles map[string]bool) ([]courseDTO.
 		// get all courses
 		courses, err = CourseServiceSingleton.queries.GetAllActiveCoursesAdmin(ctxWithTimeout)
 		if err != nil {
-			return nil, err
+			return nil, 0, "", err
 		}
 	} else {
 		// get restricted courses
@@ -64,7 +70,7 @@ func GetAllCourses(ctx context.Context, userRoles map[string]bool) ([]courseDTO.
 		}
 		coursesRestricted, err := CourseServiceSingleton.queries.GetAllActiveCoursesRestricted(ctxWithTimeout, userRolesArray)
 		if err != nil {
-			return nil, err
+			return nil, 0, "", err
 		}
 
 		for _, course := range coursesRestricted {
@@ -72,26 +78,50 @@ func GetAllCourses(ctx context.Context, userRoles map[string]bool) ([]courseDTO.
 		}
 	}
 
-	// Get all course phases for each course
 	dtoCourses := make([]courseDTO.CourseWithPhases, 0, len(courses))
 	for _, course := range courses {
-		// Get all course phases for the course
-		coursePhases, err := GetCoursePhasesForCourseID(ctx, course.ID)
+		courseWithPhases, err := courseDTO.GetCourseWithPhasesDTOFromDBModel(course)
 		if err != nil {
-			return nil, err
+			return nil, 0, "", err
 		}
 
-		courseWithPhases, err := courseDTO.GetCourseWithPhasesDTOFromDBModel(course)
-		if err != nil {
-			return nil, err
+		if listQuery.MatchesMetadata(params.Metadata, courseWithPhases.RestrictedData, courseWithPhases.StudentReadableData) {
+			dtoCourses = append(dtoCourses, courseWithPhases)
 		}
+	}
 
-		courseWithPhases.CoursePhases = coursePhases
+	sortBy := params.SortByOrDefault("name")
+	dtoCourses, total, nextCursor := listQuery.Paginate(params, dtoCourses, func(course courseDTO.CourseWithPhases) (string, uuid.UUID) {
+		return getCourseSortKey(course, sortBy), course.ID
+	})
 
-		dtoCourses = append(dtoCourses, courseWithPhases)
+	// Get all course phases for each course of the page, unless the client did not ask for them
+	if !params.Includes("coursePhases") {
+		return dtoCourses, total, nextCursor, nil
+	}
+	for i := range dtoCourses {
+		coursePhases, err := GetCoursePhasesForCourseID(ctx, dtoCourses[i].ID)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		dtoCourses[i].CoursePhases = coursePhases
 	}
 
-	return dtoCourses, nil
+	return dtoCourses, total, nextCursor, nil
+}
+
+func getCourseSortKey(course courseDTO.CourseWithPhases, sortBy string) string {
+	switch sortBy {
+	case "semesterTag":
+		return strings.ToLower(course.SemesterTag)
+	case "startDate":
+		if !course.StartDate.Valid {
+			return ""
+		}
+		return course.StartDate.Time.Format(time.DateOnly)
+	default:
+		return strings.ToLower(course.Name)
+	}
 }
 
 func GetCoursePhasesForCourseID(ctx context.Context, courseID uuid.UUID) ([]coursePhaseDTO.CoursePhaseSequence, error) {
@@ -179,6 +209,11 @@ func CreateCourse(ctx context.Context, course courseDTO.CreateCourse, requesterI
 		return courseDTO.Course{}, err
 	}
 
+	// store the requester as course lecturer if the course roles are kept in the database
+	if err := keycloakTokenVerifier.AddCourseLecturer(ctx, qtx, createdCourse.ID, requesterID); err != nil {
+		return courseDTO.Course{}, err
+	}
+
 	// create keycloak roles - also add the requester to the course lecturer role
 	err = CourseServiceSingleton.createCourseGroupsAndRoles(ctx, createdCourse.Name, createdCourse.SemesterTag.String, requesterID)
 	if err != nil {
@@ -189,6 +224,7 @@ func CreateCourse(ctx context.Context, course courseDTO.CreateCourse, requesterI
 	if err := tx.Commit(ctx); err != nil {
 		return courseDTO.Course{}, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	keycloakTokenVerifier.InvalidateRoleCache()
 	return courseDTO.GetCourseDTOFromDBModel(createdCourse)
 }
 
@@ -443,6 +479,7 @@ func DeleteCourse(ctx context.Context, courseID uuid.UUID) error {
 		log.Error(err)
 		return errors.New("failed to delete course")
 	}
+	keycloakTokenVerifier.InvalidateRoleCache()
 
 	return nil
 }
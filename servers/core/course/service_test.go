@@ -2,6 +2,8 @@ package course
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/coursePhase"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/meta"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	"github.com/stretchr/testify/assert"
@@ -61,7 +64,7 @@ func (suite *CourseServiceTestSuite) TearDownSuite() {
 }
 
 func (suite *CourseServiceTestSuite) TestGetAllCourses() {
-	courses, err := GetAllCourses(suite.ctx, map[string]bool{permissionValidation.PromptAdmin: true})
+	courses, _, _, err := GetAllCourses(suite.ctx, map[string]bool{permissionValidation.PromptAdmin: true}, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), len(courses), 10, "Expected all courses")
 
@@ -71,8 +74,34 @@ func (suite *CourseServiceTestSuite) TestGetAllCourses() {
 	}
 }
 
+func (suite *CourseServiceTestSuite) TestGetAllCoursesPaged() {
+	admin := map[string]bool{permissionValidation.PromptAdmin: true}
+	params := listQuery.Params{Limit: 4, SortBy: "name", Fields: []string{"id", "name"}}
+
+	names := make([]string, 0)
+	for {
+		courses, total, nextCursor, err := GetAllCourses(suite.ctx, admin, params)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), int64(10), total, "Expected the total to count all courses")
+		assert.LessOrEqual(suite.T(), len(courses), 4)
+		for _, course := range courses {
+			assert.Nil(suite.T(), course.CoursePhases, "Expected the course phases to be skipped")
+			names = append(names, strings.ToLower(course.Name))
+		}
+		if nextCursor == "" {
+			break
+		}
+		cursor, err := listQuery.DecodeCursor(nextCursor)
+		assert.NoError(suite.T(), err)
+		params.Cursor = &cursor
+	}
+
+	assert.Len(suite.T(), names, 10)
+	assert.True(suite.T(), slices.IsSorted(names), "Expected the courses to be sorted by name")
+}
+
 func (suite *CourseServiceTestSuite) TestGetAllCoursesWithRestriction() {
-	courses, err := GetAllCourses(suite.ctx, map[string]bool{"ios2425-Another TEst-Lecturer": true})
+	courses, _, _, err := GetAllCourses(suite.ctx, map[string]bool{"ios2425-Another TEst-Lecturer": true}, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 1, len(courses), "Expected to get only one course")
 
@@ -83,7 +112,7 @@ func (suite *CourseServiceTestSuite) TestGetAllCoursesWithRestriction() {
 }
 
 func (suite *CourseServiceTestSuite) TestGetAllCoursesWithStudent() {
-	courses, err := GetAllCourses(suite.ctx, map[string]bool{"ios2425-Another TEst-Student": true})
+	courses, _, _, err := GetAllCourses(suite.ctx, map[string]bool{"ios2425-Another TEst-Student": true}, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), 1, len(courses), "Expected to get only one course")
 
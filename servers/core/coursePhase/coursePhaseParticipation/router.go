@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation/coursePhaseParticipationDTO"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 	log "github.com/sirupsen/logrus"
@@ -21,11 +22,11 @@ import (
 func setupCoursePhaseParticipationRouter(routerGroup *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionIDMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
 	courseParticipation := routerGroup.Group("/course_phases/:uuid/participations", authMiddleware())
 	courseParticipation.GET("/self", permissionIDMiddleware(permissionValidation.CourseStudent), getOwnCoursePhaseParticipation)
-	courseParticipation.GET("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getParticipationsForCoursePhase)
-	courseParticipation.GET("/:course_participation_id", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getParticipation)
-	courseParticipation.PUT("/:course_participation_id", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateCoursePhaseParticipation)
+	courseParticipation.GET("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewParticipants), getParticipationsForCoursePhase)
+	courseParticipation.GET("/:course_participation_id", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CanViewParticipants), getParticipation)
+	courseParticipation.PUT("/:course_participation_id", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CanAssess), updateCoursePhaseParticipation)
 	// allow to modify multiple at once
-	courseParticipation.PUT("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), updateBatchCoursePhaseParticipation)
+	courseParticipation.PUT("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CanAssess), updateBatchCoursePhaseParticipation)
 
 	// get the students data of the participations
 	courseParticipation.GET("/students", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getStudentsOfCoursePhase)
@@ -73,10 +74,16 @@ func getOwnCoursePhaseParticipation(c *gin.Context) {
 
 // getParticipationsForCoursePhase godoc
 // @Summary Get all participations for a course phase
-// @Description Get all participations for a given course phase
+// @Description Get the participations for a given course phase, all of them unless a limit is given. The total count is sent in the X-Total-Count header, the next page in the Link header. Query parameters metadata.<key>=<value> filter on the restricted and student readable data.
 // @Tags course_phase_participation
 // @Produce json
 // @Param uuid path string true "Course Phase UUID"
+// @Param limit query int false "Maximum number of participations"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Param sortBy query string false "Sort field: lastName, firstName, email or passStatus"
+// @Param sortOrder query string false "asc or desc"
+// @Param passStatus query string false "Only participations with this pass status"
+// @Param fields query string false "Comma-separated participation fields to return, e.g. student.lastName,passStatus"
 // @Success 200 {object} coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
@@ -88,13 +95,34 @@ func getParticipationsForCoursePhase(c *gin.Context) {
 		return
 	}
 
-	courseParticipations, err := GetAllParticipationsForCoursePhase(c, id)
+	params, err := listQuery.Parse(c, listQuery.Options{
+		SortFields:       []string{"lastName", "firstName", "email", "passStatus"},
+		PassStatusFilter: true,
+		MetadataFilter:   true,
+	})
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	courseParticipations, total, nextCursor, err := GetAllParticipationsForCoursePhase(c, id, params)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, courseParticipations)
+	// the fields only select from the participations, the resolutions are always sent
+	participations, err := listQuery.Project(courseParticipations.Participations, params.Fields)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	listQuery.SetHeaders(c, total, nextCursor)
+	c.IndentedJSON(http.StatusOK, gin.H{
+		"participations": participations,
+		"resolutions":    courseParticipations.Resolutions,
+	})
 }
 
 // getParticipation godoc
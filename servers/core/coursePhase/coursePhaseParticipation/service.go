@@ -14,6 +14,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/resolution"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/resolution/resolutionDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 	log "github.com/sirupsen/logrus"
@@ -45,17 +46,27 @@ func GetOwnCoursePhaseParticipation(ctx context.Context, coursePhaseID uuid.UUID
 	return participationDTO, nil
 }
 
-func GetAllParticipationsForCoursePhase(ctx context.Context, coursePhaseID uuid.UUID) (coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions, error) {
-	coursePhaseParticipations, err := CoursePhaseParticipationServiceSingleton.queries.GetAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious(ctx, coursePhaseID)
+// GetAllParticipationsForCoursePhase returns a page of the participations matching the list parameters,
+// the total number of matching participations and the cursor of the next page
+func GetAllParticipationsForCoursePhase(ctx context.Context, coursePhaseID uuid.UUID, params listQuery.Params) (coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions, int64, string, error) {
+	coursePhaseParticipations, err := getParticipationRows(ctx, coursePhaseID, params)
 	if err != nil {
-		return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, err
+		return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, 0, "", err
 	}
 
+	var total int64
+	if len(coursePhaseParticipations) > 0 {
+		total = coursePhaseParticipations[0].TotalCount
+	}
+	coursePhaseParticipations, nextCursor := listQuery.TrimPage(params, coursePhaseParticipations, func(row db.GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousRow) (string, uuid.UUID) {
+		return row.SortKey, row.CourseParticipationID
+	})
+
 	participationDTOs := make([]coursePhaseParticipationDTO.GetAllCPPsForCoursePhase, 0, len(coursePhaseParticipations))
 	for _, coursePhaseParticipation := range coursePhaseParticipations {
 		dto, err := coursePhaseParticipationDTO.GetAllCPPsForCoursePhaseDTOFromDBModel(coursePhaseParticipation)
 		if err != nil {
-			return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, err
+			return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, 0, "", err
 		}
 		participationDTOs = append(participationDTOs, dto)
 	}
@@ -63,24 +74,37 @@ func GetAllParticipationsForCoursePhase(ctx context.Context, coursePhaseID uuid.
 	// Get required resolutions
 	resolutions, err := CoursePhaseParticipationServiceSingleton.queries.GetResolutionsForCoursePhase(ctx, coursePhaseID)
 	if err != nil {
-		return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, err
+		return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, 0, "", err
 	}
 
 	resolutionDTOs := resolutionDTO.GetParticipationResolutionsDTOFromDBModels(resolutions)
 	resolutionDTOs, err = resolution.ReplaceResolutionURLs(ctx, resolutionDTOs)
 	if err != nil {
 		log.Error(err)
-		return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, errors.New("failed to replace resolution URLs")
+		return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{}, 0, "", errors.New("failed to replace resolution URLs")
 	}
 
 	return coursePhaseParticipationDTO.CoursePhaseParticipationsWithResolutions{
 		Participations: participationDTOs,
 		Resolutions:    resolutionDTOs,
-	}, nil
+	}, total, nextCursor, nil
+}
+
+func getParticipationRows(ctx context.Context, coursePhaseID uuid.UUID, params listQuery.Params) ([]db.GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousRow, error) {
+	return CoursePhaseParticipationServiceSingleton.queries.GetAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious(ctx, db.GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousParams{
+		CoursePhaseID: coursePhaseID,
+		SortBy:        params.SortByOrDefault("lastName"),
+		PassStatus:    params.PassStatusFilter(),
+		Metadata:      params.MetadataFilter(),
+		CursorID:      params.CursorID(),
+		Descending:    params.Descending,
+		CursorKey:     params.CursorKey(),
+		PageSize:      params.PageSize(),
+	})
 }
 
 func GetCoursePhaseParticipation(ctx context.Context, coursePhaseID uuid.UUID, courseParticipationID uuid.UUID) (coursePhaseParticipationDTO.CoursePhaseParticipationWithResolution, error) {
-	coursePhaseParticipations, err := CoursePhaseParticipationServiceSingleton.queries.GetAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious(ctx, coursePhaseID)
+	coursePhaseParticipations, err := getParticipationRows(ctx, coursePhaseID, listQuery.Params{})
 	if err != nil {
 		log.Error(err)
 		return coursePhaseParticipationDTO.CoursePhaseParticipationWithResolution{}, err
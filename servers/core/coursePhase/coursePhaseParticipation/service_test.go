@@ -11,6 +11,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation/coursePhaseParticipationDTO"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/resolution"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/meta"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -50,7 +51,7 @@ func (suite *CoursePhaseParticipationTestSuite) TearDownSuite() {
 func (suite *CoursePhaseParticipationTestSuite) TestGetAllParticipationsForCoursePhase() {
 	coursePhaseID := uuid.MustParse("4e736d05-c125-48f0-8fa0-848b03ca6908")
 
-	participationsWithResolution, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID)
+	participationsWithResolution, _, _, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	assert.Greater(suite.T(), len(participationsWithResolution.Participations), 0, "Expected participations for the course phase")
 
@@ -59,10 +60,54 @@ func (suite *CoursePhaseParticipationTestSuite) TestGetAllParticipationsForCours
 	}
 }
 
+func (suite *CoursePhaseParticipationTestSuite) TestGetAllParticipationsForCoursePhasePaged() {
+	coursePhaseID := uuid.MustParse("4e736d05-c125-48f0-8fa0-848b03ca6908")
+
+	all, total, nextCursor, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID, listQuery.Params{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(len(all.Participations)), total)
+	assert.Empty(suite.T(), nextCursor, "Expected no next page without limit")
+
+	seen := make([]uuid.UUID, 0, len(all.Participations))
+	params := listQuery.Params{Limit: 1}
+	for {
+		page, pageTotal, pageCursor, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID, params)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), total, pageTotal, "Expected the total count to ignore the page")
+		assert.LessOrEqual(suite.T(), len(page.Participations), 1)
+		for _, participation := range page.Participations {
+			seen = append(seen, participation.CourseParticipationID)
+		}
+		if pageCursor == "" {
+			break
+		}
+		cursor, err := listQuery.DecodeCursor(pageCursor)
+		assert.NoError(suite.T(), err)
+		params.Cursor = &cursor
+	}
+
+	expected := make([]uuid.UUID, 0, len(all.Participations))
+	for _, participation := range all.Participations {
+		expected = append(expected, participation.CourseParticipationID)
+	}
+	assert.Equal(suite.T(), expected, seen, "Expected the pages to add up to the complete list")
+}
+
+func (suite *CoursePhaseParticipationTestSuite) TestGetAllParticipationsForCoursePhaseByPassStatus() {
+	coursePhaseID := uuid.MustParse("4e736d05-c125-48f0-8fa0-848b03ca6908")
+
+	participations, total, _, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID, listQuery.Params{PassStatus: db.PassStatusPassed})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(len(participations.Participations)), total)
+	for _, participation := range participations.Participations {
+		assert.Equal(suite.T(), string(db.PassStatusPassed), participation.PassStatus)
+	}
+}
+
 func (suite *CoursePhaseParticipationTestSuite) TestGetParticipationsWithPrevData() {
 	coursePhaseID := uuid.MustParse("2b1a55ad-8b1d-453f-b2b4-2373ecb35bc1")
 
-	participationsWithResolution, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID)
+	participationsWithResolution, _, _, err := GetAllParticipationsForCoursePhase(suite.ctx, coursePhaseID, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	assert.Greater(suite.T(), len(participationsWithResolution.Participations), 0, "Expected participations for the course phase")
 	for _, participation := range participationsWithResolution.Participations {
@@ -4,4 +4,12 @@ type GetCourseRoles struct {
 	CourseLecturerRole string `json:"courseLecturerRole"`
 	CourseEditorRole   string `json:"courseEditorRole"`
 	CustomRolePrefix   string `json:"customRolePrefix"`
+	// CoursePhaseRoles are custom course roles that are only granted access to this course phase
+	CoursePhaseRoles []CoursePhaseRole `json:"coursePhaseRoles"`
+}
+
+// CoursePhaseRole maps a Keycloak role to the built-in roles (Lecturer, Editor) and permissions it has in the course phase
+type CoursePhaseRole struct {
+	Role   string   `json:"role"`
+	Grants []string `json:"grants"`
 }
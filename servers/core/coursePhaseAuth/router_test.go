@@ -80,6 +80,7 @@ func (suite *CourseRouterTestSuite) TestGetCoursePhaseAuthRoles() {
 	suite.Contains(response, "courseLecturerRole")
 	suite.Contains(response, "courseEditorRole")
 	suite.Contains(response, "customRolePrefix")
+	suite.Contains(response, "coursePhaseRoles")
 }
 
 func (suite *CourseRouterTestSuite) TestGetCoursePhaseAuthRoles_InvalidUUID() {
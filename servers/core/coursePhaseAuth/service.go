@@ -28,10 +28,28 @@ func GetCourseRoles(ctx context.Context, coursePhaseID uuid.UUID) (coursePhaseAu
 		return coursePhaseAuthDTO.GetCourseRoles{}, err
 	}
 
+	grantRoles, err := CoursePhaseAuthServiceSingleton.queries.GetCoursePhaseGrantRoles(ctx, coursePhaseID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"coursePhaseID": coursePhaseID,
+			"error":         err,
+		}).Error("Failed to get course phase grants")
+		return coursePhaseAuthDTO.GetCourseRoles{}, err
+	}
+
+	coursePhaseRoles := make([]coursePhaseAuthDTO.CoursePhaseRole, 0, len(grantRoles))
+	for _, grantRole := range grantRoles {
+		coursePhaseRoles = append(coursePhaseRoles, coursePhaseAuthDTO.CoursePhaseRole{
+			Role:   grantRole.RoleString,
+			Grants: grantRole.Grants,
+		})
+	}
+
 	return coursePhaseAuthDTO.GetCourseRoles{
 		CourseLecturerRole: courseRoles.LecturerRole,
 		CourseEditorRole:   courseRoles.EditorRole,
 		CustomRolePrefix:   courseRoles.CustomRolePrefix,
+		CoursePhaseRoles:   coursePhaseRoles,
 	}, nil
 }
 
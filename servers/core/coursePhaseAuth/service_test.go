@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	"github.com/prompt-edu/prompt/servers/core/coursePhaseAuth/coursePhaseAuthDTO"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/suite"
@@ -71,6 +72,15 @@ func (suite *CoursePhaseAuthTestSuite) TestGetCourseRoles() {
 	suite.Equal(expectedLecturerRole, result.CourseLecturerRole, "Unexpected lecturer role")
 	suite.Equal(expectedEditorRole, result.CourseEditorRole, "Unexpected editor role")
 	suite.Equal(expectedCustomRolePrefix, result.CustomRolePrefix, "Unexpected custom role prefix")
+
+	// the tutors of the test course may only edit the intro course
+	suite.Equal([]coursePhaseAuthDTO.CoursePhaseRole{
+		{Role: "ios2425-TestCourse-cr-Tutor", Grants: []string{"Editor", "can_assess"}},
+	}, result.CoursePhaseRoles)
+
+	otherPhaseResult, err := GetCourseRoles(suite.ctx, uuid.MustParse("4179d58a-d00d-4fa7-94a5-397bc69fab02"))
+	suite.Require().NoError(err)
+	suite.Empty(otherPhaseResult.CoursePhaseRoles)
 }
 
 func TestCoursePhaseAuthTestSuite(t *testing.T) {
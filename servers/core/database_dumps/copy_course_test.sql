@@ -1139,6 +1139,140 @@ ALTER TABLE ONLY phase_data_dependency_graph
     ADD CONSTRAINT fk_to_phase_phase FOREIGN KEY (to_course_phase_id) REFERENCES course_phase(id) ON DELETE CASCADE;
 
 
+-- Add display conditions to application questions
+ALTER TABLE application_question_text ADD COLUMN display_condition JSONB;
+ALTER TABLE application_question_multi_select ADD COLUMN display_condition JSONB;
+ALTER TABLE application_question_file_upload ADD COLUMN display_condition JSONB;
+
+-- Add identifying questions for blind reviews
+ALTER TABLE application_question_text ADD COLUMN is_identifying BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE application_question_multi_select ADD COLUMN is_identifying BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE application_question_file_upload ADD COLUMN is_identifying BOOLEAN NOT NULL DEFAULT false;
+
+-- Add malware scanning of uploaded files
+CREATE TYPE file_scan_status AS ENUM ('pending', 'clean', 'infected', 'failed');
+ALTER TABLE files ADD COLUMN scan_status file_scan_status NOT NULL DEFAULT 'clean';
+ALTER TABLE files ADD COLUMN scan_result TEXT;
+ALTER TABLE files ADD COLUMN scanned_at TIMESTAMP;
+ALTER TABLE files ALTER COLUMN scan_status SET DEFAULT 'pending';
+
+-- Add storage quotas
+CREATE TABLE course_storage_quota (
+    course_id uuid PRIMARY KEY,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_course_storage_quota_course FOREIGN KEY (course_id) REFERENCES course(id) ON DELETE CASCADE
+);
+CREATE TABLE course_phase_storage_quota (
+    course_phase_id uuid PRIMARY KEY,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_course_phase_storage_quota_course_phase FOREIGN KEY (course_phase_id) REFERENCES course_phase(id) ON DELETE CASCADE
+);
+
+-- Add file attachments
+CREATE TABLE course_phase_participation_file (
+    course_participation_id uuid NOT NULL,
+    course_phase_id uuid NOT NULL,
+    file_id uuid NOT NULL,
+    PRIMARY KEY (course_participation_id, course_phase_id, file_id),
+    CONSTRAINT fk_course_phase_participation_file_file FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE,
+    CONSTRAINT fk_course_phase_participation_file_participation FOREIGN KEY (course_participation_id, course_phase_id) REFERENCES course_phase_participation(course_participation_id, course_phase_id) ON DELETE CASCADE
+);
+CREATE INDEX idx_course_phase_participation_file_file_id ON course_phase_participation_file(file_id);
+CREATE INDEX idx_files_tags ON files USING GIN (tags);
+
+-- Add file previews
+CREATE TABLE file_preview (
+    file_id uuid PRIMARY KEY,
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_file_preview_file FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+-- Add file versions
+ALTER TABLE files
+ADD COLUMN content_sha256 TEXT,
+ADD COLUMN previous_version_id uuid,
+ADD COLUMN version INT NOT NULL DEFAULT 1,
+ADD CONSTRAINT fk_files_previous_version FOREIGN KEY (previous_version_id) REFERENCES files(id) ON DELETE SET NULL;
+ALTER TABLE files DROP CONSTRAINT files_storage_key_key;
+CREATE INDEX idx_files_content_sha256 ON files(content_sha256) WHERE content_sha256 IS NOT NULL;
+CREATE INDEX idx_files_previous_version_id ON files(previous_version_id) WHERE previous_version_id IS NOT NULL;
+
+-- Add file encryption
+CREATE TABLE file_encryption_key (
+    id uuid PRIMARY KEY,
+    course_id uuid,
+    wrapped_key BYTEA NOT NULL,
+    master_key_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rotated_at TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_file_encryption_key_course ON file_encryption_key (COALESCE(course_id, '00000000-0000-0000-0000-000000000000'::uuid));
+ALTER TABLE files
+ADD COLUMN encryption_key_id uuid,
+ADD CONSTRAINT fk_files_encryption_key FOREIGN KEY (encryption_key_id) REFERENCES file_encryption_key(id);
+
+-- Add API tokens
+CREATE TABLE api_token (
+    id uuid PRIMARY KEY,
+    name TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    token_prefix TEXT NOT NULL,
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    roles TEXT[] NOT NULL,
+    created_by_user_id TEXT NOT NULL,
+    created_by_email TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_api_token_course ON api_token (course_id);
+
+-- Add course roles
+CREATE TABLE course_role (
+    id uuid PRIMARY KEY,
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, name)
+);
+
+-- Add course phase grants
+CREATE TABLE course_phase_grant (
+    course_role_id uuid NOT NULL REFERENCES course_role(id) ON DELETE CASCADE,
+    course_phase_id uuid NOT NULL REFERENCES course_phase(id) ON DELETE CASCADE,
+    grants TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_role_id, course_phase_id)
+);
+CREATE INDEX idx_course_phase_grant_course_phase ON course_phase_grant (course_phase_id);
+
+-- Add course staff
+CREATE TABLE course_staff (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('Lecturer', 'Editor')),
+    user_id TEXT,
+    student_id uuid REFERENCES student(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (user_id IS NOT NULL OR student_id IS NOT NULL)
+);
+CREATE UNIQUE INDEX ux_course_staff_user ON course_staff (course_id, role, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX ux_course_staff_student ON course_staff (course_id, role, student_id) WHERE student_id IS NOT NULL;
+CREATE INDEX idx_course_staff_user ON course_staff (user_id);
+CREATE INDEX idx_course_staff_student ON course_staff (student_id);
+
 --
 -- PostgreSQL database dump complete
 --
@@ -1178,3 +1178,29 @@ ALTER TABLE course_phase_type_required_input_dto
 -- Rename the dependency graph table to "participation_data_dependency_graph"
 ALTER TABLE meta_data_dependency_graph 
     RENAME TO participation_data_dependency_graph;
+
+-- Add course roles
+CREATE TABLE course_role (
+    id uuid PRIMARY KEY,
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    permissions TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, name)
+);
+
+-- Add course phase grants
+CREATE TABLE course_phase_grant (
+    course_role_id uuid NOT NULL REFERENCES course_role(id) ON DELETE CASCADE,
+    course_phase_id uuid NOT NULL REFERENCES course_phase(id) ON DELETE CASCADE,
+    grants TEXT[] NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_role_id, course_phase_id)
+);
+CREATE INDEX idx_course_phase_grant_course_phase ON course_phase_grant (course_phase_id);
+
+INSERT INTO course_role (id, course_id, name, description, permissions) VALUES ('5d1c4a0e-2f6b-4b8e-9a51-3c7e2d9f0a11', 'be780b32-a678-4b79-ae1c-80071771d254', 'Tutor', 'Edits the intro course', '{}');
+INSERT INTO course_phase_grant (course_role_id, course_phase_id, grants) VALUES ('5d1c4a0e-2f6b-4b8e-9a51-3c7e2d9f0a11', '4e736d05-c125-48f0-8fa0-848b03ca6908', '{Editor,can_assess}');
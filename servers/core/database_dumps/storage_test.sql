@@ -76,6 +76,12 @@ CREATE INDEX IF NOT EXISTS idx_files_course_phase_id ON files(course_phase_id) W
 CREATE INDEX IF NOT EXISTS idx_files_created_at ON files(created_at DESC);
 CREATE INDEX IF NOT EXISTS idx_files_deleted_at ON files(deleted_at) WHERE deleted_at IS NULL;
 
+-- Add malware scanning of uploaded files
+CREATE TYPE file_scan_status AS ENUM ('pending', 'clean', 'infected', 'failed');
+ALTER TABLE files ADD COLUMN scan_status file_scan_status NOT NULL DEFAULT 'pending';
+ALTER TABLE files ADD COLUMN scan_result TEXT;
+ALTER TABLE files ADD COLUMN scanned_at TIMESTAMP;
+
 -- Test data
 INSERT INTO users (user_id, keycloak_user_id, email, university_login, matriculation_number, first_name, last_name) VALUES
 ('11111111-1111-1111-1111-111111111111', '11111111-1111-1111-1111-111111111111', 'test.user@tum.de', 'testuser', '12345678', 'Test', 'User'),
@@ -92,3 +98,107 @@ INSERT INTO applications (id, student_id, course_iteration_id) VALUES
 
 INSERT INTO course_phase (id, course_iteration_id, phase_name) VALUES
 ('55555555-5555-5555-5555-555555555555', '44444444-4444-4444-4444-444444444444', 'Test Phase');
+
+-- Course tables as in the core schema, the storage quotas refer to them
+CREATE TABLE IF NOT EXISTS course (
+    id uuid PRIMARY KEY,
+    name text NOT NULL,
+    semester_tag text
+);
+ALTER TABLE course_phase ADD COLUMN course_id uuid REFERENCES course(id) ON DELETE CASCADE;
+ALTER TABLE course_phase ADD COLUMN name text;
+
+INSERT INTO course (id, name, semester_tag) VALUES
+('33333333-3333-3333-3333-333333333333', 'Test Course', 'ws24');
+
+UPDATE course_phase SET course_id = '33333333-3333-3333-3333-333333333333', name = phase_name;
+
+INSERT INTO course_phase (id, course_iteration_id, phase_name, course_id, name) VALUES
+('66666666-6666-6666-6666-666666666666', '44444444-4444-4444-4444-444444444444', 'Quota Phase', '33333333-3333-3333-3333-333333333333', 'Quota Phase');
+
+-- Add storage quotas
+CREATE TABLE course_storage_quota (
+    course_id uuid PRIMARY KEY,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_course_storage_quota_course FOREIGN KEY (course_id) REFERENCES course(id) ON DELETE CASCADE
+);
+CREATE TABLE course_phase_storage_quota (
+    course_phase_id uuid PRIMARY KEY,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_course_phase_storage_quota_course_phase FOREIGN KEY (course_phase_id) REFERENCES course_phase(id) ON DELETE CASCADE
+);
+
+-- Notes and course phase participations (minimal for FK constraints)
+CREATE TABLE IF NOT EXISTS note (
+    id uuid PRIMARY KEY
+);
+CREATE TABLE IF NOT EXISTS course_phase_participation (
+    course_participation_id uuid NOT NULL,
+    course_phase_id uuid NOT NULL REFERENCES course_phase(id) ON DELETE CASCADE,
+    PRIMARY KEY (course_participation_id, course_phase_id)
+);
+
+INSERT INTO note (id) VALUES
+('77777777-7777-7777-7777-777777777777');
+
+INSERT INTO course_phase_participation (course_participation_id, course_phase_id) VALUES
+('88888888-8888-8888-8888-888888888888', '55555555-5555-5555-5555-555555555555');
+
+-- Add file attachments
+CREATE TABLE note_file (
+    note_id uuid NOT NULL REFERENCES note(id) ON DELETE CASCADE,
+    file_id uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    PRIMARY KEY (note_id, file_id)
+);
+CREATE TABLE course_phase_participation_file (
+    course_participation_id uuid NOT NULL,
+    course_phase_id uuid NOT NULL,
+    file_id uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+    PRIMARY KEY (course_participation_id, course_phase_id, file_id),
+    CONSTRAINT fk_course_phase_participation_file_participation FOREIGN KEY (course_participation_id, course_phase_id) REFERENCES course_phase_participation(course_participation_id, course_phase_id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS application_answer_file_upload (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_question_id uuid NOT NULL,
+    course_participation_id uuid NOT NULL,
+    file_id uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_files_tags ON files USING GIN (tags);
+
+-- Add file previews
+CREATE TABLE file_preview (
+    file_id uuid PRIMARY KEY,
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_file_preview_file FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
+);
+
+-- Add file versions
+ALTER TABLE files
+ADD COLUMN content_sha256 TEXT,
+ADD COLUMN previous_version_id uuid,
+ADD COLUMN version INT NOT NULL DEFAULT 1,
+ADD CONSTRAINT fk_files_previous_version FOREIGN KEY (previous_version_id) REFERENCES files(id) ON DELETE SET NULL;
+ALTER TABLE files DROP CONSTRAINT files_storage_key_key;
+CREATE INDEX idx_files_content_sha256 ON files(content_sha256) WHERE content_sha256 IS NOT NULL;
+CREATE INDEX idx_files_previous_version_id ON files(previous_version_id) WHERE previous_version_id IS NOT NULL;
+
+-- Add file encryption
+CREATE TABLE file_encryption_key (
+    id uuid PRIMARY KEY,
+    course_id uuid,
+    wrapped_key BYTEA NOT NULL,
+    master_key_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rotated_at TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_file_encryption_key_course ON file_encryption_key (COALESCE(course_id, '00000000-0000-0000-0000-000000000000'::uuid));
+ALTER TABLE files
+ADD COLUMN encryption_key_id uuid,
+ADD CONSTRAINT fk_files_encryption_key FOREIGN KEY (encryption_key_id) REFERENCES file_encryption_key(id);
@@ -0,0 +1,216 @@
+--
+-- PostgreSQL database dump
+--
+
+SET statement_timeout = 0;
+SET lock_timeout = 0;
+SET idle_in_transaction_session_timeout = 0;
+SET client_encoding = 'UTF8';
+SET standard_conforming_strings = on;
+SELECT pg_catalog.set_config('search_path', 'public', false);
+SET check_function_bodies = false;
+SET xmloption = content;
+SET client_min_messages = warning;
+SET row_security = off;
+
+SET default_tablespace = '';
+
+SET default_table_access_method = heap;
+
+CREATE TYPE gender AS ENUM ('male', 'female', 'diverse', 'prefer_not_to_say');
+CREATE TYPE study_degree AS ENUM ('bachelor', 'master');
+CREATE TYPE course_type AS ENUM ('lecture', 'seminar', 'practical course');
+CREATE TYPE pass_status AS ENUM ('passed', 'failed', 'not_assessed');
+CREATE TYPE student_duplicate_status AS ENUM ('open', 'dismissed');
+
+--
+-- Name: student; Type: TABLE; Schema: public; Owner: prompt-postgres
+--
+
+CREATE TABLE student (
+    id uuid PRIMARY KEY,
+    first_name character varying(50),
+    last_name character varying(50),
+    email character varying(255),
+    matriculation_number character varying(30),
+    university_login character varying(20),
+    has_university_account boolean,
+    gender gender NOT NULL,
+    nationality character varying(2),
+    study_program character varying(100),
+    study_degree study_degree NOT NULL DEFAULT 'bachelor',
+    current_semester integer,
+    last_modified timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX student_email_key ON student (email);
+CREATE UNIQUE INDEX student_matriculation_number_unique ON student (matriculation_number) WHERE matriculation_number IS NOT NULL AND matriculation_number <> '';
+CREATE UNIQUE INDEX student_university_login_unique ON student (university_login) WHERE university_login IS NOT NULL AND university_login <> '';
+
+CREATE TABLE course (
+    id uuid PRIMARY KEY,
+    name text NOT NULL,
+    course_type course_type NOT NULL
+);
+
+CREATE TABLE course_phase (
+    id uuid PRIMARY KEY,
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    name text
+);
+
+CREATE TABLE course_participation (
+    id uuid PRIMARY KEY,
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    student_id uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    CONSTRAINT unique_course_participation UNIQUE (course_id, student_id)
+);
+
+CREATE TABLE course_phase_participation (
+    course_participation_id uuid NOT NULL REFERENCES course_participation(id) ON DELETE CASCADE,
+    course_phase_id uuid NOT NULL REFERENCES course_phase(id) ON DELETE CASCADE,
+    restricted_data jsonb NOT NULL DEFAULT '{}',
+    pass_status pass_status DEFAULT 'not_assessed',
+    last_modified timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    student_readable_data jsonb DEFAULT '{}',
+    PRIMARY KEY (course_participation_id, course_phase_id)
+);
+
+CREATE TABLE application_assessment (
+    id uuid PRIMARY KEY,
+    score integer,
+    course_phase_id uuid NOT NULL,
+    course_participation_id uuid NOT NULL,
+    CONSTRAINT fk_course_phase_participation FOREIGN KEY (course_participation_id, course_phase_id) REFERENCES course_phase_participation (course_participation_id, course_phase_id) ON DELETE CASCADE,
+    CONSTRAINT unique_course_phase_participation_assessment UNIQUE (course_phase_id, course_participation_id)
+);
+
+CREATE TABLE application_answer_text (
+    id uuid PRIMARY KEY,
+    application_question_id uuid NOT NULL,
+    answer text,
+    course_participation_id uuid NOT NULL REFERENCES course_participation(id) ON DELETE CASCADE,
+    CONSTRAINT unique_application_answer_text UNIQUE (course_participation_id, application_question_id)
+);
+
+CREATE TABLE application_answer_multi_select (
+    id uuid PRIMARY KEY,
+    application_question_id uuid NOT NULL,
+    answer text[],
+    course_participation_id uuid NOT NULL REFERENCES course_participation(id) ON DELETE CASCADE,
+    CONSTRAINT unique_application_answer_multi_select UNIQUE (course_participation_id, application_question_id)
+);
+
+CREATE TABLE application_answer_file_upload (
+    id uuid PRIMARY KEY,
+    application_question_id uuid NOT NULL,
+    course_participation_id uuid NOT NULL REFERENCES course_participation(id) ON DELETE CASCADE,
+    file_id uuid NOT NULL,
+    CONSTRAINT unique_file_upload_answer UNIQUE (course_participation_id, application_question_id)
+);
+
+CREATE TABLE note (
+    id uuid PRIMARY KEY,
+    for_student uuid NOT NULL REFERENCES student(id),
+    author uuid NOT NULL,
+    author_name text NOT NULL,
+    author_email text NOT NULL,
+    date_created timestamptz NOT NULL DEFAULT now(),
+    date_deleted timestamptz,
+    deleted_by uuid
+);
+
+CREATE TABLE student_duplicate_candidate (
+    id uuid PRIMARY KEY,
+    student_id uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    duplicate_student_id uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    score double precision NOT NULL,
+    reasons text[] NOT NULL,
+    status student_duplicate_status NOT NULL DEFAULT 'open',
+    detected_at timestamptz NOT NULL DEFAULT now(),
+    resolved_at timestamptz,
+    resolved_by uuid,
+    CONSTRAINT student_duplicate_candidate_ordered CHECK (student_id < duplicate_student_id),
+    CONSTRAINT unique_student_duplicate_candidate UNIQUE (student_id, duplicate_student_id)
+);
+
+CREATE TABLE course_staff (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    course_id uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+    role text NOT NULL CHECK (role IN ('Lecturer', 'Editor')),
+    user_id text,
+    student_id uuid REFERENCES student(id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (user_id IS NOT NULL OR student_id IS NOT NULL)
+);
+CREATE UNIQUE INDEX ux_course_staff_user ON course_staff (course_id, role, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX ux_course_staff_student ON course_staff (course_id, role, student_id) WHERE student_id IS NOT NULL;
+
+CREATE TYPE student_profile_change_source AS ENUM ('lecturer', 'self_service', 'application');
+
+CREATE TABLE student_profile_change (
+    id uuid PRIMARY KEY,
+    student_id uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    field text NOT NULL,
+    old_value text NOT NULL,
+    new_value text NOT NULL,
+    source student_profile_change_source NOT NULL,
+    changed_by uuid,
+    changed_by_name text NOT NULL DEFAULT '',
+    changed_by_email text NOT NULL DEFAULT '',
+    changed_at timestamptz NOT NULL DEFAULT now(),
+    is_identity_field boolean NOT NULL DEFAULT false,
+    notified_at timestamptz,
+    notification_attempts integer NOT NULL DEFAULT 0
+);
+
+--
+-- Data
+--
+
+-- Anna applied with her university account and one year earlier with her private mail
+INSERT INTO student (id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester)
+VALUES ('1b6a0f4e-7c1d-4c1e-9a43-6f0e4b6a2d11', 'Anna', 'Schmidt', 'anna.schmidt@tum.de', '03700001', 'ge12abc', true, 'female', NULL, NULL, 'master', 1);
+INSERT INTO student (id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester)
+VALUES ('c3d94a2b-5e8f-4b70-8d21-0a9e7f3c4b22', 'Anna', 'Schmitt', 'anna.schmidt@gmail.com', NULL, NULL, false, 'female', 'DE', 'Informatics', 'bachelor', 5);
+
+-- Wei entered the name in a different order
+INSERT INTO student (id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender)
+VALUES ('2e8f1c3d-9a4b-4d5e-8f6a-1b2c3d4e5f33', 'Wei', 'Zhang', 'wei@example.com', NULL, NULL, false, 'male');
+INSERT INTO student (id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender)
+VALUES ('d4e5f6a7-b8c9-4d0e-9f1a-2b3c4d5e6f44', 'Zhang', 'Wei', 'wei@example.org', NULL, NULL, false, 'male');
+
+INSERT INTO student (id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender)
+VALUES ('5f6a7b8c-9d0e-4f1a-8b2c-3d4e5f6a7b55', 'Clara', 'Weber', 'clara.weber@example.com', '03700002', 'ge34def', true, 'female');
+
+INSERT INTO course (id, name, course_type) VALUES ('a1a1a1a1-0000-4000-8000-000000000001', 'iPraktikum', 'practical course');
+INSERT INTO course (id, name, course_type) VALUES ('a1a1a1a1-0000-4000-8000-000000000002', 'Patterns', 'lecture');
+
+INSERT INTO course_phase (id, course_id, name) VALUES ('b2b2b2b2-0000-4000-8000-000000000001', 'a1a1a1a1-0000-4000-8000-000000000001', 'Application');
+INSERT INTO course_phase (id, course_id, name) VALUES ('b2b2b2b2-0000-4000-8000-000000000002', 'a1a1a1a1-0000-4000-8000-000000000001', 'Team Phase');
+INSERT INTO course_phase (id, course_id, name) VALUES ('b2b2b2b2-0000-4000-8000-000000000003', 'a1a1a1a1-0000-4000-8000-000000000002', 'Application');
+
+-- both Anna records participate in the iPraktikum, only the private one in Patterns
+INSERT INTO course_participation (id, course_id, student_id) VALUES ('c3c3c3c3-0000-4000-8000-000000000001', 'a1a1a1a1-0000-4000-8000-000000000001', '1b6a0f4e-7c1d-4c1e-9a43-6f0e4b6a2d11');
+INSERT INTO course_participation (id, course_id, student_id) VALUES ('c3c3c3c3-0000-4000-8000-000000000002', 'a1a1a1a1-0000-4000-8000-000000000001', 'c3d94a2b-5e8f-4b70-8d21-0a9e7f3c4b22');
+INSERT INTO course_participation (id, course_id, student_id) VALUES ('c3c3c3c3-0000-4000-8000-000000000003', 'a1a1a1a1-0000-4000-8000-000000000002', 'c3d94a2b-5e8f-4b70-8d21-0a9e7f3c4b22');
+
+INSERT INTO course_phase_participation (course_participation_id, course_phase_id, pass_status) VALUES ('c3c3c3c3-0000-4000-8000-000000000001', 'b2b2b2b2-0000-4000-8000-000000000001', 'not_assessed');
+INSERT INTO course_phase_participation (course_participation_id, course_phase_id, pass_status) VALUES ('c3c3c3c3-0000-4000-8000-000000000002', 'b2b2b2b2-0000-4000-8000-000000000001', 'passed');
+INSERT INTO course_phase_participation (course_participation_id, course_phase_id, pass_status) VALUES ('c3c3c3c3-0000-4000-8000-000000000002', 'b2b2b2b2-0000-4000-8000-000000000002', 'passed');
+INSERT INTO course_phase_participation (course_participation_id, course_phase_id, pass_status) VALUES ('c3c3c3c3-0000-4000-8000-000000000003', 'b2b2b2b2-0000-4000-8000-000000000003', 'failed');
+
+INSERT INTO application_assessment (id, score, course_phase_id, course_participation_id) VALUES ('d4d4d4d4-0000-4000-8000-000000000001', 42, 'b2b2b2b2-0000-4000-8000-000000000001', 'c3c3c3c3-0000-4000-8000-000000000002');
+
+INSERT INTO application_answer_text (id, application_question_id, answer, course_participation_id) VALUES ('e5e5e5e5-0000-4000-8000-000000000001', 'f6f6f6f6-0000-4000-8000-000000000001', 'Answer of the university account', 'c3c3c3c3-0000-4000-8000-000000000001');
+INSERT INTO application_answer_text (id, application_question_id, answer, course_participation_id) VALUES ('e5e5e5e5-0000-4000-8000-000000000002', 'f6f6f6f6-0000-4000-8000-000000000001', 'Answer of the private account', 'c3c3c3c3-0000-4000-8000-000000000002');
+INSERT INTO application_answer_text (id, application_question_id, answer, course_participation_id) VALUES ('e5e5e5e5-0000-4000-8000-000000000003', 'f6f6f6f6-0000-4000-8000-000000000002', 'Only answered with the private account', 'c3c3c3c3-0000-4000-8000-000000000002');
+
+INSERT INTO note (id, for_student, author, author_name, author_email) VALUES ('a7a7a7a7-0000-4000-8000-000000000001', 'c3d94a2b-5e8f-4b70-8d21-0a9e7f3c4b22', '0f0f0f0f-0000-4000-8000-000000000001', 'Jane Lecturer', 'lecturer@example.com');
+
+-- the private Anna record tutors the Patterns course
+INSERT INTO course_staff (course_id, role, student_id) VALUES ('a1a1a1a1-0000-4000-8000-000000000002', 'Editor', 'c3d94a2b-5e8f-4b70-8d21-0a9e7f3c4b22');
+
+--
+-- PostgreSQL database dump complete
+--
@@ -111,3 +111,22 @@ CREATE TRIGGER set_last_modified_student
 BEFORE UPDATE ON student
 FOR EACH ROW
 EXECUTE FUNCTION update_last_modified_column();
+
+-- Add student profile history
+CREATE TYPE student_profile_change_source AS ENUM ('lecturer', 'self_service', 'application');
+
+CREATE TABLE student_profile_change (
+    id uuid PRIMARY KEY,
+    student_id uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+    field text NOT NULL,
+    old_value text NOT NULL,
+    new_value text NOT NULL,
+    source student_profile_change_source NOT NULL,
+    changed_by uuid,
+    changed_by_name text NOT NULL DEFAULT '',
+    changed_by_email text NOT NULL DEFAULT '',
+    changed_at timestamptz NOT NULL DEFAULT now(),
+    is_identity_field boolean NOT NULL DEFAULT false,
+    notified_at timestamptz,
+    notification_attempts integer NOT NULL DEFAULT 0
+);
@@ -0,0 +1,9 @@
+-- add an optional display condition tree to every application question type
+ALTER TABLE application_question_text
+ADD COLUMN display_condition JSONB;
+
+ALTER TABLE application_question_multi_select
+ADD COLUMN display_condition JSONB;
+
+ALTER TABLE application_question_file_upload
+ADD COLUMN display_condition JSONB;
@@ -0,0 +1,10 @@
+-- partially filled applications of authenticated applicants, one per course phase and university login
+CREATE TABLE IF NOT EXISTS application_draft
+(
+    course_phase_id  UUID        NOT NULL,
+    university_login TEXT        NOT NULL,
+    application      JSONB       NOT NULL,
+    last_modified    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_phase_id, university_login),
+    CONSTRAINT fk_application_draft_course_phase FOREIGN KEY (course_phase_id) REFERENCES course_phase (id) ON DELETE CASCADE
+);
@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TYPE student_duplicate_status AS ENUM (
+  'open',
+  'dismissed'
+);
+
+-- pairs of student records that likely belong to the same person, found by the duplicate detection job
+-- merged pairs are removed together with the merged student
+CREATE TABLE student_duplicate_candidate (
+  id                    uuid PRIMARY KEY,
+  student_id            uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+  duplicate_student_id  uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+  score                 double precision NOT NULL,
+  reasons               text[] NOT NULL,
+  status                student_duplicate_status NOT NULL DEFAULT 'open',
+  detected_at           timestamptz NOT NULL DEFAULT now(),
+  resolved_at           timestamptz,
+  resolved_by           uuid,
+  CONSTRAINT student_duplicate_candidate_ordered CHECK (student_id < duplicate_student_id),
+  CONSTRAINT unique_student_duplicate_candidate UNIQUE (student_id, duplicate_student_id)
+);
+
+CREATE INDEX idx_student_duplicate_candidate_status ON student_duplicate_candidate(status);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- answers to identifying questions are hidden from editors while a blind review is running
+ALTER TABLE application_question_text
+ADD COLUMN is_identifying BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE application_question_multi_select
+ADD COLUMN is_identifying BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE application_question_file_upload
+ADD COLUMN is_identifying BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,13 @@
+-- uploaded files stay quarantined until the malware scanner has marked them as clean
+CREATE TYPE file_scan_status AS ENUM ('pending', 'clean', 'infected', 'failed');
+
+-- files uploaded before scanning was introduced are treated as clean
+ALTER TABLE files
+ADD COLUMN scan_status file_scan_status NOT NULL DEFAULT 'clean',
+ADD COLUMN scan_result TEXT,
+ADD COLUMN scanned_at TIMESTAMP;
+
+ALTER TABLE files
+ALTER COLUMN scan_status SET DEFAULT 'pending';
+
+CREATE INDEX idx_files_scan_status ON files(scan_status) WHERE scan_status <> 'clean';
@@ -0,0 +1,14 @@
+-- storage quotas limit the total size of the files of a course or a single course phase
+CREATE TABLE course_storage_quota (
+    course_id uuid PRIMARY KEY,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_course_storage_quota_course FOREIGN KEY (course_id) REFERENCES course(id) ON DELETE CASCADE
+);
+
+CREATE TABLE course_phase_storage_quota (
+    course_phase_id uuid PRIMARY KEY,
+    quota_bytes BIGINT NOT NULL CHECK (quota_bytes >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_course_phase_storage_quota_course_phase FOREIGN KEY (course_phase_id) REFERENCES course_phase(id) ON DELETE CASCADE
+);
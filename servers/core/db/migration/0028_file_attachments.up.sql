@@ -0,0 +1,23 @@
+-- Files attached to instructor notes
+CREATE TABLE note_file (
+  note_id uuid NOT NULL REFERENCES note(id) ON DELETE CASCADE,
+  file_id uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+  PRIMARY KEY (note_id, file_id)
+);
+
+CREATE INDEX idx_note_file_file_id ON note_file(file_id);
+
+-- Files referenced by the data of a course phase participation, e.g. a signed contract
+CREATE TABLE course_phase_participation_file (
+  course_participation_id uuid NOT NULL,
+  course_phase_id         uuid NOT NULL,
+  file_id                 uuid NOT NULL REFERENCES files(id) ON DELETE CASCADE,
+  PRIMARY KEY (course_participation_id, course_phase_id, file_id),
+  CONSTRAINT fk_course_phase_participation_file_participation FOREIGN KEY (course_participation_id, course_phase_id)
+    REFERENCES course_phase_participation(course_participation_id, course_phase_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_course_phase_participation_file_file_id ON course_phase_participation_file(file_id);
+
+-- Files are filtered by tags
+CREATE INDEX idx_files_tags ON files USING GIN (tags);
@@ -0,0 +1,10 @@
+-- Thumbnails of images and first-page previews of PDFs, stored as derived objects next to the original file
+CREATE TABLE file_preview (
+  file_id      uuid PRIMARY KEY REFERENCES files(id) ON DELETE CASCADE,
+  storage_key  TEXT NOT NULL UNIQUE,
+  content_type TEXT NOT NULL,
+  width        INT NOT NULL,
+  height       INT NOT NULL,
+  size_bytes   BIGINT NOT NULL,
+  created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+-- uploads are hashed, files with the same content in a course share one stored object
+ALTER TABLE files
+ADD COLUMN content_sha256 TEXT,
+ADD COLUMN previous_version_id uuid REFERENCES files(id) ON DELETE SET NULL,
+ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+ALTER TABLE files
+DROP CONSTRAINT files_storage_key_key;
+
+CREATE INDEX idx_files_content_sha256 ON files(content_sha256) WHERE content_sha256 IS NOT NULL;
+CREATE INDEX idx_files_previous_version_id ON files(previous_version_id) WHERE previous_version_id IS NOT NULL;
@@ -0,0 +1,17 @@
+-- data keys of the envelope encryption, wrapped with the master key of the server.
+-- Keys are kept when their course is deleted, so remaining files can still be read.
+CREATE TABLE file_encryption_key (
+  id            uuid PRIMARY KEY,
+  -- NULL for the key of files outside of a course
+  course_id     uuid,
+  wrapped_key   BYTEA NOT NULL,
+  master_key_id TEXT NOT NULL,
+  created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  rotated_at    TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_file_encryption_key_course ON file_encryption_key (COALESCE(course_id, '00000000-0000-0000-0000-000000000000'::uuid));
+
+-- files without a key are stored in plain form
+ALTER TABLE files
+ADD COLUMN encryption_key_id uuid REFERENCES file_encryption_key(id);
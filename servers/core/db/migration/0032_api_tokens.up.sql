@@ -0,0 +1,20 @@
+-- PROMPT-managed tokens of service accounts and scripts, which are scoped to the roles of one course.
+-- Only the SHA-256 hash of a token is stored, the token itself is shown once when it is created.
+CREATE TABLE api_token (
+  id                 uuid PRIMARY KEY,
+  name               TEXT NOT NULL,
+  token_hash         TEXT NOT NULL UNIQUE,
+  -- the beginning of the token, so lecturers can recognize their tokens
+  token_prefix       TEXT NOT NULL,
+  course_id          uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+  -- course roles like Lecturer or Editor, without the course identifier
+  roles              TEXT[] NOT NULL,
+  created_by_user_id TEXT NOT NULL,
+  created_by_email   TEXT NOT NULL DEFAULT '',
+  expires_at         TIMESTAMP NOT NULL,
+  revoked_at         TIMESTAMP,
+  last_used_at       TIMESTAMP,
+  created_at         TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_token_course ON api_token (course_id);
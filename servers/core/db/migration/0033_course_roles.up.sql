@@ -0,0 +1,12 @@
+-- roles defined by a course with explicit permissions, e.g. tutors who may assess but not see applications.
+-- Members get the Keycloak role <semester_tag>-<course name>-cr-<role name> through a Keycloak group.
+CREATE TABLE course_role (
+  id          uuid PRIMARY KEY,
+  course_id   uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+  name        TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  permissions TEXT[] NOT NULL,
+  created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (course_id, name)
+);
@@ -0,0 +1,13 @@
+-- grants of a custom course role that only apply in one course phase,
+-- e.g. tutors who may edit the intro course phase but not the application phase.
+-- A grant is a built-in course role (Lecturer, Editor) or a course permission.
+CREATE TABLE course_phase_grant (
+  course_role_id  uuid NOT NULL REFERENCES course_role(id) ON DELETE CASCADE,
+  course_phase_id uuid NOT NULL REFERENCES course_phase(id) ON DELETE CASCADE,
+  grants          TEXT[] NOT NULL,
+  created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (course_role_id, course_phase_id)
+);
+
+CREATE INDEX idx_course_phase_grant_course_phase ON course_phase_grant (course_phase_id);
@@ -0,0 +1,16 @@
+-- lecturer and editor assignments of courses that are stored in the Core Server instead of Keycloak groups.
+-- Lecturers who created a course are identified by their Keycloak user ID (sub), editors added by the course by their student record.
+CREATE TABLE course_staff (
+  id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  course_id   uuid NOT NULL REFERENCES course(id) ON DELETE CASCADE,
+  role        TEXT NOT NULL CHECK (role IN ('Lecturer', 'Editor')),
+  user_id     TEXT,
+  student_id  uuid REFERENCES student(id) ON DELETE CASCADE,
+  created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CHECK (user_id IS NOT NULL OR student_id IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX ux_course_staff_user ON course_staff (course_id, role, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX ux_course_staff_student ON course_staff (course_id, role, student_id) WHERE student_id IS NOT NULL;
+CREATE INDEX idx_course_staff_user ON course_staff (user_id);
+CREATE INDEX idx_course_staff_student ON course_staff (student_id);
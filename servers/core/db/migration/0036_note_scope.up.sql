@@ -0,0 +1,54 @@
+-- Instructor notes can be scoped to a course or course phase, and their visibility can be restricted.
+-- Existing notes keep being visible to all PROMPT lecturers.
+CREATE TYPE note_visibility AS ENUM ('private', 'course_staff', 'lecturers');
+
+ALTER TABLE note
+  ADD COLUMN course_id       uuid REFERENCES course(id) ON DELETE SET NULL,
+  ADD COLUMN course_phase_id uuid REFERENCES course_phase(id) ON DELETE SET NULL,
+  ADD COLUMN visibility      note_visibility NOT NULL DEFAULT 'lecturers';
+
+CREATE INDEX idx_note_course_id ON note(course_id);
+
+-- new columns can only be appended to the view
+CREATE OR REPLACE VIEW note_with_versions AS
+SELECT
+  n.id,
+  n.author,
+  n.author_name,
+  n.author_email,
+  n.for_student,
+  n.date_created,
+  n.date_deleted,
+  n.deleted_by,
+  CASE
+    WHEN n.date_deleted IS NULL THEN
+      jsonb_agg(
+        jsonb_build_object(
+          'id', nv.id,
+          'content', nv.content,
+          'dateCreated', nv.date_created,
+          'versionNumber', nv.version_number
+        )
+        ORDER BY nv.version_number
+      )
+    ELSE '[]'::jsonb
+  END AS versions,
+  CASE
+    WHEN n.date_deleted IS NULL THEN
+      COALESCE(
+        (
+          SELECT jsonb_agg(jsonb_build_object('id', nt.id, 'name', nt.name, 'color', nt.color) ORDER BY nt.name)
+          FROM note_tag_relation ntr
+          JOIN note_tag nt ON nt.id = ntr.tag_id
+          WHERE ntr.note_id = n.id
+        ),
+        '[]'::jsonb
+      )
+    ELSE '[]'::jsonb
+  END AS tags,
+  n.course_id,
+  n.course_phase_id,
+  n.visibility
+FROM note n
+JOIN note_version nv ON nv.for_note = n.id
+GROUP BY n.id;
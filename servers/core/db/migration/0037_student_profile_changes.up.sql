@@ -0,0 +1,32 @@
+BEGIN;
+
+CREATE TYPE student_profile_change_source AS ENUM (
+  'lecturer',
+  'self_service',
+  'application'
+);
+
+-- history of the student profiles, one row per changed field
+-- changes of identity fields (names, email, university data) are cached by phase servers,
+-- they are announced to them by the student update notification job which sets notified_at
+CREATE TABLE student_profile_change (
+  id                     uuid PRIMARY KEY,
+  student_id             uuid NOT NULL REFERENCES student(id) ON DELETE CASCADE,
+  field                  text NOT NULL,
+  old_value              text NOT NULL,
+  new_value              text NOT NULL,
+  source                 student_profile_change_source NOT NULL,
+  changed_by             uuid,
+  changed_by_name        text NOT NULL DEFAULT '',
+  changed_by_email       text NOT NULL DEFAULT '',
+  changed_at             timestamptz NOT NULL DEFAULT now(),
+  is_identity_field      boolean NOT NULL DEFAULT false,
+  notified_at            timestamptz,
+  notification_attempts  integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_student_profile_change_student ON student_profile_change(student_id, changed_at);
+CREATE INDEX idx_student_profile_change_pending ON student_profile_change(changed_at)
+  WHERE is_identity_field AND notified_at IS NULL;
+
+COMMIT;
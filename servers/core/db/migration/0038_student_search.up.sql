@@ -0,0 +1,18 @@
+BEGIN;
+
+-- full-text index for the student search, the expression has to match the one in SearchStudents exactly
+-- the simple configuration keeps names, matriculation numbers and logins as they are instead of stemming them
+CREATE INDEX idx_student_search ON student USING gin (
+  to_tsvector('simple',
+    coalesce(first_name, '') || ' ' ||
+    coalesce(last_name, '') || ' ' ||
+    coalesce(email, '') || ' ' ||
+    coalesce(matriculation_number, '') || ' ' ||
+    coalesce(university_login, ''))
+);
+
+-- the filters of the student search look up the participations of a student and the courses of a semester
+CREATE INDEX idx_course_participation_student_id ON course_participation(student_id);
+CREATE INDEX idx_course_semester_tag ON course(semester_tag);
+
+COMMIT;
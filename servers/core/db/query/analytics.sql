@@ -0,0 +1,100 @@
+-- name: GetSemesterOverview :many
+-- participants took part in any phase of a course, accepted participants passed its initial phase
+SELECT c.semester_tag::text AS semester_tag,
+       COUNT(DISTINCT c.id) AS courses,
+       COUNT(DISTINCT cp.student_id) AS participants,
+       COUNT(DISTINCT cp.student_id) FILTER (WHERE cpp.pass_status = 'passed') AS accepted
+FROM course c
+LEFT JOIN course_participation cp ON cp.course_id = c.id
+LEFT JOIN course_phase ph ON ph.course_id = c.id AND ph.is_initial_phase
+LEFT JOIN course_phase_participation cpp ON cpp.course_participation_id = cp.id AND cpp.course_phase_id = ph.id
+WHERE c.semester_tag IS NOT NULL
+  AND c.semester_tag <> ''
+  AND NOT c.template
+GROUP BY c.semester_tag
+ORDER BY MIN(c.start_date) DESC NULLS LAST, c.semester_tag;
+
+-- name: GetCoursesBySemesterTag :many
+SELECT id, name
+FROM course
+WHERE semester_tag = @semester_tag::text
+  AND NOT template
+ORDER BY start_date, name;
+
+-- name: GetCoursePhaseFunnels :many
+-- counts the participants of the phases of each course in the order of the phase graph, phases outside of the graph are left out
+WITH RECURSIVE phase_sequence AS (
+    SELECT ph.id, ph.course_id, ph.name, ph.course_phase_type_id, 1 AS sequence_order
+    FROM course_phase ph
+    WHERE ph.course_id = ANY(@course_ids::uuid[]) AND ph.is_initial_phase = true
+
+    UNION ALL
+
+    SELECT ph.id, ph.course_id, ph.name, ph.course_phase_type_id, ps.sequence_order + 1 AS sequence_order
+    FROM course_phase ph
+    INNER JOIN course_phase_graph g ON g.to_course_phase_id = ph.id
+    INNER JOIN phase_sequence ps ON g.from_course_phase_id = ps.id
+)
+SELECT ps.course_id,
+       ps.id AS course_phase_id,
+       ps.name AS course_phase_name,
+       cpt.name AS course_phase_type_name,
+       ps.sequence_order,
+       COUNT(cpp.course_participation_id) AS participants,
+       COUNT(cpp.course_participation_id) FILTER (WHERE cpp.pass_status = 'passed') AS passed,
+       COUNT(cpp.course_participation_id) FILTER (WHERE cpp.pass_status = 'failed') AS failed,
+       COUNT(cpp.course_participation_id) FILTER (WHERE cpp.pass_status IS NULL OR cpp.pass_status = 'not_assessed') AS not_assessed
+FROM phase_sequence ps
+INNER JOIN course_phase_type cpt ON cpt.id = ps.course_phase_type_id
+LEFT JOIN course_phase_participation cpp ON cpp.course_phase_id = ps.id
+GROUP BY ps.course_id, ps.id, ps.name, cpt.name, ps.sequence_order
+ORDER BY ps.course_id, ps.sequence_order;
+
+-- name: GetStudentDemographics :many
+-- every student is counted once, even if they take part in several of the courses
+WITH participants AS (
+    SELECT cp.student_id,
+           COALESCE(bool_or(cpp.pass_status = 'passed'), false) AS accepted
+    FROM course_participation cp
+    LEFT JOIN course_phase ph ON ph.course_id = cp.course_id AND ph.is_initial_phase
+    LEFT JOIN course_phase_participation cpp ON cpp.course_participation_id = cp.id AND cpp.course_phase_id = ph.id
+    WHERE cp.course_id = ANY(@course_ids::uuid[])
+    GROUP BY cp.student_id
+)
+SELECT d.dimension::text AS dimension,
+       COALESCE(d.value, '')::text AS value,
+       COUNT(*) AS participants,
+       COUNT(*) FILTER (WHERE p.accepted) AS accepted
+FROM participants p
+INNER JOIN student s ON s.id = p.student_id
+CROSS JOIN LATERAL (VALUES
+    ('gender', s.gender::text),
+    ('nationality', s.nationality::text),
+    ('studyProgram', s.study_program::text),
+    ('studyDegree', s.study_degree::text)
+) AS d(dimension, value)
+GROUP BY d.dimension, d.value
+ORDER BY d.dimension, participants DESC, value;
+
+-- name: GetRepeatParticipantStatistics :one
+-- returning participants took part in a course that started before the first of the courses they take part in
+WITH participants AS (
+    SELECT cp.student_id,
+           COUNT(*) AS course_count,
+           MIN(c.start_date) AS first_start_date
+    FROM course_participation cp
+    INNER JOIN course c ON c.id = cp.course_id
+    WHERE cp.course_id = ANY(@course_ids::uuid[])
+    GROUP BY cp.student_id
+)
+SELECT COUNT(*) AS participants,
+       COUNT(*) FILTER (WHERE p.course_count > 1) AS multi_course_participants,
+       COUNT(*) FILTER (WHERE EXISTS (
+           SELECT 1
+           FROM course_participation earlier_cp
+           INNER JOIN course earlier ON earlier.id = earlier_cp.course_id
+           WHERE earlier_cp.student_id = p.student_id
+             AND earlier.start_date < p.first_start_date
+             AND NOT earlier.template
+       )) AS returning_participants
+FROM participants p;
@@ -0,0 +1,32 @@
+-- name: CreateAPIToken :one
+INSERT INTO api_token (id, name, token_hash, token_prefix, course_id, roles, created_by_user_id, created_by_email, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP + make_interval(days => sqlc.arg(expires_in_days)::int))
+RETURNING *;
+
+-- name: GetAPITokensByCourse :many
+SELECT * FROM api_token
+WHERE course_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetActiveAPITokenByHash :one
+-- the course identifier is the prefix of the course roles, e.g. ss25-iPraktikum
+SELECT t.id, t.name, t.course_id, t.roles, CONCAT(c.semester_tag, '-', c.name)::text AS course_identifier
+FROM api_token t
+JOIN course c ON c.id = t.course_id
+WHERE t.token_hash = $1
+AND t.revoked_at IS NULL
+AND t.expires_at > CURRENT_TIMESTAMP;
+
+-- name: RevokeAPIToken :execrows
+UPDATE api_token
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1
+AND course_id = $2
+AND revoked_at IS NULL;
+
+-- name: UpdateAPITokenLastUsed :exec
+-- the last use is only tracked to the minute, so scripts with many requests do not write on every request
+UPDATE api_token
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
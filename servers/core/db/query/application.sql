@@ -28,12 +28,12 @@ SELECT EXISTS (
 
 
 -- name: CreateApplicationQuestionText :exec
-INSERT INTO application_question_text (id, course_phase_id, title, description, placeholder, validation_regex, error_message, is_required, allowed_length, order_num, accessible_for_other_phases, access_key)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
+INSERT INTO application_question_text (id, course_phase_id, title, description, placeholder, validation_regex, error_message, is_required, allowed_length, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
 
 -- name: CreateApplicationQuestionMultiSelect :exec
-INSERT INTO application_question_multi_select (id, course_phase_id, title, description, placeholder, error_message, is_required, min_select, max_select, options, order_num, accessible_for_other_phases, access_key)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+INSERT INTO application_question_multi_select (id, course_phase_id, title, description, placeholder, error_message, is_required, min_select, max_select, options, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
 
 -- name: UpdateApplicationQuestionMultiSelect :exec
 UPDATE application_question_multi_select
@@ -48,7 +48,9 @@ SET
     options = COALESCE($9, options),
     order_num = COALESCE($10, order_num), 
     accessible_for_other_phases = COALESCE($11, accessible_for_other_phases),
-    access_key = COALESCE($12, access_key)
+    access_key = COALESCE($12, access_key),
+    display_condition = $13,
+    is_identifying = $14
 WHERE id = $1;
 
 -- name: UpdateApplicationQuestionText :exec
@@ -63,7 +65,9 @@ SET
     allowed_length = COALESCE($8, allowed_length),
     order_num = COALESCE($9, order_num),
     accessible_for_other_phases = COALESCE($10, accessible_for_other_phases),
-    access_key = COALESCE($11, access_key)
+    access_key = COALESCE($11, access_key),
+    display_condition = $12,
+    is_identifying = $13
 WHERE id = $1;
 
 
@@ -194,34 +198,64 @@ DO UPDATE
 SET answer = EXCLUDED.answer;
 
 -- name: GetAllApplicationParticipations :many
-SELECT
-    cpp.course_phase_id,
-    cpp.course_participation_id,
-    cpp.pass_status,
-    cpp.restricted_data,
-    s.id AS student_id,
-    s.first_name,
-    s.last_name,
-    s.email,
-    s.matriculation_number,
-    s.university_login,
-    s.has_university_account,
-    s.gender, 
-    s.nationality,
-    s.study_degree,
-    s.study_program,
-    s.current_semester,
-    a.score
-FROM
-    course_phase_participation cpp
-JOIN
-    course_participation cp ON cpp.course_participation_id = cp.id
-JOIN
-    student s ON cp.student_id = s.id
-LEFT JOIN
-    application_assessment a ON cpp.course_participation_id = a.course_participation_id AND cpp.course_phase_id = a.course_phase_id
-WHERE
-    cpp.course_phase_id = $1;
+-- without page size all matching applications are returned, the total count ignores the cursor and the page size
+WITH matches AS (
+    SELECT
+        cpp.course_phase_id,
+        cpp.course_participation_id,
+        cpp.pass_status,
+        cpp.restricted_data,
+        s.id AS student_id,
+        s.first_name,
+        s.last_name,
+        s.email,
+        s.matriculation_number,
+        s.university_login,
+        s.has_university_account,
+        s.gender,
+        s.nationality,
+        s.study_degree,
+        s.study_program,
+        s.current_semester,
+        a.score,
+        (CASE sqlc.arg(sort_by)::text
+            WHEN 'firstName' THEN lower(coalesce(s.first_name, '') || ' ' || coalesce(s.last_name, ''))
+            WHEN 'email' THEN lower(coalesce(s.email, ''))
+            -- the offset makes the text order of the scores match their numeric order, unscored applications come first
+            WHEN 'score' THEN coalesce(lpad((a.score::bigint + 2147483648)::text, 10, '0'), '')
+            ELSE lower(coalesce(s.last_name, '') || ' ' || coalesce(s.first_name, ''))
+        END)::text       AS sort_key,
+        COUNT(*) OVER () AS total_count
+    FROM
+        course_phase_participation cpp
+    JOIN
+        course_participation cp ON cpp.course_participation_id = cp.id
+    JOIN
+        student s ON cp.student_id = s.id
+    LEFT JOIN
+        application_assessment a ON cpp.course_participation_id = a.course_participation_id AND cpp.course_phase_id = a.course_phase_id
+    WHERE
+        cpp.course_phase_id = sqlc.arg(course_phase_id)
+        AND (sqlc.narg(pass_status)::pass_status IS NULL
+             OR coalesce(cpp.pass_status, 'not_assessed') = sqlc.narg(pass_status)::pass_status)
+        -- every metadata filter has to match the restricted data
+        AND NOT EXISTS (
+            SELECT 1
+            FROM jsonb_each_text(coalesce(sqlc.narg(metadata)::jsonb, '{}'::jsonb)) AS filter
+            WHERE cpp.restricted_data ->> filter.key IS DISTINCT FROM filter.value
+        )
+)
+SELECT *
+FROM matches
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+   OR (sqlc.arg(descending)::boolean AND (sort_key, course_participation_id) < (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)::uuid))
+   OR (NOT sqlc.arg(descending)::boolean AND (sort_key, course_participation_id) > (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)::uuid))
+ORDER BY
+    CASE WHEN sqlc.arg(descending)::boolean THEN sort_key END DESC,
+    CASE WHEN sqlc.arg(descending)::boolean THEN course_participation_id END DESC,
+    CASE WHEN NOT sqlc.arg(descending)::boolean THEN sort_key END,
+    CASE WHEN NOT sqlc.arg(descending)::boolean THEN course_participation_id END
+LIMIT sqlc.narg(page_size)::int;
 
 -- name: UpdateApplicationAssessment :exec
 INSERT INTO application_assessment (id, course_phase_id, course_participation_id, score)
@@ -322,8 +356,8 @@ SELECT * FROM application_question_file_upload
 WHERE course_phase_id = $1;
 
 -- name: CreateApplicationQuestionFileUpload :exec
-INSERT INTO application_question_file_upload (id, course_phase_id, title, description, is_required, allowed_file_types, max_file_size_mb, order_num, accessible_for_other_phases, access_key)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+INSERT INTO application_question_file_upload (id, course_phase_id, title, description, is_required, allowed_file_types, max_file_size_mb, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12);
 
 -- name: UpdateApplicationQuestionFileUpload :exec
 UPDATE application_question_file_upload
@@ -335,7 +369,9 @@ SET
     max_file_size_mb = COALESCE($6, max_file_size_mb),
     order_num = COALESCE($7, order_num),
     accessible_for_other_phases = COALESCE($8, accessible_for_other_phases),
-    access_key = COALESCE($9, access_key)
+    access_key = COALESCE($9, access_key),
+    display_condition = $10,
+    is_identifying = $11
 WHERE id = $1;
 
 -- name: DeleteApplicationQuestionFileUpload :exec
@@ -359,3 +395,50 @@ ON CONFLICT (course_participation_id, application_question_id)
 DO UPDATE
 SET file_id = EXCLUDED.file_id;
 
+
+-- name: GetApplicationFileUploadsForCoursePhase :many
+SELECT
+    aafu.file_id,
+    aafu.course_participation_id,
+    aafu.application_question_id,
+    aqfu.title AS question_title,
+    f.original_filename,
+    s.first_name,
+    s.last_name,
+    a.score
+FROM application_answer_file_upload aafu
+JOIN application_question_file_upload aqfu ON aafu.application_question_id = aqfu.id
+JOIN course_participation cp ON aafu.course_participation_id = cp.id
+JOIN student s ON cp.student_id = s.id
+JOIN files f ON aafu.file_id = f.id
+LEFT JOIN application_assessment a ON aafu.course_participation_id = a.course_participation_id AND aqfu.course_phase_id = a.course_phase_id
+WHERE aqfu.course_phase_id = sqlc.arg(course_phase_id)
+  AND f.deleted_at IS NULL
+  AND f.scan_status = 'clean'
+  AND (COALESCE(cardinality(sqlc.arg(question_ids)::uuid[]), 0) = 0 OR aafu.application_question_id = ANY(sqlc.arg(question_ids)::uuid[]))
+  AND (COALESCE(cardinality(sqlc.arg(course_participation_ids)::uuid[]), 0) = 0 OR aafu.course_participation_id = ANY(sqlc.arg(course_participation_ids)::uuid[]))
+ORDER BY s.last_name, s.first_name, aafu.course_participation_id, aqfu.order_num;
+
+-- name: GetApplicationAssessmentScore :one
+SELECT score
+FROM application_assessment
+WHERE course_phase_id = $1 AND course_participation_id = $2;
+
+-- name: GetApplicationBlindReviewSettings :one
+SELECT
+    COALESCE((restricted_data->>'blindReview')::boolean, false) AS blind_review,
+    COALESCE((restricted_data->>'blindReviewRevealed')::boolean, false) AS blind_review_revealed
+FROM course_phase
+WHERE id = $1;
+
+-- name: GetIdentifyingApplicationQuestionIDs :many
+SELECT id FROM application_question_text WHERE course_phase_id = $1 AND is_identifying
+UNION ALL
+SELECT id FROM application_question_multi_select WHERE course_phase_id = $1 AND is_identifying
+UNION ALL
+SELECT id FROM application_question_file_upload WHERE course_phase_id = $1 AND is_identifying;
+
+-- name: RevealApplicationBlindReview :exec
+UPDATE course_phase
+SET restricted_data = restricted_data || '{"blindReviewRevealed": true}'::jsonb
+WHERE id = $1;
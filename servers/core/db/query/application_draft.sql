@@ -0,0 +1,16 @@
+-- name: GetApplicationDraft :one
+SELECT * FROM application_draft
+WHERE course_phase_id = $1 AND university_login = $2;
+
+-- name: UpsertApplicationDraft :one
+INSERT INTO application_draft (course_phase_id, university_login, application)
+VALUES ($1, $2, $3)
+ON CONFLICT (course_phase_id, university_login)
+DO UPDATE
+SET application = EXCLUDED.application,
+    last_modified = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteApplicationDraft :exec
+DELETE FROM application_draft
+WHERE course_phase_id = $1 AND university_login = $2;
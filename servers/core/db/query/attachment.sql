@@ -0,0 +1,31 @@
+-- name: AddFileToNote :exec
+INSERT INTO note_file (note_id, file_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveFileFromNote :execrows
+DELETE FROM note_file
+WHERE note_id = $1 AND file_id = $2;
+
+-- name: GetFilesForNote :many
+SELECT f.* FROM files f
+JOIN note_file nf ON nf.file_id = f.id
+WHERE nf.note_id = $1 AND f.deleted_at IS NULL
+ORDER BY f.created_at ASC;
+
+-- name: AddFileToCoursePhaseParticipation :exec
+INSERT INTO course_phase_participation_file (course_participation_id, course_phase_id, file_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveFileFromCoursePhaseParticipation :exec
+DELETE FROM course_phase_participation_file
+WHERE course_participation_id = $1 AND course_phase_id = $2 AND file_id = $3;
+
+-- name: GetFilesForCoursePhaseParticipation :many
+SELECT f.* FROM files f
+JOIN course_phase_participation_file cppf ON cppf.file_id = f.id
+WHERE cppf.course_participation_id = $1
+  AND cppf.course_phase_id = $2
+  AND f.deleted_at IS NULL
+ORDER BY f.created_at ASC;
@@ -0,0 +1,31 @@
+-- name: UpsertCoursePhaseGrant :one
+-- returns no row if the role or the course phase does not belong to the course
+INSERT INTO course_phase_grant (course_role_id, course_phase_id, grants)
+SELECT cr.id, cp.id, sqlc.arg(grants)::text[]
+FROM course_role cr
+JOIN course_phase cp ON cp.course_id = cr.course_id
+WHERE cr.id = sqlc.arg(course_role_id)
+AND cp.id = sqlc.arg(course_phase_id)
+AND cr.course_id = sqlc.arg(course_id)
+ON CONFLICT (course_role_id, course_phase_id)
+DO UPDATE SET
+    grants = EXCLUDED.grants,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetCoursePhaseGrantsByCourseRole :many
+SELECT cpg.*
+FROM course_phase_grant cpg
+JOIN course_phase cp ON cp.id = cpg.course_phase_id
+JOIN course_role cr ON cr.id = cpg.course_role_id
+WHERE cpg.course_role_id = $1
+AND cr.course_id = $2
+ORDER BY cp.name;
+
+-- name: DeleteCoursePhaseGrant :execrows
+DELETE FROM course_phase_grant cpg
+USING course_role cr
+WHERE cr.id = cpg.course_role_id
+AND cpg.course_role_id = $1
+AND cpg.course_phase_id = $2
+AND cr.course_id = $3;
@@ -89,7 +89,7 @@ WITH
   direct_predecessor_for_pass AS (
       SELECT cpg.from_course_phase_id AS phase_id
       FROM course_phase_graph cpg
-      WHERE cpg.to_course_phase_id = $1
+      WHERE cpg.to_course_phase_id = sqlc.arg(course_phase_id)
   ),
   
   -----------------------------------------------------------------------
@@ -113,7 +113,7 @@ WITH
       ON po.id = mdg.from_course_phase_DTO_id
     JOIN course_phase_type_participation_required_input_dto ri
       ON ri.id = mdg.to_course_phase_DTO_id
-    WHERE mdg.to_course_phase_id = $1
+    WHERE mdg.to_course_phase_id = sqlc.arg(course_phase_id)
       AND po.endpoint_path = 'core'
     GROUP BY 
       cpt.name,
@@ -149,17 +149,17 @@ WITH
         ON cpp.course_participation_id = cp.id
       JOIN student s 
         ON cp.student_id = s.id
-      WHERE cpp.course_phase_id = $1
+      WHERE cpp.course_phase_id = sqlc.arg(course_phase_id)
   ),
   
   -----------------------------------------------------------------------
   -- 2) Qualified non-participants:
-  --    They do NOT yet have a participation in $1 and
+  --    They do NOT yet have a participation in the course phase and
   --    must have passed ALL direct_predecessors_for_pass.
   -----------------------------------------------------------------------
   qualified_non_participants AS (
       SELECT
-          $1::uuid                     AS course_phase_id,
+          sqlc.arg(course_phase_id)::uuid AS course_phase_id,
           cp.id                        AS course_participation_id,
           'not_assessed'::pass_status  AS pass_status,
           '{}'::jsonb                  AS restricted_data,
@@ -184,7 +184,7 @@ WITH
           NOT EXISTS (
             SELECT 1
             FROM course_phase_participation new_cpp
-            WHERE new_cpp.course_phase_id = $1
+            WHERE new_cpp.course_phase_id = sqlc.arg(course_phase_id)
               AND new_cpp.course_participation_id = cp.id
           )
           -- And ensure they have 'passed' the direct predecessor (if any)
@@ -196,10 +196,56 @@ WITH
                 AND pcpp.course_participation_id = cp.id
               WHERE pcpp.pass_status = 'passed'
           )
+  ),
+
+  -----------------------------------------------------------------------
+  -- 3) Participants matching the filters, with their sort key and count
+  -----------------------------------------------------------------------
+  matches AS (
+      SELECT
+          participants.*,
+          (CASE sqlc.arg(sort_by)::text
+              WHEN 'firstName' THEN lower(coalesce(participants.first_name, '') || ' ' || coalesce(participants.last_name, ''))
+              WHEN 'email' THEN lower(coalesce(participants.email, ''))
+              WHEN 'passStatus' THEN coalesce(participants.pass_status::text, 'not_assessed')
+              ELSE lower(coalesce(participants.last_name, '') || ' ' || coalesce(participants.first_name, ''))
+          END)::text                   AS sort_key,
+          COUNT(*) OVER ()             AS total_count
+      FROM
+      (
+          SELECT * FROM current_phase_participations
+          UNION
+          SELECT * FROM qualified_non_participants
+      ) AS participants
+      WHERE (sqlc.narg(pass_status)::pass_status IS NULL
+             OR coalesce(participants.pass_status, 'not_assessed') = sqlc.narg(pass_status)::pass_status)
+        -- every metadata filter has to match the restricted or the student readable data
+        AND NOT EXISTS (
+            SELECT 1
+            FROM jsonb_each_text(coalesce(sqlc.narg(metadata)::jsonb, '{}'::jsonb)) AS filter
+            WHERE coalesce(participants.restricted_data ->> filter.key, participants.student_readable_data ->> filter.key) IS DISTINCT FROM filter.value
+        )
+  ),
+
+  -----------------------------------------------------------------------
+  -- 4) The requested page, all participants without page size
+  -----------------------------------------------------------------------
+  page AS (
+      SELECT *
+      FROM matches
+      WHERE sqlc.narg(cursor_id)::uuid IS NULL
+         OR (sqlc.arg(descending)::boolean AND (sort_key, course_participation_id) < (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)::uuid))
+         OR (NOT sqlc.arg(descending)::boolean AND (sort_key, course_participation_id) > (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)::uuid))
+      ORDER BY
+          CASE WHEN sqlc.arg(descending)::boolean THEN sort_key END DESC,
+          CASE WHEN sqlc.arg(descending)::boolean THEN course_participation_id END DESC,
+          CASE WHEN NOT sqlc.arg(descending)::boolean THEN sort_key END,
+          CASE WHEN NOT sqlc.arg(descending)::boolean THEN course_participation_id END
+      LIMIT sqlc.narg(page_size)::int
   )
-  
+
 -----------------------------------------------------------------------
--- 3) Final SELECT: Merge the participants and meta data from all predecessors
+-- 5) Final SELECT: Merge the participants of the page and meta data from all predecessors
 -----------------------------------------------------------------------
 SELECT
     main.*,
@@ -304,13 +350,12 @@ SELECT
          ),
          '{}'::jsonb
       )::jsonb)::jsonb AS prev_data
-FROM
-(
-    SELECT * FROM current_phase_participations
-    UNION
-    SELECT * FROM qualified_non_participants
-) AS main
-ORDER BY main.last_name, main.first_name;
+FROM page AS main
+ORDER BY
+    CASE WHEN sqlc.arg(descending)::boolean THEN main.sort_key END DESC,
+    CASE WHEN sqlc.arg(descending)::boolean THEN main.course_participation_id END DESC,
+    CASE WHEN NOT sqlc.arg(descending)::boolean THEN main.sort_key END,
+    CASE WHEN NOT sqlc.arg(descending)::boolean THEN main.course_participation_id END;
 
 
 
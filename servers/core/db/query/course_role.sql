@@ -0,0 +1,30 @@
+-- name: CreateCourseRole :one
+INSERT INTO course_role (id, course_id, name, description, permissions)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetCourseRole :one
+SELECT * FROM course_role
+WHERE id = $1
+AND course_id = $2;
+
+-- name: GetCourseRolesByCourse :many
+SELECT * FROM course_role
+WHERE course_id = $1
+ORDER BY name;
+
+-- name: UpdateCourseRole :one
+-- the name is part of the Keycloak role, so it cannot be changed
+UPDATE course_role
+SET
+    description = $3,
+    permissions = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+AND course_id = $2
+RETURNING *;
+
+-- name: DeleteCourseRole :exec
+DELETE FROM course_role
+WHERE id = $1
+AND course_id = $2;
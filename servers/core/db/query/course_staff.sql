@@ -0,0 +1,49 @@
+-- name: AddCourseStaffUser :exec
+INSERT INTO course_staff (course_id, role, user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (course_id, role, user_id) WHERE user_id IS NOT NULL DO NOTHING;
+
+-- name: AddCourseStaffStudent :exec
+INSERT INTO course_staff (course_id, role, student_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (course_id, role, student_id) WHERE student_id IS NOT NULL DO NOTHING;
+
+-- name: GetCourseStaffRoleStrings :many
+-- returns the course roles of a user, e.g. ss25-iPraktikum-Editor, by the Keycloak user ID or the student record
+SELECT DISTINCT CONCAT(c.semester_tag, '-', c.name, '-', cs.role)::text AS role_string
+FROM course_staff cs
+JOIN course c ON c.id = cs.course_id
+LEFT JOIN student s ON s.id = cs.student_id
+WHERE cs.user_id = sqlc.arg(user_id)::text
+OR (
+    sqlc.arg(matriculation_number)::text <> ''
+    AND s.matriculation_number = sqlc.arg(matriculation_number)::text
+    AND s.university_login = sqlc.arg(university_login)::text
+);
+
+-- name: GetCourseStaff :many
+-- lists the lecturer and editor assignments of a course, editors with their student record
+SELECT cs.id, cs.role, cs.user_id, cs.student_id, cs.created_at, s.first_name, s.last_name, s.email
+FROM course_staff cs
+LEFT JOIN student s ON s.id = cs.student_id
+WHERE cs.course_id = $1
+ORDER BY cs.role, cs.created_at;
+
+-- name: GetCourseStaffStudents :many
+SELECT s.*
+FROM course_staff cs
+JOIN student s ON s.id = cs.student_id
+WHERE cs.course_id = $1 AND cs.role = $2
+ORDER BY s.last_name, s.first_name;
+
+-- name: DeleteCourseStaff :one
+DELETE FROM course_staff
+WHERE id = $1 AND course_id = $2
+RETURNING *;
+
+-- name: DeleteCourseStaffStudents :many
+DELETE FROM course_staff
+WHERE course_id = $1
+  AND role = $2
+  AND student_id = ANY(sqlc.arg(student_ids)::uuid[])
+RETURNING student_id;
@@ -10,9 +10,10 @@ INSERT INTO files (
     uploaded_by_email,
     course_phase_id,
     description,
-    tags
+    tags,
+    encryption_key_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 ) RETURNING *;
 
 -- name: GetFileByID :one
@@ -28,6 +29,29 @@ SELECT * FROM files
 WHERE course_phase_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
+-- name: GetFilesByCoursePhaseIDAndTags :many
+-- a file has to have all given tags, no tags match every file.
+-- files of application answers and notes, including replaced versions, are only listed through them
+SELECT * FROM files
+WHERE course_phase_id = sqlc.arg(course_phase_id)
+  AND deleted_at IS NULL
+  AND (COALESCE(cardinality(sqlc.arg(tags)::varchar[]), 0) = 0 OR tags @> sqlc.arg(tags)::varchar[])
+  AND (sqlc.narg(uploaded_by_user_id)::text IS NULL OR uploaded_by_user_id = sqlc.narg(uploaded_by_user_id)::text)
+  AND NOT EXISTS (SELECT 1 FROM application_answer_file_upload a WHERE a.file_id = files.id)
+  AND NOT EXISTS (SELECT 1 FROM note_file n WHERE n.file_id = files.id)
+  AND NOT EXISTS (SELECT 1 FROM files successor WHERE successor.previous_version_id = files.id)
+ORDER BY created_at DESC;
+
+-- name: IsFileReferencedByApplicationOrNote :one
+-- replaced versions of a file are only kept for application answers
+SELECT EXISTS (
+    SELECT 1 FROM application_answer_file_upload a WHERE a.file_id = $1
+    UNION ALL
+    SELECT 1 FROM note_file n WHERE n.file_id = $1
+    UNION ALL
+    SELECT 1 FROM files successor WHERE successor.previous_version_id = $1
+);
+
 -- name: GetFilesByUploader :many
 SELECT * FROM files
 WHERE uploaded_by_user_id = $1 AND deleted_at IS NULL
@@ -58,6 +82,11 @@ WHERE id = $1;
 DELETE FROM files
 WHERE id = $1;
 
+-- name: HardDeleteFileWithStorageKey :execrows
+-- the record is kept if its object was replaced in the meantime, e.g. by a deduplication or an encryption
+DELETE FROM files
+WHERE id = $1 AND storage_key = $2 AND storage_provider = $3;
+
 -- name: CountFilesByUploader :one
 SELECT COUNT(*) FROM files
 WHERE uploaded_by_user_id = $1 AND deleted_at IS NULL;
@@ -72,3 +101,122 @@ SELECT * FROM files
 WHERE tags && $1::VARCHAR[] AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
+
+-- name: UpdateFileScanResult :one
+UPDATE files
+SET
+    scan_status = $2,
+    scan_result = $3,
+    scanned_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: GetFilesToScan :many
+-- files that were just uploaded are still being scanned by their upload request
+SELECT * FROM files
+WHERE scan_status IN ('pending', 'failed')
+  AND deleted_at IS NULL
+  AND created_at < CURRENT_TIMESTAMP - INTERVAL '5 minutes'
+ORDER BY created_at
+LIMIT $1;
+
+-- name: GetFilesDeletedBefore :many
+SELECT * FROM files
+WHERE deleted_at IS NOT NULL
+  AND deleted_at < CURRENT_TIMESTAMP - make_interval(secs => sqlc.arg(retention_seconds)::int)
+ORDER BY deleted_at;
+
+-- name: GetFileStorageKeys :many
+-- includes soft-deleted files, whose objects are kept until the retention period is over
+SELECT id, storage_key, storage_provider, size_bytes
+FROM files
+ORDER BY storage_key;
+
+-- name: UpdateFileContent :one
+UPDATE files
+SET
+    content_sha256 = $2,
+    storage_key = $3,
+    encryption_key_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: GetDuplicateFileInCourse :one
+-- the oldest file of the same course with the same content, whose stored object can be shared
+SELECT f.* FROM files f
+JOIN course_phase cp ON cp.id = f.course_phase_id
+WHERE cp.course_id = (SELECT course_id FROM course_phase WHERE course_phase.id = sqlc.arg(course_phase_id))
+  AND f.content_sha256 = sqlc.arg(content_sha256)
+  AND f.storage_provider = sqlc.arg(storage_provider)
+  AND f.encryption_key_id IS NOT DISTINCT FROM sqlc.narg(encryption_key_id)
+  AND f.id <> sqlc.arg(id)
+  AND f.scan_status = 'clean'
+  AND f.deleted_at IS NULL
+ORDER BY f.created_at
+LIMIT 1;
+
+-- name: CountFilesByStorageKey :one
+-- includes soft-deleted files, a stored object is deleted once no file references it anymore
+SELECT COUNT(*) FROM files
+WHERE storage_key = $1;
+
+-- name: LinkFileVersion :execrows
+-- files that are already part of the version chain are not linked again, so the chain cannot become a cycle
+WITH RECURSIVE ancestors AS (
+    SELECT id, previous_version_id FROM files WHERE id = sqlc.arg(previous_version_id)
+    UNION
+    SELECT f.id, f.previous_version_id
+    FROM files f
+    JOIN ancestors a ON f.id = a.previous_version_id
+)
+UPDATE files
+SET
+    previous_version_id = sqlc.arg(previous_version_id),
+    version = (SELECT p.version + 1 FROM files p WHERE p.id = sqlc.arg(previous_version_id)),
+    updated_at = CURRENT_TIMESTAMP
+WHERE files.id = sqlc.arg(id)
+  AND files.previous_version_id IS NULL
+  AND files.id NOT IN (SELECT id FROM ancestors);
+
+-- name: GetFileVersions :many
+-- the prior versions of a file, the newest first
+WITH RECURSIVE versions AS (
+    SELECT p.id, p.previous_version_id
+    FROM files c
+    JOIN files p ON p.id = c.previous_version_id
+    WHERE c.id = $1
+    UNION
+    SELECT f.id, f.previous_version_id
+    FROM files f
+    JOIN versions v ON f.id = v.previous_version_id
+)
+SELECT f.* FROM files f
+JOIN versions v ON v.id = f.id
+WHERE f.deleted_at IS NULL
+ORDER BY f.version DESC, f.created_at DESC;
+
+-- name: CountFilesByStorageProvider :one
+-- includes soft-deleted files, files sharing an object are migrated together
+SELECT
+    COUNT(DISTINCT storage_key)::bigint AS object_count,
+    COUNT(*)::bigint AS file_count
+FROM files
+WHERE storage_provider = $1;
+
+-- name: GetStorageKeysToMigrate :many
+SELECT storage_key, COUNT(*) AS file_count
+FROM files
+WHERE storage_provider = sqlc.arg(storage_provider)
+  AND storage_key > sqlc.arg(after_storage_key)::text
+GROUP BY storage_key
+ORDER BY storage_key
+LIMIT sqlc.arg(batch_size);
+
+-- name: UpdateFileStorageProvider :execrows
+UPDATE files
+SET
+    storage_provider = sqlc.arg(target_provider),
+    updated_at = CURRENT_TIMESTAMP
+WHERE storage_key = sqlc.arg(storage_key)
+  AND storage_provider = sqlc.arg(source_provider);
@@ -0,0 +1,26 @@
+-- name: CreateFileEncryptionKey :exec
+-- a key of the same course that was created concurrently wins, the caller reads the stored key afterwards
+INSERT INTO file_encryption_key (id, course_id, wrapped_key, master_key_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING;
+
+-- name: GetFileEncryptionKeyByCourse :one
+SELECT * FROM file_encryption_key
+WHERE course_id IS NOT DISTINCT FROM $1;
+
+-- name: GetFileEncryptionKey :one
+SELECT * FROM file_encryption_key
+WHERE id = $1;
+
+-- name: GetFileEncryptionKeysToRotate :many
+SELECT * FROM file_encryption_key
+WHERE master_key_id <> $1
+ORDER BY created_at;
+
+-- name: UpdateFileEncryptionKeyWrapping :exec
+UPDATE file_encryption_key
+SET
+    wrapped_key = $2,
+    master_key_id = $3,
+    rotated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -0,0 +1,37 @@
+-- name: UpsertFilePreview :one
+INSERT INTO file_preview (
+    file_id,
+    storage_key,
+    content_type,
+    width,
+    height,
+    size_bytes
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (file_id) DO UPDATE
+SET storage_key = EXCLUDED.storage_key,
+    content_type = EXCLUDED.content_type,
+    width = EXCLUDED.width,
+    height = EXCLUDED.height,
+    size_bytes = EXCLUDED.size_bytes,
+    created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetFilePreview :one
+SELECT * FROM file_preview
+WHERE file_id = $1;
+
+-- name: GetFilePreviewsByStorageKey :many
+SELECT fp.* FROM file_preview fp
+JOIN files f ON f.id = fp.file_id
+WHERE f.storage_key = $1
+  AND f.storage_provider = $2;
+
+-- name: GetFilePreviewStorageKeys :many
+SELECT storage_key FROM file_preview
+ORDER BY storage_key;
+
+-- name: DeleteFilePreview :exec
+DELETE FROM file_preview
+WHERE file_id = $1;
@@ -24,10 +24,29 @@ VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: CreateNote :one
-INSERT INTO note (id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO note (id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by, course_id, course_phase_id, visibility)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
+-- name: UpdateNoteScope :exec
+UPDATE note
+SET course_id = $2, course_phase_id = $3, visibility = $4
+WHERE id = $1;
+
+-- name: GetCourseIDsWithNoteAccess :many
+-- courses in which the roles make the user course staff: lecturers, editors and custom course roles with the notes permission
+SELECT c.id
+FROM course c
+WHERE CONCAT(c.semester_tag, '-', c.name, '-Lecturer') = ANY(sqlc.arg(role_strings)::text[])
+OR CONCAT(c.semester_tag, '-', c.name, '-Editor') = ANY(sqlc.arg(role_strings)::text[])
+OR EXISTS (
+    SELECT 1
+    FROM course_role cr
+    WHERE cr.course_id = c.id
+    AND 'can_view_notes' = ANY(cr.permissions)
+    AND CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name) = ANY(sqlc.arg(role_strings)::text[])
+);
+
 -- name: DeleteNote :one
 UPDATE note
 SET date_deleted = now(), deleted_by = $2
@@ -65,3 +84,35 @@ DELETE FROM note_tag_relation WHERE note_id = $1 AND tag_id = $2;
 -- name: RemoveAllTagsFromNote :exec
 DELETE FROM note_tag_relation WHERE note_id = $1;
 
+
+-- name: GetNoteVersion :one
+SELECT * FROM note_version WHERE for_note = $1 AND version_number = $2;
+
+-- name: RestoreNote :one
+UPDATE note
+SET date_deleted = NULL, deleted_by = NULL
+WHERE id = $1
+AND date_deleted IS NOT NULL
+RETURNING *;
+
+-- name: SearchStudentNotes :many
+-- searches the current version and the tags of the notes that are not deleted, best matches first
+SELECT nwv.*
+FROM note_with_versions nwv
+JOIN LATERAL (
+    SELECT nv.content
+    FROM note_version nv
+    WHERE nv.for_note = nwv.id
+    ORDER BY nv.version_number DESC
+    LIMIT 1
+) latest ON TRUE
+CROSS JOIN LATERAL (
+    SELECT to_tsvector('simple', latest.content || ' ' || COALESCE(string_agg(nt.name, ' '), '')) AS document
+    FROM note_tag_relation ntr
+    JOIN note_tag nt ON nt.id = ntr.tag_id
+    WHERE ntr.note_id = nwv.id
+) searchable
+CROSS JOIN websearch_to_tsquery('simple', sqlc.arg(query)::text) search_query
+WHERE nwv.date_deleted IS NULL
+AND searchable.document @@ search_query
+ORDER BY ts_rank(searchable.document, search_query) DESC, nwv.date_created DESC;
@@ -27,4 +27,39 @@ AND s.university_login = $2;
 SELECT CONCAT(c.semester_tag, '-', c.name, '-Lecturer')::text AS lecturer_role, CONCAT(c.semester_tag, '-', c.name, '-Editor')::text AS editor_role, CONCAT(c.semester_tag, '-', c.name, '-cg-')::text AS custom_role_prefix
 FROM course c
 JOIN course_phase cp ON c.id = cp.course_id
-WHERE cp.id = $1;
\ No newline at end of file
+WHERE cp.id = $1;
+-- name: GetPermissionStringsByStudentID :many
+SELECT DISTINCT CONCAT(c.semester_tag, '-', c.name)::text AS course_identifier
+FROM course c
+JOIN course_participation cp ON c.id = cp.course_id
+WHERE cp.student_id = $1;
+
+-- name: HasCourseRolePermission :one
+-- role_strings are Keycloak roles of custom course roles, e.g. ss25-iPraktikum-cr-Tutor
+SELECT EXISTS (
+    SELECT 1
+    FROM course_role cr
+    JOIN course c ON c.id = cr.course_id
+    WHERE CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name) = ANY(sqlc.arg(role_strings)::text[])
+    AND sqlc.arg(permission)::text = ANY(cr.permissions)
+) AS has_permission;
+
+-- name: HasCoursePhaseGrant :one
+-- checks whether one of the custom course roles grants one of the roles or permissions in the course phase
+SELECT EXISTS (
+    SELECT 1
+    FROM course_phase_grant cpg
+    JOIN course_role cr ON cr.id = cpg.course_role_id
+    JOIN course c ON c.id = cr.course_id
+    WHERE cpg.course_phase_id = sqlc.arg(course_phase_id)
+    AND CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name) = ANY(sqlc.arg(role_strings)::text[])
+    AND cpg.grants && sqlc.arg(grants)::text[]
+) AS has_grant;
+
+-- name: GetCoursePhaseGrantRoles :many
+SELECT CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name)::text AS role_string, cpg.grants
+FROM course_phase_grant cpg
+JOIN course_role cr ON cr.id = cpg.course_role_id
+JOIN course c ON c.id = cr.course_id
+WHERE cpg.course_phase_id = $1
+ORDER BY cr.name;
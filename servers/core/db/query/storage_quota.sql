@@ -0,0 +1,98 @@
+-- name: GetStorageQuotaStatus :one
+-- soft-deleted files are not counted, they are removed by the garbage collection
+SELECT
+    cp.course_id,
+    (SELECT COALESCE(SUM(f.size_bytes), 0)
+     FROM files f
+     WHERE f.course_phase_id = cp.id
+       AND f.deleted_at IS NULL)::bigint AS phase_used_bytes,
+    (SELECT COALESCE(SUM(f.size_bytes), 0)
+     FROM files f
+     JOIN course_phase p ON f.course_phase_id = p.id
+     WHERE p.course_id = cp.course_id
+       AND f.deleted_at IS NULL)::bigint AS course_used_bytes,
+    pq.quota_bytes AS phase_quota_bytes,
+    cq.quota_bytes AS course_quota_bytes
+FROM course_phase cp
+LEFT JOIN course_phase_storage_quota pq ON pq.course_phase_id = cp.id
+LEFT JOIN course_storage_quota cq ON cq.course_id = cp.course_id
+WHERE cp.id = $1;
+
+-- name: UpsertCourseStorageQuota :one
+INSERT INTO course_storage_quota (course_id, quota_bytes)
+VALUES ($1, $2)
+ON CONFLICT (course_id) DO UPDATE
+SET quota_bytes = EXCLUDED.quota_bytes,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteCourseStorageQuota :exec
+DELETE FROM course_storage_quota
+WHERE course_id = $1;
+
+-- name: UpsertCoursePhaseStorageQuota :one
+INSERT INTO course_phase_storage_quota (course_phase_id, quota_bytes)
+VALUES ($1, $2)
+ON CONFLICT (course_phase_id) DO UPDATE
+SET quota_bytes = EXCLUDED.quota_bytes,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteCoursePhaseStorageQuota :exec
+DELETE FROM course_phase_storage_quota
+WHERE course_phase_id = $1;
+
+-- name: GetStorageUsageByCourse :many
+SELECT
+    c.id AS course_id,
+    c.name AS course_name,
+    c.semester_tag,
+    COUNT(f.id) AS file_count,
+    COALESCE(SUM(f.size_bytes), 0)::bigint AS used_bytes,
+    q.quota_bytes
+FROM course c
+LEFT JOIN course_phase cp ON cp.course_id = c.id
+LEFT JOIN files f ON f.course_phase_id = cp.id AND f.deleted_at IS NULL
+LEFT JOIN course_storage_quota q ON q.course_id = c.id
+GROUP BY c.id, c.name, c.semester_tag, q.quota_bytes
+HAVING COUNT(f.id) > 0 OR q.quota_bytes IS NOT NULL
+ORDER BY used_bytes DESC, c.name;
+
+-- name: GetStorageUsageByCoursePhase :many
+SELECT
+    cp.id AS course_phase_id,
+    cp.course_id,
+    cp.name AS course_phase_name,
+    c.name AS course_name,
+    COUNT(f.id) AS file_count,
+    COALESCE(SUM(f.size_bytes), 0)::bigint AS used_bytes,
+    q.quota_bytes
+FROM course_phase cp
+JOIN course c ON cp.course_id = c.id
+LEFT JOIN files f ON f.course_phase_id = cp.id AND f.deleted_at IS NULL
+LEFT JOIN course_phase_storage_quota q ON q.course_phase_id = cp.id
+GROUP BY cp.id, cp.course_id, cp.name, c.name, q.quota_bytes
+HAVING COUNT(f.id) > 0 OR q.quota_bytes IS NOT NULL
+ORDER BY used_bytes DESC, c.name;
+
+-- name: GetStorageUsageByUploader :many
+SELECT
+    uploaded_by_user_id,
+    COALESCE(MAX(uploaded_by_email), '')::text AS uploaded_by_email,
+    COUNT(*) AS file_count,
+    COALESCE(SUM(size_bytes), 0)::bigint AS used_bytes
+FROM files
+WHERE deleted_at IS NULL
+GROUP BY uploaded_by_user_id
+ORDER BY used_bytes DESC
+LIMIT $1;
+
+-- name: GetStorageUsageByContentType :many
+SELECT
+    content_type,
+    COUNT(*) AS file_count,
+    COALESCE(SUM(size_bytes), 0)::bigint AS used_bytes
+FROM files
+WHERE deleted_at IS NULL
+GROUP BY content_type
+ORDER BY used_bytes DESC;
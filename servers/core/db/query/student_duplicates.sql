@@ -0,0 +1,121 @@
+-- name: UpsertStudentDuplicateCandidate :exec
+INSERT INTO student_duplicate_candidate (id, student_id, duplicate_student_id, score, reasons, detected_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (student_id, duplicate_student_id)
+DO UPDATE SET score = EXCLUDED.score,
+              reasons = EXCLUDED.reasons,
+              detected_at = EXCLUDED.detected_at;
+
+-- name: DeleteOpenStudentDuplicateCandidatesDetectedBefore :exec
+DELETE FROM student_duplicate_candidate
+WHERE status = 'open'
+  AND detected_at < $1;
+
+-- name: GetStudentDuplicateCandidatesByStatus :many
+SELECT * FROM student_duplicate_candidate
+WHERE status = $1
+ORDER BY score DESC, detected_at DESC;
+
+-- name: GetStudentDuplicateCandidate :one
+SELECT * FROM student_duplicate_candidate
+WHERE id = $1 LIMIT 1;
+
+-- name: DismissStudentDuplicateCandidate :one
+UPDATE student_duplicate_candidate
+SET status = 'dismissed',
+    resolved_at = now(),
+    resolved_by = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: ReassignCourseParticipationToStudent :exec
+UPDATE course_participation
+SET student_id = $2
+WHERE id = $1;
+
+-- name: CopyMissingCoursePhaseParticipations :exec
+INSERT INTO course_phase_participation (course_participation_id, course_phase_id, restricted_data, pass_status, last_modified, student_readable_data)
+SELECT @target_course_participation_id::uuid, source.course_phase_id, source.restricted_data, source.pass_status, source.last_modified, source.student_readable_data
+FROM course_phase_participation source
+WHERE source.course_participation_id = @source_course_participation_id::uuid
+  AND NOT EXISTS (
+    SELECT 1
+    FROM course_phase_participation target
+    WHERE target.course_participation_id = @target_course_participation_id::uuid
+      AND target.course_phase_id = source.course_phase_id
+  );
+
+-- name: MoveApplicationAssessments :exec
+UPDATE application_assessment a
+SET course_participation_id = @target_course_participation_id::uuid
+WHERE a.course_participation_id = @source_course_participation_id::uuid
+  AND NOT EXISTS (
+    SELECT 1
+    FROM application_assessment target
+    WHERE target.course_participation_id = @target_course_participation_id::uuid
+      AND target.course_phase_id = a.course_phase_id
+  );
+
+-- name: MoveApplicationAnswersText :exec
+UPDATE application_answer_text a
+SET course_participation_id = @target_course_participation_id::uuid
+WHERE a.course_participation_id = @source_course_participation_id::uuid
+  AND NOT EXISTS (
+    SELECT 1
+    FROM application_answer_text target
+    WHERE target.course_participation_id = @target_course_participation_id::uuid
+      AND target.application_question_id = a.application_question_id
+  );
+
+-- name: MoveApplicationAnswersMultiSelect :exec
+UPDATE application_answer_multi_select a
+SET course_participation_id = @target_course_participation_id::uuid
+WHERE a.course_participation_id = @source_course_participation_id::uuid
+  AND NOT EXISTS (
+    SELECT 1
+    FROM application_answer_multi_select target
+    WHERE target.course_participation_id = @target_course_participation_id::uuid
+      AND target.application_question_id = a.application_question_id
+  );
+
+-- name: MoveApplicationAnswersFileUpload :exec
+UPDATE application_answer_file_upload a
+SET course_participation_id = @target_course_participation_id::uuid
+WHERE a.course_participation_id = @source_course_participation_id::uuid
+  AND NOT EXISTS (
+    SELECT 1
+    FROM application_answer_file_upload target
+    WHERE target.course_participation_id = @target_course_participation_id::uuid
+      AND target.application_question_id = a.application_question_id
+  );
+
+-- name: DeleteCourseParticipation :exec
+DELETE FROM course_participation
+WHERE id = $1;
+
+-- name: ReassignNotesToStudent :exec
+UPDATE note
+SET for_student = @target_student_id::uuid
+WHERE for_student = @source_student_id::uuid;
+
+-- name: DeleteStudent :exec
+DELETE FROM student
+WHERE id = $1;
+
+-- name: ReassignCourseStaffToStudent :exec
+-- assignments the surviving student already has are removed together with the merged student
+UPDATE course_staff cs
+SET student_id = @target_student_id::uuid
+WHERE cs.student_id = @source_student_id::uuid
+AND NOT EXISTS (
+    SELECT 1
+    FROM course_staff existing
+    WHERE existing.course_id = cs.course_id
+    AND existing.role = cs.role
+    AND existing.student_id = @target_student_id::uuid
+);
+
+-- name: ReassignStudentProfileChangesToStudent :exec
+UPDATE student_profile_change
+SET student_id = @target_student_id::uuid
+WHERE student_id = @source_student_id::uuid;
@@ -0,0 +1,33 @@
+-- name: CreateStudentProfileChange :exec
+INSERT INTO student_profile_change (id, student_id, field, old_value, new_value, source, changed_by, changed_by_name, changed_by_email, is_identity_field)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+
+-- name: GetStudentProfileChanges :many
+SELECT * FROM student_profile_change
+WHERE student_id = $1
+ORDER BY changed_at DESC, field;
+
+-- name: GetPendingStudentIdentityChanges :many
+-- identity changes that were not announced to the phase servers yet, oldest first
+SELECT * FROM student_profile_change
+WHERE is_identity_field
+  AND notified_at IS NULL
+  AND notification_attempts < sqlc.arg(max_attempts)::int
+ORDER BY changed_at
+LIMIT sqlc.arg(batch_size)::int;
+
+-- name: MarkStudentProfileChangesNotified :exec
+UPDATE student_profile_change
+SET notified_at = now()
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: IncrementStudentProfileChangeNotificationAttempts :exec
+UPDATE student_profile_change
+SET notification_attempts = notification_attempts + 1
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: GetPhaseServerBaseURLs :many
+SELECT DISTINCT base_url
+FROM course_phase_type
+WHERE base_url <> 'core'
+ORDER BY base_url;
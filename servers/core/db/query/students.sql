@@ -48,14 +48,54 @@ WHERE university_login = $1
 LIMIT 1;
 
 -- name: SearchStudents :many
+-- returns one page of the students matching the full-text query and the filters, the sort key and the ID of the last student are the cursor of the next page
+-- without page size all matching students are returned, the total count ignores the cursor and the page size
+WITH matches AS (
+    SELECT s.*,
+           (CASE sqlc.arg(sort_by)::text
+                WHEN 'firstName' THEN lower(coalesce(s.first_name, '') || ' ' || coalesce(s.last_name, ''))
+                WHEN 'email' THEN lower(coalesce(s.email, ''))
+                WHEN 'matriculationNumber' THEN coalesce(s.matriculation_number, '')
+                ELSE lower(coalesce(s.last_name, '') || ' ' || coalesce(s.first_name, ''))
+            END)::text AS sort_key,
+           COUNT(*) OVER () AS total_count
+    FROM student s
+    WHERE (sqlc.narg(query)::text IS NULL
+           OR to_tsvector('simple',
+                coalesce(s.first_name, '') || ' ' ||
+                coalesce(s.last_name, '') || ' ' ||
+                coalesce(s.email, '') || ' ' ||
+                coalesce(s.matriculation_number, '') || ' ' ||
+                coalesce(s.university_login, '')) @@ to_tsquery('simple', sqlc.narg(query)::text))
+      AND (sqlc.narg(study_program)::text IS NULL OR lower(s.study_program) = lower(sqlc.narg(study_program)::text))
+      AND (sqlc.narg(nationality)::text IS NULL OR lower(s.nationality) = lower(sqlc.narg(nationality)::text))
+      AND ((sqlc.narg(course_id)::uuid IS NULL AND sqlc.narg(semester_tag)::text IS NULL AND sqlc.narg(pass_status)::pass_status IS NULL)
+           OR EXISTS (
+               SELECT 1
+               FROM course_participation cp
+               INNER JOIN course c ON c.id = cp.course_id
+               WHERE cp.student_id = s.id
+                 AND (sqlc.narg(course_id)::uuid IS NULL OR cp.course_id = sqlc.narg(course_id)::uuid)
+                 AND (sqlc.narg(semester_tag)::text IS NULL OR c.semester_tag = sqlc.narg(semester_tag)::text)
+                 AND (sqlc.narg(pass_status)::pass_status IS NULL OR EXISTS (
+                     SELECT 1
+                     FROM course_phase_participation cpp
+                     WHERE cpp.course_participation_id = cp.id
+                       AND cpp.pass_status = sqlc.narg(pass_status)::pass_status
+                 ))
+           ))
+)
 SELECT *
-FROM student
-WHERE (first_name || ' ' || last_name) ILIKE '%' || $1 || '%'
-   OR first_name ILIKE '%' || $1 || '%'
-   OR last_name ILIKE '%' || $1 || '%'
-   OR email ILIKE '%' || $1 || '%'
-   OR matriculation_number ILIKE '%' || $1 || '%'
-   OR university_login ILIKE '%' || $1 || '%';
+FROM matches
+WHERE sqlc.narg(cursor_id)::uuid IS NULL
+   OR (sqlc.arg(descending)::boolean AND (sort_key, id) < (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)::uuid))
+   OR (NOT sqlc.arg(descending)::boolean AND (sort_key, id) > (sqlc.narg(cursor_key)::text, sqlc.narg(cursor_id)::uuid))
+ORDER BY
+    CASE WHEN sqlc.arg(descending)::boolean THEN sort_key END DESC,
+    CASE WHEN sqlc.arg(descending)::boolean THEN id END DESC,
+    CASE WHEN NOT sqlc.arg(descending)::boolean THEN sort_key END,
+    CASE WHEN NOT sqlc.arg(descending)::boolean THEN id END
+LIMIT sqlc.narg(page_size)::int;
 
 -- name: GetStudentEmails :many
 SELECT id, email
@@ -71,7 +111,7 @@ SELECT id, university_login
 FROM student
 WHERE id = ANY($1::uuid[]);
 
--- name: GetAllStudentsWithCourseParticipations :many
+-- name: GetStudentsWithCourseParticipations :many
 SELECT
   s.id AS student_id,
   s.first_name AS student_first_name,
@@ -109,6 +149,7 @@ LEFT JOIN course_participation cp
   ON cp.student_id = s.id
 LEFT JOIN course c
   ON c.id = cp.course_id
+WHERE s.id = ANY(sqlc.arg(student_ids)::uuid[])
 GROUP BY
   s.id,
   s.first_name,
@@ -215,3 +256,7 @@ LEFT JOIN course c
     ON cp.course_id = c.id
 WHERE s.id = $1
 GROUP BY s.id;
+
+-- name: GetStudentsByIDs :many
+SELECT * FROM student
+WHERE id = ANY($1::uuid[]);
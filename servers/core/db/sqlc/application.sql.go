@@ -185,8 +185,8 @@ func (q *Queries) CreateApplicationAnswerText(ctx context.Context, arg CreateApp
 }
 
 const createApplicationQuestionFileUpload = `-- name: CreateApplicationQuestionFileUpload :exec
-INSERT INTO application_question_file_upload (id, course_phase_id, title, description, is_required, allowed_file_types, max_file_size_mb, order_num, accessible_for_other_phases, access_key)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+INSERT INTO application_question_file_upload (id, course_phase_id, title, description, is_required, allowed_file_types, max_file_size_mb, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 `
 
 type CreateApplicationQuestionFileUploadParams struct {
@@ -200,6 +200,8 @@ type CreateApplicationQuestionFileUploadParams struct {
 	OrderNum                 int32       `json:"order_num"`
 	AccessibleForOtherPhases bool        `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 func (q *Queries) CreateApplicationQuestionFileUpload(ctx context.Context, arg CreateApplicationQuestionFileUploadParams) error {
@@ -214,13 +216,15 @@ func (q *Queries) CreateApplicationQuestionFileUpload(ctx context.Context, arg C
 		arg.OrderNum,
 		arg.AccessibleForOtherPhases,
 		arg.AccessKey,
+		arg.DisplayCondition,
+		arg.IsIdentifying,
 	)
 	return err
 }
 
 const createApplicationQuestionMultiSelect = `-- name: CreateApplicationQuestionMultiSelect :exec
-INSERT INTO application_question_multi_select (id, course_phase_id, title, description, placeholder, error_message, is_required, min_select, max_select, options, order_num, accessible_for_other_phases, access_key)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+INSERT INTO application_question_multi_select (id, course_phase_id, title, description, placeholder, error_message, is_required, min_select, max_select, options, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 `
 
 type CreateApplicationQuestionMultiSelectParams struct {
@@ -237,6 +241,8 @@ type CreateApplicationQuestionMultiSelectParams struct {
 	OrderNum                 pgtype.Int4 `json:"order_num"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 func (q *Queries) CreateApplicationQuestionMultiSelect(ctx context.Context, arg CreateApplicationQuestionMultiSelectParams) error {
@@ -254,13 +260,15 @@ func (q *Queries) CreateApplicationQuestionMultiSelect(ctx context.Context, arg
 		arg.OrderNum,
 		arg.AccessibleForOtherPhases,
 		arg.AccessKey,
+		arg.DisplayCondition,
+		arg.IsIdentifying,
 	)
 	return err
 }
 
 const createApplicationQuestionText = `-- name: CreateApplicationQuestionText :exec
-INSERT INTO application_question_text (id, course_phase_id, title, description, placeholder, validation_regex, error_message, is_required, allowed_length, order_num, accessible_for_other_phases, access_key)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+INSERT INTO application_question_text (id, course_phase_id, title, description, placeholder, validation_regex, error_message, is_required, allowed_length, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 `
 
 type CreateApplicationQuestionTextParams struct {
@@ -276,6 +284,8 @@ type CreateApplicationQuestionTextParams struct {
 	OrderNum                 pgtype.Int4 `json:"order_num"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 func (q *Queries) CreateApplicationQuestionText(ctx context.Context, arg CreateApplicationQuestionTextParams) error {
@@ -292,6 +302,8 @@ func (q *Queries) CreateApplicationQuestionText(ctx context.Context, arg CreateA
 		arg.OrderNum,
 		arg.AccessibleForOtherPhases,
 		arg.AccessKey,
+		arg.DisplayCondition,
+		arg.IsIdentifying,
 	)
 	return err
 }
@@ -422,36 +434,76 @@ func (q *Queries) DeleteApplications(ctx context.Context, arg DeleteApplications
 }
 
 const getAllApplicationParticipations = `-- name: GetAllApplicationParticipations :many
-SELECT
-    cpp.course_phase_id,
-    cpp.course_participation_id,
-    cpp.pass_status,
-    cpp.restricted_data,
-    s.id AS student_id,
-    s.first_name,
-    s.last_name,
-    s.email,
-    s.matriculation_number,
-    s.university_login,
-    s.has_university_account,
-    s.gender, 
-    s.nationality,
-    s.study_degree,
-    s.study_program,
-    s.current_semester,
-    a.score
-FROM
-    course_phase_participation cpp
-JOIN
-    course_participation cp ON cpp.course_participation_id = cp.id
-JOIN
-    student s ON cp.student_id = s.id
-LEFT JOIN
-    application_assessment a ON cpp.course_participation_id = a.course_participation_id AND cpp.course_phase_id = a.course_phase_id
-WHERE
-    cpp.course_phase_id = $1
+WITH matches AS (
+    SELECT
+        cpp.course_phase_id,
+        cpp.course_participation_id,
+        cpp.pass_status,
+        cpp.restricted_data,
+        s.id AS student_id,
+        s.first_name,
+        s.last_name,
+        s.email,
+        s.matriculation_number,
+        s.university_login,
+        s.has_university_account,
+        s.gender,
+        s.nationality,
+        s.study_degree,
+        s.study_program,
+        s.current_semester,
+        a.score,
+        (CASE $1::text
+            WHEN 'firstName' THEN lower(coalesce(s.first_name, '') || ' ' || coalesce(s.last_name, ''))
+            WHEN 'email' THEN lower(coalesce(s.email, ''))
+            -- the offset makes the text order of the scores match their numeric order, unscored applications come first
+            WHEN 'score' THEN coalesce(lpad((a.score::bigint + 2147483648)::text, 10, '0'), '')
+            ELSE lower(coalesce(s.last_name, '') || ' ' || coalesce(s.first_name, ''))
+        END)::text       AS sort_key,
+        COUNT(*) OVER () AS total_count
+    FROM
+        course_phase_participation cpp
+    JOIN
+        course_participation cp ON cpp.course_participation_id = cp.id
+    JOIN
+        student s ON cp.student_id = s.id
+    LEFT JOIN
+        application_assessment a ON cpp.course_participation_id = a.course_participation_id AND cpp.course_phase_id = a.course_phase_id
+    WHERE
+        cpp.course_phase_id = $2
+        AND ($3::pass_status IS NULL
+             OR coalesce(cpp.pass_status, 'not_assessed') = $3::pass_status)
+        -- every metadata filter has to match the restricted data
+        AND NOT EXISTS (
+            SELECT 1
+            FROM jsonb_each_text(coalesce($4::jsonb, '{}'::jsonb)) AS filter
+            WHERE cpp.restricted_data ->> filter.key IS DISTINCT FROM filter.value
+        )
+)
+SELECT course_phase_id, course_participation_id, pass_status, restricted_data, student_id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_degree, study_program, current_semester, score, sort_key, total_count
+FROM matches
+WHERE $5::uuid IS NULL
+   OR ($6::boolean AND (sort_key, course_participation_id) < ($7::text, $5::uuid))
+   OR (NOT $6::boolean AND (sort_key, course_participation_id) > ($7::text, $5::uuid))
+ORDER BY
+    CASE WHEN $6::boolean THEN sort_key END DESC,
+    CASE WHEN $6::boolean THEN course_participation_id END DESC,
+    CASE WHEN NOT $6::boolean THEN sort_key END,
+    CASE WHEN NOT $6::boolean THEN course_participation_id END
+LIMIT $8::int
 `
 
+type GetAllApplicationParticipationsParams struct {
+	SortBy        string         `json:"sort_by"`
+	CoursePhaseID uuid.UUID      `json:"course_phase_id"`
+	PassStatus    NullPassStatus `json:"pass_status"`
+	Metadata      []byte         `json:"metadata"`
+	CursorID      pgtype.UUID    `json:"cursor_id"`
+	Descending    bool           `json:"descending"`
+	CursorKey     pgtype.Text    `json:"cursor_key"`
+	PageSize      pgtype.Int4    `json:"page_size"`
+}
+
 type GetAllApplicationParticipationsRow struct {
 	CoursePhaseID         uuid.UUID      `json:"course_phase_id"`
 	CourseParticipationID uuid.UUID      `json:"course_participation_id"`
@@ -470,10 +522,22 @@ type GetAllApplicationParticipationsRow struct {
 	StudyProgram          pgtype.Text    `json:"study_program"`
 	CurrentSemester       pgtype.Int4    `json:"current_semester"`
 	Score                 pgtype.Int4    `json:"score"`
+	SortKey               string         `json:"sort_key"`
+	TotalCount            int64          `json:"total_count"`
 }
 
-func (q *Queries) GetAllApplicationParticipations(ctx context.Context, coursePhaseID uuid.UUID) ([]GetAllApplicationParticipationsRow, error) {
-	rows, err := q.db.Query(ctx, getAllApplicationParticipations, coursePhaseID)
+// without page size all matching applications are returned, the total count ignores the cursor and the page size
+func (q *Queries) GetAllApplicationParticipations(ctx context.Context, arg GetAllApplicationParticipationsParams) ([]GetAllApplicationParticipationsRow, error) {
+	rows, err := q.db.Query(ctx, getAllApplicationParticipations,
+		arg.SortBy,
+		arg.CoursePhaseID,
+		arg.PassStatus,
+		arg.Metadata,
+		arg.CursorID,
+		arg.Descending,
+		arg.CursorKey,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -499,6 +563,8 @@ func (q *Queries) GetAllApplicationParticipations(ctx context.Context, coursePha
 			&i.StudyProgram,
 			&i.CurrentSemester,
 			&i.Score,
+			&i.SortKey,
+			&i.TotalCount,
 		); err != nil {
 			return nil, err
 		}
@@ -718,6 +784,44 @@ func (q *Queries) GetApplicationAnswersTextForCourseParticipationID(ctx context.
 	return items, nil
 }
 
+const getApplicationAssessmentScore = `-- name: GetApplicationAssessmentScore :one
+SELECT score
+FROM application_assessment
+WHERE course_phase_id = $1 AND course_participation_id = $2
+`
+
+type GetApplicationAssessmentScoreParams struct {
+	CoursePhaseID         uuid.UUID `json:"course_phase_id"`
+	CourseParticipationID uuid.UUID `json:"course_participation_id"`
+}
+
+func (q *Queries) GetApplicationAssessmentScore(ctx context.Context, arg GetApplicationAssessmentScoreParams) (pgtype.Int4, error) {
+	row := q.db.QueryRow(ctx, getApplicationAssessmentScore, arg.CoursePhaseID, arg.CourseParticipationID)
+	var score pgtype.Int4
+	err := row.Scan(&score)
+	return score, err
+}
+
+const getApplicationBlindReviewSettings = `-- name: GetApplicationBlindReviewSettings :one
+SELECT
+    COALESCE((restricted_data->>'blindReview')::boolean, false) AS blind_review,
+    COALESCE((restricted_data->>'blindReviewRevealed')::boolean, false) AS blind_review_revealed
+FROM course_phase
+WHERE id = $1
+`
+
+type GetApplicationBlindReviewSettingsRow struct {
+	BlindReview         bool `json:"blind_review"`
+	BlindReviewRevealed bool `json:"blind_review_revealed"`
+}
+
+func (q *Queries) GetApplicationBlindReviewSettings(ctx context.Context, id uuid.UUID) (GetApplicationBlindReviewSettingsRow, error) {
+	row := q.db.QueryRow(ctx, getApplicationBlindReviewSettings, id)
+	var i GetApplicationBlindReviewSettingsRow
+	err := row.Scan(&i.BlindReview, &i.BlindReviewRevealed)
+	return i, err
+}
+
 const getApplicationExists = `-- name: GetApplicationExists :one
 SELECT EXISTS (
     SELECT 1
@@ -760,6 +864,76 @@ func (q *Queries) GetApplicationExistsForStudent(ctx context.Context, arg GetApp
 	return exists, err
 }
 
+const getApplicationFileUploadsForCoursePhase = `-- name: GetApplicationFileUploadsForCoursePhase :many
+SELECT
+    aafu.file_id,
+    aafu.course_participation_id,
+    aafu.application_question_id,
+    aqfu.title AS question_title,
+    f.original_filename,
+    s.first_name,
+    s.last_name,
+    a.score
+FROM application_answer_file_upload aafu
+JOIN application_question_file_upload aqfu ON aafu.application_question_id = aqfu.id
+JOIN course_participation cp ON aafu.course_participation_id = cp.id
+JOIN student s ON cp.student_id = s.id
+JOIN files f ON aafu.file_id = f.id
+LEFT JOIN application_assessment a ON aafu.course_participation_id = a.course_participation_id AND aqfu.course_phase_id = a.course_phase_id
+WHERE aqfu.course_phase_id = $1
+  AND f.deleted_at IS NULL
+  AND f.scan_status = 'clean'
+  AND (COALESCE(cardinality($2::uuid[]), 0) = 0 OR aafu.application_question_id = ANY($2::uuid[]))
+  AND (COALESCE(cardinality($3::uuid[]), 0) = 0 OR aafu.course_participation_id = ANY($3::uuid[]))
+ORDER BY s.last_name, s.first_name, aafu.course_participation_id, aqfu.order_num
+`
+
+type GetApplicationFileUploadsForCoursePhaseParams struct {
+	CoursePhaseID          uuid.UUID   `json:"course_phase_id"`
+	QuestionIds            []uuid.UUID `json:"question_ids"`
+	CourseParticipationIds []uuid.UUID `json:"course_participation_ids"`
+}
+
+type GetApplicationFileUploadsForCoursePhaseRow struct {
+	FileID                uuid.UUID   `json:"file_id"`
+	CourseParticipationID uuid.UUID   `json:"course_participation_id"`
+	ApplicationQuestionID uuid.UUID   `json:"application_question_id"`
+	QuestionTitle         string      `json:"question_title"`
+	OriginalFilename      string      `json:"original_filename"`
+	FirstName             pgtype.Text `json:"first_name"`
+	LastName              pgtype.Text `json:"last_name"`
+	Score                 pgtype.Int4 `json:"score"`
+}
+
+func (q *Queries) GetApplicationFileUploadsForCoursePhase(ctx context.Context, arg GetApplicationFileUploadsForCoursePhaseParams) ([]GetApplicationFileUploadsForCoursePhaseRow, error) {
+	rows, err := q.db.Query(ctx, getApplicationFileUploadsForCoursePhase, arg.CoursePhaseID, arg.QuestionIds, arg.CourseParticipationIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetApplicationFileUploadsForCoursePhaseRow
+	for rows.Next() {
+		var i GetApplicationFileUploadsForCoursePhaseRow
+		if err := rows.Scan(
+			&i.FileID,
+			&i.CourseParticipationID,
+			&i.ApplicationQuestionID,
+			&i.QuestionTitle,
+			&i.OriginalFilename,
+			&i.FirstName,
+			&i.LastName,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getApplicationPhaseIDForCourse = `-- name: GetApplicationPhaseIDForCourse :one
 SELECT cp.id
 FROM course_phase cp
@@ -777,7 +951,7 @@ func (q *Queries) GetApplicationPhaseIDForCourse(ctx context.Context, courseID u
 }
 
 const getApplicationQuestionsFileUploadForCoursePhase = `-- name: GetApplicationQuestionsFileUploadForCoursePhase :many
-SELECT id, course_phase_id, title, description, is_required, allowed_file_types, max_file_size_mb, order_num, accessible_for_other_phases, access_key FROM application_question_file_upload
+SELECT id, course_phase_id, title, description, is_required, allowed_file_types, max_file_size_mb, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying FROM application_question_file_upload
 WHERE course_phase_id = $1
 `
 
@@ -801,6 +975,8 @@ func (q *Queries) GetApplicationQuestionsFileUploadForCoursePhase(ctx context.Co
 			&i.OrderNum,
 			&i.AccessibleForOtherPhases,
 			&i.AccessKey,
+			&i.DisplayCondition,
+			&i.IsIdentifying,
 		); err != nil {
 			return nil, err
 		}
@@ -813,7 +989,7 @@ func (q *Queries) GetApplicationQuestionsFileUploadForCoursePhase(ctx context.Co
 }
 
 const getApplicationQuestionsMultiSelectForCoursePhase = `-- name: GetApplicationQuestionsMultiSelectForCoursePhase :many
-SELECT id, course_phase_id, title, description, placeholder, error_message, is_required, min_select, max_select, options, order_num, accessible_for_other_phases, access_key FROM application_question_multi_select
+SELECT id, course_phase_id, title, description, placeholder, error_message, is_required, min_select, max_select, options, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying FROM application_question_multi_select
 WHERE course_phase_id = $1
 `
 
@@ -840,6 +1016,8 @@ func (q *Queries) GetApplicationQuestionsMultiSelectForCoursePhase(ctx context.C
 			&i.OrderNum,
 			&i.AccessibleForOtherPhases,
 			&i.AccessKey,
+			&i.DisplayCondition,
+			&i.IsIdentifying,
 		); err != nil {
 			return nil, err
 		}
@@ -852,7 +1030,7 @@ func (q *Queries) GetApplicationQuestionsMultiSelectForCoursePhase(ctx context.C
 }
 
 const getApplicationQuestionsTextForCoursePhase = `-- name: GetApplicationQuestionsTextForCoursePhase :many
-SELECT id, course_phase_id, title, description, placeholder, validation_regex, error_message, is_required, allowed_length, order_num, accessible_for_other_phases, access_key FROM application_question_text
+SELECT id, course_phase_id, title, description, placeholder, validation_regex, error_message, is_required, allowed_length, order_num, accessible_for_other_phases, access_key, display_condition, is_identifying FROM application_question_text
 WHERE course_phase_id = $1
 `
 
@@ -878,6 +1056,8 @@ func (q *Queries) GetApplicationQuestionsTextForCoursePhase(ctx context.Context,
 			&i.OrderNum,
 			&i.AccessibleForOtherPhases,
 			&i.AccessKey,
+			&i.DisplayCondition,
+			&i.IsIdentifying,
 		); err != nil {
 			return nil, err
 		}
@@ -905,6 +1085,34 @@ func (q *Queries) GetExistingAdditionalScores(ctx context.Context, id uuid.UUID)
 	return additional_scores, err
 }
 
+const getIdentifyingApplicationQuestionIDs = `-- name: GetIdentifyingApplicationQuestionIDs :many
+SELECT id FROM application_question_text WHERE course_phase_id = $1 AND is_identifying
+UNION ALL
+SELECT id FROM application_question_multi_select WHERE course_phase_id = $1 AND is_identifying
+UNION ALL
+SELECT id FROM application_question_file_upload WHERE course_phase_id = $1 AND is_identifying
+`
+
+func (q *Queries) GetIdentifyingApplicationQuestionIDs(ctx context.Context, coursePhaseID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getIdentifyingApplicationQuestionIDs, coursePhaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getOpenApplicationPhase = `-- name: GetOpenApplicationPhase :one
 SELECT 
     cp.id AS course_phase_id,
@@ -968,6 +1176,17 @@ func (q *Queries) GetOpenApplicationPhase(ctx context.Context, id uuid.UUID) (Ge
 	return i, err
 }
 
+const revealApplicationBlindReview = `-- name: RevealApplicationBlindReview :exec
+UPDATE course_phase
+SET restricted_data = restricted_data || '{"blindReviewRevealed": true}'::jsonb
+WHERE id = $1
+`
+
+func (q *Queries) RevealApplicationBlindReview(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, revealApplicationBlindReview, id)
+	return err
+}
+
 const storeApplicationAnswerUpdateTimestamp = `-- name: StoreApplicationAnswerUpdateTimestamp :exec
 UPDATE course_phase_participation
 SET restricted_data = jsonb_set(
@@ -1045,7 +1264,9 @@ SET
     max_file_size_mb = COALESCE($6, max_file_size_mb),
     order_num = COALESCE($7, order_num),
     accessible_for_other_phases = COALESCE($8, accessible_for_other_phases),
-    access_key = COALESCE($9, access_key)
+    access_key = COALESCE($9, access_key),
+    display_condition = $10,
+    is_identifying = $11
 WHERE id = $1
 `
 
@@ -1059,6 +1280,8 @@ type UpdateApplicationQuestionFileUploadParams struct {
 	OrderNum                 int32       `json:"order_num"`
 	AccessibleForOtherPhases bool        `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 func (q *Queries) UpdateApplicationQuestionFileUpload(ctx context.Context, arg UpdateApplicationQuestionFileUploadParams) error {
@@ -1072,6 +1295,8 @@ func (q *Queries) UpdateApplicationQuestionFileUpload(ctx context.Context, arg U
 		arg.OrderNum,
 		arg.AccessibleForOtherPhases,
 		arg.AccessKey,
+		arg.DisplayCondition,
+		arg.IsIdentifying,
 	)
 	return err
 }
@@ -1089,7 +1314,9 @@ SET
     options = COALESCE($9, options),
     order_num = COALESCE($10, order_num), 
     accessible_for_other_phases = COALESCE($11, accessible_for_other_phases),
-    access_key = COALESCE($12, access_key)
+    access_key = COALESCE($12, access_key),
+    display_condition = $13,
+    is_identifying = $14
 WHERE id = $1
 `
 
@@ -1106,6 +1333,8 @@ type UpdateApplicationQuestionMultiSelectParams struct {
 	OrderNum                 pgtype.Int4 `json:"order_num"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 func (q *Queries) UpdateApplicationQuestionMultiSelect(ctx context.Context, arg UpdateApplicationQuestionMultiSelectParams) error {
@@ -1122,6 +1351,8 @@ func (q *Queries) UpdateApplicationQuestionMultiSelect(ctx context.Context, arg
 		arg.OrderNum,
 		arg.AccessibleForOtherPhases,
 		arg.AccessKey,
+		arg.DisplayCondition,
+		arg.IsIdentifying,
 	)
 	return err
 }
@@ -1138,7 +1369,9 @@ SET
     allowed_length = COALESCE($8, allowed_length),
     order_num = COALESCE($9, order_num),
     accessible_for_other_phases = COALESCE($10, accessible_for_other_phases),
-    access_key = COALESCE($11, access_key)
+    access_key = COALESCE($11, access_key),
+    display_condition = $12,
+    is_identifying = $13
 WHERE id = $1
 `
 
@@ -1154,6 +1387,8 @@ type UpdateApplicationQuestionTextParams struct {
 	OrderNum                 pgtype.Int4 `json:"order_num"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 func (q *Queries) UpdateApplicationQuestionText(ctx context.Context, arg UpdateApplicationQuestionTextParams) error {
@@ -1169,6 +1404,8 @@ func (q *Queries) UpdateApplicationQuestionText(ctx context.Context, arg UpdateA
 		arg.OrderNum,
 		arg.AccessibleForOtherPhases,
 		arg.AccessKey,
+		arg.DisplayCondition,
+		arg.IsIdentifying,
 	)
 	return err
 }
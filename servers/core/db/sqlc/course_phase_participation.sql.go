@@ -237,12 +237,12 @@ WITH
   
   -----------------------------------------------------------------------
   -- 2) Qualified non-participants:
-  --    They do NOT yet have a participation in $1 and
+  --    They do NOT yet have a participation in the course phase and
   --    must have passed ALL direct_predecessors_for_pass.
   -----------------------------------------------------------------------
   qualified_non_participants AS (
       SELECT
-          $1::uuid                     AS course_phase_id,
+          $1::uuid AS course_phase_id,
           cp.id                        AS course_participation_id,
           'not_assessed'::pass_status  AS pass_status,
           '{}'::jsonb                  AS restricted_data,
@@ -279,10 +279,56 @@ WITH
                 AND pcpp.course_participation_id = cp.id
               WHERE pcpp.pass_status = 'passed'
           )
+  ),
+
+  -----------------------------------------------------------------------
+  -- 3) Participants matching the filters, with their sort key and count
+  -----------------------------------------------------------------------
+  matches AS (
+      SELECT
+          participants.course_phase_id, participants.course_participation_id, participants.pass_status, participants.restricted_data, participants.student_readable_data, participants.student_id, participants.first_name, participants.last_name, participants.email, participants.matriculation_number, participants.university_login, participants.has_university_account, participants.gender, participants.nationality, participants.study_degree, participants.study_program, participants.current_semester,
+          (CASE $2::text
+              WHEN 'firstName' THEN lower(coalesce(participants.first_name, '') || ' ' || coalesce(participants.last_name, ''))
+              WHEN 'email' THEN lower(coalesce(participants.email, ''))
+              WHEN 'passStatus' THEN coalesce(participants.pass_status::text, 'not_assessed')
+              ELSE lower(coalesce(participants.last_name, '') || ' ' || coalesce(participants.first_name, ''))
+          END)::text                   AS sort_key,
+          COUNT(*) OVER ()             AS total_count
+      FROM
+      (
+          SELECT course_phase_id, course_participation_id, pass_status, restricted_data, student_readable_data, student_id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_degree, study_program, current_semester FROM current_phase_participations
+          UNION
+          SELECT course_phase_id, course_participation_id, pass_status, restricted_data, student_readable_data, student_id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_degree, study_program, current_semester FROM qualified_non_participants
+      ) AS participants
+      WHERE ($3::pass_status IS NULL
+             OR coalesce(participants.pass_status, 'not_assessed') = $3::pass_status)
+        -- every metadata filter has to match the restricted or the student readable data
+        AND NOT EXISTS (
+            SELECT 1
+            FROM jsonb_each_text(coalesce($4::jsonb, '{}'::jsonb)) AS filter
+            WHERE coalesce(participants.restricted_data ->> filter.key, participants.student_readable_data ->> filter.key) IS DISTINCT FROM filter.value
+        )
+  ),
+
+  -----------------------------------------------------------------------
+  -- 4) The requested page, all participants without page size
+  -----------------------------------------------------------------------
+  page AS (
+      SELECT course_phase_id, course_participation_id, pass_status, restricted_data, student_readable_data, student_id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_degree, study_program, current_semester, sort_key, total_count
+      FROM matches
+      WHERE $5::uuid IS NULL
+         OR ($6::boolean AND (sort_key, course_participation_id) < ($7::text, $5::uuid))
+         OR (NOT $6::boolean AND (sort_key, course_participation_id) > ($7::text, $5::uuid))
+      ORDER BY
+          CASE WHEN $6::boolean THEN sort_key END DESC,
+          CASE WHEN $6::boolean THEN course_participation_id END DESC,
+          CASE WHEN NOT $6::boolean THEN sort_key END,
+          CASE WHEN NOT $6::boolean THEN course_participation_id END
+      LIMIT $8::int
   )
-  
+
 SELECT
-    main.course_phase_id, main.course_participation_id, main.pass_status, main.restricted_data, main.student_readable_data, main.student_id, main.first_name, main.last_name, main.email, main.matriculation_number, main.university_login, main.has_university_account, main.gender, main.nationality, main.study_degree, main.study_program, main.current_semester,
+    main.course_phase_id, main.course_participation_id, main.pass_status, main.restricted_data, main.student_readable_data, main.student_id, main.first_name, main.last_name, main.email, main.matriculation_number, main.university_login, main.has_university_account, main.gender, main.nationality, main.study_degree, main.study_program, main.current_semester, main.sort_key, main.total_count,
     (COALESCE(      
       (
             ----------------------------------------------------------------
@@ -384,15 +430,25 @@ SELECT
          ),
          '{}'::jsonb
       )::jsonb)::jsonb AS prev_data
-FROM
-(
-    SELECT course_phase_id, course_participation_id, pass_status, restricted_data, student_readable_data, student_id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_degree, study_program, current_semester FROM current_phase_participations
-    UNION
-    SELECT course_phase_id, course_participation_id, pass_status, restricted_data, student_readable_data, student_id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_degree, study_program, current_semester FROM qualified_non_participants
-) AS main
-ORDER BY main.last_name, main.first_name
+FROM page AS main
+ORDER BY
+    CASE WHEN $6::boolean THEN main.sort_key END DESC,
+    CASE WHEN $6::boolean THEN main.course_participation_id END DESC,
+    CASE WHEN NOT $6::boolean THEN main.sort_key END,
+    CASE WHEN NOT $6::boolean THEN main.course_participation_id END
 `
 
+type GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousParams struct {
+	CoursePhaseID uuid.UUID      `json:"course_phase_id"`
+	SortBy        string         `json:"sort_by"`
+	PassStatus    NullPassStatus `json:"pass_status"`
+	Metadata      []byte         `json:"metadata"`
+	CursorID      pgtype.UUID    `json:"cursor_id"`
+	Descending    bool           `json:"descending"`
+	CursorKey     pgtype.Text    `json:"cursor_key"`
+	PageSize      pgtype.Int4    `json:"page_size"`
+}
+
 type GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousRow struct {
 	CoursePhaseID         uuid.UUID      `json:"course_phase_id"`
 	CourseParticipationID uuid.UUID      `json:"course_participation_id"`
@@ -411,14 +467,25 @@ type GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousRow struct {
 	StudyDegree           StudyDegree    `json:"study_degree"`
 	StudyProgram          pgtype.Text    `json:"study_program"`
 	CurrentSemester       pgtype.Int4    `json:"current_semester"`
+	SortKey               string         `json:"sort_key"`
+	TotalCount            int64          `json:"total_count"`
 	PrevData              []byte         `json:"prev_data"`
 }
 
 // ---------------------------------------------------------------------
-// 3) Final SELECT: Merge the participants and meta data from all predecessors
+// 5) Final SELECT: Merge the participants of the page and meta data from all predecessors
 // ---------------------------------------------------------------------
-func (q *Queries) GetAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious(ctx context.Context, toCoursePhaseID uuid.UUID) ([]GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousRow, error) {
-	rows, err := q.db.Query(ctx, getAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious, toCoursePhaseID)
+func (q *Queries) GetAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious(ctx context.Context, arg GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousParams) ([]GetAllCoursePhaseParticipationsForCoursePhaseIncludingPreviousRow, error) {
+	rows, err := q.db.Query(ctx, getAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious,
+		arg.CoursePhaseID,
+		arg.SortBy,
+		arg.PassStatus,
+		arg.Metadata,
+		arg.CursorID,
+		arg.Descending,
+		arg.CursorKey,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -444,6 +511,8 @@ func (q *Queries) GetAllCoursePhaseParticipationsForCoursePhaseIncludingPrevious
 			&i.StudyDegree,
 			&i.StudyProgram,
 			&i.CurrentSemester,
+			&i.SortKey,
+			&i.TotalCount,
 			&i.PrevData,
 		); err != nil {
 			return nil, err
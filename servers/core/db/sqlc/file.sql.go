@@ -12,6 +12,40 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countFilesByStorageKey = `-- name: CountFilesByStorageKey :one
+SELECT COUNT(*) FROM files
+WHERE storage_key = $1
+`
+
+// includes soft-deleted files, a stored object is deleted once no file references it anymore
+func (q *Queries) CountFilesByStorageKey(ctx context.Context, storageKey string) (int64, error) {
+	row := q.db.QueryRow(ctx, countFilesByStorageKey, storageKey)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFilesByStorageProvider = `-- name: CountFilesByStorageProvider :one
+SELECT
+    COUNT(DISTINCT storage_key)::bigint AS object_count,
+    COUNT(*)::bigint AS file_count
+FROM files
+WHERE storage_provider = $1
+`
+
+type CountFilesByStorageProviderRow struct {
+	ObjectCount int64 `json:"object_count"`
+	FileCount   int64 `json:"file_count"`
+}
+
+// includes soft-deleted files, files sharing an object are migrated together
+func (q *Queries) CountFilesByStorageProvider(ctx context.Context, storageProvider string) (CountFilesByStorageProviderRow, error) {
+	row := q.db.QueryRow(ctx, countFilesByStorageProvider, storageProvider)
+	var i CountFilesByStorageProviderRow
+	err := row.Scan(&i.ObjectCount, &i.FileCount)
+	return i, err
+}
+
 const countFilesByUploader = `-- name: CountFilesByUploader :one
 SELECT COUNT(*) FROM files
 WHERE uploaded_by_user_id = $1 AND deleted_at IS NULL
@@ -36,10 +70,11 @@ INSERT INTO files (
     uploaded_by_email,
     course_phase_id,
     description,
-    tags
+    tags,
+    encryption_key_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) RETURNING id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id
 `
 
 type CreateFileParams struct {
@@ -54,6 +89,7 @@ type CreateFileParams struct {
 	CoursePhaseID    pgtype.UUID `json:"course_phase_id"`
 	Description      pgtype.Text `json:"description"`
 	Tags             []string    `json:"tags"`
+	EncryptionKeyID  pgtype.UUID `json:"encryption_key_id"`
 }
 
 func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, error) {
@@ -69,6 +105,7 @@ func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, e
 		arg.CoursePhaseID,
 		arg.Description,
 		arg.Tags,
+		arg.EncryptionKeyID,
 	)
 	var i File
 	err := row.Scan(
@@ -87,12 +124,19 @@ func (q *Queries) CreateFile(ctx context.Context, arg CreateFileParams) (File, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
 	)
 	return i, err
 }
 
 const getAllFiles = `-- name: GetAllFiles :many
-SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at FROM files
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
 WHERE deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
@@ -128,6 +172,13 @@ func (q *Queries) GetAllFiles(ctx context.Context, arg GetAllFilesParams) ([]Fil
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -139,8 +190,67 @@ func (q *Queries) GetAllFiles(ctx context.Context, arg GetAllFilesParams) ([]Fil
 	return items, nil
 }
 
+const getDuplicateFileInCourse = `-- name: GetDuplicateFileInCourse :one
+SELECT f.id, f.filename, f.original_filename, f.content_type, f.size_bytes, f.storage_key, f.storage_provider, f.uploaded_by_user_id, f.uploaded_by_email, f.course_phase_id, f.description, f.tags, f.created_at, f.updated_at, f.deleted_at, f.scan_status, f.scan_result, f.scanned_at, f.content_sha256, f.previous_version_id, f.version, f.encryption_key_id FROM files f
+JOIN course_phase cp ON cp.id = f.course_phase_id
+WHERE cp.course_id = (SELECT course_id FROM course_phase WHERE course_phase.id = $1)
+  AND f.content_sha256 = $2
+  AND f.storage_provider = $3
+  AND f.encryption_key_id IS NOT DISTINCT FROM $4
+  AND f.id <> $5
+  AND f.scan_status = 'clean'
+  AND f.deleted_at IS NULL
+ORDER BY f.created_at
+LIMIT 1
+`
+
+type GetDuplicateFileInCourseParams struct {
+	CoursePhaseID   uuid.UUID   `json:"course_phase_id"`
+	ContentSha256   pgtype.Text `json:"content_sha256"`
+	StorageProvider string      `json:"storage_provider"`
+	EncryptionKeyID pgtype.UUID `json:"encryption_key_id"`
+	ID              uuid.UUID   `json:"id"`
+}
+
+// the oldest file of the same course with the same content, whose stored object can be shared
+func (q *Queries) GetDuplicateFileInCourse(ctx context.Context, arg GetDuplicateFileInCourseParams) (File, error) {
+	row := q.db.QueryRow(ctx, getDuplicateFileInCourse,
+		arg.CoursePhaseID,
+		arg.ContentSha256,
+		arg.StorageProvider,
+		arg.EncryptionKeyID,
+		arg.ID,
+	)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Filename,
+		&i.OriginalFilename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.StorageProvider,
+		&i.UploadedByUserID,
+		&i.UploadedByEmail,
+		&i.CoursePhaseID,
+		&i.Description,
+		&i.Tags,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
+	)
+	return i, err
+}
+
 const getFileByID = `-- name: GetFileByID :one
-SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at FROM files
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -163,12 +273,19 @@ func (q *Queries) GetFileByID(ctx context.Context, id uuid.UUID) (File, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
 	)
 	return i, err
 }
 
 const getFileByStorageKey = `-- name: GetFileByStorageKey :one
-SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at FROM files
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
 WHERE storage_key = $1 AND deleted_at IS NULL
 `
 
@@ -191,12 +308,119 @@ func (q *Queries) GetFileByStorageKey(ctx context.Context, storageKey string) (F
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
 	)
 	return i, err
 }
 
+const getFileStorageKeys = `-- name: GetFileStorageKeys :many
+SELECT id, storage_key, storage_provider, size_bytes
+FROM files
+ORDER BY storage_key
+`
+
+type GetFileStorageKeysRow struct {
+	ID              uuid.UUID `json:"id"`
+	StorageKey      string    `json:"storage_key"`
+	StorageProvider string    `json:"storage_provider"`
+	SizeBytes       int64     `json:"size_bytes"`
+}
+
+// includes soft-deleted files, whose objects are kept until the retention period is over
+func (q *Queries) GetFileStorageKeys(ctx context.Context) ([]GetFileStorageKeysRow, error) {
+	rows, err := q.db.Query(ctx, getFileStorageKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFileStorageKeysRow
+	for rows.Next() {
+		var i GetFileStorageKeysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StorageKey,
+			&i.StorageProvider,
+			&i.SizeBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFileVersions = `-- name: GetFileVersions :many
+WITH RECURSIVE versions AS (
+    SELECT p.id, p.previous_version_id
+    FROM files c
+    JOIN files p ON p.id = c.previous_version_id
+    WHERE c.id = $1
+    UNION
+    SELECT f.id, f.previous_version_id
+    FROM files f
+    JOIN versions v ON f.id = v.previous_version_id
+)
+SELECT f.id, f.filename, f.original_filename, f.content_type, f.size_bytes, f.storage_key, f.storage_provider, f.uploaded_by_user_id, f.uploaded_by_email, f.course_phase_id, f.description, f.tags, f.created_at, f.updated_at, f.deleted_at, f.scan_status, f.scan_result, f.scanned_at, f.content_sha256, f.previous_version_id, f.version, f.encryption_key_id FROM files f
+JOIN versions v ON v.id = f.id
+WHERE f.deleted_at IS NULL
+ORDER BY f.version DESC, f.created_at DESC
+`
+
+// the prior versions of a file, the newest first
+func (q *Queries) GetFileVersions(ctx context.Context, id uuid.UUID) ([]File, error) {
+	rows, err := q.db.Query(ctx, getFileVersions, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.OriginalFilename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.StorageProvider,
+			&i.UploadedByUserID,
+			&i.UploadedByEmail,
+			&i.CoursePhaseID,
+			&i.Description,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFilesByCoursePhaseID = `-- name: GetFilesByCoursePhaseID :many
-SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at FROM files
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
 WHERE course_phase_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -226,6 +450,76 @@ func (q *Queries) GetFilesByCoursePhaseID(ctx context.Context, coursePhaseID pgt
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilesByCoursePhaseIDAndTags = `-- name: GetFilesByCoursePhaseIDAndTags :many
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
+WHERE course_phase_id = $1
+  AND deleted_at IS NULL
+  AND (COALESCE(cardinality($2::varchar[]), 0) = 0 OR tags @> $2::varchar[])
+  AND ($3::text IS NULL OR uploaded_by_user_id = $3::text)
+  AND NOT EXISTS (SELECT 1 FROM application_answer_file_upload a WHERE a.file_id = files.id)
+  AND NOT EXISTS (SELECT 1 FROM note_file n WHERE n.file_id = files.id)
+  AND NOT EXISTS (SELECT 1 FROM files successor WHERE successor.previous_version_id = files.id)
+ORDER BY created_at DESC
+`
+
+type GetFilesByCoursePhaseIDAndTagsParams struct {
+	CoursePhaseID    pgtype.UUID `json:"course_phase_id"`
+	Tags             []string    `json:"tags"`
+	UploadedByUserID pgtype.Text `json:"uploaded_by_user_id"`
+}
+
+// a file has to have all given tags, no tags match every file.
+// files of application answers and notes, including replaced versions, are only listed through them
+func (q *Queries) GetFilesByCoursePhaseIDAndTags(ctx context.Context, arg GetFilesByCoursePhaseIDAndTagsParams) ([]File, error) {
+	rows, err := q.db.Query(ctx, getFilesByCoursePhaseIDAndTags, arg.CoursePhaseID, arg.Tags, arg.UploadedByUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.OriginalFilename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.StorageProvider,
+			&i.UploadedByUserID,
+			&i.UploadedByEmail,
+			&i.CoursePhaseID,
+			&i.Description,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -238,7 +532,7 @@ func (q *Queries) GetFilesByCoursePhaseID(ctx context.Context, coursePhaseID pgt
 }
 
 const getFilesByTags = `-- name: GetFilesByTags :many
-SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at FROM files
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
 WHERE tags && $1::VARCHAR[] AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -275,6 +569,13 @@ func (q *Queries) GetFilesByTags(ctx context.Context, arg GetFilesByTagsParams)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -287,7 +588,7 @@ func (q *Queries) GetFilesByTags(ctx context.Context, arg GetFilesByTagsParams)
 }
 
 const getFilesByUploader = `-- name: GetFilesByUploader :many
-SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at FROM files
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
 WHERE uploaded_by_user_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -324,6 +625,116 @@ func (q *Queries) GetFilesByUploader(ctx context.Context, arg GetFilesByUploader
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilesDeletedBefore = `-- name: GetFilesDeletedBefore :many
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
+WHERE deleted_at IS NOT NULL
+  AND deleted_at < CURRENT_TIMESTAMP - make_interval(secs => $1::int)
+ORDER BY deleted_at
+`
+
+func (q *Queries) GetFilesDeletedBefore(ctx context.Context, retentionSeconds int32) ([]File, error) {
+	rows, err := q.db.Query(ctx, getFilesDeletedBefore, retentionSeconds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.OriginalFilename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.StorageProvider,
+			&i.UploadedByUserID,
+			&i.UploadedByEmail,
+			&i.CoursePhaseID,
+			&i.Description,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFilesToScan = `-- name: GetFilesToScan :many
+SELECT id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id FROM files
+WHERE scan_status IN ('pending', 'failed')
+  AND deleted_at IS NULL
+  AND created_at < CURRENT_TIMESTAMP - INTERVAL '5 minutes'
+ORDER BY created_at
+LIMIT $1
+`
+
+// files that were just uploaded are still being scanned by their upload request
+func (q *Queries) GetFilesToScan(ctx context.Context, limit int32) ([]File, error) {
+	rows, err := q.db.Query(ctx, getFilesToScan, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []File
+	for rows.Next() {
+		var i File
+		if err := rows.Scan(
+			&i.ID,
+			&i.Filename,
+			&i.OriginalFilename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.StorageKey,
+			&i.StorageProvider,
+			&i.UploadedByUserID,
+			&i.UploadedByEmail,
+			&i.CoursePhaseID,
+			&i.Description,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.ScanStatus,
+			&i.ScanResult,
+			&i.ScannedAt,
+			&i.ContentSha256,
+			&i.PreviousVersionID,
+			&i.Version,
+			&i.EncryptionKeyID,
 		); err != nil {
 			return nil, err
 		}
@@ -335,6 +746,47 @@ func (q *Queries) GetFilesByUploader(ctx context.Context, arg GetFilesByUploader
 	return items, nil
 }
 
+const getStorageKeysToMigrate = `-- name: GetStorageKeysToMigrate :many
+SELECT storage_key, COUNT(*) AS file_count
+FROM files
+WHERE storage_provider = $1
+  AND storage_key > $2::text
+GROUP BY storage_key
+ORDER BY storage_key
+LIMIT $3
+`
+
+type GetStorageKeysToMigrateParams struct {
+	StorageProvider string `json:"storage_provider"`
+	AfterStorageKey string `json:"after_storage_key"`
+	BatchSize       int32  `json:"batch_size"`
+}
+
+type GetStorageKeysToMigrateRow struct {
+	StorageKey string `json:"storage_key"`
+	FileCount  int64  `json:"file_count"`
+}
+
+func (q *Queries) GetStorageKeysToMigrate(ctx context.Context, arg GetStorageKeysToMigrateParams) ([]GetStorageKeysToMigrateRow, error) {
+	rows, err := q.db.Query(ctx, getStorageKeysToMigrate, arg.StorageProvider, arg.AfterStorageKey, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStorageKeysToMigrateRow
+	for rows.Next() {
+		var i GetStorageKeysToMigrateRow
+		if err := rows.Scan(&i.StorageKey, &i.FileCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTotalFileSizeByUploader = `-- name: GetTotalFileSizeByUploader :one
 SELECT COALESCE(SUM(size_bytes), 0) as total_size
 FROM files
@@ -358,6 +810,76 @@ func (q *Queries) HardDeleteFile(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const hardDeleteFileWithStorageKey = `-- name: HardDeleteFileWithStorageKey :execrows
+DELETE FROM files
+WHERE id = $1 AND storage_key = $2 AND storage_provider = $3
+`
+
+type HardDeleteFileWithStorageKeyParams struct {
+	ID              uuid.UUID `json:"id"`
+	StorageKey      string    `json:"storage_key"`
+	StorageProvider string    `json:"storage_provider"`
+}
+
+// the record is kept if its object was replaced in the meantime, e.g. by a deduplication or an encryption
+func (q *Queries) HardDeleteFileWithStorageKey(ctx context.Context, arg HardDeleteFileWithStorageKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, hardDeleteFileWithStorageKey, arg.ID, arg.StorageKey, arg.StorageProvider)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const isFileReferencedByApplicationOrNote = `-- name: IsFileReferencedByApplicationOrNote :one
+SELECT EXISTS (
+    SELECT 1 FROM application_answer_file_upload a WHERE a.file_id = $1
+    UNION ALL
+    SELECT 1 FROM note_file n WHERE n.file_id = $1
+    UNION ALL
+    SELECT 1 FROM files successor WHERE successor.previous_version_id = $1
+)
+`
+
+// replaced versions of a file are only kept for application answers
+func (q *Queries) IsFileReferencedByApplicationOrNote(ctx context.Context, fileID uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isFileReferencedByApplicationOrNote, fileID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const linkFileVersion = `-- name: LinkFileVersion :execrows
+WITH RECURSIVE ancestors AS (
+    SELECT id, previous_version_id FROM files WHERE id = $1
+    UNION
+    SELECT f.id, f.previous_version_id
+    FROM files f
+    JOIN ancestors a ON f.id = a.previous_version_id
+)
+UPDATE files
+SET
+    previous_version_id = $1,
+    version = (SELECT p.version + 1 FROM files p WHERE p.id = $1),
+    updated_at = CURRENT_TIMESTAMP
+WHERE files.id = $2
+  AND files.previous_version_id IS NULL
+  AND files.id NOT IN (SELECT id FROM ancestors)
+`
+
+type LinkFileVersionParams struct {
+	PreviousVersionID uuid.UUID `json:"previous_version_id"`
+	ID                uuid.UUID `json:"id"`
+}
+
+// files that are already part of the version chain are not linked again, so the chain cannot become a cycle
+func (q *Queries) LinkFileVersion(ctx context.Context, arg LinkFileVersionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, linkFileVersion, arg.PreviousVersionID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const softDeleteFile = `-- name: SoftDeleteFile :exec
 UPDATE files
 SET deleted_at = CURRENT_TIMESTAMP
@@ -369,6 +891,59 @@ func (q *Queries) SoftDeleteFile(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const updateFileContent = `-- name: UpdateFileContent :one
+UPDATE files
+SET
+    content_sha256 = $2,
+    storage_key = $3,
+    encryption_key_id = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id
+`
+
+type UpdateFileContentParams struct {
+	ID              uuid.UUID   `json:"id"`
+	ContentSha256   pgtype.Text `json:"content_sha256"`
+	StorageKey      string      `json:"storage_key"`
+	EncryptionKeyID pgtype.UUID `json:"encryption_key_id"`
+}
+
+func (q *Queries) UpdateFileContent(ctx context.Context, arg UpdateFileContentParams) (File, error) {
+	row := q.db.QueryRow(ctx, updateFileContent,
+		arg.ID,
+		arg.ContentSha256,
+		arg.StorageKey,
+		arg.EncryptionKeyID,
+	)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Filename,
+		&i.OriginalFilename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.StorageProvider,
+		&i.UploadedByUserID,
+		&i.UploadedByEmail,
+		&i.CoursePhaseID,
+		&i.Description,
+		&i.Tags,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
+	)
+	return i, err
+}
+
 const updateFileMetadata = `-- name: UpdateFileMetadata :one
 UPDATE files
 SET 
@@ -376,7 +951,7 @@ SET
     tags = COALESCE($3, tags),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at
+RETURNING id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id
 `
 
 type UpdateFileMetadataParams struct {
@@ -404,6 +979,82 @@ func (q *Queries) UpdateFileMetadata(ctx context.Context, arg UpdateFileMetadata
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
 	)
 	return i, err
 }
+
+const updateFileScanResult = `-- name: UpdateFileScanResult :one
+UPDATE files
+SET
+    scan_status = $2,
+    scan_result = $3,
+    scanned_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, filename, original_filename, content_type, size_bytes, storage_key, storage_provider, uploaded_by_user_id, uploaded_by_email, course_phase_id, description, tags, created_at, updated_at, deleted_at, scan_status, scan_result, scanned_at, content_sha256, previous_version_id, version, encryption_key_id
+`
+
+type UpdateFileScanResultParams struct {
+	ID         uuid.UUID      `json:"id"`
+	ScanStatus FileScanStatus `json:"scan_status"`
+	ScanResult pgtype.Text    `json:"scan_result"`
+}
+
+func (q *Queries) UpdateFileScanResult(ctx context.Context, arg UpdateFileScanResultParams) (File, error) {
+	row := q.db.QueryRow(ctx, updateFileScanResult, arg.ID, arg.ScanStatus, arg.ScanResult)
+	var i File
+	err := row.Scan(
+		&i.ID,
+		&i.Filename,
+		&i.OriginalFilename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.StorageKey,
+		&i.StorageProvider,
+		&i.UploadedByUserID,
+		&i.UploadedByEmail,
+		&i.CoursePhaseID,
+		&i.Description,
+		&i.Tags,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.ScanStatus,
+		&i.ScanResult,
+		&i.ScannedAt,
+		&i.ContentSha256,
+		&i.PreviousVersionID,
+		&i.Version,
+		&i.EncryptionKeyID,
+	)
+	return i, err
+}
+
+const updateFileStorageProvider = `-- name: UpdateFileStorageProvider :execrows
+UPDATE files
+SET
+    storage_provider = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE storage_key = $2
+  AND storage_provider = $3
+`
+
+type UpdateFileStorageProviderParams struct {
+	TargetProvider string `json:"target_provider"`
+	StorageKey     string `json:"storage_key"`
+	SourceProvider string `json:"source_provider"`
+}
+
+func (q *Queries) UpdateFileStorageProvider(ctx context.Context, arg UpdateFileStorageProviderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateFileStorageProvider, arg.TargetProvider, arg.StorageKey, arg.SourceProvider)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -27,20 +27,23 @@ func (q *Queries) AddTagToNote(ctx context.Context, arg AddTagToNoteParams) erro
 }
 
 const createNote = `-- name: CreateNote :one
-INSERT INTO note (id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-RETURNING id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by
+INSERT INTO note (id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by, course_id, course_phase_id, visibility)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by, course_id, course_phase_id, visibility
 `
 
 type CreateNoteParams struct {
-	ID          uuid.UUID          `json:"id"`
-	ForStudent  uuid.UUID          `json:"for_student"`
-	Author      uuid.UUID          `json:"author"`
-	AuthorName  string             `json:"author_name"`
-	AuthorEmail string             `json:"author_email"`
-	DateCreated pgtype.Timestamptz `json:"date_created"`
-	DateDeleted pgtype.Timestamptz `json:"date_deleted"`
-	DeletedBy   pgtype.UUID        `json:"deleted_by"`
+	ID            uuid.UUID          `json:"id"`
+	ForStudent    uuid.UUID          `json:"for_student"`
+	Author        uuid.UUID          `json:"author"`
+	AuthorName    string             `json:"author_name"`
+	AuthorEmail   string             `json:"author_email"`
+	DateCreated   pgtype.Timestamptz `json:"date_created"`
+	DateDeleted   pgtype.Timestamptz `json:"date_deleted"`
+	DeletedBy     pgtype.UUID        `json:"deleted_by"`
+	CourseID      pgtype.UUID        `json:"course_id"`
+	CoursePhaseID pgtype.UUID        `json:"course_phase_id"`
+	Visibility    NoteVisibility     `json:"visibility"`
 }
 
 func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) (Note, error) {
@@ -53,6 +56,9 @@ func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) (Note, e
 		arg.DateCreated,
 		arg.DateDeleted,
 		arg.DeletedBy,
+		arg.CourseID,
+		arg.CoursePhaseID,
+		arg.Visibility,
 	)
 	var i Note
 	err := row.Scan(
@@ -64,6 +70,9 @@ func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) (Note, e
 		&i.DateCreated,
 		&i.DateDeleted,
 		&i.DeletedBy,
+		&i.CourseID,
+		&i.CoursePhaseID,
+		&i.Visibility,
 	)
 	return i, err
 }
@@ -123,7 +132,7 @@ UPDATE note
 SET date_deleted = now(), deleted_by = $2
 WHERE id = $1
 AND date_deleted IS NULL
-RETURNING id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by
+RETURNING id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by, course_id, course_phase_id, visibility
 `
 
 type DeleteNoteParams struct {
@@ -143,6 +152,9 @@ func (q *Queries) DeleteNote(ctx context.Context, arg DeleteNoteParams) (Note, e
 		&i.DateCreated,
 		&i.DateDeleted,
 		&i.DeletedBy,
+		&i.CourseID,
+		&i.CoursePhaseID,
+		&i.Visibility,
 	)
 	return i, err
 }
@@ -157,7 +169,7 @@ func (q *Queries) DeleteTag(ctx context.Context, id uuid.UUID) error {
 }
 
 const getAllStudentNotes = `-- name: GetAllStudentNotes :many
-SELECT id, author, author_name, author_email, for_student, date_created, date_deleted, deleted_by, versions, tags FROM note_with_versions ORDER BY date_created DESC
+SELECT id, author, author_name, author_email, for_student, date_created, date_deleted, deleted_by, versions, tags, course_id, course_phase_id, visibility FROM note_with_versions ORDER BY date_created DESC
 `
 
 func (q *Queries) GetAllStudentNotes(ctx context.Context) ([]NoteWithVersion, error) {
@@ -180,6 +192,9 @@ func (q *Queries) GetAllStudentNotes(ctx context.Context) ([]NoteWithVersion, er
 			&i.DeletedBy,
 			&i.Versions,
 			&i.Tags,
+			&i.CourseID,
+			&i.CoursePhaseID,
+			&i.Visibility,
 		); err != nil {
 			return nil, err
 		}
@@ -215,6 +230,41 @@ func (q *Queries) GetAllTags(ctx context.Context) ([]NoteTag, error) {
 	return items, nil
 }
 
+const getCourseIDsWithNoteAccess = `-- name: GetCourseIDsWithNoteAccess :many
+SELECT c.id
+FROM course c
+WHERE CONCAT(c.semester_tag, '-', c.name, '-Lecturer') = ANY($1::text[])
+OR CONCAT(c.semester_tag, '-', c.name, '-Editor') = ANY($1::text[])
+OR EXISTS (
+    SELECT 1
+    FROM course_role cr
+    WHERE cr.course_id = c.id
+    AND 'can_view_notes' = ANY(cr.permissions)
+    AND CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name) = ANY($1::text[])
+)
+`
+
+// courses in which the roles make the user course staff: lecturers, editors and custom course roles with the notes permission
+func (q *Queries) GetCourseIDsWithNoteAccess(ctx context.Context, roleStrings []string) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getCourseIDsWithNoteAccess, roleStrings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLatestNoteVersionForNoteId = `-- name: GetLatestNoteVersionForNoteId :one
 SELECT nv.version_number
 FROM note_version nv
@@ -230,8 +280,30 @@ func (q *Queries) GetLatestNoteVersionForNoteId(ctx context.Context, forNote uui
 	return version_number, err
 }
 
+const getNoteVersion = `-- name: GetNoteVersion :one
+SELECT id, content, date_created, version_number, for_note FROM note_version WHERE for_note = $1 AND version_number = $2
+`
+
+type GetNoteVersionParams struct {
+	ForNote       uuid.UUID `json:"for_note"`
+	VersionNumber int32     `json:"version_number"`
+}
+
+func (q *Queries) GetNoteVersion(ctx context.Context, arg GetNoteVersionParams) (NoteVersion, error) {
+	row := q.db.QueryRow(ctx, getNoteVersion, arg.ForNote, arg.VersionNumber)
+	var i NoteVersion
+	err := row.Scan(
+		&i.ID,
+		&i.Content,
+		&i.DateCreated,
+		&i.VersionNumber,
+		&i.ForNote,
+	)
+	return i, err
+}
+
 const getSingleNoteWithVersionsByID = `-- name: GetSingleNoteWithVersionsByID :one
-SELECT id, author, author_name, author_email, for_student, date_created, date_deleted, deleted_by, versions, tags FROM note_with_versions WHERE id = $1 LIMIT 1
+SELECT id, author, author_name, author_email, for_student, date_created, date_deleted, deleted_by, versions, tags, course_id, course_phase_id, visibility FROM note_with_versions WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSingleNoteWithVersionsByID(ctx context.Context, id uuid.UUID) (NoteWithVersion, error) {
@@ -248,12 +320,15 @@ func (q *Queries) GetSingleNoteWithVersionsByID(ctx context.Context, id uuid.UUI
 		&i.DeletedBy,
 		&i.Versions,
 		&i.Tags,
+		&i.CourseID,
+		&i.CoursePhaseID,
+		&i.Visibility,
 	)
 	return i, err
 }
 
 const getSingleStudentNoteByID = `-- name: GetSingleStudentNoteByID :one
-SELECT id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by FROM note WHERE id = $1 LIMIT 1
+SELECT id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by, course_id, course_phase_id, visibility FROM note WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSingleStudentNoteByID(ctx context.Context, id uuid.UUID) (Note, error) {
@@ -268,12 +343,15 @@ func (q *Queries) GetSingleStudentNoteByID(ctx context.Context, id uuid.UUID) (N
 		&i.DateCreated,
 		&i.DateDeleted,
 		&i.DeletedBy,
+		&i.CourseID,
+		&i.CoursePhaseID,
+		&i.Visibility,
 	)
 	return i, err
 }
 
 const getStudentNotesForStudent = `-- name: GetStudentNotesForStudent :many
-SELECT id, author, author_name, author_email, for_student, date_created, date_deleted, deleted_by, versions, tags FROM note_with_versions WHERE for_student = $1 ORDER BY date_created ASC
+SELECT id, author, author_name, author_email, for_student, date_created, date_deleted, deleted_by, versions, tags, course_id, course_phase_id, visibility FROM note_with_versions WHERE for_student = $1 ORDER BY date_created ASC
 `
 
 func (q *Queries) GetStudentNotesForStudent(ctx context.Context, forStudent uuid.UUID) ([]NoteWithVersion, error) {
@@ -296,6 +374,9 @@ func (q *Queries) GetStudentNotesForStudent(ctx context.Context, forStudent uuid
 			&i.DeletedBy,
 			&i.Versions,
 			&i.Tags,
+			&i.CourseID,
+			&i.CoursePhaseID,
+			&i.Visibility,
 		); err != nil {
 			return nil, err
 		}
@@ -368,6 +449,113 @@ func (q *Queries) RemoveTagFromNote(ctx context.Context, arg RemoveTagFromNotePa
 	return err
 }
 
+const restoreNote = `-- name: RestoreNote :one
+UPDATE note
+SET date_deleted = NULL, deleted_by = NULL
+WHERE id = $1
+AND date_deleted IS NOT NULL
+RETURNING id, for_student, author, author_name, author_email, date_created, date_deleted, deleted_by, course_id, course_phase_id, visibility
+`
+
+func (q *Queries) RestoreNote(ctx context.Context, id uuid.UUID) (Note, error) {
+	row := q.db.QueryRow(ctx, restoreNote, id)
+	var i Note
+	err := row.Scan(
+		&i.ID,
+		&i.ForStudent,
+		&i.Author,
+		&i.AuthorName,
+		&i.AuthorEmail,
+		&i.DateCreated,
+		&i.DateDeleted,
+		&i.DeletedBy,
+		&i.CourseID,
+		&i.CoursePhaseID,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const searchStudentNotes = `-- name: SearchStudentNotes :many
+SELECT nwv.id, nwv.author, nwv.author_name, nwv.author_email, nwv.for_student, nwv.date_created, nwv.date_deleted, nwv.deleted_by, nwv.versions, nwv.tags, nwv.course_id, nwv.course_phase_id, nwv.visibility
+FROM note_with_versions nwv
+JOIN LATERAL (
+    SELECT nv.content
+    FROM note_version nv
+    WHERE nv.for_note = nwv.id
+    ORDER BY nv.version_number DESC
+    LIMIT 1
+) latest ON TRUE
+CROSS JOIN LATERAL (
+    SELECT to_tsvector('simple', latest.content || ' ' || COALESCE(string_agg(nt.name, ' '), '')) AS document
+    FROM note_tag_relation ntr
+    JOIN note_tag nt ON nt.id = ntr.tag_id
+    WHERE ntr.note_id = nwv.id
+) searchable
+CROSS JOIN websearch_to_tsquery('simple', $1::text) search_query
+WHERE nwv.date_deleted IS NULL
+AND searchable.document @@ search_query
+ORDER BY ts_rank(searchable.document, search_query) DESC, nwv.date_created DESC
+`
+
+// searches the current version and the tags of the notes that are not deleted, best matches first
+func (q *Queries) SearchStudentNotes(ctx context.Context, query string) ([]NoteWithVersion, error) {
+	rows, err := q.db.Query(ctx, searchStudentNotes, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NoteWithVersion
+	for rows.Next() {
+		var i NoteWithVersion
+		if err := rows.Scan(
+			&i.ID,
+			&i.Author,
+			&i.AuthorName,
+			&i.AuthorEmail,
+			&i.ForStudent,
+			&i.DateCreated,
+			&i.DateDeleted,
+			&i.DeletedBy,
+			&i.Versions,
+			&i.Tags,
+			&i.CourseID,
+			&i.CoursePhaseID,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateNoteScope = `-- name: UpdateNoteScope :exec
+UPDATE note
+SET course_id = $2, course_phase_id = $3, visibility = $4
+WHERE id = $1
+`
+
+type UpdateNoteScopeParams struct {
+	ID            uuid.UUID      `json:"id"`
+	CourseID      pgtype.UUID    `json:"course_id"`
+	CoursePhaseID pgtype.UUID    `json:"course_phase_id"`
+	Visibility    NoteVisibility `json:"visibility"`
+}
+
+func (q *Queries) UpdateNoteScope(ctx context.Context, arg UpdateNoteScopeParams) error {
+	_, err := q.db.Exec(ctx, updateNoteScope,
+		arg.ID,
+		arg.CourseID,
+		arg.CoursePhaseID,
+		arg.Visibility,
+	)
+	return err
+}
+
 const updateTag = `-- name: UpdateTag :one
 UPDATE note_tag SET name = $2, color = $3 WHERE id = $1 RETURNING id, name, color
 `
@@ -55,6 +55,50 @@ func (ns NullCourseType) Value() (driver.Value, error) {
 	return string(ns.CourseType), nil
 }
 
+type FileScanStatus string
+
+const (
+	FileScanStatusPending  FileScanStatus = "pending"
+	FileScanStatusClean    FileScanStatus = "clean"
+	FileScanStatusInfected FileScanStatus = "infected"
+	FileScanStatusFailed   FileScanStatus = "failed"
+)
+
+func (e *FileScanStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = FileScanStatus(s)
+	case string:
+		*e = FileScanStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for FileScanStatus: %T", src)
+	}
+	return nil
+}
+
+type NullFileScanStatus struct {
+	FileScanStatus FileScanStatus `json:"file_scan_status"`
+	Valid          bool           `json:"valid"` // Valid is true if FileScanStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullFileScanStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.FileScanStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.FileScanStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullFileScanStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.FileScanStatus), nil
+}
+
 type Gender string
 
 const (
@@ -145,6 +189,49 @@ func (ns NullNoteTagColor) Value() (driver.Value, error) {
 	return string(ns.NoteTagColor), nil
 }
 
+type NoteVisibility string
+
+const (
+	NoteVisibilityPrivate     NoteVisibility = "private"
+	NoteVisibilityCourseStaff NoteVisibility = "course_staff"
+	NoteVisibilityLecturers   NoteVisibility = "lecturers"
+)
+
+func (e *NoteVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NoteVisibility(s)
+	case string:
+		*e = NoteVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NoteVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullNoteVisibility struct {
+	NoteVisibility NoteVisibility `json:"note_visibility"`
+	Valid          bool           `json:"valid"` // Valid is true if NoteVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNoteVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.NoteVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NoteVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNoteVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NoteVisibility), nil
+}
+
 type PassStatus string
 
 const (
@@ -188,6 +275,91 @@ func (ns NullPassStatus) Value() (driver.Value, error) {
 	return string(ns.PassStatus), nil
 }
 
+type StudentDuplicateStatus string
+
+const (
+	StudentDuplicateStatusOpen      StudentDuplicateStatus = "open"
+	StudentDuplicateStatusDismissed StudentDuplicateStatus = "dismissed"
+)
+
+func (e *StudentDuplicateStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StudentDuplicateStatus(s)
+	case string:
+		*e = StudentDuplicateStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StudentDuplicateStatus: %T", src)
+	}
+	return nil
+}
+
+type NullStudentDuplicateStatus struct {
+	StudentDuplicateStatus StudentDuplicateStatus `json:"student_duplicate_status"`
+	Valid                  bool                   `json:"valid"` // Valid is true if StudentDuplicateStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStudentDuplicateStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.StudentDuplicateStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StudentDuplicateStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStudentDuplicateStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StudentDuplicateStatus), nil
+}
+
+type StudentProfileChangeSource string
+
+const (
+	StudentProfileChangeSourceLecturer    StudentProfileChangeSource = "lecturer"
+	StudentProfileChangeSourceSelfService StudentProfileChangeSource = "self_service"
+	StudentProfileChangeSourceApplication StudentProfileChangeSource = "application"
+)
+
+func (e *StudentProfileChangeSource) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StudentProfileChangeSource(s)
+	case string:
+		*e = StudentProfileChangeSource(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StudentProfileChangeSource: %T", src)
+	}
+	return nil
+}
+
+type NullStudentProfileChangeSource struct {
+	StudentProfileChangeSource StudentProfileChangeSource `json:"student_profile_change_source"`
+	Valid                      bool                       `json:"valid"` // Valid is true if StudentProfileChangeSource is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStudentProfileChangeSource) Scan(value interface{}) error {
+	if value == nil {
+		ns.StudentProfileChangeSource, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StudentProfileChangeSource.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStudentProfileChangeSource) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StudentProfileChangeSource), nil
+}
+
 type StudyDegree string
 
 const (
@@ -258,6 +430,13 @@ type ApplicationAssessment struct {
 	CourseParticipationID uuid.UUID   `json:"course_participation_id"`
 }
 
+type ApplicationDraft struct {
+	CoursePhaseID   uuid.UUID          `json:"course_phase_id"`
+	UniversityLogin string             `json:"university_login"`
+	Application     []byte             `json:"application"`
+	LastModified    pgtype.Timestamptz `json:"last_modified"`
+}
+
 type ApplicationQuestionFileUpload struct {
 	ID                       uuid.UUID   `json:"id"`
 	CoursePhaseID            uuid.UUID   `json:"course_phase_id"`
@@ -269,6 +448,8 @@ type ApplicationQuestionFileUpload struct {
 	OrderNum                 int32       `json:"order_num"`
 	AccessibleForOtherPhases bool        `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 type ApplicationQuestionMultiSelect struct {
@@ -285,6 +466,8 @@ type ApplicationQuestionMultiSelect struct {
 	OrderNum                 pgtype.Int4 `json:"order_num"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 type ApplicationQuestionText struct {
@@ -300,6 +483,8 @@ type ApplicationQuestionText struct {
 	OrderNum                 pgtype.Int4 `json:"order_num"`
 	AccessibleForOtherPhases pgtype.Bool `json:"accessible_for_other_phases"`
 	AccessKey                pgtype.Text `json:"access_key"`
+	DisplayCondition         []byte      `json:"display_condition"`
+	IsIdentifying            bool        `json:"is_identifying"`
 }
 
 type Course struct {
@@ -390,14 +575,17 @@ type CoursePhaseTypePhaseRequiredInputDto struct {
 }
 
 type Note struct {
-	ID          uuid.UUID          `json:"id"`
-	ForStudent  uuid.UUID          `json:"for_student"`
-	Author      uuid.UUID          `json:"author"`
-	AuthorName  string             `json:"author_name"`
-	AuthorEmail string             `json:"author_email"`
-	DateCreated pgtype.Timestamptz `json:"date_created"`
-	DateDeleted pgtype.Timestamptz `json:"date_deleted"`
-	DeletedBy   pgtype.UUID        `json:"deleted_by"`
+	ID            uuid.UUID          `json:"id"`
+	ForStudent    uuid.UUID          `json:"for_student"`
+	Author        uuid.UUID          `json:"author"`
+	AuthorName    string             `json:"author_name"`
+	AuthorEmail   string             `json:"author_email"`
+	DateCreated   pgtype.Timestamptz `json:"date_created"`
+	DateDeleted   pgtype.Timestamptz `json:"date_deleted"`
+	DeletedBy     pgtype.UUID        `json:"deleted_by"`
+	CourseID      pgtype.UUID        `json:"course_id"`
+	CoursePhaseID pgtype.UUID        `json:"course_phase_id"`
+	Visibility    NoteVisibility     `json:"visibility"`
 }
 
 type NoteTag struct {
@@ -420,34 +608,44 @@ type NoteVersion struct {
 }
 
 type NoteWithVersion struct {
-	ID          uuid.UUID          `json:"id"`
-	Author      uuid.UUID          `json:"author"`
-	AuthorName  string             `json:"author_name"`
-	AuthorEmail string             `json:"author_email"`
-	ForStudent  uuid.UUID          `json:"for_student"`
-	DateCreated pgtype.Timestamptz `json:"date_created"`
-	DateDeleted pgtype.Timestamptz `json:"date_deleted"`
-	DeletedBy   pgtype.UUID        `json:"deleted_by"`
-	Versions    []byte             `json:"versions"`
-	Tags        []byte             `json:"tags"`
+	ID            uuid.UUID          `json:"id"`
+	Author        uuid.UUID          `json:"author"`
+	AuthorName    string             `json:"author_name"`
+	AuthorEmail   string             `json:"author_email"`
+	ForStudent    uuid.UUID          `json:"for_student"`
+	DateCreated   pgtype.Timestamptz `json:"date_created"`
+	DateDeleted   pgtype.Timestamptz `json:"date_deleted"`
+	DeletedBy     pgtype.UUID        `json:"deleted_by"`
+	Versions      []byte             `json:"versions"`
+	Tags          []byte             `json:"tags"`
+	CourseID      pgtype.UUID        `json:"course_id"`
+	CoursePhaseID pgtype.UUID        `json:"course_phase_id"`
+	Visibility    NoteVisibility     `json:"visibility"`
 }
 
 type File struct {
-	ID               uuid.UUID        `json:"id"`
-	Filename         string           `json:"filename"`
-	OriginalFilename string           `json:"original_filename"`
-	ContentType      string           `json:"content_type"`
-	SizeBytes        int64            `json:"size_bytes"`
-	StorageKey       string           `json:"storage_key"`
-	StorageProvider  string           `json:"storage_provider"`
-	UploadedByUserID string           `json:"uploaded_by_user_id"`
-	UploadedByEmail  pgtype.Text      `json:"uploaded_by_email"`
-	CoursePhaseID    pgtype.UUID      `json:"course_phase_id"`
-	Description      pgtype.Text      `json:"description"`
-	Tags             []string         `json:"tags"`
-	CreatedAt        pgtype.Timestamp `json:"created_at"`
-	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
-	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
+	ID                uuid.UUID        `json:"id"`
+	Filename          string           `json:"filename"`
+	OriginalFilename  string           `json:"original_filename"`
+	ContentType       string           `json:"content_type"`
+	SizeBytes         int64            `json:"size_bytes"`
+	StorageKey        string           `json:"storage_key"`
+	StorageProvider   string           `json:"storage_provider"`
+	UploadedByUserID  string           `json:"uploaded_by_user_id"`
+	UploadedByEmail   pgtype.Text      `json:"uploaded_by_email"`
+	CoursePhaseID     pgtype.UUID      `json:"course_phase_id"`
+	Description       pgtype.Text      `json:"description"`
+	Tags              []string         `json:"tags"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	DeletedAt         pgtype.Timestamp `json:"deleted_at"`
+	ScanStatus        FileScanStatus   `json:"scan_status"`
+	ScanResult        pgtype.Text      `json:"scan_result"`
+	ScannedAt         pgtype.Timestamp `json:"scanned_at"`
+	ContentSha256     pgtype.Text      `json:"content_sha256"`
+	PreviousVersionID pgtype.UUID      `json:"previous_version_id"`
+	Version           int32            `json:"version"`
+	EncryptionKeyID   pgtype.UUID      `json:"encryption_key_id"`
 }
 
 type ParticipationDataDependencyGraph struct {
@@ -479,3 +677,115 @@ type Student struct {
 	CurrentSemester      pgtype.Int4      `json:"current_semester"`
 	LastModified         pgtype.Timestamp `json:"last_modified"`
 }
+
+type StudentDuplicateCandidate struct {
+	ID                 uuid.UUID              `json:"id"`
+	StudentID          uuid.UUID              `json:"student_id"`
+	DuplicateStudentID uuid.UUID              `json:"duplicate_student_id"`
+	Score              float64                `json:"score"`
+	Reasons            []string               `json:"reasons"`
+	Status             StudentDuplicateStatus `json:"status"`
+	DetectedAt         pgtype.Timestamptz     `json:"detected_at"`
+	ResolvedAt         pgtype.Timestamptz     `json:"resolved_at"`
+	ResolvedBy         pgtype.UUID            `json:"resolved_by"`
+}
+
+type CourseStorageQuota struct {
+	CourseID   uuid.UUID        `json:"course_id"`
+	QuotaBytes int64            `json:"quota_bytes"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type CoursePhaseStorageQuota struct {
+	CoursePhaseID uuid.UUID        `json:"course_phase_id"`
+	QuotaBytes    int64            `json:"quota_bytes"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type NoteFile struct {
+	NoteID uuid.UUID `json:"note_id"`
+	FileID uuid.UUID `json:"file_id"`
+}
+
+type CoursePhaseParticipationFile struct {
+	CourseParticipationID uuid.UUID `json:"course_participation_id"`
+	CoursePhaseID         uuid.UUID `json:"course_phase_id"`
+	FileID                uuid.UUID `json:"file_id"`
+}
+
+type FilePreview struct {
+	FileID      uuid.UUID        `json:"file_id"`
+	StorageKey  string           `json:"storage_key"`
+	ContentType string           `json:"content_type"`
+	Width       int32            `json:"width"`
+	Height      int32            `json:"height"`
+	SizeBytes   int64            `json:"size_bytes"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type FileEncryptionKey struct {
+	ID          uuid.UUID        `json:"id"`
+	CourseID    pgtype.UUID      `json:"course_id"`
+	WrappedKey  []byte           `json:"wrapped_key"`
+	MasterKeyID string           `json:"master_key_id"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	RotatedAt   pgtype.Timestamp `json:"rotated_at"`
+}
+
+type ApiToken struct {
+	ID              uuid.UUID        `json:"id"`
+	Name            string           `json:"name"`
+	TokenHash       string           `json:"token_hash"`
+	TokenPrefix     string           `json:"token_prefix"`
+	CourseID        uuid.UUID        `json:"course_id"`
+	Roles           []string         `json:"roles"`
+	CreatedByUserID string           `json:"created_by_user_id"`
+	CreatedByEmail  string           `json:"created_by_email"`
+	ExpiresAt       pgtype.Timestamp `json:"expires_at"`
+	RevokedAt       pgtype.Timestamp `json:"revoked_at"`
+	LastUsedAt      pgtype.Timestamp `json:"last_used_at"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+}
+
+type CourseRole struct {
+	ID          uuid.UUID        `json:"id"`
+	CourseID    uuid.UUID        `json:"course_id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Permissions []string         `json:"permissions"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+}
+
+type CoursePhaseGrant struct {
+	CourseRoleID  uuid.UUID        `json:"course_role_id"`
+	CoursePhaseID uuid.UUID        `json:"course_phase_id"`
+	Grants        []string         `json:"grants"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type CourseStaff struct {
+	ID        uuid.UUID        `json:"id"`
+	CourseID  uuid.UUID        `json:"course_id"`
+	Role      string           `json:"role"`
+	UserID    pgtype.Text      `json:"user_id"`
+	StudentID pgtype.UUID      `json:"student_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type StudentProfileChange struct {
+	ID                   uuid.UUID                  `json:"id"`
+	StudentID            uuid.UUID                  `json:"student_id"`
+	Field                string                     `json:"field"`
+	OldValue             string                     `json:"old_value"`
+	NewValue             string                     `json:"new_value"`
+	Source               StudentProfileChangeSource `json:"source"`
+	ChangedBy            pgtype.UUID                `json:"changed_by"`
+	ChangedByName        string                     `json:"changed_by_name"`
+	ChangedByEmail       string                     `json:"changed_by_email"`
+	ChangedAt            pgtype.Timestamptz         `json:"changed_at"`
+	IsIdentityField      bool                       `json:"is_identity_field"`
+	NotifiedAt           pgtype.Timestamptz         `json:"notified_at"`
+	NotificationAttempts int32                      `json:"notification_attempts"`
+}
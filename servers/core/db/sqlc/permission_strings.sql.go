@@ -32,6 +32,40 @@ func (q *Queries) GetCoursePhaseAuthRoleMapping(ctx context.Context, id uuid.UUI
 	return i, err
 }
 
+const getCoursePhaseGrantRoles = `-- name: GetCoursePhaseGrantRoles :many
+SELECT CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name)::text AS role_string, cpg.grants
+FROM course_phase_grant cpg
+JOIN course_role cr ON cr.id = cpg.course_role_id
+JOIN course c ON c.id = cr.course_id
+WHERE cpg.course_phase_id = $1
+ORDER BY cr.name
+`
+
+type GetCoursePhaseGrantRolesRow struct {
+	RoleString string   `json:"role_string"`
+	Grants     []string `json:"grants"`
+}
+
+func (q *Queries) GetCoursePhaseGrantRoles(ctx context.Context, coursePhaseID uuid.UUID) ([]GetCoursePhaseGrantRolesRow, error) {
+	rows, err := q.db.Query(ctx, getCoursePhaseGrantRoles, coursePhaseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCoursePhaseGrantRolesRow
+	for rows.Next() {
+		var i GetCoursePhaseGrantRolesRow
+		if err := rows.Scan(&i.RoleString, &i.Grants); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPermissionStringByCourseID = `-- name: GetPermissionStringByCourseID :one
 SELECT CONCAT(semester_tag, '-', name) AS course_identifier
 FROM course
@@ -73,6 +107,33 @@ func (q *Queries) GetPermissionStringByCoursePhaseID(ctx context.Context, id uui
 	return course_identifier, err
 }
 
+const getPermissionStringsByStudentID = `-- name: GetPermissionStringsByStudentID :many
+SELECT DISTINCT CONCAT(c.semester_tag, '-', c.name)::text AS course_identifier
+FROM course c
+JOIN course_participation cp ON c.id = cp.course_id
+WHERE cp.student_id = $1
+`
+
+func (q *Queries) GetPermissionStringsByStudentID(ctx context.Context, studentID uuid.UUID) ([]string, error) {
+	rows, err := q.db.Query(ctx, getPermissionStringsByStudentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var course_identifier string
+		if err := rows.Scan(&course_identifier); err != nil {
+			return nil, err
+		}
+		items = append(items, course_identifier)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStudentRoleStrings = `-- name: GetStudentRoleStrings :many
 SELECT CONCAT(c.semester_tag, '-', c.name, '-Student')::text AS student_role
 FROM course c
@@ -106,3 +167,52 @@ func (q *Queries) GetStudentRoleStrings(ctx context.Context, arg GetStudentRoleS
 	}
 	return items, nil
 }
+
+const hasCoursePhaseGrant = `-- name: HasCoursePhaseGrant :one
+SELECT EXISTS (
+    SELECT 1
+    FROM course_phase_grant cpg
+    JOIN course_role cr ON cr.id = cpg.course_role_id
+    JOIN course c ON c.id = cr.course_id
+    WHERE cpg.course_phase_id = $1
+    AND CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name) = ANY($2::text[])
+    AND cpg.grants && $3::text[]
+) AS has_grant
+`
+
+type HasCoursePhaseGrantParams struct {
+	CoursePhaseID uuid.UUID `json:"course_phase_id"`
+	RoleStrings   []string  `json:"role_strings"`
+	Grants        []string  `json:"grants"`
+}
+
+// checks whether one of the custom course roles grants one of the roles or permissions in the course phase
+func (q *Queries) HasCoursePhaseGrant(ctx context.Context, arg HasCoursePhaseGrantParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasCoursePhaseGrant, arg.CoursePhaseID, arg.RoleStrings, arg.Grants)
+	var has_grant bool
+	err := row.Scan(&has_grant)
+	return has_grant, err
+}
+
+const hasCourseRolePermission = `-- name: HasCourseRolePermission :one
+SELECT EXISTS (
+    SELECT 1
+    FROM course_role cr
+    JOIN course c ON c.id = cr.course_id
+    WHERE CONCAT(c.semester_tag, '-', c.name, '-cr-', cr.name) = ANY($1::text[])
+    AND $2::text = ANY(cr.permissions)
+) AS has_permission
+`
+
+type HasCourseRolePermissionParams struct {
+	RoleStrings []string `json:"role_strings"`
+	Permission  string   `json:"permission"`
+}
+
+// role_strings are Keycloak roles of custom course roles, e.g. ss25-iPraktikum-cr-Tutor
+func (q *Queries) HasCourseRolePermission(ctx context.Context, arg HasCourseRolePermissionParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasCourseRolePermission, arg.RoleStrings, arg.Permission)
+	var has_permission bool
+	err := row.Scan(&has_permission)
+	return has_permission, err
+}
@@ -105,94 +105,6 @@ func (q *Queries) GetAllStudents(ctx context.Context) ([]Student, error) {
 	return items, nil
 }
 
-const getAllStudentsWithCourseParticipations = `-- name: GetAllStudentsWithCourseParticipations :many
-SELECT
-  s.id AS student_id,
-  s.first_name AS student_first_name,
-  s.last_name AS student_last_name,
-  s.email AS student_email,
-  s.has_university_account AS student_has_university_account,
-  s.current_semester,
-  s.study_program,
-  COALESCE(
-    jsonb_agg(
-      jsonb_build_object(
-        'courseId', c.id,
-        'courseName', c.name,
-        'studentReadableData', c.student_readable_data
-      )
-    ) FILTER (WHERE c.id IS NOT NULL),
-    '[]'::jsonb
-  )::jsonb AS courses,
-  COALESCE(
-    (
-      SELECT jsonb_agg(jsonb_build_object('id', nt.id, 'name', nt.name, 'color', nt.color) ORDER BY nt.name)
-      FROM (
-        SELECT DISTINCT nt.id, nt.name, nt.color
-        FROM note n
-        JOIN note_tag_relation ntr ON ntr.note_id = n.id
-        JOIN note_tag nt ON nt.id = ntr.tag_id
-        WHERE n.for_student = s.id
-          AND n.date_deleted IS NULL
-      ) nt
-    ),
-    '[]'::jsonb
-  )::jsonb AS note_tags
-FROM student s
-LEFT JOIN course_participation cp
-  ON cp.student_id = s.id
-LEFT JOIN course c
-  ON c.id = cp.course_id
-GROUP BY
-  s.id,
-  s.first_name,
-  s.last_name,
-  s.email,
-  s.has_university_account
-`
-
-type GetAllStudentsWithCourseParticipationsRow struct {
-	StudentID                   uuid.UUID   `json:"student_id"`
-	StudentFirstName            pgtype.Text `json:"student_first_name"`
-	StudentLastName             pgtype.Text `json:"student_last_name"`
-	StudentEmail                pgtype.Text `json:"student_email"`
-	StudentHasUniversityAccount pgtype.Bool `json:"student_has_university_account"`
-	CurrentSemester             pgtype.Int4 `json:"current_semester"`
-	StudyProgram                pgtype.Text `json:"study_program"`
-	Courses                     []byte      `json:"courses"`
-	NoteTags                    []byte      `json:"note_tags"`
-}
-
-func (q *Queries) GetAllStudentsWithCourseParticipations(ctx context.Context) ([]GetAllStudentsWithCourseParticipationsRow, error) {
-	rows, err := q.db.Query(ctx, getAllStudentsWithCourseParticipations)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetAllStudentsWithCourseParticipationsRow
-	for rows.Next() {
-		var i GetAllStudentsWithCourseParticipationsRow
-		if err := rows.Scan(
-			&i.StudentID,
-			&i.StudentFirstName,
-			&i.StudentLastName,
-			&i.StudentEmail,
-			&i.StudentHasUniversityAccount,
-			&i.CurrentSemester,
-			&i.StudyProgram,
-			&i.Courses,
-			&i.NoteTags,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const getStudent = `-- name: GetStudent :one
 SELECT id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester, last_modified FROM student
 WHERE id = $1 LIMIT 1
@@ -503,6 +415,95 @@ func (q *Queries) GetStudentUniversityLogins(ctx context.Context, dollar_1 []uui
 	return items, nil
 }
 
+const getStudentsWithCourseParticipations = `-- name: GetStudentsWithCourseParticipations :many
+SELECT
+  s.id AS student_id,
+  s.first_name AS student_first_name,
+  s.last_name AS student_last_name,
+  s.email AS student_email,
+  s.has_university_account AS student_has_university_account,
+  s.current_semester,
+  s.study_program,
+  COALESCE(
+    jsonb_agg(
+      jsonb_build_object(
+        'courseId', c.id,
+        'courseName', c.name,
+        'studentReadableData', c.student_readable_data
+      )
+    ) FILTER (WHERE c.id IS NOT NULL),
+    '[]'::jsonb
+  )::jsonb AS courses,
+  COALESCE(
+    (
+      SELECT jsonb_agg(jsonb_build_object('id', nt.id, 'name', nt.name, 'color', nt.color) ORDER BY nt.name)
+      FROM (
+        SELECT DISTINCT nt.id, nt.name, nt.color
+        FROM note n
+        JOIN note_tag_relation ntr ON ntr.note_id = n.id
+        JOIN note_tag nt ON nt.id = ntr.tag_id
+        WHERE n.for_student = s.id
+          AND n.date_deleted IS NULL
+      ) nt
+    ),
+    '[]'::jsonb
+  )::jsonb AS note_tags
+FROM student s
+LEFT JOIN course_participation cp
+  ON cp.student_id = s.id
+LEFT JOIN course c
+  ON c.id = cp.course_id
+WHERE s.id = ANY($1::uuid[])
+GROUP BY
+  s.id,
+  s.first_name,
+  s.last_name,
+  s.email,
+  s.has_university_account
+`
+
+type GetStudentsWithCourseParticipationsRow struct {
+	StudentID                   uuid.UUID   `json:"student_id"`
+	StudentFirstName            pgtype.Text `json:"student_first_name"`
+	StudentLastName             pgtype.Text `json:"student_last_name"`
+	StudentEmail                pgtype.Text `json:"student_email"`
+	StudentHasUniversityAccount pgtype.Bool `json:"student_has_university_account"`
+	CurrentSemester             pgtype.Int4 `json:"current_semester"`
+	StudyProgram                pgtype.Text `json:"study_program"`
+	Courses                     []byte      `json:"courses"`
+	NoteTags                    []byte      `json:"note_tags"`
+}
+
+func (q *Queries) GetStudentsWithCourseParticipations(ctx context.Context, studentIds []uuid.UUID) ([]GetStudentsWithCourseParticipationsRow, error) {
+	rows, err := q.db.Query(ctx, getStudentsWithCourseParticipations, studentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStudentsWithCourseParticipationsRow
+	for rows.Next() {
+		var i GetStudentsWithCourseParticipationsRow
+		if err := rows.Scan(
+			&i.StudentID,
+			&i.StudentFirstName,
+			&i.StudentLastName,
+			&i.StudentEmail,
+			&i.StudentHasUniversityAccount,
+			&i.CurrentSemester,
+			&i.StudyProgram,
+			&i.Courses,
+			&i.NoteTags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getStudentsByEmail = `-- name: GetStudentsByEmail :many
 SELECT id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester, last_modified FROM student
 WHERE email = ANY($1::text[])
@@ -542,19 +543,13 @@ func (q *Queries) GetStudentsByEmail(ctx context.Context, dollar_1 []string) ([]
 	return items, nil
 }
 
-const searchStudents = `-- name: SearchStudents :many
-SELECT id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester, last_modified
-FROM student
-WHERE (first_name || ' ' || last_name) ILIKE '%' || $1 || '%'
-   OR first_name ILIKE '%' || $1 || '%'
-   OR last_name ILIKE '%' || $1 || '%'
-   OR email ILIKE '%' || $1 || '%'
-   OR matriculation_number ILIKE '%' || $1 || '%'
-   OR university_login ILIKE '%' || $1 || '%'
+const getStudentsByIDs = `-- name: GetStudentsByIDs :many
+SELECT id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester, last_modified FROM student
+WHERE id = ANY($1::uuid[])
 `
 
-func (q *Queries) SearchStudents(ctx context.Context, dollar_1 pgtype.Text) ([]Student, error) {
-	rows, err := q.db.Query(ctx, searchStudents, dollar_1)
+func (q *Queries) GetStudentsByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]Student, error) {
+	rows, err := q.db.Query(ctx, getStudentsByIDs, dollar_1)
 	if err != nil {
 		return nil, err
 	}
@@ -587,6 +582,137 @@ func (q *Queries) SearchStudents(ctx context.Context, dollar_1 pgtype.Text) ([]S
 	return items, nil
 }
 
+const searchStudents = `-- name: SearchStudents :many
+WITH matches AS (
+    SELECT s.id, s.first_name, s.last_name, s.email, s.matriculation_number, s.university_login, s.has_university_account, s.gender, s.nationality, s.study_program, s.study_degree, s.current_semester, s.last_modified,
+           (CASE $1::text
+                WHEN 'firstName' THEN lower(coalesce(s.first_name, '') || ' ' || coalesce(s.last_name, ''))
+                WHEN 'email' THEN lower(coalesce(s.email, ''))
+                WHEN 'matriculationNumber' THEN coalesce(s.matriculation_number, '')
+                ELSE lower(coalesce(s.last_name, '') || ' ' || coalesce(s.first_name, ''))
+            END)::text AS sort_key,
+           COUNT(*) OVER () AS total_count
+    FROM student s
+    WHERE ($2::text IS NULL
+           OR to_tsvector('simple',
+                coalesce(s.first_name, '') || ' ' ||
+                coalesce(s.last_name, '') || ' ' ||
+                coalesce(s.email, '') || ' ' ||
+                coalesce(s.matriculation_number, '') || ' ' ||
+                coalesce(s.university_login, '')) @@ to_tsquery('simple', $2::text))
+      AND ($3::text IS NULL OR lower(s.study_program) = lower($3::text))
+      AND ($4::text IS NULL OR lower(s.nationality) = lower($4::text))
+      AND (($5::uuid IS NULL AND $6::text IS NULL AND $7::pass_status IS NULL)
+           OR EXISTS (
+               SELECT 1
+               FROM course_participation cp
+               INNER JOIN course c ON c.id = cp.course_id
+               WHERE cp.student_id = s.id
+                 AND ($5::uuid IS NULL OR cp.course_id = $5::uuid)
+                 AND ($6::text IS NULL OR c.semester_tag = $6::text)
+                 AND ($7::pass_status IS NULL OR EXISTS (
+                     SELECT 1
+                     FROM course_phase_participation cpp
+                     WHERE cpp.course_participation_id = cp.id
+                       AND cpp.pass_status = $7::pass_status
+                 ))
+           ))
+)
+SELECT id, first_name, last_name, email, matriculation_number, university_login, has_university_account, gender, nationality, study_program, study_degree, current_semester, last_modified, sort_key, total_count
+FROM matches
+WHERE $8::uuid IS NULL
+   OR ($9::boolean AND (sort_key, id) < ($10::text, $8::uuid))
+   OR (NOT $9::boolean AND (sort_key, id) > ($10::text, $8::uuid))
+ORDER BY
+    CASE WHEN $9::boolean THEN sort_key END DESC,
+    CASE WHEN $9::boolean THEN id END DESC,
+    CASE WHEN NOT $9::boolean THEN sort_key END,
+    CASE WHEN NOT $9::boolean THEN id END
+LIMIT $11::int
+`
+
+type SearchStudentsParams struct {
+	SortBy       string         `json:"sort_by"`
+	Query        pgtype.Text    `json:"query"`
+	StudyProgram pgtype.Text    `json:"study_program"`
+	Nationality  pgtype.Text    `json:"nationality"`
+	CourseID     pgtype.UUID    `json:"course_id"`
+	SemesterTag  pgtype.Text    `json:"semester_tag"`
+	PassStatus   NullPassStatus `json:"pass_status"`
+	CursorID     pgtype.UUID    `json:"cursor_id"`
+	Descending   bool           `json:"descending"`
+	CursorKey    pgtype.Text    `json:"cursor_key"`
+	PageSize     pgtype.Int4    `json:"page_size"`
+}
+
+type SearchStudentsRow struct {
+	ID                   uuid.UUID        `json:"id"`
+	FirstName            pgtype.Text      `json:"first_name"`
+	LastName             pgtype.Text      `json:"last_name"`
+	Email                pgtype.Text      `json:"email"`
+	MatriculationNumber  pgtype.Text      `json:"matriculation_number"`
+	UniversityLogin      pgtype.Text      `json:"university_login"`
+	HasUniversityAccount pgtype.Bool      `json:"has_university_account"`
+	Gender               Gender           `json:"gender"`
+	Nationality          pgtype.Text      `json:"nationality"`
+	StudyProgram         pgtype.Text      `json:"study_program"`
+	StudyDegree          StudyDegree      `json:"study_degree"`
+	CurrentSemester      pgtype.Int4      `json:"current_semester"`
+	LastModified         pgtype.Timestamp `json:"last_modified"`
+	SortKey              string           `json:"sort_key"`
+	TotalCount           int64            `json:"total_count"`
+}
+
+// returns one page of the students matching the full-text query and the filters, the sort key and the ID of the last student are the cursor of the next page
+// without page size all matching students are returned, the total count ignores the cursor and the page size
+func (q *Queries) SearchStudents(ctx context.Context, arg SearchStudentsParams) ([]SearchStudentsRow, error) {
+	rows, err := q.db.Query(ctx, searchStudents,
+		arg.SortBy,
+		arg.Query,
+		arg.StudyProgram,
+		arg.Nationality,
+		arg.CourseID,
+		arg.SemesterTag,
+		arg.PassStatus,
+		arg.CursorID,
+		arg.Descending,
+		arg.CursorKey,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchStudentsRow
+	for rows.Next() {
+		var i SearchStudentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FirstName,
+			&i.LastName,
+			&i.Email,
+			&i.MatriculationNumber,
+			&i.UniversityLogin,
+			&i.HasUniversityAccount,
+			&i.Gender,
+			&i.Nationality,
+			&i.StudyProgram,
+			&i.StudyDegree,
+			&i.CurrentSemester,
+			&i.LastModified,
+			&i.SortKey,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateStudent = `-- name: UpdateStudent :one
 UPDATE student
 SET first_name = $2,
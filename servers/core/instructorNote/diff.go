@@ -0,0 +1,71 @@
+package instructorNote
+
+import (
+	"strings"
+
+	"github.com/prompt-edu/prompt/servers/core/instructorNote/instructorNoteDTO"
+)
+
+// diffMaxCells bounds the memory of a comparison, longer notes are shown as replaced entirely
+const diffMaxCells = 1_000_000
+
+// diffLines compares two texts line by line, based on their longest common subsequence of lines.
+// Removed lines are listed before the lines that replace them.
+func diffLines(from, to string) []instructorNoteDTO.NoteDiffLine {
+	fromLines, toLines := splitLines(from), splitLines(to)
+	if (len(fromLines)+1)*(len(toLines)+1) > diffMaxCells {
+		return replacedLines(fromLines, toLines)
+	}
+
+	// common[i][j] is the length of the longest common subsequence of fromLines[i:] and toLines[j:]
+	common := make([][]int, len(fromLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(toLines)+1)
+	}
+	for i := len(fromLines) - 1; i >= 0; i-- {
+		for j := len(toLines) - 1; j >= 0; j-- {
+			if fromLines[i] == toLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]instructorNoteDTO.NoteDiffLine, 0, max(len(fromLines), len(toLines)))
+	i, j := 0, 0
+	for i < len(fromLines) || j < len(toLines) {
+		switch {
+		case i < len(fromLines) && j < len(toLines) && fromLines[i] == toLines[j]:
+			lines = append(lines, instructorNoteDTO.NoteDiffLine{Type: instructorNoteDTO.DiffLineUnchanged, Content: fromLines[i]})
+			i++
+			j++
+		case i < len(fromLines) && (j == len(toLines) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, instructorNoteDTO.NoteDiffLine{Type: instructorNoteDTO.DiffLineRemoved, Content: fromLines[i]})
+			i++
+		default:
+			lines = append(lines, instructorNoteDTO.NoteDiffLine{Type: instructorNoteDTO.DiffLineAdded, Content: toLines[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+func replacedLines(fromLines, toLines []string) []instructorNoteDTO.NoteDiffLine {
+	lines := make([]instructorNoteDTO.NoteDiffLine, 0, len(fromLines)+len(toLines))
+	for _, line := range fromLines {
+		lines = append(lines, instructorNoteDTO.NoteDiffLine{Type: instructorNoteDTO.DiffLineRemoved, Content: line})
+	}
+	for _, line := range toLines {
+		lines = append(lines, instructorNoteDTO.NoteDiffLine{Type: instructorNoteDTO.DiffLineAdded, Content: line})
+	}
+	return lines
+}
+
+// splitLines returns no lines for an empty text
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,72 @@
+package instructorNote
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prompt-edu/prompt/servers/core/instructorNote/instructorNoteDTO"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected []instructorNoteDTO.NoteDiffLine
+	}{
+		{
+			name:     "identical",
+			from:     "Strong in Swift",
+			to:       "Strong in Swift",
+			expected: []instructorNoteDTO.NoteDiffLine{{Type: instructorNoteDTO.DiffLineUnchanged, Content: "Strong in Swift"}},
+		},
+		{
+			name: "first version",
+			from: "",
+			to:   "Strong in Swift\nNeeds help with git",
+			expected: []instructorNoteDTO.NoteDiffLine{
+				{Type: instructorNoteDTO.DiffLineAdded, Content: "Strong in Swift"},
+				{Type: instructorNoteDTO.DiffLineAdded, Content: "Needs help with git"},
+			},
+		},
+		{
+			name: "changed line",
+			from: "Strong in Swift\nNeeds help with git\nMissed the kickoff",
+			to:   "Strong in Swift\nKnows git well\nMissed the kickoff",
+			expected: []instructorNoteDTO.NoteDiffLine{
+				{Type: instructorNoteDTO.DiffLineUnchanged, Content: "Strong in Swift"},
+				{Type: instructorNoteDTO.DiffLineRemoved, Content: "Needs help with git"},
+				{Type: instructorNoteDTO.DiffLineAdded, Content: "Knows git well"},
+				{Type: instructorNoteDTO.DiffLineUnchanged, Content: "Missed the kickoff"},
+			},
+		},
+		{
+			name: "windows line endings",
+			from: "Strong in Swift\r\nMissed the kickoff",
+			to:   "Strong in Swift",
+			expected: []instructorNoteDTO.NoteDiffLine{
+				{Type: instructorNoteDTO.DiffLineUnchanged, Content: "Strong in Swift"},
+				{Type: instructorNoteDTO.DiffLineRemoved, Content: "Missed the kickoff"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, diffLines(tt.from, tt.to))
+		})
+	}
+}
+
+func TestDiffLinesOfLongNotes(t *testing.T) {
+	from := strings.Repeat("line\n", 1500) + "old"
+	to := strings.Repeat("line\n", 1500) + "new"
+
+	lines := diffLines(from, to)
+
+	// too long to compare, the note is shown as replaced
+	assert.Len(t, lines, 2*1501)
+	assert.Equal(t, instructorNoteDTO.DiffLineRemoved, lines[0].Type)
+	assert.Equal(t, instructorNoteDTO.NoteDiffLine{Type: instructorNoteDTO.DiffLineAdded, Content: "new"}, lines[len(lines)-1])
+}
@@ -9,5 +9,11 @@ type CreateInstructorNote struct {
   New     bool        `json:"new"`
   ForNote uuid.UUID   `json:"forNote"`
   Tags    []uuid.UUID `json:"tags,omitempty"`
+  // CourseID and CoursePhaseID optionally scope the note, the course is derived from the phase if omitted
+  CourseID      *uuid.UUID `json:"courseId,omitempty"`
+  CoursePhaseID *uuid.UUID `json:"coursePhaseId,omitempty"`
+  // Visibility is private, course_staff or lecturers. It defaults to course_staff for scoped notes and to lecturers otherwise.
+  // Edits only change the scope if it is set.
+  Visibility string `json:"visibility,omitempty"`
 }
 
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 )
 
@@ -26,6 +27,9 @@ type InstructorNote struct {
 	DeletedBy   *uuid.UUID     `json:"deletedBy,omitempty"`
 	Versions    []NoteVersion  `json:"versions"`
 	Tags        []NoteTag      `json:"tags"`
+	CourseID      *uuid.UUID `json:"courseId,omitempty"`
+	CoursePhaseID *uuid.UUID `json:"coursePhaseId,omitempty"`
+	Visibility    string     `json:"visibility"`
 }
 
 func GetInstructorNoteDTOFromDBModel(model db.NoteWithVersion) (InstructorNote, error) {
@@ -69,6 +73,9 @@ func GetInstructorNoteDTOFromDBModel(model db.NoteWithVersion) (InstructorNote,
 		DeletedBy:   deletedBy,
 		Versions:    versions,
 		Tags:        tags,
+		CourseID:      optionalUUID(model.CourseID),
+		CoursePhaseID: optionalUUID(model.CoursePhaseID),
+		Visibility:    string(model.Visibility),
 	}, nil
 }
 
@@ -84,3 +91,11 @@ func InstructorNotesFromDBModelToDTO(notes []db.NoteWithVersion) ([]InstructorNo
 	}
   return dtoInstructorNotes, nil
 }
+
+func optionalUUID(value pgtype.UUID) *uuid.UUID {
+	if !value.Valid {
+		return nil
+	}
+	id := uuid.UUID(value.Bytes)
+	return &id
+}
@@ -0,0 +1,23 @@
+package instructorNoteDTO
+
+import "github.com/google/uuid"
+
+const (
+	DiffLineUnchanged = "unchanged"
+	DiffLineAdded     = "added"
+	DiffLineRemoved   = "removed"
+)
+
+type NoteDiffLine struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// NoteDiff compares the content of two versions of a note line by line.
+// FromVersion is -1 if the first version is compared with an empty note.
+type NoteDiff struct {
+	NoteID      uuid.UUID      `json:"noteId"`
+	FromVersion int32          `json:"fromVersion"`
+	ToVersion   int32          `json:"toVersion"`
+	Lines       []NoteDiffLine `json:"lines"`
+}
@@ -0,0 +1,12 @@
+package instructorNoteDTO
+
+import "github.com/google/uuid"
+
+type UpdateInstructorNote struct {
+	Content       string      `json:"content"`
+	Tags          []uuid.UUID `json:"tags,omitempty"`
+	CourseID      *uuid.UUID  `json:"courseId,omitempty"`
+	CoursePhaseID *uuid.UUID  `json:"coursePhaseId,omitempty"`
+	// Visibility replaces the scope of the note if it is set
+	Visibility string `json:"visibility,omitempty"`
+}
@@ -10,7 +10,7 @@ import (
 
 func InitInstructorNoteModule(api *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) {
 
-	setupInstructorNoteRouter(api, keycloakTokenVerifier.KeycloakMiddleware, permissionValidation.CheckAccessControlByRole)
+	setupInstructorNoteRouter(api, keycloakTokenVerifier.KeycloakMiddleware, permissionValidation.CheckAccessControlByRole, checkAccessControlByStudentIDWrapper)
 	InstructorNoteServiceSingleton = &InstructorNoteService{
 		queries: queries,
 		conn:    conn,
@@ -19,3 +19,7 @@ func InitInstructorNoteModule(api *gin.RouterGroup, queries db.Queries, conn *pg
 	// possibly more setup tasks
 }
 
+// initializes the handler func with CheckStudentPermission
+func checkAccessControlByStudentIDWrapper(allowedRoles ...string) gin.HandlerFunc {
+	return permissionValidation.CheckAccessControlByID(permissionValidation.CheckStudentPermission, "student-uuid", allowedRoles...)
+}
@@ -1,21 +1,34 @@
 package instructorNote
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/prompt-edu/prompt/servers/core/instructorNote/instructorNoteDTO"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/storage"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 )
 
-func setupInstructorNoteRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionRoleMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+func setupInstructorNoteRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionRoleMiddleware, permissionStudentMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
 	instructorNoteRouter := router.Group("/instructor-notes", authMiddleware())
 	instructorNoteRouter.GET("/", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getAllInstructorNotes)
+	instructorNoteRouter.GET("/search", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), searchInstructorNotes)
+	instructorNoteRouter.PUT("/:note-uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), updateInstructorNote)
 	instructorNoteRouter.DELETE("/:note-uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), deleteInstructorNote)
+	instructorNoteRouter.GET("/:note-uuid/diff", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getInstructorNoteDiff)
+	instructorNoteRouter.POST("/:note-uuid/restore", permissionRoleMiddleware(permissionValidation.PromptAdmin), restoreInstructorNote)
 
-	instructorNoteRouter.GET("/s/:student-uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getInstructorNoteForStudentByID)
+	instructorNoteRouter.GET("/:note-uuid/files", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getInstructorNoteFiles)
+	instructorNoteRouter.POST("/:note-uuid/files", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), uploadInstructorNoteFile)
+	instructorNoteRouter.DELETE("/:note-uuid/files/:file-uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), deleteInstructorNoteFile)
+
+	// course roles with the notes permission can read the notes of the students of their course
+	instructorNoteRouter.GET("/s/:student-uuid", permissionStudentMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer, permissionValidation.CanViewNotes), getInstructorNoteForStudentByID)
 	instructorNoteRouter.POST("/s/:student-uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), createInstructorNoteForStudentByID)
 
 	instructorNoteRouter.GET("/tags", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getAllNoteTags)
@@ -26,7 +39,7 @@ func setupInstructorNoteRouter(router *gin.RouterGroup, authMiddleware func() gi
 
 // getAllInstructorNotes godoc
 // @Summary Get all notes
-// @Description Get all instructor notes with note versions
+// @Description Get all instructor notes with note versions that are visible to the user
 // @Tags instructorNotes
 // @Produce json
 // @Success 200 {object} []instructorNoteDTO.InstructorNote
@@ -34,7 +47,13 @@ func setupInstructorNoteRouter(router *gin.RouterGroup, authMiddleware func() gi
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /instructor-notes [get]
 func getAllInstructorNotes(c *gin.Context) {
-	studentNotes, err := GetStudentNotes(c)
+	reader, err := noteReaderFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	studentNotes, err := GetStudentNotes(c, reader)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
@@ -44,7 +63,7 @@ func getAllInstructorNotes(c *gin.Context) {
 
 // getInstructorNoteForStudentByID godoc
 // @Summary Get all notes for a student
-// @Description Get all instructor notes with note versions for a specific student, provided the student ID
+// @Description Get all instructor notes with note versions for a specific student that are visible to the user, provided the student ID
 // @Tags instructorNotes
 // @Produce json
 // @Param student-uuid path string true "Student UUID"
@@ -59,7 +78,13 @@ func getInstructorNoteForStudentByID(c *gin.Context) {
 		return
 	}
 
-	studentNotes, err := GetStudentNotesByID(c, id)
+	reader, err := noteReaderFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	studentNotes, err := GetStudentNotesByID(c, id, reader)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
@@ -69,7 +94,7 @@ func getInstructorNoteForStudentByID(c *gin.Context) {
 
 // createInstructorNoteForStudentByID godoc
 // @Summary Create an instructor Note for a student
-// @Description Create a new instructor note or a new edit for a specific student given its ID
+// @Description Create a new instructor note or a new edit for a specific student given its ID. The note can be scoped to a course or course phase, and its visibility restricted.
 // @Tags instructorNotes
 // @Accept json
 // @Produce json
@@ -110,14 +135,25 @@ func createInstructorNoteForStudentByID(c *gin.Context) {
 		handleError(c, http.StatusBadRequest, err)
 		return
 	}
+	scope, err := ValidateNoteScope(c, id, newNote)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
 
-	_, err = NewStudentNote(c, id, newNote, userID, authorName, authorEmail)
+	_, err = NewStudentNote(c, id, newNote, scope, userID, authorName, authorEmail)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	studentNotes, err := GetStudentNotesByID(c, id)
+	reader, err := noteReaderFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	studentNotes, err := GetStudentNotesByID(c, id, reader)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
@@ -162,6 +198,346 @@ func deleteInstructorNote(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, note)
 }
 
+// updateInstructorNote godoc
+// @Summary Update an instructor Note
+// @Description Create a new version of an instructor note. Only the author of the note can update it.
+// @Tags instructorNotes
+// @Accept json
+// @Produce json
+// @Param note-uuid path string true "Note UUID"
+// @Param note body instructorNoteDTO.UpdateInstructorNote true "New version of the note"
+// @Success 200 {object} instructorNoteDTO.InstructorNote
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/{note-uuid} [put]
+func updateInstructorNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var updatedNote instructorNoteDTO.UpdateInstructorNote
+	if err := c.BindJSON(&updatedNote); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := utils.GetUserUUIDFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	note, err := VerifyNoteOwnership(c, noteID, userID)
+	if err != nil {
+		handleError(c, http.StatusForbidden, err)
+		return
+	}
+	if note.DateDeleted.Valid {
+		handleError(c, http.StatusBadRequest, errors.New("cannot edit a deleted note"))
+		return
+	}
+
+	newVersion := instructorNoteDTO.CreateInstructorNote{
+		Content:       updatedNote.Content,
+		New:           false,
+		ForNote:       noteID,
+		Tags:          updatedNote.Tags,
+		CourseID:      updatedNote.CourseID,
+		CoursePhaseID: updatedNote.CoursePhaseID,
+		Visibility:    updatedNote.Visibility,
+	}
+	scope, err := ValidateNoteScope(c, note.ForStudent, newVersion)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	updated, err := NewStudentNote(c, note.ForStudent, newVersion, scope, userID, note.AuthorName, note.AuthorEmail)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, updated)
+}
+
+// getInstructorNoteDiff godoc
+// @Summary Compare two versions of a note
+// @Description Get the line by line difference between two versions of an instructor note. By default, the latest version is compared with the one before.
+// @Tags instructorNotes
+// @Produce json
+// @Param note-uuid path string true "Note UUID"
+// @Param from query int false "Version number to compare from"
+// @Param to query int false "Version number to compare to"
+// @Success 200 {object} instructorNoteDTO.NoteDiff
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/{note-uuid}/diff [get]
+func getInstructorNoteDiff(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	fromVersion, err := parseVersionNumber(c.Query("from"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	toVersion, err := parseVersionNumber(c.Query("to"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	reader, err := noteReaderFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	note, err := GetSingleNoteByID(c, noteID)
+	if err != nil || !reader.CanRead(note.Author, note.CourseID, note.Visibility) {
+		handleError(c, http.StatusNotFound, ErrNoteNotFound)
+		return
+	}
+	// like the versions, the history of deleted notes is hidden
+	if note.DateDeleted.Valid {
+		handleError(c, http.StatusBadRequest, errors.New("the note is deleted"))
+		return
+	}
+
+	diff, err := GetNoteVersionDiff(c, noteID, fromVersion, toVersion)
+	if errors.Is(err, ErrNoteNotFound) || errors.Is(err, ErrNoteVersionNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, diff)
+}
+
+// restoreInstructorNote godoc
+// @Summary Restore a deleted note
+// @Description Restore a soft-deleted instructor note together with its versions and tags
+// @Tags instructorNotes
+// @Produce json
+// @Param note-uuid path string true "Note UUID"
+// @Success 200 {object} instructorNoteDTO.InstructorNote
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/{note-uuid}/restore [post]
+func restoreInstructorNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	note, err := RestoreInstructorNote(c, noteID)
+	if errors.Is(err, ErrNoteNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, ErrNoteNotDeleted) {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, note)
+}
+
+// searchInstructorNotes godoc
+// @Summary Search notes
+// @Description Full-text search in the current content and the tags of the instructor notes that are visible to the user. Deleted notes are not searched.
+// @Tags instructorNotes
+// @Produce json
+// @Param q query string true "Search terms, supports quoted phrases, or and -"
+// @Success 200 {object} []instructorNoteDTO.InstructorNote
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/search [get]
+func searchInstructorNotes(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		handleError(c, http.StatusBadRequest, errors.New("a search query is required"))
+		return
+	}
+
+	reader, err := noteReaderFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	studentNotes, err := SearchStudentNotes(c, query, reader)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, studentNotes)
+}
+
+// getInstructorNoteFiles godoc
+// @Summary Get the files of a note
+// @Description Get the files attached to an instructor note
+// @Tags instructorNotes
+// @Produce json
+// @Param note-uuid path string true "Note UUID"
+// @Success 200 {object} []storage.FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/{note-uuid}/files [get]
+func getInstructorNoteFiles(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	reader, err := noteReaderFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	note, err := GetSingleNoteByID(c, noteID)
+	if err != nil || !reader.CanRead(note.Author, note.CourseID, note.Visibility) {
+		handleError(c, http.StatusNotFound, errors.New("note not found"))
+		return
+	}
+	// like the versions, the files of deleted notes are hidden
+	if note.DateDeleted.Valid {
+		c.IndentedJSON(http.StatusOK, []storage.FileResponse{})
+		return
+	}
+
+	files, err := storage.StorageServiceSingleton.GetNoteFiles(c, noteID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, files)
+}
+
+// uploadInstructorNoteFile godoc
+// @Summary Attach a file to a note
+// @Description Upload a file and attach it to an instructor note. Only the author of the note can attach files.
+// @Tags instructorNotes
+// @Accept multipart/form-data
+// @Produce json
+// @Param note-uuid path string true "Note UUID"
+// @Param file formData file true "File to upload"
+// @Param description formData string false "Description of the file"
+// @Success 201 {object} storage.FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/{note-uuid}/files [post]
+func uploadInstructorNoteFile(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := utils.GetUserUUIDFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	note, err := VerifyNoteOwnership(c, noteID, userID)
+	if err != nil {
+		handleError(c, http.StatusForbidden, err)
+		return
+	}
+	if note.DateDeleted.Valid {
+		handleError(c, http.StatusBadRequest, errors.New("cannot attach files to a deleted note"))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("file is required"))
+		return
+	}
+
+	file, err := storage.StorageServiceSingleton.AttachFileToNote(c, noteID, storage.FileUploadRequest{
+		File:           fileHeader,
+		UploaderUserID: userID.String(),
+		UploaderEmail:  utils.GetUserEmailFromContext(c),
+		Description:    c.PostForm("description"),
+	})
+	if err != nil {
+		if status := storage.RejectedUploadStatus(err); status != 0 {
+			handleError(c, status, err)
+			return
+		}
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, file)
+}
+
+// deleteInstructorNoteFile godoc
+// @Summary Delete a file of a note
+// @Description Remove a file from an instructor note and delete it. Only the author of the note can delete files.
+// @Tags instructorNotes
+// @Param note-uuid path string true "Note UUID"
+// @Param file-uuid path string true "File UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /instructor-notes/{note-uuid}/files/{file-uuid} [delete]
+func deleteInstructorNoteFile(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("note-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	fileID, err := uuid.Parse(c.Param("file-uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := utils.GetUserUUIDFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if _, err := VerifyNoteOwnership(c, noteID, userID); err != nil {
+		handleError(c, http.StatusForbidden, err)
+		return
+	}
+
+	err = storage.StorageServiceSingleton.DeleteNoteFile(c, noteID, fileID)
+	if errors.Is(err, storage.ErrFileNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 // createNoteTag godoc
 // @Summary Create a note tag
 // @Description Create a new note tag with a name and color
@@ -262,6 +638,38 @@ func getAllNoteTags(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, tags)
 }
 
+// parseVersionNumber returns nil for an empty query parameter
+func parseVersionNumber(value string) (*int32, error) {
+	if value == "" {
+		return nil, nil
+	}
+	versionNumber, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || versionNumber < 0 {
+		return nil, errors.New("version numbers must be non-negative integers")
+	}
+	result := int32(versionNumber)
+	return &result, nil
+}
+
+// noteReaderFromContext returns the signed in user, who only gets the notes visible to them
+func noteReaderFromContext(c *gin.Context) (NoteReader, error) {
+	userID, err := utils.GetUserUUIDFromContext(c)
+	if err != nil {
+		return NoteReader{}, err
+	}
+
+	rolesVal, exists := c.Get("userRoles")
+	if !exists {
+		return NoteReader{}, errors.New("missing user roles")
+	}
+	userRoles, ok := rolesVal.(map[string]bool)
+	if !ok {
+		return NoteReader{}, errors.New("invalid roles format in context")
+	}
+
+	return GetNoteReader(c, userID, userRoles)
+}
+
 func handleError(c *gin.Context, statusCode int, err error) {
 	c.JSON(statusCode, utils.ErrorResponse{
 		Error: err.Error(),
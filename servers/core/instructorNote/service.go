@@ -23,20 +23,20 @@ type InstructorNoteService struct {
 
 var InstructorNoteServiceSingleton *InstructorNoteService
 
-func GetStudentNotes(ctx context.Context) ([]instructorNoteDTO.InstructorNote, error) {
+func GetStudentNotes(ctx context.Context, reader NoteReader) ([]instructorNoteDTO.InstructorNote, error) {
   instructorNotes, err := InstructorNoteServiceSingleton.queries.GetAllStudentNotes(ctx)
   if err != nil {
     return nil, err
   }
-  return instructorNoteDTO.InstructorNotesFromDBModelToDTO(instructorNotes)
+  return instructorNoteDTO.InstructorNotesFromDBModelToDTO(filterReadableNotes(instructorNotes, reader))
 }
 
-func GetStudentNotesByID(ctx context.Context, id uuid.UUID) ([]instructorNoteDTO.InstructorNote, error) {
+func GetStudentNotesByID(ctx context.Context, id uuid.UUID, reader NoteReader) ([]instructorNoteDTO.InstructorNote, error) {
   instructorNotes, err := InstructorNoteServiceSingleton.queries.GetStudentNotesForStudent(ctx, id)
   if err != nil {
     return nil, err
   }
-  return instructorNoteDTO.InstructorNotesFromDBModelToDTO(instructorNotes)
+  return instructorNoteDTO.InstructorNotesFromDBModelToDTO(filterReadableNotes(instructorNotes, reader))
 }
 
 func GetAllTags(ctx context.Context) ([]instructorNoteDTO.NoteTag, error) {
@@ -55,7 +55,8 @@ func GetSingleNoteByID(ctx context.Context, id uuid.UUID) (db.Note, error) {
   return note, nil
 }
 
-func NewStudentNote(ctx context.Context, studentID uuid.UUID, params instructorNoteDTO.CreateInstructorNote, signedInUserUUID uuid.UUID, authorName string, authorEmail string) (instructorNoteDTO.InstructorNote, error) {
+// NewStudentNote creates a note or a new version of it. A nil scope keeps the scope of an edited note.
+func NewStudentNote(ctx context.Context, studentID uuid.UUID, params instructorNoteDTO.CreateInstructorNote, scope *NoteScope, signedInUserUUID uuid.UUID, authorName string, authorEmail string) (instructorNoteDTO.InstructorNote, error) {
   tx, err := InstructorNoteServiceSingleton.conn.Begin(ctx)
   if err != nil {
     return instructorNoteDTO.InstructorNote{}, err
@@ -81,6 +82,9 @@ func NewStudentNote(ctx context.Context, studentID uuid.UUID, params instructorN
       DateCreated: rightNow,
       DateDeleted: pgtype.Timestamptz{},
       DeletedBy:   pgtype.UUID{},
+      CourseID:      scope.CourseID,
+      CoursePhaseID: scope.CoursePhaseID,
+      Visibility:    scope.Visibility,
     })
     if err != nil {
       return instructorNoteDTO.InstructorNote{}, err
@@ -93,6 +97,18 @@ func NewStudentNote(ctx context.Context, studentID uuid.UUID, params instructorN
       return instructorNoteDTO.InstructorNote{}, err
     }
     versionNumber = int(latestVersionNumber) + 1
+
+    if scope != nil {
+      err = qtx.UpdateNoteScope(ctx, db.UpdateNoteScopeParams{
+        ID:            noteID,
+        CourseID:      scope.CourseID,
+        CoursePhaseID: scope.CoursePhaseID,
+        Visibility:    scope.Visibility,
+      })
+      if err != nil {
+        return instructorNoteDTO.InstructorNote{}, err
+      }
+    }
   }
 
   versionID, err := uuid.NewRandom()
@@ -192,3 +208,94 @@ func DeleteInstructorNote(ctx context.Context, noteID uuid.UUID, authorID uuid.U
   // Convert to DTO
   return instructorNoteDTO.GetInstructorNoteDTOFromDBModel(deletedNoteWithVersions)
 }
+
+var (
+  ErrNoteNotFound        = errors.New("note not found")
+  ErrNoteVersionNotFound = errors.New("note version not found")
+  ErrNoteNotDeleted      = errors.New("the note is not deleted")
+)
+
+// GetNoteVersionDiff compares two versions of a note. Without versions, the latest version is compared with the one before.
+// The first version is compared with an empty note.
+func GetNoteVersionDiff(ctx context.Context, noteID uuid.UUID, fromVersion, toVersion *int32) (instructorNoteDTO.NoteDiff, error) {
+  if toVersion == nil {
+    latestVersionNumber, err := InstructorNoteServiceSingleton.queries.GetLatestNoteVersionForNoteId(ctx, noteID)
+    if errors.Is(err, pgx.ErrNoRows) {
+      return instructorNoteDTO.NoteDiff{}, ErrNoteNotFound
+    }
+    if err != nil {
+      return instructorNoteDTO.NoteDiff{}, err
+    }
+    toVersion = &latestVersionNumber
+  }
+  if fromVersion == nil {
+    previousVersionNumber := *toVersion - 1
+    fromVersion = &previousVersionNumber
+  }
+
+  toContent, err := getNoteVersionContent(ctx, noteID, *toVersion)
+  if err != nil {
+    return instructorNoteDTO.NoteDiff{}, err
+  }
+  fromContent := ""
+  if *fromVersion >= 0 {
+    fromContent, err = getNoteVersionContent(ctx, noteID, *fromVersion)
+    if err != nil {
+      return instructorNoteDTO.NoteDiff{}, err
+    }
+  }
+
+  return instructorNoteDTO.NoteDiff{
+    NoteID:      noteID,
+    FromVersion: max(*fromVersion, -1),
+    ToVersion:   *toVersion,
+    Lines:       diffLines(fromContent, toContent),
+  }, nil
+}
+
+func getNoteVersionContent(ctx context.Context, noteID uuid.UUID, versionNumber int32) (string, error) {
+  version, err := InstructorNoteServiceSingleton.queries.GetNoteVersion(ctx, db.GetNoteVersionParams{
+    ForNote:       noteID,
+    VersionNumber: versionNumber,
+  })
+  if errors.Is(err, pgx.ErrNoRows) {
+    return "", ErrNoteVersionNotFound
+  }
+  if err != nil {
+    return "", err
+  }
+  return version.Content, nil
+}
+
+// RestoreInstructorNote undoes the deletion of a note, its versions and tags are kept while it is deleted
+func RestoreInstructorNote(ctx context.Context, noteID uuid.UUID) (instructorNoteDTO.InstructorNote, error) {
+  note, err := InstructorNoteServiceSingleton.queries.GetSingleStudentNoteByID(ctx, noteID)
+  if errors.Is(err, pgx.ErrNoRows) {
+    return instructorNoteDTO.InstructorNote{}, ErrNoteNotFound
+  }
+  if err != nil {
+    return instructorNoteDTO.InstructorNote{}, err
+  }
+  if !note.DateDeleted.Valid {
+    return instructorNoteDTO.InstructorNote{}, ErrNoteNotDeleted
+  }
+
+  if _, err := InstructorNoteServiceSingleton.queries.RestoreNote(ctx, noteID); err != nil {
+    return instructorNoteDTO.InstructorNote{}, err
+  }
+
+  restoredNote, err := InstructorNoteServiceSingleton.queries.GetSingleNoteWithVersionsByID(ctx, noteID)
+  if err != nil {
+    return instructorNoteDTO.InstructorNote{}, err
+  }
+  return instructorNoteDTO.GetInstructorNoteDTOFromDBModel(restoredNote)
+}
+
+// SearchStudentNotes searches the current content and the tags of the notes visible to the reader
+func SearchStudentNotes(ctx context.Context, query string, reader NoteReader) ([]instructorNoteDTO.InstructorNote, error) {
+  instructorNotes, err := InstructorNoteServiceSingleton.queries.SearchStudentNotes(ctx, query)
+  if err != nil {
+    return nil, err
+  }
+  return instructorNoteDTO.InstructorNotesFromDBModelToDTO(filterReadableNotes(instructorNotes, reader))
+}
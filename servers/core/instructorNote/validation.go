@@ -3,8 +3,10 @@ package instructorNote
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	"github.com/prompt-edu/prompt/servers/core/instructorNote/instructorNoteDTO"
 )
@@ -41,3 +43,69 @@ func ValidateReferencedNote(createRequest instructorNoteDTO.CreateInstructorNote
 	}
 	return nil
 }
+
+var noteVisibilities = []db.NoteVisibility{db.NoteVisibilityPrivate, db.NoteVisibilityCourseStaff, db.NoteVisibilityLecturers}
+
+// NoteScope is the validated course scope and visibility of a note
+type NoteScope struct {
+	CourseID      pgtype.UUID
+	CoursePhaseID pgtype.UUID
+	Visibility    db.NoteVisibility
+}
+
+// ValidateNoteScope checks that the student participates in the course the note is scoped to.
+// It returns nil if an edit keeps the scope of the note.
+func ValidateNoteScope(ctx context.Context, studentID uuid.UUID, createRequest instructorNoteDTO.CreateInstructorNote) (*NoteScope, error) {
+	if !createRequest.New && createRequest.Visibility == "" {
+		if createRequest.CourseID != nil || createRequest.CoursePhaseID != nil {
+			return nil, errors.New("the visibility must be provided to change the scope of a note")
+		}
+		return nil, nil
+	}
+
+	scope := NoteScope{Visibility: db.NoteVisibility(createRequest.Visibility)}
+	if scope.Visibility != "" && !slices.Contains(noteVisibilities, scope.Visibility) {
+		return nil, errors.New("the visibility must be private, course_staff or lecturers")
+	}
+
+	if createRequest.CoursePhaseID != nil {
+		participation, err := InstructorNoteServiceSingleton.queries.GetCourseParticipationByStudentAndCoursePhaseID(ctx, db.GetCourseParticipationByStudentAndCoursePhaseIDParams{
+			StudentID:     studentID,
+			CoursePhaseID: *createRequest.CoursePhaseID,
+		})
+		if err != nil {
+			return nil, errors.New("the student does not participate in the course of the course phase")
+		}
+		if createRequest.CourseID != nil && *createRequest.CourseID != participation.CourseID {
+			return nil, errors.New("the course phase does not belong to the course")
+		}
+		scope.CourseID = pgtype.UUID{Bytes: participation.CourseID, Valid: true}
+		scope.CoursePhaseID = pgtype.UUID{Bytes: *createRequest.CoursePhaseID, Valid: true}
+	} else if createRequest.CourseID != nil {
+		_, err := InstructorNoteServiceSingleton.queries.GetCourseParticipationByStudentAndCourseID(ctx, db.GetCourseParticipationByStudentAndCourseIDParams{
+			StudentID: studentID,
+			CourseID:  *createRequest.CourseID,
+		})
+		if err != nil {
+			return nil, errors.New("the student does not participate in the course")
+		}
+		scope.CourseID = pgtype.UUID{Bytes: *createRequest.CourseID, Valid: true}
+	}
+
+	if scope.Visibility == "" {
+		scope.Visibility = defaultNoteVisibility(scope.CourseID)
+	}
+	if scope.Visibility == db.NoteVisibilityCourseStaff && !scope.CourseID.Valid {
+		return nil, errors.New("notes visible to the course staff must be scoped to a course")
+	}
+	return &scope, nil
+}
+
+// defaultNoteVisibility keeps course scoped notes within the staff of their course,
+// only unscoped notes are visible to all lecturers by default
+func defaultNoteVisibility(courseID pgtype.UUID) db.NoteVisibility {
+	if courseID.Valid {
+		return db.NoteVisibilityCourseStaff
+	}
+	return db.NoteVisibilityLecturers
+}
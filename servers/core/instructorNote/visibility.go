@@ -0,0 +1,74 @@
+package instructorNote
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+)
+
+// NoteReader is the user reading notes, it decides which notes the user may see:
+//   - private notes are only visible to their author
+//   - course staff notes are visible to the staff of their course and to PROMPT admins
+//   - lecturer notes are visible to all PROMPT lecturers and to the staff of their course
+type NoteReader struct {
+	UserID     uuid.UUID
+	IsAdmin    bool
+	IsLecturer bool
+	// StaffCourseIDs are the courses the user is lecturer, editor or has a course role with the notes permission in
+	StaffCourseIDs map[uuid.UUID]bool
+}
+
+func GetNoteReader(ctx context.Context, userID uuid.UUID, userRoles map[string]bool) (NoteReader, error) {
+	roleStrings := make([]string, 0, len(userRoles))
+	for role, hasRole := range userRoles {
+		if hasRole {
+			roleStrings = append(roleStrings, role)
+		}
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	courseIDs, err := InstructorNoteServiceSingleton.queries.GetCourseIDsWithNoteAccess(ctxWithTimeout, roleStrings)
+	if err != nil {
+		return NoteReader{}, err
+	}
+
+	staffCourseIDs := make(map[uuid.UUID]bool, len(courseIDs))
+	for _, courseID := range courseIDs {
+		staffCourseIDs[courseID] = true
+	}
+
+	return NoteReader{
+		UserID:         userID,
+		IsAdmin:        userRoles[permissionValidation.PromptAdmin],
+		IsLecturer:     userRoles[permissionValidation.PromptLecturer],
+		StaffCourseIDs: staffCourseIDs,
+	}, nil
+}
+
+func (r NoteReader) CanRead(author uuid.UUID, courseID pgtype.UUID, visibility db.NoteVisibility) bool {
+	if author == r.UserID {
+		return true
+	}
+	if visibility == db.NoteVisibilityPrivate {
+		return false
+	}
+	if courseID.Valid && r.StaffCourseIDs[uuid.UUID(courseID.Bytes)] {
+		return true
+	}
+	if visibility == db.NoteVisibilityCourseStaff {
+		return r.IsAdmin
+	}
+	return r.IsAdmin || r.IsLecturer
+}
+
+func filterReadableNotes(notes []db.NoteWithVersion, reader NoteReader) []db.NoteWithVersion {
+	return slices.DeleteFunc(notes, func(note db.NoteWithVersion) bool {
+		return !reader.CanRead(note.Author, note.CourseID, note.Visibility)
+	})
+}
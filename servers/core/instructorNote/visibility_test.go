@@ -0,0 +1,83 @@
+package instructorNote
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteReaderCanRead(t *testing.T) {
+	author := uuid.MustParse("0f0f0f0f-0000-4000-8000-000000000001")
+	courseID := uuid.MustParse("be780b32-a678-4b79-ae1c-80071771d254")
+	otherCourseID := uuid.MustParse("918977e1-2d27-4b55-9064-8504ff027a1a")
+	course := pgtype.UUID{Bytes: courseID, Valid: true}
+	otherCourse := pgtype.UUID{Bytes: otherCourseID, Valid: true}
+
+	authorReader := NoteReader{UserID: author}
+	admin := NoteReader{UserID: uuid.New(), IsAdmin: true}
+	lecturer := NoteReader{UserID: uuid.New(), IsLecturer: true}
+	courseStaff := NoteReader{UserID: uuid.New(), StaffCourseIDs: map[uuid.UUID]bool{courseID: true}}
+	lecturerOfOtherCourse := NoteReader{UserID: uuid.New(), IsLecturer: true, StaffCourseIDs: map[uuid.UUID]bool{otherCourseID: true}}
+
+	tests := []struct {
+		name       string
+		reader     NoteReader
+		courseID   pgtype.UUID
+		visibility db.NoteVisibility
+		canRead    bool
+	}{
+		{"author reads private note", authorReader, course, db.NoteVisibilityPrivate, true},
+		{"admin cannot read private note", admin, course, db.NoteVisibilityPrivate, false},
+		{"course staff cannot read private note", courseStaff, course, db.NoteVisibilityPrivate, false},
+		{"course staff reads course staff note", courseStaff, course, db.NoteVisibilityCourseStaff, true},
+		{"admin reads course staff note", admin, course, db.NoteVisibilityCourseStaff, true},
+		{"lecturer of other course cannot read course staff note", lecturerOfOtherCourse, course, db.NoteVisibilityCourseStaff, false},
+		{"course staff without course cannot be read", courseStaff, pgtype.UUID{}, db.NoteVisibilityCourseStaff, false},
+		{"lecturer reads lecturer note", lecturer, otherCourse, db.NoteVisibilityLecturers, true},
+		{"lecturer reads unscoped lecturer note", lecturer, pgtype.UUID{}, db.NoteVisibilityLecturers, true},
+		{"course staff reads lecturer note of its course", courseStaff, course, db.NoteVisibilityLecturers, true},
+		{"course staff cannot read lecturer note of other course", courseStaff, otherCourse, db.NoteVisibilityLecturers, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.canRead, tt.reader.CanRead(author, tt.courseID, tt.visibility))
+		})
+	}
+}
+
+func TestDefaultNoteVisibility(t *testing.T) {
+	courseID := uuid.MustParse("be780b32-a678-4b79-ae1c-80071771d254")
+	course := pgtype.UUID{Bytes: courseID, Valid: true}
+	author := uuid.New()
+	lecturerOfOtherCourse := NoteReader{UserID: uuid.New(), IsLecturer: true, StaffCourseIDs: map[uuid.UUID]bool{uuid.New(): true}}
+	courseStaff := NoteReader{UserID: uuid.New(), StaffCourseIDs: map[uuid.UUID]bool{courseID: true}}
+
+	assert.Equal(t, db.NoteVisibilityCourseStaff, defaultNoteVisibility(course))
+	assert.False(t, lecturerOfOtherCourse.CanRead(author, course, defaultNoteVisibility(course)), "a lecturer of another course cannot read a scoped note with the default visibility")
+	assert.True(t, courseStaff.CanRead(author, course, defaultNoteVisibility(course)))
+
+	assert.Equal(t, db.NoteVisibilityLecturers, defaultNoteVisibility(pgtype.UUID{}))
+	assert.True(t, lecturerOfOtherCourse.CanRead(author, pgtype.UUID{}, defaultNoteVisibility(pgtype.UUID{})))
+}
+
+func TestFilterReadableNotes(t *testing.T) {
+	reader := NoteReader{UserID: uuid.New(), IsLecturer: true}
+	notes := []db.NoteWithVersion{
+		{ID: uuid.New(), Author: uuid.New(), Visibility: db.NoteVisibilityLecturers},
+		{ID: uuid.New(), Author: uuid.New(), Visibility: db.NoteVisibilityPrivate},
+		{ID: uuid.New(), Author: reader.UserID, Visibility: db.NoteVisibilityPrivate},
+	}
+	expected := []uuid.UUID{notes[0].ID, notes[2].ID}
+
+	readable := filterReadableNotes(notes, reader)
+
+	ids := []uuid.UUID{}
+	for _, note := range readable {
+		ids = append(ids, note.ID)
+	}
+	assert.Equal(t, expected, ids)
+}
@@ -5,11 +5,27 @@ import (
 	"fmt"
 
 	"github.com/Nerzal/gocloak/v13"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	log "github.com/sirupsen/logrus"
 )
 
+// CreateCourseGroupsAndRoles creates the lecturer and editor groups and roles of a new course in Keycloak.
+// If the course roles are stored in the database, Keycloak is only synced on a best effort basis.
 func CreateCourseGroupsAndRoles(ctx context.Context, courseName, iterationName, userID string) error {
+	if !keycloakTokenVerifier.KeycloakRoleSyncEnabled() {
+		return nil
+	}
+
+	err := createCourseGroupsAndRoles(ctx, courseName, iterationName, userID)
+	if err != nil && keycloakTokenVerifier.LocalCourseRolesEnabled() {
+		log.WithError(err).Warn("Failed to sync the course roles to keycloak, the roles stored in the database still apply")
+		return nil
+	}
+	return err
+}
+
+func createCourseGroupsAndRoles(ctx context.Context, courseName, iterationName, userID string) error {
 	token, err := LoginClient(ctx)
 	if err != nil {
 		return err
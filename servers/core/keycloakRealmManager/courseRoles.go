@@ -0,0 +1,165 @@
+package keycloakRealmManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager/keycloakRealmDTO"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	log "github.com/sirupsen/logrus"
+)
+
+// COURSE_ROLES_GROUP_NAME is the subgroup of a course that contains a group per custom course role
+var COURSE_ROLES_GROUP_NAME = "CourseRoles"
+
+// CreateCourseRoleGroup creates the Keycloak group and role of a custom course role.
+// It is idempotent, so a failed creation can be retried.
+func CreateCourseRoleGroup(ctx context.Context, courseID uuid.UUID, roleName string) error {
+	courseGroupName, err := GetCourseGroupName(ctx, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to get course group name: %w", err)
+	}
+
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	courseRolesGroupID, err := getOrCreateCourseSubGroup(ctx, token.AccessToken, courseID, courseGroupName)
+	if err != nil {
+		return err
+	}
+
+	groupPath := fmt.Sprintf("/%s/%s/%s/%s", TOP_LEVEL_GROUP_NAME, courseGroupName, COURSE_ROLES_GROUP_NAME, roleName)
+	roleGroupID, err := getOrCreateChildGroup(ctx, token.AccessToken, groupPath, roleName, courseRolesGroupID)
+	if err != nil {
+		return err
+	}
+
+	role, err := GetOrCreateRealmRole(ctx, token.AccessToken, permissionValidation.CourseRoleString(courseGroupName, roleName))
+	if err != nil {
+		return fmt.Errorf("failed to create keycloak role: %w", err)
+	}
+
+	if err := AddRoleToGroup(ctx, token.AccessToken, roleGroupID, role); err != nil {
+		return errors.New("failed to associate role with group")
+	}
+	return nil
+}
+
+// DeleteCourseRoleGroup removes the Keycloak group and role of a custom course role, missing ones are ignored
+func DeleteCourseRoleGroup(ctx context.Context, courseID uuid.UUID, roleName string) error {
+	courseGroupName, err := GetCourseGroupName(ctx, courseID)
+	if err != nil {
+		return fmt.Errorf("failed to get course group name: %w", err)
+	}
+
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	groupPath := fmt.Sprintf("/%s/%s/%s/%s", TOP_LEVEL_GROUP_NAME, courseGroupName, COURSE_ROLES_GROUP_NAME, roleName)
+	group, err := KeycloakRealmSingleton.client.GetGroupByPath(ctx, token.AccessToken, KeycloakRealmSingleton.Realm, groupPath)
+	if err == nil && group.ID != nil {
+		if err := KeycloakRealmSingleton.client.DeleteGroup(ctx, token.AccessToken, KeycloakRealmSingleton.Realm, *group.ID); err != nil {
+			log.Error("failed to delete course role group: ", err)
+			return errors.New("failed to delete keycloak group")
+		}
+	} else if err != nil && !strings.Contains(err.Error(), "404") {
+		log.Errorf("failed to get group from Keycloak for path [%s]: %v", groupPath, err)
+		return fmt.Errorf("failed to get group: %w", err)
+	}
+
+	roleString := permissionValidation.CourseRoleString(courseGroupName, roleName)
+	err = KeycloakRealmSingleton.client.DeleteClientRole(ctx, token.AccessToken, KeycloakRealmSingleton.Realm, KeycloakRealmSingleton.idOfClient, roleString)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		log.Error("failed to delete course role: ", err)
+		return errors.New("failed to delete keycloak role")
+	}
+	return nil
+}
+
+// AddStudentsToCourseRoleGroup gives students, e.g. tutors, a custom course role
+func AddStudentsToCourseRoleGroup(ctx context.Context, courseID uuid.UUID, roleName string, studentIDs []uuid.UUID) (keycloakRealmDTO.AddStudentsToGroupResponse, error) {
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return keycloakRealmDTO.AddStudentsToGroupResponse{}, err
+	}
+
+	roleGroupID, err := getCourseRoleGroupID(ctx, token.AccessToken, courseID, roleName)
+	if err != nil {
+		return keycloakRealmDTO.AddStudentsToGroupResponse{}, err
+	}
+
+	succeededStudents, failedStudentIDs, err := AddStudentIDsToKeycloakGroup(ctx, token.AccessToken, studentIDs, roleGroupID)
+	if err != nil {
+		log.Error("Failed to add students to group: ", err)
+		return keycloakRealmDTO.AddStudentsToGroupResponse{}, errors.New("failed to add students to group")
+	}
+
+	return keycloakRealmDTO.AddStudentsToGroupResponse{
+		SucceededToAddStudentIDs: succeededStudents,
+		FailedToAddStudentIDs:    failedStudentIDs,
+	}, nil
+}
+
+// GetCourseRoleMembers returns the members of a custom course role
+func GetCourseRoleMembers(ctx context.Context, courseID uuid.UUID, roleName string) (keycloakRealmDTO.GroupMembers, error) {
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return keycloakRealmDTO.GroupMembers{}, fmt.Errorf("failed to login to keycloak: %w", err)
+	}
+
+	roleGroupID, err := getCourseRoleGroupID(ctx, token.AccessToken, courseID, roleName)
+	if err != nil {
+		return keycloakRealmDTO.GroupMembers{}, err
+	}
+
+	members, err := GetGroupMembers(ctx, token.AccessToken, roleGroupID)
+	if err != nil {
+		return keycloakRealmDTO.GroupMembers{}, fmt.Errorf("failed to get group members: %w", err)
+	}
+	return groupMembersToDTO(ctx, members)
+}
+
+func getCourseRoleGroupID(ctx context.Context, accessToken string, courseID uuid.UUID, roleName string) (string, error) {
+	courseGroupName, err := GetCourseGroupName(ctx, courseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get course group name: %w", err)
+	}
+
+	groupPath := fmt.Sprintf("/%s/%s/%s/%s", TOP_LEVEL_GROUP_NAME, courseGroupName, COURSE_ROLES_GROUP_NAME, roleName)
+	group, err := GetGroupByPath(ctx, accessToken, groupPath, roleName)
+	if err != nil {
+		return "", errors.New("failed to get course role group")
+	}
+	return *group.ID, nil
+}
+
+// getOrCreateCourseSubGroup returns the ID of the COURSE_ROLES_GROUP_NAME group of a course, which is created if needed
+func getOrCreateCourseSubGroup(ctx context.Context, accessToken string, courseID uuid.UUID, courseGroupName string) (string, error) {
+	courseGroup, err := GetCourseGroup(ctx, accessToken, courseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get course group: %w", err)
+	}
+
+	groupPath := fmt.Sprintf("/%s/%s/%s", TOP_LEVEL_GROUP_NAME, courseGroupName, COURSE_ROLES_GROUP_NAME)
+	return getOrCreateChildGroup(ctx, accessToken, groupPath, COURSE_ROLES_GROUP_NAME, *courseGroup.ID)
+}
+
+// getOrCreateChildGroup returns the ID of the group at groupPath, or creates it under parentGroupID
+func getOrCreateChildGroup(ctx context.Context, accessToken, groupPath, groupName, parentGroupID string) (string, error) {
+	group, err := KeycloakRealmSingleton.client.GetGroupByPath(ctx, accessToken, KeycloakRealmSingleton.Realm, groupPath)
+	if err == nil && group.Name != nil && *group.Name == groupName {
+		return *group.ID, nil
+	} else if err != nil && !strings.Contains(err.Error(), "404") {
+		log.Errorf("failed to get group from Keycloak for path [%s]: %v", groupPath, err)
+		return "", fmt.Errorf("failed to get group: %w", err)
+	}
+
+	return CreateChildGroup(ctx, accessToken, groupName, parentGroupID)
+}
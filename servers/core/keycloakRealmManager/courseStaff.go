@@ -0,0 +1,193 @@
+package keycloakRealmManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager/keycloakRealmDTO"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrLocalCourseRolesDisabled = errors.New("the course lecturers and editors are stored in keycloak")
+	ErrCourseStaffNotFound      = errors.New("course staff member not found")
+)
+
+// GetEditorGroupMembers returns the editors of a course, from the database if the course roles are stored there
+func GetEditorGroupMembers(ctx context.Context, courseID uuid.UUID) (keycloakRealmDTO.GroupMembers, error) {
+	if !keycloakTokenVerifier.LocalCourseRolesEnabled() {
+		return getKeycloakEditorGroupMembers(ctx, courseID)
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	students, err := KeycloakRealmSingleton.queries.GetCourseStaffStudents(ctxWithTimeout, db.GetCourseStaffStudentsParams{
+		CourseID: courseID,
+		Role:     permissionValidation.CourseEditor,
+	})
+	if err != nil {
+		log.Error("Failed to get the course editors: ", err)
+		return keycloakRealmDTO.GroupMembers{}, errors.New("failed to get the course editors")
+	}
+
+	studentDTOs := make([]studentDTO.Student, 0, len(students))
+	for _, student := range students {
+		studentDTOs = append(studentDTOs, studentDTO.GetStudentDTOFromDBModel(student))
+	}
+	return keycloakRealmDTO.GroupMembers{
+		Students:    studentDTOs,
+		NonStudents: []keycloakRealmDTO.KeycloakUser{},
+	}, nil
+}
+
+func getKeycloakEditorGroupMembers(ctx context.Context, courseID uuid.UUID) (keycloakRealmDTO.GroupMembers, error) {
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return keycloakRealmDTO.GroupMembers{}, fmt.Errorf("failed to login to keycloak: %w", err)
+	}
+
+	editorGroup, err := GetCourseEditorGroup(ctx, token.AccessToken, courseID)
+	if err != nil {
+		log.Error("Failed to get course group: ", err)
+		return keycloakRealmDTO.GroupMembers{}, errors.New("failed to get course group")
+	}
+
+	members, err := GetGroupMembers(ctx, token.AccessToken, *editorGroup.ID)
+	if err != nil {
+		return keycloakRealmDTO.GroupMembers{}, fmt.Errorf("failed to get group members: %w", err)
+	}
+	return groupMembersToDTO(ctx, members)
+}
+
+// RemoveStudentFromEditorGroup revokes the editor role of a student.
+// If the course roles are stored in the database, the assignment is deleted there and Keycloak is only synced on a best effort basis.
+func RemoveStudentFromEditorGroup(ctx context.Context, courseID, studentID uuid.UUID) error {
+	if !keycloakTokenVerifier.LocalCourseRolesEnabled() {
+		return removeStudentsFromKeycloakStaffGroup(ctx, courseID, permissionValidation.CourseEditor, []uuid.UUID{studentID})
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	removedStudentIDs, err := KeycloakRealmSingleton.queries.DeleteCourseStaffStudents(ctxWithTimeout, db.DeleteCourseStaffStudentsParams{
+		CourseID:   courseID,
+		Role:       permissionValidation.CourseEditor,
+		StudentIds: []uuid.UUID{studentID},
+	})
+	if err != nil {
+		log.Error("Failed to remove the course editor: ", err)
+		return errors.New("failed to remove the course editor")
+	}
+	keycloakTokenVerifier.InvalidateRoleCache()
+
+	if keycloakTokenVerifier.KeycloakRoleSyncEnabled() {
+		if err := removeStudentsFromKeycloakStaffGroup(ctx, courseID, permissionValidation.CourseEditor, []uuid.UUID{studentID}); err != nil {
+			log.WithError(err).Warn("Failed to sync the course editors to keycloak, the roles stored in the database still apply")
+		}
+	}
+
+	if len(removedStudentIDs) == 0 {
+		return ErrCourseStaffNotFound
+	}
+	return nil
+}
+
+// GetCourseStaff lists the lecturer and editor assignments of a course that are stored in the database
+func GetCourseStaff(ctx context.Context, courseID uuid.UUID) ([]keycloakRealmDTO.CourseStaffMember, error) {
+	if !keycloakTokenVerifier.LocalCourseRolesEnabled() {
+		return nil, ErrLocalCourseRolesDisabled
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	staff, err := KeycloakRealmSingleton.queries.GetCourseStaff(ctxWithTimeout, courseID)
+	if err != nil {
+		log.Error("Failed to get the course staff: ", err)
+		return nil, errors.New("failed to get the course staff")
+	}
+
+	staffDTOs := make([]keycloakRealmDTO.CourseStaffMember, 0, len(staff))
+	for _, member := range staff {
+		staffDTOs = append(staffDTOs, keycloakRealmDTO.GetCourseStaffMemberDTOFromDBModel(member))
+	}
+	return staffDTOs, nil
+}
+
+// RemoveCourseStaff deletes a lecturer or editor assignment that is stored in the database.
+// Keycloak is only synced on a best effort basis.
+func RemoveCourseStaff(ctx context.Context, courseID, staffID uuid.UUID) error {
+	if !keycloakTokenVerifier.LocalCourseRolesEnabled() {
+		return ErrLocalCourseRolesDisabled
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	member, err := KeycloakRealmSingleton.queries.DeleteCourseStaff(ctxWithTimeout, db.DeleteCourseStaffParams{
+		ID:       staffID,
+		CourseID: courseID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCourseStaffNotFound
+	}
+	if err != nil {
+		log.Error("Failed to remove the course staff member: ", err)
+		return errors.New("failed to remove the course staff member")
+	}
+	keycloakTokenVerifier.InvalidateRoleCache()
+
+	if keycloakTokenVerifier.KeycloakRoleSyncEnabled() {
+		if err := removeCourseStaffFromKeycloak(ctx, member); err != nil {
+			log.WithError(err).Warn("Failed to sync the course staff to keycloak, the roles stored in the database still apply")
+		}
+	}
+	return nil
+}
+
+func removeCourseStaffFromKeycloak(ctx context.Context, member db.CourseStaff) error {
+	if member.StudentID.Valid {
+		return removeStudentsFromKeycloakStaffGroup(ctx, member.CourseID, member.Role, []uuid.UUID{member.StudentID.Bytes})
+	}
+
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return err
+	}
+	staffGroup, err := GetCourseStaffGroup(ctx, token.AccessToken, member.CourseID, member.Role)
+	if err != nil {
+		return fmt.Errorf("failed to get course group: %w", err)
+	}
+	return RemoveUserFromKeycloakGroup(ctx, token.AccessToken, member.UserID.String, *staffGroup.ID)
+}
+
+func removeStudentsFromKeycloakStaffGroup(ctx context.Context, courseID uuid.UUID, role string, studentIDs []uuid.UUID) error {
+	token, err := LoginClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	staffGroup, err := GetCourseStaffGroup(ctx, token.AccessToken, courseID, role)
+	if err != nil {
+		log.Error("Failed to get course group: ", err)
+		return errors.New("failed to get course group")
+	}
+
+	_, failedStudentIDs, err := RemoveStudentIDsFromKeycloakGroup(ctx, token.AccessToken, studentIDs, *staffGroup.ID)
+	if err != nil {
+		log.Error("Failed to remove students from group: ", err)
+		return errors.New("failed to remove students from group")
+	}
+	if len(failedStudentIDs) > 0 {
+		return fmt.Errorf("failed to remove %d students from group", len(failedStudentIDs))
+	}
+	return nil
+}
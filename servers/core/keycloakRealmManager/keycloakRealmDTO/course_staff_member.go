@@ -0,0 +1,40 @@
+package keycloakRealmDTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+// CourseStaffMember is a lecturer or editor assignment stored in the Core Server.
+// Lecturers who created the course are identified by their Keycloak user ID, editors by their student record.
+type CourseStaffMember struct {
+	ID        uuid.UUID  `json:"id"`
+	Role      string     `json:"role"`
+	UserID    string     `json:"userID,omitempty"`
+	StudentID *uuid.UUID `json:"studentID,omitempty"`
+	FirstName string     `json:"firstName,omitempty"`
+	LastName  string     `json:"lastName,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func GetCourseStaffMemberDTOFromDBModel(member db.GetCourseStaffRow) CourseStaffMember {
+	var studentID *uuid.UUID
+	if member.StudentID.Valid {
+		id := uuid.UUID(member.StudentID.Bytes)
+		studentID = &id
+	}
+
+	return CourseStaffMember{
+		ID:        member.ID,
+		Role:      member.Role,
+		UserID:    member.UserID.String,
+		StudentID: studentID,
+		FirstName: member.FirstName.String,
+		LastName:  member.LastName.String,
+		Email:     member.Email.String,
+		CreatedAt: member.CreatedAt.Time,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Nerzal/gocloak/v13"
 	"github.com/google/uuid"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -99,13 +100,18 @@ func GetCourseGroup(ctx context.Context, accessToken string, courseID uuid.UUID)
 }
 
 func GetCourseEditorGroup(ctx context.Context, accessToken string, courseID uuid.UUID) (*gocloak.Group, error) {
+	return GetCourseStaffGroup(ctx, accessToken, courseID, permissionValidation.CourseEditor)
+}
+
+// GetCourseStaffGroup returns the Lecturer or Editor group of a course
+func GetCourseStaffGroup(ctx context.Context, accessToken string, courseID uuid.UUID, role string) (*gocloak.Group, error) {
 	courseGroupName, err := GetCourseGroupName(ctx, courseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get course group name: %w", err)
 	}
-	groupPath := "/" + TOP_LEVEL_GROUP_NAME + "/" + courseGroupName + "/" + "Editor"
+	groupPath := "/" + TOP_LEVEL_GROUP_NAME + "/" + courseGroupName + "/" + role
 
-	return GetGroupByPath(ctx, accessToken, groupPath, "Editor")
+	return GetGroupByPath(ctx, accessToken, groupPath, role)
 }
 
 // GetOrCreateCustomTopLevelGroup returns the ID of the “CUSTOM_GROUPS_NAME” subgroup under the
@@ -275,6 +281,52 @@ func AddStudentIDsToKeycloakGroup(ctx context.Context, accessToken string, stude
 	return succeededStudents, failedStudents, nil
 }
 
+// RemoveStudentIDsFromKeycloakGroup removes each student from the given group.
+// Returns slices of succeeded and failed student UUIDs.
+func RemoveStudentIDsFromKeycloakGroup(ctx context.Context, accessToken string, studentIDs []uuid.UUID, groupID string) ([]uuid.UUID, []uuid.UUID, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	students, err := KeycloakRealmSingleton.queries.GetStudentUniversityLogins(ctxWithTimeout, studentIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get student emails: %w", err)
+	}
+	if len(students) != len(studentIDs) {
+		return nil, nil, errors.New("not all students found in DB")
+	}
+
+	var failedStudents []uuid.UUID
+	var succeededStudents []uuid.UUID
+	for _, student := range students {
+		keycloakUser, err := KeycloakRealmSingleton.client.GetUsers(ctxWithTimeout, accessToken, KeycloakRealmSingleton.Realm, gocloak.GetUsersParams{
+			Username: &student.UniversityLogin.String,
+		})
+		if err != nil || len(keycloakUser) != 1 {
+			log.Error("failed to get keycloak user for student: ", err)
+			failedStudents = append(failedStudents, student.ID)
+			continue
+		}
+
+		if err := RemoveUserFromKeycloakGroup(ctxWithTimeout, accessToken, *keycloakUser[0].ID, groupID); err != nil {
+			failedStudents = append(failedStudents, student.ID)
+			continue
+		}
+		succeededStudents = append(succeededStudents, student.ID)
+	}
+
+	return succeededStudents, failedStudents, nil
+}
+
+// RemoveUserFromKeycloakGroup removes a Keycloak user from the given group
+func RemoveUserFromKeycloakGroup(ctx context.Context, accessToken, userID, groupID string) error {
+	err := KeycloakRealmSingleton.client.DeleteUserFromGroup(ctx, accessToken, KeycloakRealmSingleton.Realm, userID, groupID)
+	if err != nil {
+		log.Error("failed to remove user from group: ", err)
+		return err
+	}
+	return nil
+}
+
 // GetGroupMembers returns the users that belong to the given group.
 func GetGroupMembers(ctx context.Context, accessToken, groupID string) ([]*gocloak.User, error) {
 	members, err := KeycloakRealmSingleton.client.GetGroupMembers(ctx, accessToken, KeycloakRealmSingleton.Realm, groupID, gocloak.GetGroupsParams{})
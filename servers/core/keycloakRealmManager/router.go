@@ -22,6 +22,11 @@ func setupKeycloakRouter(router *gin.RouterGroup, authMiddleware func() gin.Hand
 	keycloak.GET("/group/:groupName/students", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getStudentsInGroup)
 	// Adding Students to the editor role of a course
 	keycloak.PUT("/group/editor/students", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), addStudentsToEditorGroup)
+	keycloak.GET("/group/editor/students", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getEditorGroupMembers)
+	keycloak.DELETE("/group/editor/students/:studentID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), removeStudentFromEditorGroup)
+	// Lecturers and editors stored in the Core Server
+	keycloak.GET("/staff", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), getCourseStaff)
+	keycloak.DELETE("/staff/:staffID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), removeCourseStaff)
 	// Adding Students to a custom group
 	keycloak.PUT("/group/:groupName/students", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer), addStudentsToGroup)
 }
@@ -169,6 +174,133 @@ func getStudentsInGroup(c *gin.Context) {
 
 }
 
+// getEditorGroupMembers godoc
+// @Summary Get the editors of a course
+// @Description Get the editors of a course, read from the database if the course roles are stored there
+// @Tags keycloak
+// @Produce json
+// @Param courseID path string true "Course UUID"
+// @Success 200 {object} keycloakRealmDTO.GroupMembers
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /keycloak/{courseID}/group/editor/students [get]
+func getEditorGroupMembers(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	members, err := GetEditorGroupMembers(c, courseID)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, members)
+}
+
+// removeStudentFromEditorGroup godoc
+// @Summary Remove a student from the editor group
+// @Description Revoke the editor role of a student for a course
+// @Tags keycloak
+// @Param courseID path string true "Course UUID"
+// @Param studentID path string true "Student UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /keycloak/{courseID}/group/editor/students/{studentID} [delete]
+func removeStudentFromEditorGroup(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	studentID, err := uuid.Parse(c.Param("studentID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := RemoveStudentFromEditorGroup(c, courseID, studentID); err != nil {
+		handleCourseStaffError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getCourseStaff godoc
+// @Summary Get the course staff
+// @Description Get the lecturers and editors of a course that are stored in the Core Server (COURSE_ROLE_STORE=database)
+// @Tags keycloak
+// @Produce json
+// @Param courseID path string true "Course UUID"
+// @Success 200 {array} keycloakRealmDTO.CourseStaffMember
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /keycloak/{courseID}/staff [get]
+func getCourseStaff(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	staff, err := GetCourseStaff(c, courseID)
+	if err != nil {
+		handleCourseStaffError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, staff)
+}
+
+// removeCourseStaff godoc
+// @Summary Remove a course staff member
+// @Description Remove a lecturer or editor of a course that is stored in the Core Server (COURSE_ROLE_STORE=database)
+// @Tags keycloak
+// @Param courseID path string true "Course UUID"
+// @Param staffID path string true "Course staff UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /keycloak/{courseID}/staff/{staffID} [delete]
+func removeCourseStaff(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	staffID, err := uuid.Parse(c.Param("staffID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := RemoveCourseStaff(c, courseID, staffID); err != nil {
+		handleCourseStaffError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func handleCourseStaffError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrLocalCourseRolesDisabled):
+		handleError(c, http.StatusBadRequest, err)
+	case errors.Is(err, ErrCourseStaffNotFound):
+		handleError(c, http.StatusNotFound, err)
+	default:
+		handleError(c, http.StatusInternalServerError, err)
+	}
+}
+
 func handleError(c *gin.Context, statusCode int, err error) {
 	c.JSON(statusCode, utils.ErrorResponse{
 		Error: err.Error(),
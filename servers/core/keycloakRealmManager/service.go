@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/Nerzal/gocloak/v13"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	"github.com/prompt-edu/prompt/servers/core/keycloakRealmManager/keycloakRealmDTO"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	log "github.com/sirupsen/logrus"
 )
@@ -99,6 +104,11 @@ func GetStudentsInGroup(ctx context.Context, courseID uuid.UUID, groupName strin
 		return keycloakRealmDTO.GroupMembers{}, fmt.Errorf("failed to get group members: %w", err)
 	}
 
+	return groupMembersToDTO(ctx, members)
+}
+
+// groupMembersToDTO splits Keycloak group members into students of PROMPT and other users.
+func groupMembersToDTO(ctx context.Context, members []*gocloak.User) (keycloakRealmDTO.GroupMembers, error) {
 	// Build a slice of emails from the group members.
 	// (Skip any members without an email.)
 	var memberEmails []string
@@ -110,7 +120,7 @@ func GetStudentsInGroup(ctx context.Context, courseID uuid.UUID, groupName strin
 		memberEmails = append(memberEmails, *member.Email)
 	}
 
-	// 4. Get students from the database using the list of emails.
+	// Get students from the database using the list of emails.
 	studentsObjects, err := KeycloakRealmSingleton.queries.GetStudentsByEmail(ctx, memberEmails)
 	if err != nil {
 		log.Error("Failed to get students by email", "error", err)
@@ -132,7 +142,7 @@ func GetStudentsInGroup(ctx context.Context, courseID uuid.UUID, groupName strin
 
 	var notFoundUsers []keycloakRealmDTO.KeycloakUser
 
-	// 5. Check each group member against the student lookup.
+	// Check each group member against the student lookup.
 	for _, member := range members {
 		// Skip members with missing email.
 		if member.Email == nil || *member.Email == "" {
@@ -151,7 +161,50 @@ func GetStudentsInGroup(ctx context.Context, courseID uuid.UUID, groupName strin
 	}, nil
 }
 
+// AddStudentsToEditorGroup makes the students editors of the course.
+// If the course roles are stored in the database, the students are stored as editors and Keycloak is only synced on a best effort basis.
 func AddStudentsToEditorGroup(ctx context.Context, courseID uuid.UUID, studentIDs []uuid.UUID) (keycloakRealmDTO.AddStudentsToGroupResponse, error) {
+	if !keycloakTokenVerifier.LocalCourseRolesEnabled() {
+		return addStudentsToKeycloakEditorGroup(ctx, courseID, studentIDs)
+	}
+
+	response := addStudentsToCourseStaff(ctx, courseID, studentIDs, permissionValidation.CourseEditor)
+	keycloakTokenVerifier.InvalidateRoleCache()
+
+	if keycloakTokenVerifier.KeycloakRoleSyncEnabled() {
+		if _, err := addStudentsToKeycloakEditorGroup(ctx, courseID, response.SucceededToAddStudentIDs); err != nil {
+			log.WithError(err).Warn("Failed to sync the course editors to keycloak, the roles stored in the database still apply")
+		}
+	}
+	return response, nil
+}
+
+// addStudentsToCourseStaff stores the role assignments in the database, unknown students are reported as failed
+func addStudentsToCourseStaff(ctx context.Context, courseID uuid.UUID, studentIDs []uuid.UUID, role string) keycloakRealmDTO.AddStudentsToGroupResponse {
+	response := keycloakRealmDTO.AddStudentsToGroupResponse{
+		SucceededToAddStudentIDs: []uuid.UUID{},
+		FailedToAddStudentIDs:    []uuid.UUID{},
+	}
+
+	for _, studentID := range studentIDs {
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		err := KeycloakRealmSingleton.queries.AddCourseStaffStudent(ctxWithTimeout, db.AddCourseStaffStudentParams{
+			CourseID:  courseID,
+			Role:      role,
+			StudentID: pgtype.UUID{Bytes: studentID, Valid: true},
+		})
+		cancel()
+		if err != nil {
+			log.WithError(err).WithField("studentID", studentID).Warn("Failed to store the course role of a student")
+			response.FailedToAddStudentIDs = append(response.FailedToAddStudentIDs, studentID)
+			continue
+		}
+		response.SucceededToAddStudentIDs = append(response.SucceededToAddStudentIDs, studentID)
+	}
+	return response
+}
+
+func addStudentsToKeycloakEditorGroup(ctx context.Context, courseID uuid.UUID, studentIDs []uuid.UUID) (keycloakRealmDTO.AddStudentsToGroupResponse, error) {
 	// 1. Log into keycloak
 	token, err := LoginClient(ctx)
 	if err != nil {
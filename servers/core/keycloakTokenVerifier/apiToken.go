@@ -0,0 +1,70 @@
+package keycloakTokenVerifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+// APITokenPrefix marks the API tokens managed by PROMPT, all other bearer tokens are verified by Keycloak
+const APITokenPrefix = "prompt_"
+
+var ErrInvalidAPIToken = errors.New("invalid, expired or revoked API token")
+
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// HashAPIToken returns the hex encoded SHA-256 hash under which a token is stored.
+// The tokens are random, so they need no salt or slow hash.
+func HashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// authenticateAPIToken sets the same context keys as a Keycloak user token.
+// The token acts with its course roles only, the user ID is the token ID.
+func authenticateAPIToken(c *gin.Context, token string) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(c.Request.Context())
+	defer cancel()
+
+	apiToken, err := KeycloakTokenVerifierSingleton.queries.GetActiveAPITokenByHash(ctxWithTimeout, HashAPIToken(token))
+	if err != nil {
+		log.Debug("API token not found: ", err)
+		return ErrInvalidAPIToken
+	}
+
+	if err := KeycloakTokenVerifierSingleton.queries.UpdateAPITokenLastUsed(ctxWithTimeout, apiToken.ID); err != nil {
+		// the last use is informational, the request is still allowed
+		log.WithError(err).WithField("apiTokenId", apiToken.ID).Warn("Failed to update the last use of an API token")
+	}
+
+	c.Set(CtxUserRoles, apiTokenRoles(apiToken.CourseIdentifier, apiToken.Roles))
+	c.Set(CtxUserID, apiToken.ID.String())
+	c.Set(CtxUserEmail, "")
+	c.Set(CtxMatriculationNumber, "")
+	c.Set(CtxUniversityLogin, "")
+	c.Set(CtxFirstName, apiToken.Name)
+	c.Set(CtxLastName, "")
+	c.Set(CtxAPITokenID, apiToken.ID.String())
+	return nil
+}
+
+// apiTokenRoles maps the course roles of a token to the role strings of Keycloak, e.g. ss25-iPraktikum-Lecturer
+func apiTokenRoles(courseIdentifier string, roles []string) map[string]bool {
+	userRoles := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		userRoles[courseIdentifier+"-"+role] = true
+	}
+	return userRoles
+}
+
+// IsAPITokenRequest reports whether the request was authenticated with an API token instead of a Keycloak user token
+func IsAPITokenRequest(c *gin.Context) bool {
+	return c.GetString(CtxAPITokenID) != ""
+}
@@ -8,5 +8,7 @@ const (
     CtxUniversityLogin      = "universityLogin"
     CtxFirstName            = "firstName"
     CtxLastName             = "lastName"
+    // CtxAPITokenID is only set for requests authenticated with an API token
+    CtxAPITokenID           = "apiTokenID"
 )
 
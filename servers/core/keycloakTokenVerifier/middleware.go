@@ -20,6 +20,8 @@ import (
 // 3. Check that the token audience matches the configured client ID.
 // 4. Confirm that the user has all of the required roles.
 //
+// Bearer tokens starting with APITokenPrefix are API tokens of PROMPT, which are looked up in the database instead.
+//
 // If any validation fails, the request is aborted with an appropriate HTTP status code.
 // On success, "resourceAccess" is attached to the context for further use.
 func KeycloakMiddleware() gin.HandlerFunc {
@@ -30,6 +32,16 @@ func KeycloakMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// scripts and service accounts use API tokens managed by PROMPT instead of a Keycloak user
+		if IsAPIToken(tokenString) {
+			if err := authenticateAPIToken(c, tokenString); err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			c.Next()
+			return
+		}
+
 		ctx := c.Request.Context()
 		idToken, err := verifier.Verify(ctx, tokenString)
 		if err != nil {
@@ -91,21 +103,21 @@ func KeycloakMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Retrieve all student roles from the DB
-		studentRoles, err := getStudentRoles(matriculationNumber, universityLogin)
+		// Retrieve the student roles and the locally stored course roles from the DB, they are cached for a short time
+		databaseRoles, err := getDatabaseRoles(ctx, userID, matriculationNumber, universityLogin)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "could not authenticate user"})
 			return
 		}
 
-		if len(studentRoles) == 0 && len(userRoles) == 0 {
+		if len(databaseRoles) == 0 && len(userRoles) == 0 {
 			log.Error("User has no roles")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "User has no roles"})
 			return
 		}
 
-		// store also the student roles in the userRoles map
-		for _, role := range studentRoles {
+		// store also the database roles in the userRoles map
+		for _, role := range databaseRoles {
 			userRoles[role] = true
 		}
 
@@ -0,0 +1,88 @@
+package keycloakTokenVerifier
+
+import (
+	"sync"
+	"time"
+
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// roleCacheMaxEntries bounds the memory of the cache, it is cleared when it grows beyond
+const roleCacheMaxEntries = 10000
+
+// roleCache keeps the roles read from the database for a short time, so not every request queries them.
+// Roles loaded before an invalidation are not stored, as they may already be outdated.
+type roleCache struct {
+	mu         sync.Mutex
+	entries    map[string]roleCacheEntry
+	generation uint64
+	now        func() time.Time
+}
+
+type roleCacheEntry struct {
+	roles     []string
+	expiresAt time.Time
+}
+
+var databaseRoleCache = newRoleCache()
+
+func newRoleCache() *roleCache {
+	return &roleCache{
+		entries: make(map[string]roleCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached roles, and the generation to store freshly loaded roles with
+func (c *roleCache) get(key string) ([]string, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[key]
+	if !found || !c.now().Before(entry.expiresAt) {
+		return nil, c.generation, false
+	}
+	return entry.roles, c.generation, true
+}
+
+func (c *roleCache) set(key string, roles []string, generation uint64, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if len(c.entries) >= roleCacheMaxEntries {
+		c.entries = make(map[string]roleCacheEntry)
+	}
+	c.entries[key] = roleCacheEntry{roles: roles, expiresAt: c.now().Add(ttl)}
+}
+
+func (c *roleCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]roleCacheEntry)
+	c.generation++
+}
+
+// InvalidateRoleCache drops all cached roles.
+// It has to be called whenever course participations or course staff assignments change.
+func InvalidateRoleCache() {
+	databaseRoleCache.invalidate()
+}
+
+// roleCacheTTL is the time the roles of a user are cached, ROLE_CACHE_TTL=0 disables the cache
+func roleCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(sdkUtils.GetEnv("ROLE_CACHE_TTL", "30s"))
+	if err != nil || ttl < 0 {
+		log.Warn("Invalid ROLE_CACHE_TTL, using 30 seconds")
+		return 30 * time.Second
+	}
+	return ttl
+}
@@ -0,0 +1,89 @@
+package keycloakTokenVerifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRoleCache(now *time.Time) *roleCache {
+	cache := newRoleCache()
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+func TestRoleCacheExpiresEntries(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestRoleCache(&now)
+
+	_, generation, found := cache.get("user")
+	assert.False(t, found)
+	cache.set("user", []string{"ss25-iPraktikum-Student"}, generation, 30*time.Second)
+
+	roles, _, found := cache.get("user")
+	assert.True(t, found)
+	assert.Equal(t, []string{"ss25-iPraktikum-Student"}, roles)
+
+	now = now.Add(30 * time.Second)
+	_, _, found = cache.get("user")
+	assert.False(t, found)
+}
+
+func TestRoleCacheInvalidate(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestRoleCache(&now)
+
+	_, generation, _ := cache.get("user")
+	cache.set("user", []string{"ss25-iPraktikum-Student"}, generation, time.Minute)
+	cache.invalidate()
+
+	_, _, found := cache.get("user")
+	assert.False(t, found)
+
+	// roles loaded before the invalidation may be outdated and are not stored
+	cache.set("user", []string{"ss25-iPraktikum-Student"}, generation, time.Minute)
+	_, newGeneration, found := cache.get("user")
+	assert.False(t, found)
+
+	cache.set("user", []string{}, newGeneration, time.Minute)
+	roles, _, found := cache.get("user")
+	assert.True(t, found)
+	assert.Empty(t, roles)
+}
+
+func TestRoleCacheDisabled(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestRoleCache(&now)
+
+	_, generation, _ := cache.get("user")
+	cache.set("user", []string{"ss25-iPraktikum-Student"}, generation, 0)
+
+	_, _, found := cache.get("user")
+	assert.False(t, found)
+}
+
+func TestRoleCacheTTL(t *testing.T) {
+	t.Setenv("ROLE_CACHE_TTL", "")
+	assert.Equal(t, 30*time.Second, roleCacheTTL())
+
+	t.Setenv("ROLE_CACHE_TTL", "0")
+	assert.Equal(t, time.Duration(0), roleCacheTTL())
+
+	t.Setenv("ROLE_CACHE_TTL", "soon")
+	assert.Equal(t, 30*time.Second, roleCacheTTL())
+}
+
+func TestKeycloakRoleSyncEnabled(t *testing.T) {
+	t.Setenv("COURSE_ROLE_STORE", "")
+	t.Setenv("KEYCLOAK_ROLE_SYNC", "false")
+	assert.False(t, LocalCourseRolesEnabled())
+	assert.True(t, KeycloakRoleSyncEnabled(), "without local roles Keycloak is the only store")
+
+	t.Setenv("COURSE_ROLE_STORE", CourseRoleStoreDatabase)
+	assert.True(t, LocalCourseRolesEnabled())
+	assert.False(t, KeycloakRoleSyncEnabled())
+
+	t.Setenv("KEYCLOAK_ROLE_SYNC", "")
+	assert.True(t, KeycloakRoleSyncEnabled())
+}
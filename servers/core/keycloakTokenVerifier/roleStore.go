@@ -0,0 +1,97 @@
+package keycloakTokenVerifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	log "github.com/sirupsen/logrus"
+)
+
+// COURSE_ROLE_STORE selects where the lecturer and editor assignments of courses are kept.
+// With "keycloak" they are groups and client roles of Keycloak, which are read from the token.
+// With "database" they are stored in the Core Server, so permission checks do not need the Keycloak admin API.
+const (
+	CourseRoleStoreKeycloak = "keycloak"
+	CourseRoleStoreDatabase = "database"
+)
+
+func LocalCourseRolesEnabled() bool {
+	return sdkUtils.GetEnv("COURSE_ROLE_STORE", CourseRoleStoreKeycloak) == CourseRoleStoreDatabase
+}
+
+// KeycloakRoleSyncEnabled reports whether the course groups, roles and memberships are also created in Keycloak.
+// Phase servers read the course roles from the token, so they need the sync.
+// If the roles are stored in the database, KEYCLOAK_ROLE_SYNC=false turns the sync off.
+func KeycloakRoleSyncEnabled() bool {
+	return !LocalCourseRolesEnabled() || sdkUtils.GetEnv("KEYCLOAK_ROLE_SYNC", "true") == "true"
+}
+
+// AddCourseLecturer stores the creator of a course as its lecturer if the course roles are stored in the database.
+// It runs in the transaction that creates the course.
+func AddCourseLecturer(ctx context.Context, qtx *db.Queries, courseID uuid.UUID, userID string) error {
+	if !LocalCourseRolesEnabled() {
+		return nil
+	}
+	if userID == "" {
+		return errors.New("the lecturer of the course has no user ID")
+	}
+
+	err := qtx.AddCourseStaffUser(ctx, db.AddCourseStaffUserParams{
+		CourseID: courseID,
+		Role:     permissionValidation.CourseLecturer,
+		UserID:   pgtype.Text{String: userID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store the course lecturer: %w", err)
+	}
+	return nil
+}
+
+// getDatabaseRoles returns the course roles of a user that are stored in the database:
+// the student roles of its course participations and, if enabled, its lecturer and editor assignments.
+func getDatabaseRoles(ctx context.Context, userID, matriculationNumber, universityLogin string) ([]string, error) {
+	cacheKey := strings.Join([]string{userID, matriculationNumber, universityLogin}, "\x00")
+	roles, generation, found := databaseRoleCache.get(cacheKey)
+	if found {
+		return roles, nil
+	}
+
+	roles, err := getStudentRoles(matriculationNumber, universityLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	if LocalCourseRolesEnabled() {
+		staffRoles, err := getCourseStaffRoles(ctx, userID, matriculationNumber, universityLogin)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, staffRoles...)
+	}
+
+	databaseRoleCache.set(cacheKey, roles, generation, roleCacheTTL())
+	return roles, nil
+}
+
+func getCourseStaffRoles(ctx context.Context, userID, matriculationNumber, universityLogin string) ([]string, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	staffRoles, err := KeycloakTokenVerifierSingleton.queries.GetCourseStaffRoleStrings(ctxWithTimeout, db.GetCourseStaffRoleStringsParams{
+		UserID:              userID,
+		MatriculationNumber: matriculationNumber,
+		UniversityLogin:     universityLogin,
+	})
+	if err != nil {
+		log.Error("Failed to retrieve course staff roles: ", err)
+		return nil, errors.New("could not retrieve course staff roles")
+	}
+	return staffRoles, nil
+}
@@ -0,0 +1,39 @@
+package listQuery
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points behind the last item of a page, it is only valid for the sorting it was created with.
+// The ID breaks ties between items with the same sort key.
+type Cursor struct {
+	SortBy     string    `json:"sortBy"`
+	Descending bool      `json:"descending"`
+	SortKey    string    `json:"sortKey"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	// marshalling the cursor cannot fail, it only consists of strings, a bool and a UUID
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == uuid.Nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,74 @@
+package listQuery
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Includes reports whether the field, or one of its nested fields, is part of the response.
+// Services use it to skip loading data the client did not ask for.
+func (p Params) Includes(field string) bool {
+	if len(p.Fields) == 0 {
+		return true
+	}
+	for _, requested := range p.Fields {
+		if requested == field || strings.HasPrefix(requested, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Project reduces the JSON representation of the items to the requested fields, unknown fields are left out.
+// Without requested fields the items are returned unchanged.
+func Project[T any](items []T, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keeps large numbers exact
+	decoder.UseNumber()
+	if err := decoder.Decode(&objects); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]any, 0, len(objects))
+	for _, object := range objects {
+		projectedObject := make(map[string]any)
+		for _, field := range fields {
+			copyField(object, projectedObject, strings.Split(field, "."))
+		}
+		projected = append(projected, projectedObject)
+	}
+	return projected, nil
+}
+
+func copyField(from, to map[string]any, path []string) {
+	value, found := from[path[0]]
+	if !found {
+		return
+	}
+	if len(path) == 1 {
+		to[path[0]] = value
+		return
+	}
+
+	nestedFrom, isObject := value.(map[string]any)
+	if !isObject {
+		return
+	}
+	nestedTo, isObject := to[path[0]].(map[string]any)
+	if !isObject {
+		nestedTo = make(map[string]any)
+		to[path[0]] = nestedTo
+	}
+	copyField(nestedFrom, nestedTo, path[1:])
+}
@@ -0,0 +1,82 @@
+package listQuery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStudent struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+type testParticipation struct {
+	ID             string         `json:"id"`
+	Student        testStudent    `json:"student"`
+	RestrictedData map[string]any `json:"restrictedData"`
+}
+
+func TestIncludes(t *testing.T) {
+	assert.True(t, Params{}.Includes("coursePhases"), "Expected all fields without projection")
+
+	params := Params{Fields: []string{"id", "coursePhases.name"}}
+	assert.True(t, params.Includes("id"))
+	assert.True(t, params.Includes("coursePhases"))
+	assert.False(t, params.Includes("name"))
+	assert.False(t, params.Includes("course"), "Expected only whole path segments to match")
+}
+
+func TestProject(t *testing.T) {
+	participations := []testParticipation{{
+		ID:             "1",
+		Student:        testStudent{FirstName: "Niclas", LastName: "Heun"},
+		RestrictedData: map[string]any{"score": 12345678901234, "team": "Blue"},
+	}}
+
+	projected, err := Project(participations, nil)
+	require.NoError(t, err)
+	assert.Equal(t, participations, projected, "Expected the items unchanged without fields")
+
+	projected, err = Project(participations, []string{"id", "student.lastName", "restrictedData.score", "unknown", "id.nested"})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{{
+		"id":             "1",
+		"student":        map[string]any{"lastName": "Heun"},
+		"restrictedData": map[string]any{"score": "12345678901234"},
+	}}, toComparable(projected))
+}
+
+// toComparable turns JSON numbers into their text, so that the expected values can be written as literals
+func toComparable(value any) any {
+	switch typed := value.(type) {
+	case []map[string]any:
+		result := make([]map[string]any, 0, len(typed))
+		for _, item := range typed {
+			result = append(result, toComparable(item).(map[string]any))
+		}
+		return result
+	case map[string]any:
+		result := make(map[string]any, len(typed))
+		for key, item := range typed {
+			result[key] = toComparable(item)
+		}
+		return result
+	case interface{ String() string }:
+		return typed.String()
+	default:
+		return value
+	}
+}
+
+func TestMatchesMetadata(t *testing.T) {
+	restrictedData := map[string]any{"team": "Blue", "score": 12, "accepted": true, "comment": nil}
+	studentReadableData := map[string]any{"team": "Red", "comment": "late", "room": "01.07"}
+
+	assert.True(t, MatchesMetadata(nil, restrictedData))
+	assert.True(t, MatchesMetadata(map[string]string{"team": "Blue", "score": "12", "accepted": "true"}, restrictedData, studentReadableData))
+	assert.False(t, MatchesMetadata(map[string]string{"team": "Red"}, restrictedData, studentReadableData), "Expected the first metadata with the key to decide")
+	assert.True(t, MatchesMetadata(map[string]string{"room": "01.07", "comment": "late"}, restrictedData, studentReadableData), "Expected missing and null values to fall through")
+	assert.False(t, MatchesMetadata(map[string]string{"missing": ""}, restrictedData, studentReadableData))
+}
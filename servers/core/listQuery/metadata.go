@@ -0,0 +1,30 @@
+package listQuery
+
+import "encoding/json"
+
+// MatchesMetadata applies the metadata filters to items that were loaded completely.
+// Like the ->> operator in the queries, strings are compared as they are and other values by their JSON text.
+// A key is looked up in the metadata in the given order, the first metadata with a value for it decides.
+func MatchesMetadata(filters map[string]string, metadata ...map[string]any) bool {
+	for key, expected := range filters {
+		if !matchesMetadataKey(key, expected, metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesMetadataKey(key, expected string, metadata []map[string]any) bool {
+	for _, data := range metadata {
+		value := data[key]
+		if value == nil {
+			continue
+		}
+		if text, isString := value.(string); isString {
+			return text == expected
+		}
+		text, err := json.Marshal(value)
+		return err == nil && string(text) == expected
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package listQuery
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const TotalCountHeader = "X-Total-Count"
+
+// TrimPage cuts the extra item requested with PageSize off and returns the cursor of the next page,
+// which is empty on the last page
+func TrimPage[T any](params Params, items []T, cursorOf func(T) (string, uuid.UUID)) ([]T, string) {
+	if params.Limit == 0 || len(items) <= int(params.Limit) {
+		return items, ""
+	}
+
+	items = items[:params.Limit]
+	sortKey, id := cursorOf(items[len(items)-1])
+	return items, params.NextCursor(sortKey, id)
+}
+
+// Paginate sorts and pages items that were loaded completely, for lists that are small enough to not page them in the database.
+// Without sorting and limit the items keep their order. It returns the page, the total number of items and the next cursor.
+func Paginate[T any](params Params, items []T, sortKeyOf func(T) (string, uuid.UUID)) ([]T, int64, string) {
+	total := int64(len(items))
+	if params.SortBy == "" && params.Limit == 0 {
+		return items, total, ""
+	}
+
+	type keyedItem struct {
+		sortKey string
+		id      uuid.UUID
+		item    T
+	}
+	keyedItems := make([]keyedItem, 0, len(items))
+	for _, item := range items {
+		sortKey, id := sortKeyOf(item)
+		keyedItems = append(keyedItems, keyedItem{sortKey: sortKey, id: id, item: item})
+	}
+
+	compare := func(sortKey string, id uuid.UUID, other keyedItem) int {
+		if result := strings.Compare(sortKey, other.sortKey); result != 0 {
+			return result
+		}
+		return bytes.Compare(id[:], other.id[:])
+	}
+	slices.SortStableFunc(keyedItems, func(a, b keyedItem) int {
+		if params.Descending {
+			return compare(b.sortKey, b.id, a)
+		}
+		return compare(a.sortKey, a.id, b)
+	})
+
+	page := make([]T, 0)
+	for _, keyedItem := range keyedItems {
+		if params.Cursor != nil {
+			// skip the items up to and including the cursor
+			result := compare(params.Cursor.SortKey, params.Cursor.ID, keyedItem)
+			if (!params.Descending && result >= 0) || (params.Descending && result <= 0) {
+				continue
+			}
+		}
+		page = append(page, keyedItem.item)
+		if params.Limit > 0 && len(page) > int(params.Limit) {
+			break
+		}
+	}
+
+	page, nextCursor := TrimPage(params, page, sortKeyOf)
+	return page, total, nextCursor
+}
+
+// SetHeaders sends the total number of matching items and, if there is a next page, its link
+func SetHeaders(c *gin.Context, total int64, nextCursor string) {
+	// the client runs on another origin and can only read exposed headers
+	c.Header("Access-Control-Expose-Headers", TotalCountHeader+", Link")
+	c.Header(TotalCountHeader, strconv.FormatInt(total, 10))
+	if nextCursor == "" {
+		return
+	}
+
+	query := c.Request.URL.Query()
+	query.Set("cursor", nextCursor)
+	next := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	c.Header("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
@@ -0,0 +1,118 @@
+package listQuery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func testItemKey(item testItem) (string, uuid.UUID) {
+	return item.Name, item.ID
+}
+
+func newTestItems(names ...string) []testItem {
+	items := make([]testItem, 0, len(names))
+	for i, name := range names {
+		items = append(items, testItem{ID: uuid.UUID{byte(i + 1)}, Name: name})
+	}
+	return items
+}
+
+func itemNames(items []testItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestTrimPage(t *testing.T) {
+	items := newTestItems("a", "b", "c")
+
+	page, nextCursor := TrimPage(Params{}, items, testItemKey)
+	assert.Equal(t, items, page)
+	assert.Empty(t, nextCursor)
+
+	page, nextCursor = TrimPage(Params{Limit: 3}, items, testItemKey)
+	assert.Len(t, page, 3)
+	assert.Empty(t, nextCursor, "Expected no next page without the extra item")
+
+	page, nextCursor = TrimPage(Params{Limit: 2, SortBy: "name"}, items, testItemKey)
+	assert.Equal(t, []string{"a", "b"}, itemNames(page))
+	cursor, err := DecodeCursor(nextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{SortBy: "name", SortKey: "b", ID: items[1].ID}, cursor)
+}
+
+func TestPaginateKeepsOrderWithoutSortingAndLimit(t *testing.T) {
+	items := newTestItems("c", "a", "b")
+
+	page, total, nextCursor := Paginate(Params{}, items, testItemKey)
+	assert.Equal(t, []string{"c", "a", "b"}, itemNames(page))
+	assert.Equal(t, int64(3), total)
+	assert.Empty(t, nextCursor)
+}
+
+func TestPaginatePages(t *testing.T) {
+	items := newTestItems("c", "a", "b", "a", "d")
+
+	for _, descending := range []bool{false, true} {
+		params := Params{Limit: 2, SortBy: "name", Descending: descending}
+		names := make([]string, 0)
+		pages := 0
+		for {
+			page, total, nextCursor := Paginate(params, items, testItemKey)
+			assert.Equal(t, int64(5), total)
+			names = append(names, itemNames(page)...)
+			pages++
+			if nextCursor == "" {
+				break
+			}
+			cursor, err := DecodeCursor(nextCursor)
+			require.NoError(t, err)
+			params.Cursor = &cursor
+		}
+
+		assert.Equal(t, 3, pages)
+		if descending {
+			assert.Equal(t, []string{"d", "c", "b", "a", "a"}, names)
+		} else {
+			assert.Equal(t, []string{"a", "a", "b", "c", "d"}, names, "Expected items with the same sort key to be paged by ID")
+		}
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/students/?limit=2&sortBy=email&cursor=old", nil)
+
+	SetHeaders(c, 42, "next")
+	assert.Equal(t, "42", recorder.Header().Get(TotalCountHeader))
+
+	link := recorder.Header().Get("Link")
+	require.Regexp(t, `^<.+>; rel="next"$`, link)
+	next, err := url.Parse(link[1 : len(link)-len(`>; rel="next"`)])
+	require.NoError(t, err)
+	assert.Equal(t, "/api/students/", next.Path)
+	assert.Equal(t, url.Values{"limit": {"2"}, "sortBy": {"email"}, "cursor": {"next"}}, next.Query())
+
+	recorder = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/students/", nil)
+
+	SetHeaders(c, 3, "")
+	assert.Equal(t, "3", recorder.Header().Get(TotalCountHeader))
+	assert.Empty(t, recorder.Header().Get("Link"), "Expected no link on the last page")
+}
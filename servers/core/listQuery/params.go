@@ -0,0 +1,180 @@
+package listQuery
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+const (
+	MaxLimit = 500
+
+	metadataFilterPrefix = "metadata."
+)
+
+var filterablePassStatuses = []db.PassStatus{db.PassStatusPassed, db.PassStatusFailed, db.PassStatusNotAssessed}
+
+// Params is the query contract shared by the list endpoints:
+//
+//	limit, cursor          pages of at most limit items, the cursor of the next page is sent in the Link header
+//	sortBy, sortOrder      a sort field of the endpoint and asc or desc
+//	passStatus             only items with this pass status
+//	metadata.<key>=<value> only items whose metadata has the value at the top-level key
+//	fields                 comma-separated JSON fields of the items to return, nested fields are separated by dots
+type Params struct {
+	// 0 returns all items
+	Limit  int32
+	Cursor *Cursor
+	// empty if the client did not ask for a sorting, the endpoint then uses its default order
+	SortBy     string
+	Descending bool
+	PassStatus db.PassStatus
+	Metadata   map[string]string
+	Fields     []string
+}
+
+// Options describe which parts of the contract an endpoint supports
+type Options struct {
+	SortFields []string
+	// applies if the client does not send a limit, 0 returns all items
+	DefaultLimit     int32
+	PassStatusFilter bool
+	MetadataFilter   bool
+}
+
+// Parse reads the list parameters from the query and rejects the parameters the endpoint does not support
+func Parse(c *gin.Context, options Options) (Params, error) {
+	params := Params{
+		Limit:  options.DefaultLimit,
+		SortBy: c.Query("sortBy"),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		parsedLimit, err := strconv.Atoi(limit)
+		if err != nil || parsedLimit < 1 || parsedLimit > MaxLimit {
+			return Params{}, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		params.Limit = int32(parsedLimit)
+	}
+
+	if params.SortBy != "" && !slices.Contains(options.SortFields, params.SortBy) {
+		return Params{}, fmt.Errorf("sortBy must be one of %s", strings.Join(options.SortFields, ", "))
+	}
+
+	switch c.DefaultQuery("sortOrder", "asc") {
+	case "asc":
+	case "desc":
+		params.Descending = true
+	default:
+		return Params{}, errors.New("sortOrder must be asc or desc")
+	}
+
+	if encodedCursor := c.Query("cursor"); encodedCursor != "" {
+		if params.Limit == 0 {
+			return Params{}, errors.New("cursor requires a limit")
+		}
+		cursor, err := DecodeCursor(encodedCursor)
+		if err != nil {
+			return Params{}, err
+		}
+		if cursor.SortBy != params.SortBy || cursor.Descending != params.Descending {
+			return Params{}, ErrInvalidCursor
+		}
+		params.Cursor = &cursor
+	}
+
+	if passStatus := c.Query("passStatus"); passStatus != "" {
+		if !options.PassStatusFilter {
+			return Params{}, errors.New("passStatus is not supported")
+		}
+		params.PassStatus = db.PassStatus(passStatus)
+		if !slices.Contains(filterablePassStatuses, params.PassStatus) {
+			return Params{}, errors.New("passStatus is invalid")
+		}
+	}
+
+	for key, values := range c.Request.URL.Query() {
+		metadataKey, isMetadataFilter := strings.CutPrefix(key, metadataFilterPrefix)
+		if !isMetadataFilter {
+			continue
+		}
+		if !options.MetadataFilter {
+			return Params{}, errors.New("metadata filters are not supported")
+		}
+		if metadataKey == "" {
+			return Params{}, errors.New("metadata filters need a key")
+		}
+		if params.Metadata == nil {
+			params.Metadata = make(map[string]string)
+		}
+		params.Metadata[metadataKey] = values[len(values)-1]
+	}
+
+	if fields := c.Query("fields"); fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				params.Fields = append(params.Fields, field)
+			}
+		}
+	}
+	return params, nil
+}
+
+// SortByOrDefault returns the requested sort field or the default sort field of the endpoint
+func (p Params) SortByOrDefault(defaultSortBy string) string {
+	if p.SortBy == "" {
+		return defaultSortBy
+	}
+	return p.SortBy
+}
+
+// PageSize asks the database for one more item than the limit, which shows whether there is a next page
+func (p Params) PageSize() pgtype.Int4 {
+	return pgtype.Int4{Int32: p.Limit + 1, Valid: p.Limit > 0}
+}
+
+func (p Params) CursorKey() pgtype.Text {
+	if p.Cursor == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: p.Cursor.SortKey, Valid: true}
+}
+
+func (p Params) CursorID() pgtype.UUID {
+	if p.Cursor == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: p.Cursor.ID, Valid: true}
+}
+
+func (p Params) PassStatusFilter() db.NullPassStatus {
+	return db.NullPassStatus{PassStatus: p.PassStatus, Valid: p.PassStatus != ""}
+}
+
+// MetadataFilter returns the metadata filters as JSON object for the queries, or nil without filters
+func (p Params) MetadataFilter() []byte {
+	if len(p.Metadata) == 0 {
+		return nil
+	}
+	// a map of strings can always be marshalled
+	data, _ := json.Marshal(p.Metadata)
+	return data
+}
+
+// NextCursor returns the cursor that continues after the given item
+func (p Params) NextCursor(sortKey string, id uuid.UUID) string {
+	return EncodeCursor(Cursor{
+		SortBy:     p.SortBy,
+		Descending: p.Descending,
+		SortKey:    sortKey,
+		ID:         id,
+	})
+}
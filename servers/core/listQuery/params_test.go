@@ -0,0 +1,110 @@
+package listQuery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptions = Options{
+	SortFields:       []string{"lastName", "email"},
+	PassStatusFilter: true,
+	MetadataFilter:   true,
+}
+
+func newContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/participations?"+query, nil)
+	return c
+}
+
+func TestParse(t *testing.T) {
+	params, err := Parse(newContext("limit=20&sortBy=email&sortOrder=desc&passStatus=passed&metadata.team=Blue&fields=id,%20student.lastName,"), testOptions)
+	require.NoError(t, err)
+	assert.Equal(t, Params{
+		Limit:      20,
+		SortBy:     "email",
+		Descending: true,
+		PassStatus: db.PassStatusPassed,
+		Metadata:   map[string]string{"team": "Blue"},
+		Fields:     []string{"id", "student.lastName"},
+	}, params)
+
+	params, err = Parse(newContext(""), testOptions)
+	require.NoError(t, err)
+	assert.Equal(t, Params{}, params, "Expected all items in the default order")
+	assert.Equal(t, "lastName", params.SortByOrDefault("lastName"))
+
+	params, err = Parse(newContext(""), Options{DefaultLimit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), params.Limit)
+}
+
+func TestParseRejectsInvalidParameters(t *testing.T) {
+	otherSortingCursor := EncodeCursor(Cursor{SortBy: "email", SortKey: "a@tum.de", ID: uuid.New()})
+
+	for _, query := range []string{
+		"limit=0",
+		"limit=501",
+		"limit=ten",
+		"sortBy=gender",
+		"sortOrder=up",
+		"passStatus=accepted",
+		"metadata.=x",
+		"cursor=abc",
+		"cursor=" + otherSortingCursor,
+		"limit=10&cursor=" + otherSortingCursor,
+	} {
+		_, err := Parse(newContext(query), testOptions)
+		assert.Error(t, err, query)
+	}
+
+	_, err := Parse(newContext("passStatus=passed"), Options{})
+	assert.Error(t, err, "Expected the pass status filter to be rejected if the endpoint does not support it")
+	_, err = Parse(newContext("metadata.team=Blue"), Options{})
+	assert.Error(t, err, "Expected metadata filters to be rejected if the endpoint does not support them")
+}
+
+func TestParseCursor(t *testing.T) {
+	cursor := Cursor{SortBy: "email", Descending: true, SortKey: "a@tum.de", ID: uuid.New()}
+
+	params, err := Parse(newContext("limit=10&sortBy=email&sortOrder=desc&cursor="+EncodeCursor(cursor)), testOptions)
+	require.NoError(t, err)
+	require.NotNil(t, params.Cursor)
+	assert.Equal(t, cursor, *params.Cursor)
+	assert.Equal(t, pgtype.Text{String: "a@tum.de", Valid: true}, params.CursorKey())
+	assert.Equal(t, pgtype.UUID{Bytes: cursor.ID, Valid: true}, params.CursorID())
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{SortBy: "lastName", SortKey: "heun niclas", ID: uuid.New()}
+
+	decoded, err := DecodeCursor(EncodeCursor(cursor))
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	_, err = DecodeCursor("not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = DecodeCursor(EncodeCursor(Cursor{SortBy: "lastName"}))
+	assert.ErrorIs(t, err, ErrInvalidCursor, "Expected a cursor without ID to be rejected")
+}
+
+func TestQueryParameters(t *testing.T) {
+	params := Params{}
+	assert.False(t, params.PageSize().Valid, "Expected no page size without limit")
+	assert.False(t, params.CursorID().Valid)
+	assert.False(t, params.PassStatusFilter().Valid)
+	assert.Nil(t, params.MetadataFilter())
+
+	params = Params{Limit: 10, PassStatus: db.PassStatusFailed, Metadata: map[string]string{"team": "Blue"}}
+	assert.Equal(t, pgtype.Int4{Int32: 11, Valid: true}, params.PageSize(), "Expected one more item than the limit")
+	assert.Equal(t, db.NullPassStatus{PassStatus: db.PassStatusFailed, Valid: true}, params.PassStatusFilter())
+	assert.JSONEq(t, `{"team": "Blue"}`, string(params.MetadataFilter()))
+}
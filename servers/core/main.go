@@ -14,10 +14,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	"github.com/prompt-edu/prompt/servers/core/analytics"
 	"github.com/prompt-edu/prompt/servers/core/applicationAdministration"
 	"github.com/prompt-edu/prompt/servers/core/course"
+	"github.com/prompt-edu/prompt/servers/core/course/apiToken"
 	"github.com/prompt-edu/prompt/servers/core/course/copy"
 	"github.com/prompt-edu/prompt/servers/core/course/courseParticipation"
+	"github.com/prompt-edu/prompt/servers/core/course/courseRole"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/coursePhaseParticipation"
 	"github.com/prompt-edu/prompt/servers/core/coursePhase/resolution"
@@ -31,6 +34,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	"github.com/prompt-edu/prompt/servers/core/storage"
 	"github.com/prompt-edu/prompt/servers/core/student"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDuplicate"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -155,6 +159,17 @@ func initSentry() {
 	log.Info("Sentry initialized successfully")
 }
 
+func runCommand(command string, query db.Queries) {
+	switch command {
+	case "rotate-file-encryption-keys":
+		if err := storage.RotateEncryptionKeysFromEnv(context.Background(), query); err != nil {
+			log.Fatalf("Failed to rotate file encryption keys: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown command %s, available commands: rotate-file-encryption-keys", command)
+	}
+}
+
 // @title           PROMPT Core API
 // @version         1.0
 // @description     This is a core sever of PROMPT.
@@ -191,6 +206,12 @@ func main() {
 
 	query := db.New(conn)
 
+	// maintenance commands run instead of the server, e.g. `main rotate-file-encryption-keys`
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], *query)
+		return
+	}
+
 	router := gin.Default()
 	router.Use(sentrygin.New(sentrygin.Options{}))
 	localHost := "http://localhost:3000"
@@ -218,19 +239,52 @@ func main() {
 	coursePhaseAuth.InitCoursePhaseAuthModule(api, *query, conn)
 	initMailing(api, *query, conn)
 	student.InitStudentModule(api, *query, conn)
+	studentDuplicate.InitStudentDuplicateModule(api, *query, conn)
 	course.InitCourseModule(api, *query, conn)
 	copy.InitCourseCopyModule(api, *query, conn)
+	apiToken.InitAPITokenModule(api, *query, conn)
+	courseRole.InitCourseRoleModule(api, *query, conn)
 	coursePhase.InitCoursePhaseModule(api, *query, conn)
 	courseParticipation.InitCourseParticipationModule(api, *query, conn)
 	coursePhaseParticipation.InitCoursePhaseParticipationModule(api, *query, conn)
 	applicationAdministration.InitApplicationAdministrationModule(api, *query, conn)
 	instructorNote.InitInstructorNoteModule(api, *query, conn)
+	analytics.InitAnalyticsModule(api, *query, conn)
 
-	// Initialize storage module (service only)
-	if err := storage.InitStorageModule(*query, conn); err != nil {
+	// Initialize storage module
+	if err := storage.InitStorageModule(api, *query, conn); err != nil {
 		log.Fatalf("Failed to initialize storage module: %v", err)
 	}
 
+	duplicateDetectionInterval, err := time.ParseDuration(sdkUtils.GetEnv("STUDENT_DUPLICATE_DETECTION_INTERVAL", "24h"))
+	if err != nil || duplicateDetectionInterval <= 0 {
+		log.Warn("Duplicate student detection job is disabled")
+	} else {
+		studentDuplicate.StartDuplicateDetectionJob(context.Background(), duplicateDetectionInterval)
+	}
+
+	studentUpdateNotificationInterval, err := time.ParseDuration(sdkUtils.GetEnv("STUDENT_UPDATE_NOTIFICATION_INTERVAL", "1m"))
+	studentUpdateWebhookSecret := sdkUtils.GetEnv("STUDENT_UPDATE_WEBHOOK_SECRET", "")
+	if err != nil || studentUpdateNotificationInterval <= 0 || studentUpdateWebhookSecret == "" {
+		log.Warn("Notifying the phase servers of student updates is disabled")
+	} else {
+		student.StartStudentUpdateNotificationJob(context.Background(), studentUpdateNotificationInterval, studentUpdateWebhookSecret)
+	}
+
+	fileRescanInterval, err := time.ParseDuration(sdkUtils.GetEnv("FILE_RESCAN_INTERVAL", "15m"))
+	if err != nil || fileRescanInterval <= 0 {
+		log.Warn("Rescanning of quarantined files is disabled")
+	} else {
+		storage.StartFileRescanJob(context.Background(), fileRescanInterval)
+	}
+
+	fileGarbageCollectionInterval, err := time.ParseDuration(sdkUtils.GetEnv("FILE_GC_INTERVAL", "24h"))
+	if err != nil || fileGarbageCollectionInterval <= 0 {
+		log.Warn("File garbage collection job is disabled")
+	} else {
+		storage.StartGarbageCollectionJob(context.Background(), fileGarbageCollectionInterval)
+	}
+
 	serverAddress := sdkUtils.GetEnv("SERVER_ADDRESS", "localhost:8080")
 	log.Info("Core Server started")
 	err = router.Run(serverAddress)
@@ -1,6 +1,7 @@
 package permissionValidation
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -8,26 +9,65 @@ import (
 )
 
 func checkUserRole(c *gin.Context, courseIdentifier string, allowedUsers ...string) (bool, error) {
+	return checkUserAccess(c, courseIdentifier, func(userRoles map[string]bool) (bool, error) {
+		return hasAllowedRole(c, userRoles, courseIdentifier, allowedUsers...)
+	})
+}
+
+// checkUserAccess responds with 403 if hasAccess denies the roles of the user, and with 500 if the check fails
+func checkUserAccess(c *gin.Context, courseIdentifier string, hasAccess func(userRoles map[string]bool) (bool, error)) (bool, error) {
 	// Inject the course identifier for later use
 	c.Set("courseTokenIdentifier", courseIdentifier)
 
-	// Extract user roles from context
-	rolesVal, exists := c.Get("userRoles")
-	if !exists {
-		err := errors.New("user roles not found in context")
+	userRoles, err := userRolesFromContext(c)
+	if err != nil {
 		c.IndentedJSON(500, gin.H{"error": err.Error()})
 		return false, err
 	}
 
-	userRoles, ok := rolesVal.(map[string]bool)
-	if !ok {
-		err := errors.New("invalid roles format in context")
+	hasRole, err := hasAccess(userRoles)
+	if err != nil {
 		c.IndentedJSON(500, gin.H{"error": err.Error()})
 		return false, err
 	}
+	if hasRole {
+		return true, nil // Found at least one matching role
+	}
 
+	c.IndentedJSON(403, gin.H{"error": "no matching permission found"})
+
+	return false, nil // No matching role found
+}
+
+// Extract user roles from context
+func userRolesFromContext(c *gin.Context) (map[string]bool, error) {
+	rolesVal, exists := c.Get("userRoles")
+	if !exists {
+		return nil, errors.New("user roles not found in context")
+	}
+
+	userRoles, ok := rolesVal.(map[string]bool)
+	if !ok {
+		return nil, errors.New("invalid roles format in context")
+	}
+	return userRoles, nil
+}
+
+// hasAllowedRole checks the allowed roles and permissions of a course against the roles of the user
+func hasAllowedRole(ctx context.Context, userRoles map[string]bool, courseIdentifier string, allowedUsers ...string) (bool, error) {
 	// Generate the desired role keys based on input
 	for _, role := range allowedUsers {
+		if IsCoursePermission(role) {
+			hasPermission, err := hasCoursePermission(ctx, userRoles, courseIdentifier, role)
+			if err != nil {
+				return false, fmt.Errorf("failed to check course role permissions: %w", err)
+			}
+			if hasPermission {
+				return true, nil
+			}
+			continue
+		}
+
 		var desiredRole string
 		switch role {
 		case PromptAdmin:
@@ -39,11 +79,8 @@ func checkUserRole(c *gin.Context, courseIdentifier string, allowedUsers ...stri
 		}
 
 		if userRoles[desiredRole] {
-			return true, nil // Found at least one matching role
+			return true, nil
 		}
 	}
-
-	c.IndentedJSON(403, gin.H{"error": "no matching permission found"})
-
-	return false, nil // No matching role found
+	return false, nil
 }
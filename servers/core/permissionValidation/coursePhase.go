@@ -15,7 +15,26 @@ func CheckCoursePhasePermission(c *gin.Context, coursePhaseID uuid.UUID, allowed
 		return false, err
 	}
 
-	return checkUserRole(c, courseIdentifier, allowedUsers...)
+	// roles of the whole course apply to all of its phases, custom course roles can additionally be granted single phases
+	return checkUserAccess(c, courseIdentifier, func(userRoles map[string]bool) (bool, error) {
+		return HasCoursePhaseRole(c, userRoles, courseIdentifier, coursePhaseID, allowedUsers...)
+	})
+}
+
+// HasCoursePhaseRole checks whether the user has one of the allowed roles or permissions in the course phase,
+// through the roles of the course or a custom course role that is granted them in the phase.
+// Handlers use it to distinguish the roles the course phase permission middleware accepted.
+func HasCoursePhaseRole(ctx context.Context, userRoles map[string]bool, courseIdentifier string, coursePhaseID uuid.UUID, allowedUsers ...string) (bool, error) {
+	hasRole, err := hasAllowedRole(ctx, userRoles, courseIdentifier, allowedUsers...)
+	if err != nil || hasRole {
+		return hasRole, err
+	}
+
+	hasGrant, err := hasCoursePhaseGrant(ctx, userRoles, courseIdentifier, coursePhaseID, allowedUsers...)
+	if err != nil {
+		return false, fmt.Errorf("failed to check course phase grants: %w", err)
+	}
+	return hasGrant, nil
 }
 
 func courseIdentifierStringFromCoursePhaseID(ctx context.Context, uuid uuid.UUID) (string, error) {
@@ -0,0 +1,89 @@
+package permissionValidation
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+// Permissions are granted by custom course roles and can be allowed on routes like roles.
+// A user with a custom course role gets the Keycloak role <courseIdentifier>-cr-<role name>.
+const (
+	CanViewApplications = "can_view_applications"
+	CanAssess           = "can_assess"
+	CanViewParticipants = "can_view_participants"
+	CanViewNotes        = "can_view_notes"
+	CanSendMail         = "can_send_mail"
+)
+
+// CourseRoleInfix separates the course identifier and the name of a custom course role
+const CourseRoleInfix = "-cr-"
+
+var CoursePermissions = []string{CanViewApplications, CanAssess, CanViewParticipants, CanViewNotes, CanSendMail}
+
+func IsCoursePermission(permission string) bool {
+	return slices.Contains(CoursePermissions, permission)
+}
+
+// IsCoursePhaseGrant checks whether a custom course role can be granted the role or permission in a single course phase
+func IsCoursePhaseGrant(grant string) bool {
+	return grant == CourseLecturer || grant == CourseEditor || IsCoursePermission(grant)
+}
+
+// CourseRoleString returns the Keycloak role of a custom course role, e.g. ss25-iPraktikum-cr-Tutor
+func CourseRoleString(courseIdentifier, roleName string) string {
+	return courseIdentifier + CourseRoleInfix + roleName
+}
+
+// hasCoursePermission checks whether one of the custom course roles of the user grants the permission in the course
+func hasCoursePermission(ctx context.Context, userRoles map[string]bool, courseIdentifier, permission string) (bool, error) {
+	courseRoles := courseRolesOfUser(userRoles, courseIdentifier)
+	// most users have no custom course role, they need no query
+	if len(courseRoles) == 0 {
+		return false, nil
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	return ValidationServiceSingleton.queries.HasCourseRolePermission(ctxWithTimeout, db.HasCourseRolePermissionParams{
+		RoleStrings: courseRoles,
+		Permission:  permission,
+	})
+}
+
+// hasCoursePhaseGrant checks whether one of the custom course roles of the user is granted
+// one of the allowed roles or permissions in the course phase
+func hasCoursePhaseGrant(ctx context.Context, userRoles map[string]bool, courseIdentifier string, coursePhaseID uuid.UUID, allowedUsers ...string) (bool, error) {
+	courseRoles := courseRolesOfUser(userRoles, courseIdentifier)
+	grants := slices.DeleteFunc(slices.Clone(allowedUsers), func(allowedUser string) bool {
+		return !IsCoursePhaseGrant(allowedUser)
+	})
+	if len(courseRoles) == 0 || len(grants) == 0 {
+		return false, nil
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	return ValidationServiceSingleton.queries.HasCoursePhaseGrant(ctxWithTimeout, db.HasCoursePhaseGrantParams{
+		CoursePhaseID: coursePhaseID,
+		RoleStrings:   courseRoles,
+		Grants:        grants,
+	})
+}
+
+// courseRolesOfUser returns the Keycloak roles of the custom course roles the user has in the course
+func courseRolesOfUser(userRoles map[string]bool, courseIdentifier string) []string {
+	courseRolePrefix := courseIdentifier + CourseRoleInfix
+	courseRoles := []string{}
+	for userRole, hasRole := range userRoles {
+		if hasRole && strings.HasPrefix(userRole, courseRolePrefix) {
+			courseRoles = append(courseRoles, userRole)
+		}
+	}
+	return courseRoles
+}
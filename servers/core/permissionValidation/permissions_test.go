@@ -0,0 +1,58 @@
+package permissionValidation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasAllowedRole(t *testing.T) {
+	userRoles := map[string]bool{
+		"ss25-iPraktikum-Editor": true,
+	}
+
+	hasRole, err := hasAllowedRole(context.Background(), userRoles, "ss25-iPraktikum", PromptAdmin, CourseEditor)
+	assert.NoError(t, err)
+	assert.True(t, hasRole)
+
+	hasRole, err = hasAllowedRole(context.Background(), userRoles, "ws25-iPraktikum", PromptAdmin, CourseEditor)
+	assert.NoError(t, err)
+	assert.False(t, hasRole, "course roles only apply to their course")
+
+	// users without custom course roles never get a permission
+	hasRole, err = hasAllowedRole(context.Background(), userRoles, "ss25-iPraktikum", CanAssess)
+	assert.NoError(t, err)
+	assert.False(t, hasRole)
+}
+
+func TestCourseRoleString(t *testing.T) {
+	assert.Equal(t, "ss25-iPraktikum-cr-Tutor", CourseRoleString("ss25-iPraktikum", "Tutor"))
+	assert.True(t, IsCoursePermission(CanViewNotes))
+	assert.False(t, IsCoursePermission(CourseEditor))
+}
+
+func TestIsCoursePhaseGrant(t *testing.T) {
+	assert.True(t, IsCoursePhaseGrant(CourseEditor))
+	assert.True(t, IsCoursePhaseGrant(CourseLecturer))
+	assert.True(t, IsCoursePhaseGrant(CanAssess))
+	assert.False(t, IsCoursePhaseGrant(CourseStudent), "students are enrolled in phases through their participation")
+	assert.False(t, IsCoursePhaseGrant(PromptAdmin))
+}
+
+func TestCourseRolesOfUser(t *testing.T) {
+	userRoles := map[string]bool{
+		"ss25-iPraktikum-cr-Tutor":   true,
+		"ss25-iPraktikum-cr-Grader":  false,
+		"ss25-iPraktikum-Editor":     true,
+		"ws25-iPraktikum-cr-Tutor":   true,
+		"ss25-iPraktikum-cg-Team_01": true,
+	}
+	assert.Equal(t, []string{"ss25-iPraktikum-cr-Tutor"}, courseRolesOfUser(userRoles, "ss25-iPraktikum"))
+
+	// users without custom course roles never get a grant and need no query
+	hasGrant, err := hasCoursePhaseGrant(context.Background(), map[string]bool{"ss25-iPraktikum-Editor": true}, "ss25-iPraktikum", uuid.New(), CourseEditor)
+	assert.NoError(t, err)
+	assert.False(t, hasGrant)
+}
@@ -0,0 +1,42 @@
+package permissionValidation
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CheckStudentPermission allows course roles and permissions in any course the student participates in
+func CheckStudentPermission(c *gin.Context, studentID uuid.UUID, allowedUsers ...string) (bool, error) {
+	userRoles, err := userRolesFromContext(c)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return false, err
+	}
+
+	// system-wide roles do not depend on the courses of the student
+	if (slices.Contains(allowedUsers, PromptAdmin) && userRoles[PromptAdmin]) || (slices.Contains(allowedUsers, PromptLecturer) && userRoles[PromptLecturer]) {
+		return true, nil
+	}
+
+	courseIdentifiers, err := ValidationServiceSingleton.queries.GetPermissionStringsByStudentID(c, studentID)
+	if err != nil {
+		c.IndentedJSON(500, gin.H{"error": err.Error()})
+		return false, err
+	}
+
+	for _, courseIdentifier := range courseIdentifiers {
+		hasRole, err := hasAllowedRole(c, userRoles, courseIdentifier, allowedUsers...)
+		if err != nil {
+			c.IndentedJSON(500, gin.H{"error": err.Error()})
+			return false, err
+		}
+		if hasRole {
+			return true, nil
+		}
+	}
+
+	c.IndentedJSON(403, gin.H{"error": "no matching permission found"})
+	return false, nil
+}
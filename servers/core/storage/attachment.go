@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	ErrFileNotFound          = errors.New("file not found")
+	ErrFileNotInCoursePhase  = errors.New("file does not belong to the course phase")
+	ErrParticipationNotFound = errors.New("course phase participation not found")
+)
+
+// GetCoursePhaseFiles lists the files of a course phase that have all given tags.
+// If uploaderUserID is set, only the files of this uploader are returned.
+func (s *StorageService) GetCoursePhaseFiles(ctx context.Context, coursePhaseID uuid.UUID, tags []string, uploaderUserID string) ([]FileResponse, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	var uploaderPgtype pgtype.Text
+	if uploaderUserID != "" {
+		uploaderPgtype = pgtype.Text{String: uploaderUserID, Valid: true}
+	}
+
+	files, err := s.queries.GetFilesByCoursePhaseIDAndTags(ctxWithTimeout, db.GetFilesByCoursePhaseIDAndTagsParams{
+		CoursePhaseID:    pgtype.UUID{Bytes: coursePhaseID, Valid: true},
+		Tags:             tags,
+		UploadedByUserID: uploaderPgtype,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve files: %w", err)
+	}
+
+	return s.convertToFileResponses(ctx, files), nil
+}
+
+// GetCoursePhaseFile returns a file if it belongs to the course phase.
+// Files of application answers and notes are reported as missing, they are only accessible through the application or the note.
+func (s *StorageService) GetCoursePhaseFile(ctx context.Context, coursePhaseID, fileID uuid.UUID) (*FileResponse, error) {
+	file, err := s.GetFileByID(ctx, fileID)
+	if err != nil {
+		return nil, ErrFileNotFound
+	}
+	if file.CoursePhaseID == nil || *file.CoursePhaseID != coursePhaseID {
+		return nil, ErrFileNotInCoursePhase
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	referenced, err := s.queries.IsFileReferencedByApplicationOrNote(ctxWithTimeout, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the file references: %w", err)
+	}
+	if referenced {
+		return nil, ErrFileNotFound
+	}
+	return file, nil
+}
+
+// GetNoteFiles lists the files attached to an instructor note
+func (s *StorageService) GetNoteFiles(ctx context.Context, noteID uuid.UUID) ([]FileResponse, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	files, err := s.queries.GetFilesForNote(ctxWithTimeout, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve note files: %w", err)
+	}
+
+	return s.convertToFileResponses(ctx, files), nil
+}
+
+// AttachFileToNote uploads a file and attaches it to an instructor note.
+// Note attachments do not belong to a course phase, so they are not counted against a quota.
+func (s *StorageService) AttachFileToNote(ctx context.Context, noteID uuid.UUID, req FileUploadRequest) (*FileResponse, error) {
+	req.CoursePhaseID = nil
+	file, err := s.UploadFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	err = s.queries.AddFileToNote(ctxWithTimeout, db.AddFileToNoteParams{NoteID: noteID, FileID: file.ID})
+	if err != nil {
+		if deleteErr := s.DeleteFile(ctx, file.ID, true); deleteErr != nil {
+			log.WithError(deleteErr).WithField("fileId", file.ID).Warn("Failed to delete unattached note file")
+		}
+		return nil, fmt.Errorf("failed to attach file to note: %w", err)
+	}
+	return file, nil
+}
+
+// DeleteNoteFile removes an attachment from an instructor note and deletes the file
+func (s *StorageService) DeleteNoteFile(ctx context.Context, noteID, fileID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctxWithTimeout)
+	if err != nil {
+		return err
+	}
+	defer sdkUtils.DeferRollback(tx, ctxWithTimeout)
+	qtx := s.queries.WithTx(tx)
+
+	detached, err := qtx.RemoveFileFromNote(ctxWithTimeout, db.RemoveFileFromNoteParams{NoteID: noteID, FileID: fileID})
+	if err != nil {
+		return fmt.Errorf("failed to detach file from note: %w", err)
+	}
+	// only files attached to the note may be deleted
+	if detached == 0 {
+		return ErrFileNotFound
+	}
+	if err := qtx.SoftDeleteFile(ctxWithTimeout, fileID); err != nil {
+		return fmt.Errorf("failed to delete note file: %w", err)
+	}
+	return tx.Commit(ctxWithTimeout)
+}
+
+// GetCoursePhaseParticipationFiles lists the files referenced by the data of a course phase participation
+func (s *StorageService) GetCoursePhaseParticipationFiles(ctx context.Context, coursePhaseID, courseParticipationID uuid.UUID) ([]FileResponse, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	files, err := s.queries.GetFilesForCoursePhaseParticipation(ctxWithTimeout, db.GetFilesForCoursePhaseParticipationParams{
+		CourseParticipationID: courseParticipationID,
+		CoursePhaseID:         coursePhaseID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve participation files: %w", err)
+	}
+
+	return s.convertToFileResponses(ctx, files), nil
+}
+
+// AttachFileToCoursePhaseParticipation references a file of the course phase from a participation.
+// Detaching the file again keeps it in the course phase.
+func (s *StorageService) AttachFileToCoursePhaseParticipation(ctx context.Context, coursePhaseID, courseParticipationID, fileID uuid.UUID) error {
+	if _, err := s.GetCoursePhaseFile(ctx, coursePhaseID, fileID); err != nil {
+		return err
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	err := s.queries.AddFileToCoursePhaseParticipation(ctxWithTimeout, db.AddFileToCoursePhaseParticipationParams{
+		CourseParticipationID: courseParticipationID,
+		CoursePhaseID:         coursePhaseID,
+		FileID:                fileID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrParticipationNotFound
+		}
+		return fmt.Errorf("failed to attach file to participation: %w", err)
+	}
+	return nil
+}
+
+// DetachFileFromCoursePhaseParticipation removes the reference from a participation to a file
+func (s *StorageService) DetachFileFromCoursePhaseParticipation(ctx context.Context, coursePhaseID, courseParticipationID, fileID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	err := s.queries.RemoveFileFromCoursePhaseParticipation(ctxWithTimeout, db.RemoveFileFromCoursePhaseParticipationParams{
+		CourseParticipationID: courseParticipationID,
+		CoursePhaseID:         coursePhaseID,
+		FileID:                fileID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach file from participation: %w", err)
+	}
+	return nil
+}
+
+func (s *StorageService) convertToFileResponses(ctx context.Context, files []db.File) []FileResponse {
+	responses := make([]FileResponse, len(files))
+	for i, file := range files {
+		responses[i] = *s.convertToFileResponse(ctx, file)
+	}
+	return responses
+}
+
+// ParseTags splits comma-separated tags and drops empty ones
+func ParseTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	parts := strings.Split(tags, ",")
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
@@ -0,0 +1,12 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.Nil(t, ParseTags(""))
+	assert.Equal(t, []string{"contract", "signed"}, ParseTags(" contract, ,signed "))
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLength is the number of bytes http.DetectContentType considers
+const sniffLength = 512
+
+var ErrContentTypeMismatch = errors.New("file content does not match its content type")
+
+// containerTypes are detected by their container format, the declared type refines them
+var containerTypes = map[string][]string{
+	"application/zip": {
+		"application/vnd.openxmlformats-officedocument.",
+		"application/vnd.oasis.opendocument.",
+		"application/epub+zip",
+		"application/java-archive",
+	},
+	"application/x-ole-storage": {
+		"application/msword",
+		"application/vnd.ms-excel",
+		"application/vnd.ms-powerpoint",
+	},
+}
+
+// executableSignatures are never accepted, whatever the client declares
+var executableSignatures = [][]byte{
+	[]byte("MZ"),               // Windows PE
+	[]byte("\x7fELF"),          // Linux ELF
+	[]byte("\xcf\xfa\xed\xfe"), // Mach-O
+	[]byte("\xca\xfe\xba\xbe"), // Mach-O universal binary and Java class
+	[]byte("#!"),               // scripts
+}
+
+// readHead reads the first bytes of a file for content sniffing and returns a reader over the complete content
+func readHead(reader io.Reader) ([]byte, io.Reader, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+	head = head[:n]
+	return head, io.MultiReader(bytes.NewReader(head), reader), nil
+}
+
+// verifyContentType determines the content type from the first bytes of a file.
+// The declared type is only used when it is compatible with the detected one,
+// e.g. a docx file is detected as zip archive.
+func verifyContentType(declared string, head []byte) (string, error) {
+	declaredType := normalizeMediaType(declared)
+
+	for _, signature := range executableSignatures {
+		if bytes.HasPrefix(head, signature) {
+			return "", fmt.Errorf("%w: executables are not allowed", ErrContentTypeMismatch)
+		}
+	}
+
+	detectedType := normalizeMediaType(http.DetectContentType(head))
+	if detectedType == declaredType {
+		return detectedType, nil
+	}
+
+	switch {
+	case detectedType == "application/octet-stream":
+		// unknown binary formats keep their declared type, unless the declared type could have been detected
+		if declaredType == "" || isSniffableType(declaredType) {
+			return "", fmt.Errorf("%w: declared %s", ErrContentTypeMismatch, declared)
+		}
+		return declaredType, nil
+	case detectedType == "text/plain":
+		// plain text is not distinguishable from csv, json, markdown, ...
+		if strings.HasPrefix(declaredType, "text/") && declaredType != "text/html" || declaredType == "application/json" {
+			return declaredType, nil
+		}
+	default:
+		for _, prefix := range containerTypes[detectedType] {
+			if strings.HasPrefix(declaredType, prefix) {
+				return declaredType, nil
+			}
+		}
+	}
+
+	// a mismatching declared type, e.g. html uploaded as image, is replaced by the detected one,
+	// which then has to pass the allowed file types
+	return detectedType, nil
+}
+
+// isSniffableType reports whether http.DetectContentType recognizes the type by its signature
+func isSniffableType(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		return mediaType != "image/svg+xml"
+	case mediaType == "application/pdf", mediaType == "application/zip", mediaType == "application/x-gzip",
+		mediaType == "application/x-rar-compressed", mediaType == "application/wasm", mediaType == "text/html":
+		return true
+	}
+	return false
+}
+
+func normalizeMediaType(contentType string) string {
+	contentType = strings.TrimSpace(contentType)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	if separatorIndex := strings.Index(contentType, ";"); separatorIndex >= 0 {
+		contentType = contentType[:separatorIndex]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
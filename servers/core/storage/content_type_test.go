@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyContentType(t *testing.T) {
+	pngHeader := "\x89PNG\x0D\x0A\x1A\x0A"
+	zipHeader := "PK\x03\x04"
+
+	tests := []struct {
+		name     string
+		declared string
+		content  string
+		expected string
+	}{
+		{"matching pdf", "application/pdf", "%PDF-1.7\n", "application/pdf"},
+		{"parameters are ignored", "application/pdf; charset=binary", "%PDF-1.7\n", "application/pdf"},
+		{"mismatching declared type is replaced", "application/pdf", pngHeader, "image/png"},
+		{"html declared as image", "image/png", "<html><body>hi</body></html>", "text/html"},
+		{"docx is refined from zip", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", zipHeader, "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"zip declared as pdf", "application/pdf", zipHeader, "application/zip"},
+		{"csv is refined from plain text", "text/csv", "name,email\n", "text/csv"},
+		{"text declared as pdf", "application/pdf", "not a pdf", "text/plain"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contentType, err := verifyContentType(test.declared, []byte(test.content))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, contentType)
+		})
+	}
+}
+
+func TestVerifyContentType_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared string
+		content  string
+	}{
+		{"windows executable", "application/pdf", "MZ\x90\x00\x03"},
+		{"linux executable", "application/octet-stream", "\x7fELF\x02\x01"},
+		{"script", "text/plain", "#!/bin/sh\nrm -rf /"},
+		{"unknown binary declared as pdf", "application/pdf", "\x00\x01\x02\x03"},
+		{"unknown binary without type", "", "\x00\x01\x02\x03"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := verifyContentType(test.declared, []byte(test.content))
+			assert.ErrorIs(t, err, ErrContentTypeMismatch)
+		})
+	}
+}
+
+func TestReadHead(t *testing.T) {
+	content := strings.Repeat("x", sniffLength+100)
+	head, reader, err := readHead(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Len(t, head, sniffLength)
+
+	complete, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(complete), "the sniffed bytes are not lost")
+
+	head, _, err = readHead(strings.NewReader("short"))
+	assert.NoError(t, err)
+	assert.Equal(t, "short", string(head))
+}
@@ -0,0 +1,639 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+// Encrypted objects start with a header, followed by chunks that are sealed separately with AES-GCM.
+// The nonce of a chunk consists of the random prefix of the header, the chunk counter and a flag for the last chunk,
+// so reordered, dropped or truncated chunks fail to decrypt.
+const (
+	encryptionMagic           = "PRMTENC1"
+	encryptionChunkSize       = 64 * 1024
+	encryptionMaxChunkSize    = 16 * 1024 * 1024
+	encryptionNoncePrefixSize = 7
+	encryptionHeaderSize      = len(encryptionMagic) + 4 + encryptionNoncePrefixSize
+	encryptionKeySize         = 32
+	// encryptedStorageKeySuffix marks the encrypted copy of an object that was uploaded in plain form with a presigned URL
+	encryptedStorageKeySuffix = ".enc"
+	encryptedContentType      = "application/octet-stream"
+
+	encryptedOperationDownload = "download"
+	encryptedOperationPreview  = "preview"
+)
+
+var (
+	ErrEncryptedFileCorrupt  = errors.New("encrypted file is corrupt or was encrypted with another key")
+	ErrEncryptionDisabled    = errors.New("file encryption is not configured")
+	ErrUnknownMasterKey      = errors.New("the data key was wrapped with an unknown master key")
+	ErrInvalidEncryptionSize = errors.New("invalid encryption chunk size")
+)
+
+// fileEncryption wraps the data keys of the courses with the master key of the server.
+// Previous master keys are only used to unwrap data keys until they are rotated.
+type fileEncryption struct {
+	masterKey          []byte
+	masterKeyID        string
+	previousMasterKeys map[string][]byte
+	downloadBaseURL    string
+	signingKey         []byte
+	// dataKeys caches unwrapped data keys by key id, a rotation of the master key does not change them
+	dataKeys sync.Map
+}
+
+func newFileEncryption(masterKey []byte, previousMasterKeys [][]byte, downloadBaseURL string) (*fileEncryption, error) {
+	if len(masterKey) != encryptionKeySize {
+		return nil, fmt.Errorf("the master key must have %d bytes, got %d", encryptionKeySize, len(masterKey))
+	}
+
+	previous := make(map[string][]byte, len(previousMasterKeys))
+	for _, key := range previousMasterKeys {
+		if len(key) != encryptionKeySize {
+			return nil, fmt.Errorf("previous master keys must have %d bytes, got %d", encryptionKeySize, len(key))
+		}
+		previous[masterKeyID(key)] = key
+	}
+
+	// download URLs are signed with a key derived from the master key, so no further secret is needed
+	mac := hmac.New(sha256.New, masterKey)
+	mac.Write([]byte("prompt-file-download-url"))
+
+	return &fileEncryption{
+		masterKey:          masterKey,
+		masterKeyID:        masterKeyID(masterKey),
+		previousMasterKeys: previous,
+		downloadBaseURL:    strings.TrimSuffix(downloadBaseURL, "/"),
+		signingKey:         mac.Sum(nil),
+	}, nil
+}
+
+// newFileEncryptionFromEnv returns nil if no master key is configured, files are then stored in plain form
+func newFileEncryptionFromEnv() (*fileEncryption, error) {
+	encodedMasterKey := strings.TrimSpace(sdkUtils.GetEnv("FILE_ENCRYPTION_MASTER_KEY", ""))
+	if encodedMasterKey == "" {
+		return nil, nil
+	}
+
+	masterKey, err := base64.StdEncoding.DecodeString(encodedMasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FILE_ENCRYPTION_MASTER_KEY: %w", err)
+	}
+
+	var previousMasterKeys [][]byte
+	for _, encodedKey := range strings.Split(sdkUtils.GetEnv("FILE_ENCRYPTION_PREVIOUS_MASTER_KEYS", ""), ",") {
+		if strings.TrimSpace(encodedKey) == "" {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid FILE_ENCRYPTION_PREVIOUS_MASTER_KEYS: %w", err)
+		}
+		previousMasterKeys = append(previousMasterKeys, key)
+	}
+
+	return newFileEncryption(masterKey, previousMasterKeys, sdkUtils.GetEnv("FILE_DOWNLOAD_PUBLIC_URL", "http://localhost:8080/api"))
+}
+
+// masterKeyID identifies a master key without revealing it
+func masterKeyID(masterKey []byte) string {
+	sum := sha256.Sum256(masterKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wrapDataKey seals a data key with the master key, bound to the id of its key record
+func (e *fileEncryption) wrapDataKey(keyID uuid.UUID, dataKey []byte) ([]byte, error) {
+	aead, err := newGCM(e.masterKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, keyID[:]), nil
+}
+
+func (e *fileEncryption) unwrapDataKey(key db.FileEncryptionKey) ([]byte, error) {
+	masterKey := e.masterKey
+	if key.MasterKeyID != e.masterKeyID {
+		var ok bool
+		if masterKey, ok = e.previousMasterKeys[key.MasterKeyID]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownMasterKey, key.MasterKeyID)
+		}
+	}
+
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(key.WrappedKey) < aead.NonceSize() {
+		return nil, ErrEncryptedFileCorrupt
+	}
+	nonce, sealed := key.WrappedKey[:aead.NonceSize()], key.WrappedKey[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, key.ID[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key %s: %w", key.ID, err)
+	}
+	return dataKey, nil
+}
+
+// signedURL grants access to the decrypted content of a file until the URL expires, like a presigned URL of the storage
+func (e *fileEncryption) signedURL(operation string, fileID uuid.UUID, ttl int) string {
+	expires := strconv.FormatInt(time.Now().Add(time.Duration(ttl)*time.Second).Unix(), 10)
+	query := url.Values{}
+	query.Set("file", fileID.String())
+	query.Set("expires", expires)
+	query.Set("signature", e.sign(operation, fileID.String(), expires))
+	return fmt.Sprintf("%s/storage/encrypted/%s?%s", e.downloadBaseURL, operation, query.Encode())
+}
+
+func (e *fileEncryption) sign(operation, fileID, expires string) string {
+	mac := hmac.New(sha256.New, e.signingKey)
+	mac.Write([]byte(operation + "\n" + fileID + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (e *fileEncryption) verifySignature(operation, fileID, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(e.sign(operation, fileID, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// dataKeyForCoursePhase returns the data key of the course of a phase and creates it on first use.
+// Files without a course phase share one key. Without encryption, no key is returned.
+func (s *StorageService) dataKeyForCoursePhase(ctx context.Context, coursePhaseID *uuid.UUID) (pgtype.UUID, []byte, error) {
+	if s.encryption == nil {
+		return pgtype.UUID{}, nil, nil
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	var courseID pgtype.UUID
+	if coursePhaseID != nil {
+		id, err := s.queries.GetCourseIDByCoursePhaseID(ctxWithTimeout, *coursePhaseID)
+		if err != nil {
+			return pgtype.UUID{}, nil, fmt.Errorf("failed to get course of course phase: %w", err)
+		}
+		courseID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	key, err := s.queries.GetFileEncryptionKeyByCourse(ctxWithTimeout, courseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		key, err = s.createDataKey(ctxWithTimeout, courseID)
+	}
+	if err != nil {
+		return pgtype.UUID{}, nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+
+	dataKey, err := s.unwrapCachedDataKey(key)
+	if err != nil {
+		return pgtype.UUID{}, nil, err
+	}
+	return pgtype.UUID{Bytes: key.ID, Valid: true}, dataKey, nil
+}
+
+func (s *StorageService) createDataKey(ctx context.Context, courseID pgtype.UUID) (db.FileEncryptionKey, error) {
+	dataKey := make([]byte, encryptionKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return db.FileEncryptionKey{}, err
+	}
+
+	keyID := uuid.New()
+	wrappedKey, err := s.encryption.wrapDataKey(keyID, dataKey)
+	if err != nil {
+		return db.FileEncryptionKey{}, err
+	}
+
+	if err := s.queries.CreateFileEncryptionKey(ctx, db.CreateFileEncryptionKeyParams{
+		ID:          keyID,
+		CourseID:    courseID,
+		WrappedKey:  wrappedKey,
+		MasterKeyID: s.encryption.masterKeyID,
+	}); err != nil {
+		return db.FileEncryptionKey{}, err
+	}
+
+	log.WithField("courseId", courseID).Info("File encryption key created")
+	return s.queries.GetFileEncryptionKeyByCourse(ctx, courseID)
+}
+
+// dataKey returns the data key a file was encrypted with
+func (s *StorageService) dataKey(ctx context.Context, keyID uuid.UUID) ([]byte, error) {
+	if s.encryption == nil {
+		return nil, ErrEncryptionDisabled
+	}
+	if dataKey, ok := s.encryption.dataKeys.Load(keyID); ok {
+		return dataKey.([]byte), nil
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	key, err := s.queries.GetFileEncryptionKey(ctxWithTimeout, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	return s.unwrapCachedDataKey(key)
+}
+
+func (s *StorageService) unwrapCachedDataKey(key db.FileEncryptionKey) ([]byte, error) {
+	if dataKey, ok := s.encryption.dataKeys.Load(key.ID); ok {
+		return dataKey.([]byte), nil
+	}
+	dataKey, err := s.encryption.unwrapDataKey(key)
+	if err != nil {
+		return nil, err
+	}
+	s.encryption.dataKeys.Store(key.ID, dataKey)
+	return dataKey, nil
+}
+
+// openStoredObject reads an object of the storage and decrypts it, if it was encrypted with the given key
+func (s *StorageService) openStoredObject(ctx context.Context, adapter StorageAdapter, storageKey string, encryptionKeyID pgtype.UUID) (io.ReadCloser, error) {
+	var dataKey []byte
+	if encryptionKeyID.Valid {
+		var err error
+		if dataKey, err = s.dataKey(ctx, encryptionKeyID.Bytes); err != nil {
+			return nil, err
+		}
+	}
+
+	reader, err := adapter.Download(ctx, storageKey)
+	if err != nil || dataKey == nil {
+		return reader, err
+	}
+
+	plaintext, err := newDecryptingReader(dataKey, reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{plaintext, reader}, nil
+}
+
+// openFileContent returns the plain content of a file
+func (s *StorageService) openFileContent(ctx context.Context, file db.File) (io.ReadCloser, error) {
+	return s.openStoredObject(ctx, s.adapterFor(file.StorageProvider), file.StorageKey, file.EncryptionKeyID)
+}
+
+// encryptStoredFile replaces the plain object of a file that was uploaded with a presigned URL by an encrypted copy.
+// A failure is logged and leaves the file in plain form.
+func (s *StorageService) encryptStoredFile(ctx context.Context, file db.File) db.File {
+	if s.encryption == nil || file.EncryptionKeyID.Valid {
+		return file
+	}
+
+	var coursePhaseID *uuid.UUID
+	if file.CoursePhaseID.Valid {
+		id := uuid.UUID(file.CoursePhaseID.Bytes)
+		coursePhaseID = &id
+	}
+
+	encryptedFile, err := s.encryptStoredObject(ctx, file, coursePhaseID)
+	if err != nil {
+		log.WithError(err).WithField("fileId", file.ID).Error("Failed to encrypt uploaded file, it stays unencrypted")
+		return file
+	}
+
+	// deduplicated files may still share the plain object
+	if references, err := s.countStorageKeyReferences(ctx, file.StorageKey); err == nil && references == 0 {
+		if err := s.adapterFor(file.StorageProvider).Delete(ctx, file.StorageKey); err != nil {
+			log.WithError(err).WithField("storageKey", file.StorageKey).Warn("Failed to delete unencrypted upload - left to the garbage collection")
+		}
+	}
+	return encryptedFile
+}
+
+func (s *StorageService) encryptStoredObject(ctx context.Context, file db.File, coursePhaseID *uuid.UUID) (db.File, error) {
+	encryptionKeyID, dataKey, err := s.dataKeyForCoursePhase(ctx, coursePhaseID)
+	if err != nil {
+		return file, err
+	}
+
+	adapter := s.adapterFor(file.StorageProvider)
+	reader, err := adapter.Download(ctx, file.StorageKey)
+	if err != nil {
+		return file, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer reader.Close()
+
+	ciphertext, err := newEncryptingReader(dataKey, reader)
+	if err != nil {
+		return file, err
+	}
+	storageKey := file.StorageKey + encryptedStorageKeySuffix
+	if _, err := adapter.Upload(ctx, storageKey, encryptedContentType, ciphertext); err != nil {
+		return file, fmt.Errorf("failed to store encrypted file: %w", err)
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	encryptedFile, err := s.queries.UpdateFileContent(ctxWithTimeout, db.UpdateFileContentParams{
+		ID:              file.ID,
+		ContentSha256:   file.ContentSha256,
+		StorageKey:      storageKey,
+		EncryptionKeyID: encryptionKeyID,
+	})
+	if err != nil {
+		_ = adapter.Delete(ctx, storageKey)
+		return file, fmt.Errorf("failed to save encrypted file: %w", err)
+	}
+	return encryptedFile, nil
+}
+
+// RotateEncryptionKeys wraps all data keys with the current master key.
+// The files are not re-encrypted, so the previous master keys can be removed once the rotation is done.
+func (s *StorageService) RotateEncryptionKeys(ctx context.Context) (int, error) {
+	if s.encryption == nil {
+		return 0, ErrEncryptionDisabled
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	keys, err := s.queries.GetFileEncryptionKeysToRotate(ctxWithTimeout, s.encryption.masterKeyID)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get encryption keys: %w", err)
+	}
+
+	rotated := 0
+	for _, key := range keys {
+		dataKey, err := s.encryption.unwrapDataKey(key)
+		if err != nil {
+			return rotated, err
+		}
+		wrappedKey, err := s.encryption.wrapDataKey(key.ID, dataKey)
+		if err != nil {
+			return rotated, err
+		}
+
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		err = s.queries.UpdateFileEncryptionKeyWrapping(ctxWithTimeout, db.UpdateFileEncryptionKeyWrappingParams{
+			ID:          key.ID,
+			WrappedKey:  wrappedKey,
+			MasterKeyID: s.encryption.masterKeyID,
+		})
+		cancel()
+		if err != nil {
+			return rotated, fmt.Errorf("failed to save rotated key %s: %w", key.ID, err)
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+// RotateEncryptionKeysFromEnv runs the key rotation with the master keys of the environment
+func RotateEncryptionKeysFromEnv(ctx context.Context, queries db.Queries) error {
+	encryption, err := newFileEncryptionFromEnv()
+	if err != nil {
+		return err
+	}
+	if encryption == nil {
+		return fmt.Errorf("%w: set FILE_ENCRYPTION_MASTER_KEY", ErrEncryptionDisabled)
+	}
+
+	service := &StorageService{queries: queries, encryption: encryption}
+	rotated, err := service.RotateEncryptionKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("key rotation stopped after %d keys: %w", rotated, err)
+	}
+
+	log.WithFields(log.Fields{
+		"rotatedKeys": rotated,
+		"masterKeyId": encryption.masterKeyID,
+	}).Info("File encryption keys rotated")
+	return nil
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, encryptionNoncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// encryptingReader encrypts the content of the source while it is read, so large files are never held in memory
+type encryptingReader struct {
+	aead    cipher.AEAD
+	source  *bufio.Reader
+	header  []byte
+	counter uint32
+	plain   []byte
+	sealed  []byte
+	pending []byte
+	done    bool
+}
+
+func newEncryptingReader(dataKey []byte, plaintext io.Reader) (io.Reader, error) {
+	return newEncryptingReaderWithChunkSize(dataKey, plaintext, encryptionChunkSize)
+}
+
+func newEncryptingReaderWithChunkSize(dataKey []byte, plaintext io.Reader, chunkSize int) (io.Reader, error) {
+	if chunkSize <= 0 || chunkSize > encryptionMaxChunkSize {
+		return nil, ErrInvalidEncryptionSize
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, encryptionHeaderSize)
+	header = append(header, encryptionMagic...)
+	header = binary.BigEndian.AppendUint32(header, uint32(chunkSize))
+	prefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header = append(header, prefix...)
+
+	return &encryptingReader{
+		aead:    aead,
+		source:  bufio.NewReader(plaintext),
+		header:  header,
+		plain:   make([]byte, chunkSize),
+		sealed:  make([]byte, 0, chunkSize+aead.Overhead()),
+		pending: header,
+	}, nil
+}
+
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.sealNextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *encryptingReader) sealNextChunk() error {
+	n, err := io.ReadFull(r.source, r.plain)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		// a full chunk is the last one if nothing follows
+		if _, err := r.source.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+	if !last && r.counter == math.MaxUint32 {
+		return fmt.Errorf("%w: the file has too many chunks", ErrInvalidEncryptionSize)
+	}
+
+	prefix := r.header[len(encryptionMagic)+4:]
+	r.pending = r.aead.Seal(r.sealed[:0], chunkNonce(prefix, r.counter, last), r.plain[:n], r.header)
+	r.counter++
+	r.done = last
+	return nil
+}
+
+// decryptingReader verifies and decrypts the chunks of an encrypted object while it is read
+type decryptingReader struct {
+	aead    cipher.AEAD
+	source  *bufio.Reader
+	header  []byte
+	counter uint32
+	sealed  []byte
+	plain   []byte
+	pending []byte
+	done    bool
+}
+
+func newDecryptingReader(dataKey []byte, ciphertext io.Reader) (io.Reader, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	source := bufio.NewReader(ciphertext)
+	header := make([]byte, encryptionHeaderSize)
+	if _, err := io.ReadFull(source, header); err != nil {
+		return nil, fmt.Errorf("%w: incomplete header", ErrEncryptedFileCorrupt)
+	}
+	if string(header[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, fmt.Errorf("%w: unknown format", ErrEncryptedFileCorrupt)
+	}
+	chunkSize := int(binary.BigEndian.Uint32(header[len(encryptionMagic):]))
+	if chunkSize <= 0 || chunkSize > encryptionMaxChunkSize {
+		return nil, fmt.Errorf("%w: chunk size %d", ErrEncryptedFileCorrupt, chunkSize)
+	}
+
+	return &decryptingReader{
+		aead:   aead,
+		source: source,
+		header: header,
+		sealed: make([]byte, chunkSize+aead.Overhead()),
+		plain:  make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.openNextChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func (r *decryptingReader) openNextChunk() error {
+	n, err := io.ReadFull(r.source, r.sealed)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF):
+		// the previous chunk was not marked as the last one
+		return fmt.Errorf("%w: the file is truncated", ErrEncryptedFileCorrupt)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.source.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	prefix := r.header[len(encryptionMagic)+4:]
+	plain, err := r.aead.Open(r.plain[:0], chunkNonce(prefix, r.counter, last), r.sealed[:n], r.header)
+	if err != nil {
+		return fmt.Errorf("%w: chunk %d", ErrEncryptedFileCorrupt, r.counter)
+	}
+	r.pending = plain
+	r.counter++
+	r.done = last
+	return nil
+}
+
+// countingReader counts the plain bytes of an upload, whose stored size includes the encryption overhead
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+	return n, err
+}
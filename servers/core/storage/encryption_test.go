@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEncryptionKey(fill byte) []byte {
+	return bytes.Repeat([]byte{fill}, encryptionKeySize)
+}
+
+func encryptTestContent(t *testing.T, dataKey, content []byte, chunkSize int) []byte {
+	reader, err := newEncryptingReaderWithChunkSize(dataKey, bytes.NewReader(content), chunkSize)
+	assert.NoError(t, err)
+	ciphertext, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return ciphertext
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	dataKey := testEncryptionKey(1)
+	const chunkSize = 16
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3 * chunkSize, 3*chunkSize + 5} {
+		t.Run(strconv.Itoa(size), func(t *testing.T) {
+			content := bytes.Repeat([]byte("x"), size)
+			ciphertext := encryptTestContent(t, dataKey, content, chunkSize)
+			chunks := max(1, (size+chunkSize-1)/chunkSize)
+			assert.Len(t, ciphertext, encryptionHeaderSize+size+chunks*16, "every chunk carries its tag")
+
+			reader, err := newDecryptingReader(dataKey, bytes.NewReader(ciphertext))
+			assert.NoError(t, err)
+			plaintext, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, content, plaintext)
+		})
+	}
+}
+
+func TestEncryption_DefaultChunkSize(t *testing.T) {
+	dataKey := testEncryptionKey(1)
+	content := bytes.Repeat([]byte("0123456789"), encryptionChunkSize/4)
+
+	reader, err := newEncryptingReader(dataKey, bytes.NewReader(content))
+	assert.NoError(t, err)
+	ciphertext, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+
+	decrypted, err := newDecryptingReader(dataKey, bytes.NewReader(ciphertext))
+	assert.NoError(t, err)
+	plaintext, err := io.ReadAll(decrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, content, plaintext)
+}
+
+func TestEncryption_DetectsModifications(t *testing.T) {
+	dataKey := testEncryptionKey(1)
+	const chunkSize = 16
+	ciphertext := encryptTestContent(t, dataKey, bytes.Repeat([]byte("y"), 3*chunkSize), chunkSize)
+	sealedChunkSize := chunkSize + 16
+
+	tests := []struct {
+		name       string
+		ciphertext []byte
+		key        []byte
+	}{
+		{"wrong key", ciphertext, testEncryptionKey(2)},
+		{"modified chunk", func() []byte {
+			modified := bytes.Clone(ciphertext)
+			modified[encryptionHeaderSize+3] ^= 1
+			return modified
+		}(), dataKey},
+		{"truncated at a chunk boundary", ciphertext[:encryptionHeaderSize+2*sealedChunkSize], dataKey},
+		{"truncated within a chunk", ciphertext[:len(ciphertext)-4], dataKey},
+		{"reordered chunks", func() []byte {
+			reordered := bytes.Clone(ciphertext[:encryptionHeaderSize])
+			reordered = append(reordered, ciphertext[encryptionHeaderSize+sealedChunkSize:encryptionHeaderSize+2*sealedChunkSize]...)
+			reordered = append(reordered, ciphertext[encryptionHeaderSize:encryptionHeaderSize+sealedChunkSize]...)
+			return append(reordered, ciphertext[encryptionHeaderSize+2*sealedChunkSize:]...)
+		}(), dataKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader, err := newDecryptingReader(test.key, bytes.NewReader(test.ciphertext))
+			assert.NoError(t, err)
+			_, err = io.ReadAll(reader)
+			assert.ErrorIs(t, err, ErrEncryptedFileCorrupt)
+		})
+	}
+
+	_, err := newDecryptingReader(dataKey, bytes.NewReader([]byte("%PDF-1.4 plain file")))
+	assert.ErrorIs(t, err, ErrEncryptedFileCorrupt)
+}
+
+func TestWrapDataKey(t *testing.T) {
+	previous, err := newFileEncryption(testEncryptionKey(1), nil, "http://localhost:8080/api")
+	assert.NoError(t, err)
+	dataKey := testEncryptionKey(9)
+	keyID := uuid.New()
+
+	wrappedKey, err := previous.wrapDataKey(keyID, dataKey)
+	assert.NoError(t, err)
+	key := db.FileEncryptionKey{ID: keyID, WrappedKey: wrappedKey, MasterKeyID: previous.masterKeyID}
+
+	unwrapped, err := previous.unwrapDataKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = previous.unwrapDataKey(db.FileEncryptionKey{ID: uuid.New(), WrappedKey: wrappedKey, MasterKeyID: previous.masterKeyID})
+	assert.Error(t, err, "the wrapped key is bound to its record")
+
+	current, err := newFileEncryption(testEncryptionKey(2), [][]byte{testEncryptionKey(1)}, "http://localhost:8080/api")
+	assert.NoError(t, err)
+	unwrapped, err = current.unwrapDataKey(key)
+	assert.NoError(t, err, "keys wrapped with a previous master key can be unwrapped")
+	assert.Equal(t, dataKey, unwrapped)
+
+	other, err := newFileEncryption(testEncryptionKey(3), nil, "http://localhost:8080/api")
+	assert.NoError(t, err)
+	_, err = other.unwrapDataKey(key)
+	assert.ErrorIs(t, err, ErrUnknownMasterKey)
+
+	_, err = newFileEncryption([]byte("too short"), nil, "")
+	assert.Error(t, err)
+}
+
+func TestEncryptedFileSignedURL(t *testing.T) {
+	encryption, err := newFileEncryption(testEncryptionKey(1), nil, "https://prompt.example.com/api/")
+	assert.NoError(t, err)
+	fileID := uuid.New()
+
+	signedURL, err := url.Parse(encryption.signedURL(encryptedOperationDownload, fileID, 60))
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/storage/encrypted/download", signedURL.Path)
+	query := signedURL.Query()
+	assert.Equal(t, fileID.String(), query.Get("file"))
+	assert.NoError(t, encryption.verifySignature(encryptedOperationDownload, query.Get("file"), query.Get("expires"), query.Get("signature")))
+
+	assert.ErrorIs(t, encryption.verifySignature(encryptedOperationPreview, query.Get("file"), query.Get("expires"), query.Get("signature")), ErrInvalidSignature)
+	assert.ErrorIs(t, encryption.verifySignature(encryptedOperationDownload, uuid.NewString(), query.Get("expires"), query.Get("signature")), ErrInvalidSignature)
+
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	assert.ErrorIs(t, encryption.verifySignature(encryptedOperationDownload, fileID.String(), expired, encryption.sign(encryptedOperationDownload, fileID.String(), expired)), ErrInvalidSignature)
+}
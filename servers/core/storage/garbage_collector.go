@@ -0,0 +1,306 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+// orphanGracePeriod protects objects of presigned uploads that are not completed yet.
+// It is far longer than the TTL of an upload URL.
+const orphanGracePeriod = 24 * time.Hour
+
+var ErrGarbageCollectionRunning = errors.New("a garbage collection is already running")
+
+// GarbageCollectionReport lists what a garbage collection removed, or would remove during a dry run
+type GarbageCollectionReport struct {
+	DryRun     bool      `json:"dryRun"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	// ExpiredFiles were soft-deleted longer than the retention period
+	ExpiredFiles []CollectedFile `json:"expiredFiles"`
+	// OrphanedObjects are stored objects without a file record, e.g. from presigned uploads that were never completed
+	OrphanedObjects []StoredObject `json:"orphanedObjects"`
+	// MissingObjects are file records whose object does not exist anymore
+	MissingObjects []CollectedFile `json:"missingObjects"`
+	// TemporaryFiles counts the leftovers of interrupted writes of the local storage
+	TemporaryFiles int      `json:"temporaryFiles"`
+	FreedBytes     int64    `json:"freedBytes"`
+	Errors         []string `json:"errors"`
+}
+
+type CollectedFile struct {
+	ID         uuid.UUID `json:"id"`
+	StorageKey string    `json:"storageKey"`
+	SizeBytes  int64     `json:"sizeBytes"`
+}
+
+// temporaryFileCleaner is implemented by storage adapters that write through temporary files
+type temporaryFileCleaner interface {
+	RemoveStaleTemporaryFiles(ctx context.Context, olderThan time.Time, dryRun bool) (int, int64, error)
+}
+
+type garbageCollector struct {
+	running    sync.Mutex
+	lastReport atomic.Pointer[GarbageCollectionReport]
+}
+
+// CollectGarbage reconciles the files table with the storage backend.
+// During a dry run nothing is deleted, the report lists what would have been deleted.
+func (s *StorageService) CollectGarbage(ctx context.Context, dryRun bool) (*GarbageCollectionReport, error) {
+	if !s.garbageCollector.running.TryLock() {
+		return nil, ErrGarbageCollectionRunning
+	}
+	defer s.garbageCollector.running.Unlock()
+
+	report := &GarbageCollectionReport{
+		DryRun:          dryRun,
+		StartedAt:       time.Now(),
+		ExpiredFiles:    []CollectedFile{},
+		OrphanedObjects: []StoredObject{},
+		MissingObjects:  []CollectedFile{},
+		Errors:          []string{},
+	}
+
+	if err := s.collectExpiredFiles(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.reconcileStoredObjects(ctx, report); err != nil {
+		return nil, err
+	}
+	s.collectTemporaryFiles(ctx, report)
+
+	report.FinishedAt = time.Now()
+	if !dryRun {
+		s.garbageCollector.lastReport.Store(report)
+	}
+
+	log.WithFields(log.Fields{
+		"dryRun":          dryRun,
+		"expiredFiles":    len(report.ExpiredFiles),
+		"orphanedObjects": len(report.OrphanedObjects),
+		"missingObjects":  len(report.MissingObjects),
+		"temporaryFiles":  report.TemporaryFiles,
+		"freedBytes":      report.FreedBytes,
+		"errors":          len(report.Errors),
+	}).Info("File garbage collection finished")
+
+	return report, nil
+}
+
+// GetLastGarbageCollectionReport returns the report of the last garbage collection that was not a dry run
+func (s *StorageService) GetLastGarbageCollectionReport() *GarbageCollectionReport {
+	return s.garbageCollector.lastReport.Load()
+}
+
+// collectExpiredFiles hard deletes files that were soft-deleted longer than the retention period
+func (s *StorageService) collectExpiredFiles(ctx context.Context, report *GarbageCollectionReport) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	expiredFiles, err := s.queries.GetFilesDeletedBefore(ctxWithTimeout, int32(fileRetentionPeriod().Seconds()))
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get expired files: %w", err)
+	}
+
+	for _, file := range expiredFiles {
+		collected := CollectedFile{ID: file.ID, StorageKey: file.StorageKey, SizeBytes: file.SizeBytes}
+
+		// deduplicated files share their object, it is deleted together with the last file referencing it
+		references, err := s.countStorageKeyReferences(ctx, file.StorageKey)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to count references of object %s: %v", file.StorageKey, err))
+			continue
+		}
+		objectShared := references > 1
+
+		if !report.DryRun {
+			// the objects are deleted first, a remaining record is collected again in the next run
+			if err := s.deleteFilePreview(ctx, file.ID, file.StorageProvider); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to delete preview of file %s: %v", file.ID, err))
+				continue
+			}
+			if !objectShared {
+				if err := s.adapterFor(file.StorageProvider).Delete(ctx, file.StorageKey); err != nil {
+					report.Errors = append(report.Errors, fmt.Sprintf("failed to delete object %s: %v", file.StorageKey, err))
+					continue
+				}
+			}
+			deleted, err := s.hardDeleteFileRecord(ctx, file.ID, file.StorageKey, file.StorageProvider)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to delete file %s: %v", file.ID, err))
+				continue
+			}
+			if !deleted {
+				continue
+			}
+		}
+		report.ExpiredFiles = append(report.ExpiredFiles, collected)
+		if !objectShared {
+			report.FreedBytes += file.SizeBytes
+		}
+	}
+	return nil
+}
+
+// reconcileStoredObjects removes objects without file records and file records without objects
+func (s *StorageService) reconcileStoredObjects(ctx context.Context, report *GarbageCollectionReport) error {
+	// The records are loaded before the objects are listed: objects are uploaded before their record is created,
+	// so every loaded record whose object is not listed has really lost its object.
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	records, err := s.queries.GetFileStorageKeys(ctxWithTimeout)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get file records: %w", err)
+	}
+
+	ctxWithTimeout, cancel = db.GetTimeoutContext(ctx)
+	previewKeys, err := s.queries.GetFilePreviewStorageKeys(ctxWithTimeout)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get file previews: %w", err)
+	}
+
+	objects, err := s.storageAdapter.ListObjects(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list stored objects: %w", err)
+	}
+
+	orphanedObjects, missingObjects := findUnreferencedFiles(records, previewKeys, objects, configuredStorageProvider(), time.Now())
+	if len(objects) == 0 && len(missingObjects) > 0 {
+		// most likely a misconfigured bucket or directory, which must not wipe all file records
+		return fmt.Errorf("the storage is empty, refusing to delete %d file records", len(missingObjects))
+	}
+
+	for _, object := range orphanedObjects {
+		if !report.DryRun {
+			if err := s.storageAdapter.Delete(ctx, object.StorageKey); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to delete object %s: %v", object.StorageKey, err))
+				continue
+			}
+		}
+		report.OrphanedObjects = append(report.OrphanedObjects, object)
+		report.FreedBytes += object.Size
+	}
+
+	for _, record := range missingObjects {
+		if !report.DryRun {
+			deleted, err := s.hardDeleteFileRecord(ctx, record.ID, record.StorageKey, record.StorageProvider)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to delete file %s: %v", record.ID, err))
+				continue
+			}
+			if !deleted {
+				continue
+			}
+		}
+		report.MissingObjects = append(report.MissingObjects, CollectedFile{
+			ID:         record.ID,
+			StorageKey: record.StorageKey,
+			SizeBytes:  record.SizeBytes,
+		})
+	}
+	return nil
+}
+
+// collectTemporaryFiles removes temporary files of interrupted writes older than the grace period
+func (s *StorageService) collectTemporaryFiles(ctx context.Context, report *GarbageCollectionReport) {
+	cleaner, ok := s.storageAdapter.(temporaryFileCleaner)
+	if !ok {
+		return
+	}
+
+	removedFiles, removedBytes, err := cleaner.RemoveStaleTemporaryFiles(ctx, time.Now().Add(-orphanGracePeriod), report.DryRun)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	report.TemporaryFiles += removedFiles
+	report.FreedBytes += removedBytes
+}
+
+// findUnreferencedFiles compares the file records and the previews with the stored objects.
+// Records of other storage providers are ignored, and objects younger than the grace period are kept,
+// since their presigned upload may still be completed. A missing preview does not make its file missing.
+func findUnreferencedFiles(records []db.GetFileStorageKeysRow, previewKeys []string, objects []StoredObject, storageProvider string, now time.Time) ([]StoredObject, []db.GetFileStorageKeysRow) {
+	referencedKeys := make(map[string]bool, len(records)+len(previewKeys))
+	for _, record := range records {
+		referencedKeys[record.StorageKey] = true
+	}
+	for _, previewKey := range previewKeys {
+		referencedKeys[previewKey] = true
+	}
+
+	storedKeys := make(map[string]bool, len(objects))
+	orphanedObjects := []StoredObject{}
+	for _, object := range objects {
+		storedKeys[object.StorageKey] = true
+		if !referencedKeys[object.StorageKey] && now.Sub(object.LastModified) > orphanGracePeriod {
+			orphanedObjects = append(orphanedObjects, object)
+		}
+	}
+
+	missingObjects := []db.GetFileStorageKeysRow{}
+	for _, record := range records {
+		if record.StorageProvider == storageProvider && !storedKeys[record.StorageKey] {
+			missingObjects = append(missingObjects, record)
+		}
+	}
+
+	return orphanedObjects, missingObjects
+}
+
+// hardDeleteFileRecord deletes a file record if it still references the object the garbage collection checked.
+// Deduplication and encryption replace the object of live files, their records are kept.
+func (s *StorageService) hardDeleteFileRecord(ctx context.Context, fileID uuid.UUID, storageKey, storageProvider string) (bool, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	deleted, err := s.queries.HardDeleteFileWithStorageKey(ctxWithTimeout, db.HardDeleteFileWithStorageKeyParams{
+		ID:              fileID,
+		StorageKey:      storageKey,
+		StorageProvider: storageProvider,
+	})
+	if err != nil {
+		return false, err
+	}
+	if deleted == 0 {
+		log.WithField("fileId", fileID).Info("File record changed during the garbage collection, it is kept")
+	}
+	return deleted > 0, nil
+}
+
+// StartGarbageCollectionJob runs the file garbage collection every interval until the context is cancelled.
+func StartGarbageCollectionJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := StorageServiceSingleton.CollectGarbage(ctx, false); err != nil {
+					log.Error("file garbage collection failed: ", err)
+				}
+			}
+		}
+	}()
+}
+
+// fileRetentionPeriod is the time soft-deleted files are kept before they are deleted permanently
+func fileRetentionPeriod() time.Duration {
+	retention, err := time.ParseDuration(sdkUtils.GetEnv("FILE_RETENTION_PERIOD", "720h"))
+	if err != nil || retention < 0 {
+		log.Warn("Invalid FILE_RETENTION_PERIOD, using 30 days")
+		return 30 * 24 * time.Hour
+	}
+	return retention
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUnreferencedFiles(t *testing.T) {
+	now := time.Now()
+	records := []db.GetFileStorageKeysRow{
+		{ID: uuid.New(), StorageKey: "course-phase/a/cv.pdf", StorageProvider: StorageProviderSeaweedFS},
+		{ID: uuid.New(), StorageKey: "course-phase/a/lost.pdf", StorageProvider: StorageProviderSeaweedFS},
+		{ID: uuid.New(), StorageKey: "course-phase/a/local.pdf", StorageProvider: StorageProviderLocal},
+	}
+	objects := []StoredObject{
+		{StorageKey: "course-phase/a/cv.pdf", LastModified: now.Add(-48 * time.Hour)},
+		{StorageKey: "course-phase/a/abandoned.pdf", Size: 100, LastModified: now.Add(-48 * time.Hour)},
+		{StorageKey: "course-phase/a/uploading.pdf", LastModified: now.Add(-time.Minute)},
+		{StorageKey: "previews/course-phase/a/cv.pdf.jpg", LastModified: now.Add(-48 * time.Hour)},
+	}
+	previewKeys := []string{"previews/course-phase/a/cv.pdf.jpg"}
+
+	orphanedObjects, missingObjects := findUnreferencedFiles(records, previewKeys, objects, StorageProviderSeaweedFS, now)
+
+	assert.Len(t, orphanedObjects, 1, "recent objects may belong to a presigned upload in progress, previews are referenced")
+	assert.Equal(t, "course-phase/a/abandoned.pdf", orphanedObjects[0].StorageKey)
+
+	assert.Len(t, missingObjects, 1, "files of other storage providers are not expected in this storage")
+	assert.Equal(t, records[1].ID, missingObjects[0].ID)
+}
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	localOperationDownload = "download"
+	localOperationUpload   = "upload"
+)
+
+// localTemporaryFilePrefix marks files that are still being written
+const localTemporaryFilePrefix = ".upload-"
+
+var ErrInvalidSignature = errors.New("invalid or expired signature")
+
+// LocalAdapter implements the StorageAdapter interface on the local filesystem
+// Files are stored in sharded directories and accessed through signed URLs served by the core server
+type LocalAdapter struct {
+	rootDir         string
+	publicBaseURL   string
+	signingKey      []byte
+	presignDuration time.Duration
+}
+
+// localObjectMetadata is stored next to every file, since the filesystem has no place for the content type
+type localObjectMetadata struct {
+	StorageKey  string `json:"storageKey"`
+	ContentType string `json:"contentType"`
+}
+
+// NewLocalAdapter creates a new filesystem storage adapter
+// publicBaseURL is the URL under which the core API is reachable by clients, e.g. https://prompt.example.com/api
+func NewLocalAdapter(rootDir, publicBaseURL string, signingKey []byte) (*LocalAdapter, error) {
+	if len(signingKey) == 0 {
+		return nil, fmt.Errorf("signing key cannot be empty")
+	}
+
+	absoluteRootDir, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve storage directory: %w", err)
+	}
+
+	if err := os.MkdirAll(absoluteRootDir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	log.WithField("rootDir", absoluteRootDir).Info("Local storage adapter initialized")
+
+	return &LocalAdapter{
+		rootDir:         absoluteRootDir,
+		publicBaseURL:   publicBaseURL,
+		signingKey:      signingKey,
+		presignDuration: time.Minute, // Default presign duration
+	}, nil
+}
+
+// objectPath derives the location of a file from the hash of its storage key.
+// Hashing keeps user-provided keys out of the path and spreads the files over 65536 directories.
+func (l *LocalAdapter) objectPath(storageKey string) string {
+	hash := sha256.Sum256([]byte(storageKey))
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(l.rootDir, name[0:2], name[2:4], name)
+}
+
+func (l *LocalAdapter) metadataPath(storageKey string) string {
+	return l.objectPath(storageKey) + ".meta.json"
+}
+
+// Upload stores a file on the local filesystem
+func (l *LocalAdapter) Upload(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
+	if storageKey == "" {
+		return nil, fmt.Errorf("storage key cannot be empty")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path := l.objectPath(storageKey)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	size, err := writeFileAtomically(path, reader)
+	if err != nil {
+		log.WithError(err).WithField("key", storageKey).Error("Failed to write file to local storage")
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+
+	metadata, err := json.Marshal(localObjectMetadata{StorageKey: storageKey, ContentType: contentType})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writeFileAtomically(l.metadataPath(storageKey), bytes.NewReader(metadata)); err != nil {
+		_ = os.Remove(path)
+		log.WithError(err).WithField("key", storageKey).Error("Failed to write file metadata to local storage")
+		return nil, fmt.Errorf("failed to write file metadata: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"key":  storageKey,
+		"size": size,
+	}).Info("File uploaded to local storage successfully")
+
+	return &UploadResult{
+		StorageKey: storageKey,
+		PublicURL:  path, // Note: This is not a real URL, just the location on disk
+		Size:       size,
+	}, nil
+}
+
+// writeFileAtomically writes into a temporary file of the same directory and renames it afterwards,
+// so that readers never see a partially written file
+func writeFileAtomically(path string, reader io.Reader) (int64, error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), localTemporaryFilePrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		// no-op after a successful rename
+		_ = os.Remove(tmpPath)
+	}()
+
+	size, err := io.Copy(tmpFile, reader)
+	if err != nil {
+		tmpFile.Close()
+		return 0, err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return 0, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// Download retrieves a file from the local filesystem
+func (l *LocalAdapter) Download(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+	file, err := os.Open(l.objectPath(storageKey))
+	if err != nil {
+		log.WithError(err).WithField("key", storageKey).Error("Failed to read file from local storage")
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return file, nil
+}
+
+// Delete removes a file from the local filesystem
+func (l *LocalAdapter) Delete(ctx context.Context, storageKey string) error {
+	for _, path := range []string{l.objectPath(storageKey), l.metadataPath(storageKey)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.WithError(err).WithField("key", storageKey).Error("Failed to delete file from local storage")
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
+	}
+
+	log.WithField("key", storageKey).Info("File deleted from local storage successfully")
+	return nil
+}
+
+// GetUploadURL returns a signed URL of the core server for uploading a file
+func (l *LocalAdapter) GetUploadURL(ctx context.Context, storageKey string, contentType string, ttl int) (string, error) {
+	return l.signedURL(localOperationUpload, storageKey, contentType, ttl), nil
+}
+
+// GetURL returns a signed URL of the core server for accessing the file
+func (l *LocalAdapter) GetURL(ctx context.Context, storageKey string, ttl int) (string, error) {
+	return l.signedURL(localOperationDownload, storageKey, "", ttl), nil
+}
+
+// GetMetadata retrieves metadata about a file
+func (l *LocalAdapter) GetMetadata(ctx context.Context, storageKey string) (*FileMetadata, error) {
+	info, err := os.Stat(l.objectPath(storageKey))
+	if err != nil {
+		log.WithError(err).WithField("key", storageKey).Error("Failed to get file metadata from local storage")
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	content, err := os.ReadFile(l.metadataPath(storageKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	var metadata localObjectMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	return &FileMetadata{
+		StorageKey:  storageKey,
+		Filename:    filepath.Base(storageKey),
+		ContentType: metadata.ContentType,
+		Size:        info.Size(),
+	}, nil
+}
+
+// ListObjects walks the storage directory, the storage keys are read from the metadata files
+func (l *LocalAdapter) ListObjects(ctx context.Context, prefix string) ([]StoredObject, error) {
+	var objects []StoredObject
+	err := filepath.WalkDir(l.rootDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".meta.json") {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var metadata localObjectMetadata
+		if err := json.Unmarshal(content, &metadata); err != nil {
+			log.WithError(err).WithField("path", path).Warn("Skipping unreadable metadata file in local storage")
+			return nil
+		}
+		if !strings.HasPrefix(metadata.StorageKey, prefix) {
+			return nil
+		}
+
+		info, err := os.Stat(strings.TrimSuffix(path, ".meta.json"))
+		if errors.Is(err, os.ErrNotExist) {
+			// the file was deleted in the meantime
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, StoredObject{
+			StorageKey:   metadata.StorageKey,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).WithField("prefix", prefix).Error("Failed to list files in local storage")
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return objects, nil
+}
+
+// RemoveStaleTemporaryFiles deletes temporary files last modified before olderThan.
+// They are left behind if the server is killed during a write, and ListObjects does not return them.
+// During a dry run the files are only counted.
+func (l *LocalAdapter) RemoveStaleTemporaryFiles(ctx context.Context, olderThan time.Time, dryRun bool) (int, int64, error) {
+	removedFiles := 0
+	var removedBytes int64
+	err := filepath.WalkDir(l.rootDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), localTemporaryFilePrefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			// the write finished in the meantime
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !info.ModTime().Before(olderThan) {
+			return nil
+		}
+
+		if !dryRun {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+		removedFiles++
+		removedBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to remove temporary files from local storage")
+		return removedFiles, removedBytes, fmt.Errorf("failed to remove temporary files: %w", err)
+	}
+
+	return removedFiles, removedBytes, nil
+}
+
+func (l *LocalAdapter) signedURL(operation, storageKey, contentType string, ttl int) string {
+	duration := l.presignDuration
+	if ttl > 0 {
+		duration = time.Duration(ttl) * time.Second
+	}
+	expires := strconv.FormatInt(time.Now().Add(duration).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("key", storageKey)
+	query.Set("expires", expires)
+	if contentType != "" {
+		query.Set("contentType", contentType)
+	}
+	query.Set("signature", l.sign(operation, storageKey, contentType, expires))
+
+	return fmt.Sprintf("%s/storage/local/%s?%s", l.publicBaseURL, operation, query.Encode())
+}
+
+func (l *LocalAdapter) sign(operation, storageKey, contentType, expires string) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	// the operation is signed as well, so a download URL cannot be used for uploads
+	mac.Write([]byte(operation + "\n" + storageKey + "\n" + contentType + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks that a signed URL was issued by this adapter and has not expired yet
+func (l *LocalAdapter) VerifySignature(operation, storageKey, contentType, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidSignature
+	}
+
+	expected := l.sign(operation, storageKey, contentType, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Multipart uploads are not supported by the local storage, clients fall back to a single presigned upload.
+
+func (l *LocalAdapter) CreateMultipartUpload(ctx context.Context, storageKey string, contentType string) (string, error) {
+	return "", ErrMultipartUploadNotSupported
+}
+
+func (l *LocalAdapter) GetUploadPartURL(ctx context.Context, storageKey string, uploadID string, partNumber int32, ttl int) (string, error) {
+	return "", ErrMultipartUploadNotSupported
+}
+
+func (l *LocalAdapter) CompleteMultipartUpload(ctx context.Context, storageKey string, uploadID string, parts []UploadedPart) error {
+	return ErrMultipartUploadNotSupported
+}
+
+func (l *LocalAdapter) AbortMultipartUpload(ctx context.Context, storageKey string, uploadID string) error {
+	return ErrMultipartUploadNotSupported
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLocalAdapter(t *testing.T) *LocalAdapter {
+	adapter, err := NewLocalAdapter(t.TempDir(), "http://localhost:8080/api", []byte("test-signing-key"))
+	assert.NoError(t, err)
+	return adapter
+}
+
+func TestLocalAdapter_UploadDownloadDelete(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTestLocalAdapter(t)
+	storageKey := "course-phase/4179d58a-d00d-4fa7-94a5-397bc69fab02/cv.pdf"
+
+	result, err := adapter.Upload(ctx, storageKey, "application/pdf", strings.NewReader("%PDF-1.7 test"))
+	assert.NoError(t, err)
+	assert.Equal(t, storageKey, result.StorageKey)
+	assert.Equal(t, int64(13), result.Size)
+
+	// files are sharded by the hash of the key and no temporary files are left behind
+	path := adapter.objectPath(storageKey)
+	assert.Equal(t, adapter.rootDir, filepath.Dir(filepath.Dir(filepath.Dir(path))))
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	metadata, err := adapter.GetMetadata(ctx, storageKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/pdf", metadata.ContentType)
+	assert.Equal(t, int64(13), metadata.Size)
+
+	reader, err := adapter.Download(ctx, storageKey)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	reader.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7 test", string(content))
+
+	assert.NoError(t, adapter.Delete(ctx, storageKey))
+	_, err = adapter.Download(ctx, storageKey)
+	assert.Error(t, err)
+	assert.NoError(t, adapter.Delete(ctx, storageKey), "deleting a missing file is not an error")
+}
+
+func TestLocalAdapter_ListObjects(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTestLocalAdapter(t)
+	for _, storageKey := range []string{"course-phase/a/cv.pdf", "course-phase/b/cv.pdf", "profile.png"} {
+		_, err := adapter.Upload(ctx, storageKey, "application/pdf", strings.NewReader("content"))
+		assert.NoError(t, err)
+	}
+
+	objects, err := adapter.ListObjects(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 3)
+
+	objects, err = adapter.ListObjects(ctx, "course-phase/a/")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "course-phase/a/cv.pdf", objects[0].StorageKey)
+	assert.Equal(t, int64(7), objects[0].Size)
+	assert.False(t, objects[0].LastModified.IsZero())
+}
+
+func TestLocalAdapter_RemoveStaleTemporaryFiles(t *testing.T) {
+	ctx := context.Background()
+	adapter := newTestLocalAdapter(t)
+	_, err := adapter.Upload(ctx, "course-phase/a/cv.pdf", "application/pdf", strings.NewReader("content"))
+	assert.NoError(t, err)
+
+	directory := filepath.Dir(adapter.objectPath("course-phase/a/cv.pdf"))
+	stalePath := filepath.Join(directory, localTemporaryFilePrefix+"stale")
+	freshPath := filepath.Join(directory, localTemporaryFilePrefix+"fresh")
+	assert.NoError(t, os.WriteFile(stalePath, []byte("partial"), 0o600))
+	assert.NoError(t, os.WriteFile(freshPath, []byte("partial"), 0o600))
+	staleTime := time.Now().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(stalePath, staleTime, staleTime))
+
+	objects, err := adapter.ListObjects(ctx, "")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1, "temporary files are not stored objects")
+
+	removedFiles, removedBytes, err := adapter.RemoveStaleTemporaryFiles(ctx, time.Now().Add(-24*time.Hour), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removedFiles)
+	assert.Equal(t, int64(7), removedBytes)
+	assert.FileExists(t, stalePath, "a dry run keeps the file")
+
+	removedFiles, _, err = adapter.RemoveStaleTemporaryFiles(ctx, time.Now().Add(-24*time.Hour), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removedFiles)
+	assert.NoFileExists(t, stalePath)
+	assert.FileExists(t, freshPath, "a write may still be in progress")
+
+	_, err = adapter.Download(ctx, "course-phase/a/cv.pdf")
+	assert.NoError(t, err)
+}
+
+func TestConfiguredStorageProvider_KeepsTheCase(t *testing.T) {
+	t.Setenv("STORAGE_PROVIDER", "S3")
+	assert.Equal(t, "S3", configuredStorageProvider(), "existing files store the provider unchanged")
+	assert.False(t, isLocalStorageProvider(configuredStorageProvider()))
+
+	t.Setenv("STORAGE_PROVIDER", "Local")
+	assert.True(t, isLocalStorageProvider(configuredStorageProvider()))
+}
+
+func TestLocalAdapter_KeysCannotEscapeTheRootDirectory(t *testing.T) {
+	adapter := newTestLocalAdapter(t)
+	path := adapter.objectPath("../../etc/passwd")
+	assert.True(t, strings.HasPrefix(path, adapter.rootDir+string(filepath.Separator)))
+}
+
+func TestLocalAdapter_VerifySignature(t *testing.T) {
+	adapter := newTestLocalAdapter(t)
+	storageKey := "course-phase/abc/file.pdf"
+
+	downloadURL, err := adapter.GetURL(context.Background(), storageKey, 30)
+	assert.NoError(t, err)
+	parsedURL, err := url.Parse(downloadURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/storage/local/download", parsedURL.Path)
+
+	query := parsedURL.Query()
+	assert.NoError(t, adapter.VerifySignature(localOperationDownload, storageKey, "", query.Get("expires"), query.Get("signature")))
+
+	assert.ErrorIs(t, adapter.VerifySignature(localOperationDownload, "course-phase/abc/other.pdf", "", query.Get("expires"), query.Get("signature")), ErrInvalidSignature)
+	assert.ErrorIs(t, adapter.VerifySignature(localOperationUpload, storageKey, "", query.Get("expires"), query.Get("signature")), ErrInvalidSignature, "a download URL cannot be used for uploads")
+	assert.ErrorIs(t, adapter.VerifySignature(localOperationDownload, storageKey, "", "9999999999", query.Get("signature")), ErrInvalidSignature)
+
+	expiredSignature := adapter.sign(localOperationDownload, storageKey, "", "1000")
+	assert.ErrorIs(t, adapter.VerifySignature(localOperationDownload, storageKey, "", "1000", expiredSignature), ErrInvalidSignature)
+}
+
+func TestLocalStorageRouter_UploadAndDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	adapter := newTestLocalAdapter(t)
+	router := gin.New()
+	setupLocalStorageRouter(router.Group("/api"), adapter, 16)
+	storageKey := "course-phase/abc/notes.txt"
+
+	uploadURL, err := adapter.GetUploadURL(context.Background(), storageKey, "text/plain", 60)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, uploadURL, strings.NewReader("hello"))
+	req.Header.Set("Content-Type", "application/pdf")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "the signed content type has to be used")
+
+	req = httptest.NewRequest(http.MethodPut, uploadURL, strings.NewReader("this body is too large"))
+	req.Header.Set("Content-Type", "text/plain")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+	req = httptest.NewRequest(http.MethodPut, uploadURL, strings.NewReader("hello"))
+	req.Header.Set("Content-Type", "text/plain")
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	downloadURL, err := adapter.GetURL(context.Background(), storageKey, 30)
+	assert.NoError(t, err)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, downloadURL, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "hello", resp.Body.String())
+	assert.Equal(t, "text/plain", resp.Header().Get("Content-Type"))
+
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, strings.Replace(downloadURL, "signature=", "signature=x", 1), nil))
+	assert.Equal(t, http.StatusForbidden, resp.Code)
+}
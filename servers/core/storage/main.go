@@ -1,19 +1,29 @@
 package storage
 
 import (
+	"crypto/rand"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
 	log "github.com/sirupsen/logrus"
 )
 
-// InitStorageModule initializes the storage module with service only (no public routes)
-func InitStorageModule(queries db.Queries, conn *pgxpool.Pool) error {
+// InitStorageModule initializes the storage module with the configured storage backend.
+// The local storage backend and the file encryption additionally expose routes, which serve their signed URLs.
+func InitStorageModule(api *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) error {
 	// Get storage configuration from environment
-	maxFileSizeMB := int64(50) // Default 50MB
+	maxFileSizeMB, err := strconv.ParseInt(strings.TrimSpace(sdkUtils.GetEnv("MAX_FILE_UPLOAD_SIZE_MB", "50")), 10, 64)
+	if err != nil || maxFileSizeMB <= 0 {
+		return fmt.Errorf("invalid MAX_FILE_UPLOAD_SIZE_MB: %s", sdkUtils.GetEnv("MAX_FILE_UPLOAD_SIZE_MB", ""))
+	}
 
 	// Parse allowed file types
 	allowedTypesStr := sdkUtils.GetEnv("ALLOWED_FILE_TYPES", "")
@@ -25,24 +35,145 @@ func InitStorageModule(queries db.Queries, conn *pgxpool.Pool) error {
 		}
 	}
 
+	var adapter StorageAdapter
+	storageProvider := configuredStorageProvider()
+	if isLocalStorageProvider(storageProvider) {
+		localAdapter, err := newLocalAdapterFromEnv("")
+		if err != nil {
+			return err
+		}
+		setupLocalStorageRouter(api, localAdapter, maxFileSizeMB*1024*1024)
+		adapter = localAdapter
+	} else {
+		s3Adapter, err := newS3AdapterFromEnv("")
+		if err != nil {
+			return err
+		}
+		adapter = s3Adapter
+	}
+
+	scanner, err := newMalwareScannerFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// Create storage service singleton
+	StorageServiceSingleton = NewStorageService(queries, conn, adapter, maxFileSizeMB, allowedTypes, scanner)
+	if err := configureStorageMigrationSource(api, StorageServiceSingleton, storageProvider, maxFileSizeMB*1024*1024); err != nil {
+		return err
+	}
+	encryption, err := newFileEncryptionFromEnv()
+	if err != nil {
+		return err
+	}
+	if encryption != nil {
+		StorageServiceSingleton.encryption = encryption
+		setupEncryptedFileRouter(api, encryption)
+	} else {
+		log.Warn("FILE_ENCRYPTION_MASTER_KEY is not set, uploaded files are stored unencrypted")
+	}
+	pdfPreviews := false
+	if pdfRenderer := newPDFRendererFromEnv(); pdfRenderer != nil {
+		StorageServiceSingleton.pdfRenderer = pdfRenderer
+		pdfPreviews = true
+	}
+	setupStorageRouter(api, keycloakTokenVerifier.KeycloakMiddleware, permissionValidation.CheckAccessControlByRole)
+	setupCoursePhaseFileRouter(api, keycloakTokenVerifier.KeycloakMiddleware, checkAccessControlByIDWrapper)
+
+	log.WithFields(log.Fields{
+		"storageProvider": storageProvider,
+		"fileScanner":     scanner.Name(),
+		"pdfPreviews":     pdfPreviews,
+		"migrationSource": StorageServiceSingleton.migration.sourceProvider,
+		"fileEncryption":  encryption != nil,
+		"maxFileSizeMB":   maxFileSizeMB,
+		"courseQuotaMB":   defaultCourseStorageQuotaBytes() / 1024 / 1024,
+		"allowedTypes":    allowedTypes,
+	}).Info("Storage service initialized")
+
+	return nil
+}
+
+func checkAccessControlByIDWrapper(allowedRoles ...string) gin.HandlerFunc {
+	return permissionValidation.CheckAccessControlByID(permissionValidation.CheckCoursePhasePermission, "uuid", allowedRoles...)
+}
+
+// newPDFRendererFromEnv returns nil if PDF previews are disabled or the renderer is not installed
+func newPDFRendererFromEnv() *CommandPDFRenderer {
+	command := strings.TrimSpace(sdkUtils.GetEnv("PDF_PREVIEW_COMMAND", "pdftoppm"))
+	if strings.EqualFold(command, "none") {
+		return nil
+	}
+
+	renderer := NewCommandPDFRenderer(command)
+	if renderer == nil {
+		log.WithField("command", command).Warn("PDF preview renderer not found, PDFs get no preview")
+	}
+	return renderer
+}
+
+// storageMigrationSourceEnvPrefix prefixes the variables of the storage that files are migrated from,
+// e.g. STORAGE_MIGRATION_SOURCE_S3_BUCKET
+const storageMigrationSourceEnvPrefix = "STORAGE_MIGRATION_SOURCE_"
+
+// configureStorageMigrationSource sets up the storage that files are migrated from, if STORAGE_MIGRATION_SOURCE_PROVIDER is set.
+// Files that are not migrated yet are still served from there.
+func configureStorageMigrationSource(api *gin.RouterGroup, service *StorageService, storageProvider string, maxFileSize int64) error {
+	// compared with the storage provider of the files as it is set, like STORAGE_PROVIDER
+	sourceProvider := sdkUtils.GetEnv(storageMigrationSourceEnvPrefix+"PROVIDER", "")
+	if sourceProvider == "" {
+		return nil
+	}
+	if sourceProvider == storageProvider {
+		return fmt.Errorf("STORAGE_MIGRATION_SOURCE_PROVIDER must differ from STORAGE_PROVIDER %s", storageProvider)
+	}
+
+	var source StorageAdapter
+	if isLocalStorageProvider(sourceProvider) {
+		localAdapter, err := newLocalAdapterFromEnv(storageMigrationSourceEnvPrefix)
+		if err != nil {
+			return err
+		}
+		// the signed URLs of files that are not migrated yet point to the local storage routes
+		setupLocalStorageRouter(api, localAdapter, maxFileSize)
+		source = localAdapter
+	} else {
+		s3Adapter, err := newS3AdapterFromEnv(storageMigrationSourceEnvPrefix)
+		if err != nil {
+			return err
+		}
+		source = s3Adapter
+	}
+
+	service.migration.sourceProvider = sourceProvider
+	service.migration.source = source
+	log.WithFields(log.Fields{
+		"sourceProvider": sourceProvider,
+		"targetProvider": storageProvider,
+	}).Info("Storage migration source configured")
+	return nil
+}
+
+// newS3AdapterFromEnv reads the S3 variables with the given prefix, which is empty for the configured storage
+func newS3AdapterFromEnv(envPrefix string) (*S3Adapter, error) {
 	// S3 configuration (works with AWS S3, SeaweedFS S3 gateway, MinIO, etc.)
-	bucket := sdkUtils.GetEnv("S3_BUCKET", "prompt-files")
-	region := sdkUtils.GetEnv("S3_REGION", "us-east-1")
-	endpoint := sdkUtils.GetEnv("S3_ENDPOINT", "http://localhost:8334") // Empty for AWS S3, set for SeaweedFS/MinIO
-	publicEndpoint := sdkUtils.GetEnv("S3_PUBLIC_ENDPOINT", "")
-	accessKey := sdkUtils.GetEnv("S3_ACCESS_KEY", "")
-	secretKey := sdkUtils.GetEnv("S3_SECRET_KEY", "")
-	forcePathStyle := sdkUtils.GetEnv("S3_FORCE_PATH_STYLE", "true") == "true" // Required for SeaweedFS/MinIO
+	bucket := sdkUtils.GetEnv(envPrefix+"S3_BUCKET", "prompt-files")
+	region := sdkUtils.GetEnv(envPrefix+"S3_REGION", "us-east-1")
+	endpoint := sdkUtils.GetEnv(envPrefix+"S3_ENDPOINT", "http://localhost:8334") // Empty for AWS S3, set for SeaweedFS/MinIO
+	publicEndpoint := sdkUtils.GetEnv(envPrefix+"S3_PUBLIC_ENDPOINT", "")
+	accessKey := sdkUtils.GetEnv(envPrefix+"S3_ACCESS_KEY", "")
+	secretKey := sdkUtils.GetEnv(envPrefix+"S3_SECRET_KEY", "")
+	forcePathStyle := sdkUtils.GetEnv(envPrefix+"S3_FORCE_PATH_STYLE", "true") == "true" // Required for SeaweedFS/MinIO
 
 	lowerEndpoint := strings.ToLower(endpoint)
 	isLocalEndpoint := strings.Contains(lowerEndpoint, "localhost") || strings.Contains(lowerEndpoint, "127.0.0.1")
 	if !isLocalEndpoint && (accessKey == "" || secretKey == "") {
-		return fmt.Errorf("missing S3 credentials for non-local endpoint: set S3_ACCESS_KEY and S3_SECRET_KEY")
+		return nil, fmt.Errorf("missing S3 credentials for non-local endpoint: set %sS3_ACCESS_KEY and %sS3_SECRET_KEY", envPrefix, envPrefix)
 	}
 
 	adapter, err := NewS3Adapter(bucket, region, endpoint, publicEndpoint, accessKey, secretKey, forcePathStyle)
 	if err != nil {
-		return fmt.Errorf("failed to create S3 adapter: %w", err)
+		return nil, fmt.Errorf("failed to create S3 adapter: %w", err)
 	}
 
 	log.WithFields(log.Fields{
@@ -52,13 +183,44 @@ func InitStorageModule(queries db.Queries, conn *pgxpool.Pool) error {
 		"forcePathStyle": forcePathStyle,
 	}).Info("Initialized S3-compatible storage adapter")
 
-	// Create storage service singleton
-	StorageServiceSingleton = NewStorageService(queries, conn, adapter, maxFileSizeMB, allowedTypes)
+	return adapter, nil
+}
 
-	log.WithFields(log.Fields{
-		"maxFileSizeMB": maxFileSizeMB,
-		"allowedTypes":  allowedTypes,
-	}).Info("Storage service initialized")
+func newLocalAdapterFromEnv(envPrefix string) (*LocalAdapter, error) {
+	rootDir := sdkUtils.GetEnv(envPrefix+"LOCAL_STORAGE_DIR", "./files")
+	publicBaseURL := strings.TrimSuffix(sdkUtils.GetEnv(envPrefix+"LOCAL_STORAGE_PUBLIC_URL", "http://localhost:8080/api"), "/")
 
-	return nil
+	signingKey := []byte(sdkUtils.GetEnv(envPrefix+"LOCAL_STORAGE_SIGNING_KEY", ""))
+	if len(signingKey) == 0 {
+		// signed URLs become invalid with every restart, which is only acceptable for local development
+		log.Warn(envPrefix + "LOCAL_STORAGE_SIGNING_KEY is not set, using a random key")
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+	}
+
+	adapter, err := NewLocalAdapter(rootDir, publicBaseURL, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local storage adapter: %w", err)
+	}
+	return adapter, nil
+}
+
+func newMalwareScannerFromEnv() (MalwareScanner, error) {
+	switch scanner := strings.ToLower(strings.TrimSpace(sdkUtils.GetEnv("FILE_SCANNER", FileScannerNone))); scanner {
+	case FileScannerNone:
+		log.Warn("FILE_SCANNER is not set, uploaded files are not scanned for malware")
+		return NoopScanner{}, nil
+	case FileScannerClamAV:
+		address := sdkUtils.GetEnv("CLAMAV_ADDRESS", "localhost:3310")
+		timeout, err := time.ParseDuration(sdkUtils.GetEnv("CLAMAV_TIMEOUT", "60s"))
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid CLAMAV_TIMEOUT: %s", sdkUtils.GetEnv("CLAMAV_TIMEOUT", ""))
+		}
+		log.WithField("address", address).Info("Uploaded files are scanned with ClamAV")
+		return NewClamAVScanner(address, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown file scanner %s, use %s or %s", scanner, FileScannerNone, FileScannerClamAV)
+	}
 }
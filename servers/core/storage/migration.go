@@ -0,0 +1,312 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+const storageMigrationBatchSize = 100
+
+var (
+	ErrStorageMigrationRunning       = errors.New("a storage migration is already running")
+	ErrStorageMigrationNotConfigured = errors.New("no storage migration source is configured, set STORAGE_MIGRATION_SOURCE_PROVIDER")
+	ErrStorageMigrationVerification  = errors.New("the copied object does not match the original")
+)
+
+// StorageMigrationReport shows the progress of a running migration, or the result of the last one
+type StorageMigrationReport struct {
+	SourceProvider string     `json:"sourceProvider"`
+	TargetProvider string     `json:"targetProvider"`
+	Running        bool       `json:"running"`
+	StartedAt      time.Time  `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
+	// TotalObjects and TotalFiles were stored with the source provider when the migration started,
+	// deduplicated files share their object
+	TotalObjects    int64                 `json:"totalObjects"`
+	TotalFiles      int64                 `json:"totalFiles"`
+	MigratedObjects int64                 `json:"migratedObjects"`
+	MigratedFiles   int64                 `json:"migratedFiles"`
+	MigratedBytes   int64                 `json:"migratedBytes"`
+	FailedObjects   []FailedStorageObject `json:"failedObjects"`
+	Errors          []string              `json:"errors"`
+}
+
+type FailedStorageObject struct {
+	StorageKey string `json:"storageKey"`
+	Error      string `json:"error"`
+}
+
+// storageMigrator copies the objects of the files from the migration source to the configured storage.
+// Files that are not migrated yet are read from the source, so the server keeps serving them during the migration.
+type storageMigrator struct {
+	sourceProvider string
+	// source is nil if no migration is configured
+	source StorageAdapter
+
+	running sync.Mutex
+	mu      sync.Mutex
+	report  *StorageMigrationReport
+}
+
+// adapterFor returns the adapter of the storage provider a file is stored with
+func (s *StorageService) adapterFor(storageProvider string) StorageAdapter {
+	if s.migration.source != nil && storageProvider == s.migration.sourceProvider {
+		return s.migration.source
+	}
+	return s.storageAdapter
+}
+
+// StartStorageMigration migrates the files in the background, the progress is reported by GetStorageMigrationReport.
+// The migration is resumable: it only copies files still stored with the source provider,
+// so failed objects are retried by starting it again.
+func (s *StorageService) StartStorageMigration() (*StorageMigrationReport, error) {
+	ctx := context.Background()
+	if err := s.beginStorageMigration(ctx); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		defer s.migration.running.Unlock()
+		s.runStorageMigration(ctx)
+	}()
+	return s.GetStorageMigrationReport(), nil
+}
+
+// MigrateStorage migrates the files and returns when the migration is done
+func (s *StorageService) MigrateStorage(ctx context.Context) (*StorageMigrationReport, error) {
+	if err := s.beginStorageMigration(ctx); err != nil {
+		return nil, err
+	}
+	defer s.migration.running.Unlock()
+
+	s.runStorageMigration(ctx)
+	return s.GetStorageMigrationReport(), nil
+}
+
+// GetStorageMigrationReport returns nil if no migration was started since the server started
+func (s *StorageService) GetStorageMigrationReport() *StorageMigrationReport {
+	s.migration.mu.Lock()
+	defer s.migration.mu.Unlock()
+
+	if s.migration.report == nil {
+		return nil
+	}
+	report := *s.migration.report
+	report.FailedObjects = slices.Clone(report.FailedObjects)
+	report.Errors = slices.Clone(report.Errors)
+	return &report
+}
+
+func (s *StorageService) updateStorageMigrationReport(update func(report *StorageMigrationReport)) {
+	s.migration.mu.Lock()
+	defer s.migration.mu.Unlock()
+	update(s.migration.report)
+}
+
+// beginStorageMigration locks the migration, the caller has to unlock it once the migration is done
+func (s *StorageService) beginStorageMigration(ctx context.Context) error {
+	if s.migration.source == nil {
+		return ErrStorageMigrationNotConfigured
+	}
+	if !s.migration.running.TryLock() {
+		return ErrStorageMigrationRunning
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	counts, err := s.queries.CountFilesByStorageProvider(ctxWithTimeout, s.migration.sourceProvider)
+	if err != nil {
+		s.migration.running.Unlock()
+		return fmt.Errorf("failed to count files to migrate: %w", err)
+	}
+
+	s.migration.mu.Lock()
+	s.migration.report = &StorageMigrationReport{
+		SourceProvider: s.migration.sourceProvider,
+		TargetProvider: configuredStorageProvider(),
+		Running:        true,
+		StartedAt:      time.Now(),
+		TotalObjects:   counts.ObjectCount,
+		TotalFiles:     counts.FileCount,
+		FailedObjects:  []FailedStorageObject{},
+		Errors:         []string{},
+	}
+	s.migration.mu.Unlock()
+	return nil
+}
+
+func (s *StorageService) runStorageMigration(ctx context.Context) {
+	sourceProvider, targetProvider := s.migration.sourceProvider, configuredStorageProvider()
+	log.WithFields(log.Fields{
+		"sourceProvider": sourceProvider,
+		"targetProvider": targetProvider,
+	}).Info("Storage migration started")
+
+	// failed objects keep the source provider, the keyset pagination skips them
+	afterStorageKey := ""
+	for ctx.Err() == nil {
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		storageKeys, err := s.queries.GetStorageKeysToMigrate(ctxWithTimeout, db.GetStorageKeysToMigrateParams{
+			StorageProvider: sourceProvider,
+			AfterStorageKey: afterStorageKey,
+			BatchSize:       storageMigrationBatchSize,
+		})
+		cancel()
+		if err != nil {
+			s.updateStorageMigrationReport(func(report *StorageMigrationReport) {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to get files to migrate: %v", err))
+			})
+			break
+		}
+		if len(storageKeys) == 0 {
+			break
+		}
+
+		for _, storageKey := range storageKeys {
+			afterStorageKey = storageKey.StorageKey
+			size, migratedFiles, err := s.migrateStoredObject(ctx, storageKey.StorageKey, sourceProvider, targetProvider)
+			s.updateStorageMigrationReport(func(report *StorageMigrationReport) {
+				if err != nil {
+					report.FailedObjects = append(report.FailedObjects, FailedStorageObject{StorageKey: storageKey.StorageKey, Error: err.Error()})
+					return
+				}
+				report.MigratedObjects++
+				report.MigratedFiles += migratedFiles
+				report.MigratedBytes += size
+			})
+			if err != nil {
+				log.WithError(err).WithField("storageKey", storageKey.StorageKey).Warn("Failed to migrate stored object")
+			}
+		}
+
+		report := s.GetStorageMigrationReport()
+		log.WithFields(log.Fields{
+			"migratedObjects": report.MigratedObjects,
+			"totalObjects":    report.TotalObjects,
+			"failedObjects":   len(report.FailedObjects),
+		}).Info("Storage migration in progress")
+	}
+
+	if ctx.Err() != nil {
+		s.updateStorageMigrationReport(func(report *StorageMigrationReport) {
+			report.Errors = append(report.Errors, "the migration was cancelled: "+ctx.Err().Error())
+		})
+	}
+
+	finishedAt := time.Now()
+	s.updateStorageMigrationReport(func(report *StorageMigrationReport) {
+		report.Running = false
+		report.FinishedAt = &finishedAt
+	})
+
+	report := s.GetStorageMigrationReport()
+	log.WithFields(log.Fields{
+		"sourceProvider":  sourceProvider,
+		"targetProvider":  targetProvider,
+		"migratedObjects": report.MigratedObjects,
+		"migratedFiles":   report.MigratedFiles,
+		"migratedBytes":   report.MigratedBytes,
+		"failedObjects":   len(report.FailedObjects),
+	}).Info("Storage migration finished")
+}
+
+// migrateStoredObject copies an object together with the previews of its files, and switches the files to the target.
+// The source objects are kept, so the source can still be used if the migration is aborted.
+func (s *StorageService) migrateStoredObject(ctx context.Context, storageKey, sourceProvider, targetProvider string) (int64, int64, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	previews, err := s.queries.GetFilePreviewsByStorageKey(ctxWithTimeout, db.GetFilePreviewsByStorageKeyParams{
+		StorageKey:      storageKey,
+		StorageProvider: sourceProvider,
+	})
+	cancel()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get previews: %w", err)
+	}
+
+	for _, preview := range previews {
+		if _, err := s.copyStoredObject(ctx, preview.StorageKey); err != nil {
+			return 0, 0, fmt.Errorf("failed to copy preview %s: %w", preview.StorageKey, err)
+		}
+	}
+
+	size, err := s.copyStoredObject(ctx, storageKey)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ctxWithTimeout, cancel = db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	migratedFiles, err := s.queries.UpdateFileStorageProvider(ctxWithTimeout, db.UpdateFileStorageProviderParams{
+		TargetProvider: targetProvider,
+		StorageKey:     storageKey,
+		SourceProvider: sourceProvider,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to update storage provider: %w", err)
+	}
+	return size, migratedFiles, nil
+}
+
+// copyStoredObject copies an object from the migration source to the configured storage,
+// and verifies the copy by its size and SHA-256 hash
+func (s *StorageService) copyStoredObject(ctx context.Context, storageKey string) (int64, error) {
+	metadata, err := s.migration.source.GetMetadata(ctx, storageKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get metadata: %w", err)
+	}
+
+	reader, err := s.migration.source.Download(ctx, storageKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read object: %w", err)
+	}
+	defer reader.Close()
+
+	sourceHash := sha256.New()
+	content := &countingReader{reader: io.TeeReader(reader, sourceHash)}
+	if _, err := s.storageAdapter.Upload(ctx, storageKey, metadata.ContentType, content); err != nil {
+		return 0, fmt.Errorf("failed to write object: %w", err)
+	}
+
+	if err := s.verifyCopiedObject(ctx, storageKey, content.count, sourceHash.Sum(nil)); err != nil {
+		s.deleteRejectedUpload(ctx, storageKey)
+		return 0, err
+	}
+	return content.count, nil
+}
+
+func (s *StorageService) verifyCopiedObject(ctx context.Context, storageKey string, size int64, sourceHash []byte) error {
+	metadata, err := s.storageAdapter.GetMetadata(ctx, storageKey)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata of the copy: %w", err)
+	}
+	if metadata.Size != size {
+		return fmt.Errorf("%w: %d bytes were copied, the copy has %d bytes", ErrStorageMigrationVerification, size, metadata.Size)
+	}
+
+	reader, err := s.storageAdapter.Download(ctx, storageKey)
+	if err != nil {
+		return fmt.Errorf("failed to read the copy: %w", err)
+	}
+	defer reader.Close()
+
+	targetHash := sha256.New()
+	if _, err := io.Copy(targetHash, reader); err != nil {
+		return fmt.Errorf("failed to read the copy: %w", err)
+	}
+	if !bytes.Equal(sourceHash, targetHash.Sum(nil)) {
+		return fmt.Errorf("%w: the SHA-256 hashes differ", ErrStorageMigrationVerification)
+	}
+	return nil
+}
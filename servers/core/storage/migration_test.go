@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdapterFor(t *testing.T) {
+	target, source := &MockStorageAdapter{}, &MockStorageAdapter{}
+	service := &StorageService{storageAdapter: target}
+	assert.Same(t, target, service.adapterFor(StorageProviderSeaweedFS), "without a migration every file uses the configured storage")
+
+	service.migration.sourceProvider = StorageProviderSeaweedFS
+	service.migration.source = source
+	assert.Same(t, source, service.adapterFor(StorageProviderSeaweedFS))
+	assert.Same(t, target, service.adapterFor("s3"))
+}
+
+func TestCopyStoredObject(t *testing.T) {
+	sourceObjects := map[string][]byte{"course-phase/cv.pdf": []byte("%PDF-1.4\ncv")}
+	targetObjects := map[string][]byte{}
+	service := &StorageService{storageAdapter: newInMemoryStorageAdapter(targetObjects)}
+	service.migration.source = newInMemoryStorageAdapter(sourceObjects)
+
+	size, err := service.copyStoredObject(context.Background(), "course-phase/cv.pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(sourceObjects["course-phase/cv.pdf"])), size)
+	assert.Equal(t, sourceObjects["course-phase/cv.pdf"], targetObjects["course-phase/cv.pdf"])
+
+	_, err = service.copyStoredObject(context.Background(), "course-phase/missing.pdf")
+	assert.Error(t, err)
+	assert.NotContains(t, targetObjects, "course-phase/missing.pdf")
+}
+
+func TestCopyStoredObject_VerifiesCopy(t *testing.T) {
+	sourceObjects := map[string][]byte{"course-phase/cv.pdf": []byte("%PDF-1.4\ncv")}
+	targetObjects := map[string][]byte{}
+	target := newInMemoryStorageAdapter(targetObjects)
+	// the target stores a corrupted copy with the same size
+	target.UploadFunc = func(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
+		content, err := io.ReadAll(reader)
+		content[0] ^= 1
+		targetObjects[storageKey] = content
+		return &UploadResult{StorageKey: storageKey, Size: int64(len(content))}, err
+	}
+	service := &StorageService{storageAdapter: target}
+	service.migration.source = newInMemoryStorageAdapter(sourceObjects)
+
+	_, err := service.copyStoredObject(context.Background(), "course-phase/cv.pdf")
+	assert.ErrorIs(t, err, ErrStorageMigrationVerification)
+	assert.NotContains(t, targetObjects, "course-phase/cv.pdf", "the corrupted copy is deleted")
+}
+
+func TestStartStorageMigration_NotConfigured(t *testing.T) {
+	service := &StorageService{storageAdapter: &MockStorageAdapter{}}
+	_, err := service.StartStorageMigration()
+	assert.ErrorIs(t, err, ErrStorageMigrationNotConfigured)
+	assert.Nil(t, service.GetStorageMigrationReport())
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 )
 
@@ -14,6 +15,11 @@ type MockStorageAdapter struct {
 	GetUploadURLFunc func(ctx context.Context, storageKey string, contentType string, ttl int) (string, error)
 	GetURLFunc      func(ctx context.Context, storageKey string, ttl int) (string, error)
 	GetMetadataFunc func(ctx context.Context, storageKey string) (*FileMetadata, error)
+	ListObjectsFunc func(ctx context.Context, prefix string) ([]StoredObject, error)
+	CreateMultipartUploadFunc   func(ctx context.Context, storageKey string, contentType string) (string, error)
+	GetUploadPartURLFunc        func(ctx context.Context, storageKey string, uploadID string, partNumber int32, ttl int) (string, error)
+	CompleteMultipartUploadFunc func(ctx context.Context, storageKey string, uploadID string, parts []UploadedPart) error
+	AbortMultipartUploadFunc    func(ctx context.Context, storageKey string, uploadID string) error
 }
 
 func (m *MockStorageAdapter) Upload(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
@@ -71,3 +77,70 @@ func (m *MockStorageAdapter) GetMetadata(ctx context.Context, storageKey string)
 		Filename:    "mock-file.pdf",
 	}, nil
 }
+
+func (m *MockStorageAdapter) ListObjects(ctx context.Context, prefix string) ([]StoredObject, error) {
+	if m.ListObjectsFunc != nil {
+		return m.ListObjectsFunc(ctx, prefix)
+	}
+	return []StoredObject{}, nil
+}
+
+func (m *MockStorageAdapter) CreateMultipartUpload(ctx context.Context, storageKey string, contentType string) (string, error) {
+	if m.CreateMultipartUploadFunc != nil {
+		return m.CreateMultipartUploadFunc(ctx, storageKey, contentType)
+	}
+	return "mock-upload-id", nil
+}
+
+func (m *MockStorageAdapter) GetUploadPartURL(ctx context.Context, storageKey string, uploadID string, partNumber int32, ttl int) (string, error) {
+	if m.GetUploadPartURLFunc != nil {
+		return m.GetUploadPartURLFunc(ctx, storageKey, uploadID, partNumber, ttl)
+	}
+	return fmt.Sprintf("https://mock-s3.example.com/%s?uploadId=%s&partNumber=%d", storageKey, uploadID, partNumber), nil
+}
+
+func (m *MockStorageAdapter) CompleteMultipartUpload(ctx context.Context, storageKey string, uploadID string, parts []UploadedPart) error {
+	if m.CompleteMultipartUploadFunc != nil {
+		return m.CompleteMultipartUploadFunc(ctx, storageKey, uploadID, parts)
+	}
+	return nil
+}
+
+func (m *MockStorageAdapter) AbortMultipartUpload(ctx context.Context, storageKey string, uploadID string) error {
+	if m.AbortMultipartUploadFunc != nil {
+		return m.AbortMultipartUploadFunc(ctx, storageKey, uploadID)
+	}
+	return nil
+}
+
+// newInMemoryStorageAdapter stores the uploaded objects in the given map
+func newInMemoryStorageAdapter(objects map[string][]byte) *MockStorageAdapter {
+	return &MockStorageAdapter{
+		UploadFunc: func(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				return nil, err
+			}
+			objects[storageKey] = content
+			return &UploadResult{StorageKey: storageKey, Size: int64(len(content))}, nil
+		},
+		DownloadFunc: func(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+			content, ok := objects[storageKey]
+			if !ok {
+				return nil, fmt.Errorf("object %s not found", storageKey)
+			}
+			return io.NopCloser(bytes.NewReader(content)), nil
+		},
+		DeleteFunc: func(ctx context.Context, storageKey string) error {
+			delete(objects, storageKey)
+			return nil
+		},
+		GetMetadataFunc: func(ctx context.Context, storageKey string) (*FileMetadata, error) {
+			content, ok := objects[storageKey]
+			if !ok {
+				return nil, fmt.Errorf("object %s not found", storageKey)
+			}
+			return &FileMetadata{StorageKey: storageKey, ContentType: "application/octet-stream", Size: int64(len(content))}, nil
+		},
+	}
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Limits of the S3 API: every part except the last one has at least 5 MiB, and an upload has at most 10000 parts
+const (
+	minMultipartPartSize  = 5 * 1024 * 1024
+	maxMultipartPartCount = 10000
+)
+
+var ErrInvalidMultipartUpload = errors.New("invalid multipart upload")
+
+type InitiateMultipartUploadResponse struct {
+	StorageKey string `json:"storageKey"`
+	UploadID   string `json:"uploadId"`
+	PartSize   int64  `json:"partSize"`
+	PartCount  int32  `json:"partCount"`
+}
+
+type UploadPartURL struct {
+	PartNumber int32  `json:"partNumber"`
+	UploadURL  string `json:"uploadUrl"`
+}
+
+type CompleteMultipartUploadRequest struct {
+	CreateFileFromStorageKeyRequest
+	UploadID string
+	Parts    []UploadedPart
+}
+
+// InitiateMultipartUpload starts an upload in parts for a large file.
+// The file is validated like a presigned upload, so the declared size is required to split it into parts.
+func (s *StorageService) InitiateMultipartUpload(ctx context.Context, req PresignUploadRequest) (*InitiateMultipartUploadResponse, error) {
+	if req.SizeBytes <= 0 {
+		return nil, fmt.Errorf("%w: the file size is required", ErrInvalidMultipartUpload)
+	}
+	if err := s.validatePresignUpload(ctx, req); err != nil {
+		return nil, err
+	}
+
+	partSize, partCount := multipartLayout(req.SizeBytes, multipartPartSizeBytes())
+	storageKey := newStorageKey(req.CoursePhaseID, req.Filename)
+	uploadID, err := s.storageAdapter.CreateMultipartUpload(ctx, storageKey, req.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InitiateMultipartUploadResponse{
+		StorageKey: storageKey,
+		UploadID:   uploadID,
+		PartSize:   partSize,
+		PartCount:  partCount,
+	}, nil
+}
+
+// PresignUploadParts returns upload URLs for the requested parts.
+// Parts can be requested again, e.g. to retry a failed part after its URL expired.
+func (s *StorageService) PresignUploadParts(ctx context.Context, coursePhaseID *uuid.UUID, storageKey, uploadID string, partNumbers []int32) ([]UploadPartURL, error) {
+	if err := validateMultipartUpload(storageKey, uploadID, coursePhaseID); err != nil {
+		return nil, err
+	}
+	if len(partNumbers) == 0 {
+		return nil, fmt.Errorf("%w: no parts requested", ErrInvalidMultipartUpload)
+	}
+
+	partURLs := make([]UploadPartURL, 0, len(partNumbers))
+	for _, partNumber := range partNumbers {
+		if partNumber < 1 || partNumber > maxMultipartPartCount {
+			return nil, fmt.Errorf("%w: part number %d is out of range", ErrInvalidMultipartUpload, partNumber)
+		}
+		uploadURL, err := s.storageAdapter.GetUploadPartURL(ctx, storageKey, uploadID, partNumber, presignUploadTTLSeconds())
+		if err != nil {
+			return nil, err
+		}
+		partURLs = append(partURLs, UploadPartURL{PartNumber: partNumber, UploadURL: uploadURL})
+	}
+
+	return partURLs, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts and stores the file metadata.
+// The assembled file is checked by CreateFileFromStorageKey, which also enforces the size limit and the quota.
+func (s *StorageService) CompleteMultipartUpload(ctx context.Context, req CompleteMultipartUploadRequest, uploaderUserID, uploaderEmail string) (*FileResponse, error) {
+	if err := validateMultipartUpload(req.StorageKey, req.UploadID, req.CoursePhaseID); err != nil {
+		return nil, err
+	}
+	if err := validateUploadedParts(req.Parts); err != nil {
+		return nil, err
+	}
+
+	if err := s.storageAdapter.CompleteMultipartUpload(ctx, req.StorageKey, req.UploadID, req.Parts); err != nil {
+		return nil, err
+	}
+
+	return s.CreateFileFromStorageKey(ctx, req.CreateFileFromStorageKeyRequest, uploaderUserID, uploaderEmail)
+}
+
+// AbortMultipartUpload discards an upload that will not be completed and frees its uploaded parts
+func (s *StorageService) AbortMultipartUpload(ctx context.Context, coursePhaseID *uuid.UUID, storageKey, uploadID string) error {
+	if err := validateMultipartUpload(storageKey, uploadID, coursePhaseID); err != nil {
+		return err
+	}
+
+	if err := s.storageAdapter.AbortMultipartUpload(ctx, storageKey, uploadID); err != nil {
+		return err
+	}
+
+	log.WithField("storageKey", storageKey).Info("Multipart upload aborted")
+	return nil
+}
+
+func validateMultipartUpload(storageKey, uploadID string, coursePhaseID *uuid.UUID) error {
+	if storageKey == "" || uploadID == "" {
+		return fmt.Errorf("%w: storage key and upload id are required", ErrInvalidMultipartUpload)
+	}
+	if err := validateStorageKeyScope(storageKey, coursePhaseID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMultipartUpload, err)
+	}
+	return nil
+}
+
+func validateUploadedParts(parts []UploadedPart) error {
+	if len(parts) == 0 {
+		return fmt.Errorf("%w: no uploaded parts", ErrInvalidMultipartUpload)
+	}
+
+	seen := make(map[int32]bool, len(parts))
+	for _, part := range parts {
+		if part.PartNumber < 1 || part.PartNumber > maxMultipartPartCount {
+			return fmt.Errorf("%w: part number %d is out of range", ErrInvalidMultipartUpload, part.PartNumber)
+		}
+		if seen[part.PartNumber] {
+			return fmt.Errorf("%w: part %d is listed twice", ErrInvalidMultipartUpload, part.PartNumber)
+		}
+		if strings.TrimSpace(part.ETag) == "" {
+			return fmt.Errorf("%w: part %d has no etag", ErrInvalidMultipartUpload, part.PartNumber)
+		}
+		seen[part.PartNumber] = true
+	}
+	return nil
+}
+
+// multipartLayout splits a file into parts of the preferred size,
+// the parts are enlarged if the file would otherwise need more parts than allowed
+func multipartLayout(sizeBytes, preferredPartSize int64) (int64, int32) {
+	partSize := max(preferredPartSize, minMultipartPartSize)
+	if minPartSize := (sizeBytes + maxMultipartPartCount - 1) / maxMultipartPartCount; partSize < minPartSize {
+		partSize = minPartSize
+	}
+
+	partCount := (sizeBytes + partSize - 1) / partSize
+	return partSize, int32(max(partCount, 1))
+}
+
+func multipartPartSizeBytes() int64 {
+	partSizeMB, err := strconv.ParseInt(strings.TrimSpace(sdkUtils.GetEnv("MULTIPART_UPLOAD_PART_SIZE_MB", "16")), 10, 64)
+	if err != nil || partSizeMB <= 0 {
+		log.Warn("Invalid MULTIPART_UPLOAD_PART_SIZE_MB, using 16 MB")
+		partSizeMB = 16
+	}
+	return partSizeMB * 1024 * 1024
+}
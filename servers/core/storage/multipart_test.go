@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultipartLayout(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name              string
+		sizeBytes         int64
+		preferredPartSize int64
+		expectedPartSize  int64
+		expectedPartCount int32
+	}{
+		{"single part", 3 * mb, 16 * mb, 16 * mb, 1},
+		{"last part is smaller", 40 * mb, 16 * mb, 16 * mb, 3},
+		{"exact multiple", 32 * mb, 16 * mb, 16 * mb, 2},
+		{"parts are at least 5 MiB", 12 * mb, 1 * mb, 5 * mb, 3},
+		{"parts grow to stay within the part limit", 100000 * mb, 5 * mb, 10 * mb, 10000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			partSize, partCount := multipartLayout(test.sizeBytes, test.preferredPartSize)
+			assert.Equal(t, test.expectedPartSize, partSize)
+			assert.Equal(t, test.expectedPartCount, partCount)
+		})
+	}
+}
+
+func TestValidateUploadedParts(t *testing.T) {
+	assert.NoError(t, validateUploadedParts([]UploadedPart{{PartNumber: 2, ETag: "b"}, {PartNumber: 1, ETag: "a"}}))
+
+	invalidParts := map[string][]UploadedPart{
+		"no parts":            {},
+		"part number zero":    {{PartNumber: 0, ETag: "a"}},
+		"part number too big": {{PartNumber: maxMultipartPartCount + 1, ETag: "a"}},
+		"duplicate part":      {{PartNumber: 1, ETag: "a"}, {PartNumber: 1, ETag: "b"}},
+		"missing etag":        {{PartNumber: 1, ETag: " "}},
+	}
+	for name, parts := range invalidParts {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, validateUploadedParts(parts), ErrInvalidMultipartUpload)
+		})
+	}
+}
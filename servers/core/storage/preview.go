@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // registers the decoder
+	"image/jpeg"
+	_ "image/png" // registers the decoder
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// previewMaxDimension is the maximum width and height of a preview in pixels
+	previewMaxDimension = 320
+	previewContentType  = "image/jpeg"
+	previewJPEGQuality  = 80
+	// previewMaxPixels protects against decompression bombs, larger images get no preview
+	previewMaxPixels = 25_000_000
+	// previewSamplesPerAxis limits the source pixels averaged for one preview pixel
+	previewSamplesPerAxis = 4
+	previewTimeout        = 30 * time.Second
+)
+
+var ErrPreviewNotSupported = errors.New("no preview can be generated for this file")
+
+// PDFRenderer renders the first page of a PDF document
+type PDFRenderer interface {
+	RenderFirstPage(ctx context.Context, content io.Reader) (image.Image, error)
+}
+
+// CommandPDFRenderer renders PDFs with pdftoppm of poppler-utils, which has to be installed on the server
+type CommandPDFRenderer struct {
+	command string
+}
+
+// NewCommandPDFRenderer returns nil if the command cannot be found, PDFs then get no preview
+func NewCommandPDFRenderer(command string) *CommandPDFRenderer {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return nil
+	}
+	return &CommandPDFRenderer{command: path}
+}
+
+// RenderFirstPage writes the PDF to a temporary directory, as pdftoppm needs to seek in it
+func (r *CommandPDFRenderer) RenderFirstPage(ctx context.Context, content io.Reader) (image.Image, error) {
+	dir, err := os.MkdirTemp("", "pdf-preview-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pdfPath := filepath.Join(dir, "document.pdf")
+	pdfFile, err := os.Create(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	_, err = io.Copy(pdfFile, content)
+	if closeErr := pdfFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	outputPrefix := filepath.Join(dir, "page")
+	cmd := exec.CommandContext(ctx, r.command,
+		"-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to", fmt.Sprint(2*previewMaxDimension),
+		pdfPath, outputPrefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %w: %s", err, bytes.TrimSpace(output))
+	}
+
+	page, err := os.Open(outputPrefix + ".png")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered page: %w", err)
+	}
+	defer page.Close()
+
+	img, _, err := image.Decode(page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rendered page: %w", err)
+	}
+	return img, nil
+}
+
+// generatePreview stores a thumbnail of an image or the first page of a PDF as derived object of the file.
+// Previews are optional, failures are logged and the file is served without a preview.
+func (s *StorageService) generatePreview(ctx context.Context, file db.File) {
+	if file.ScanStatus != db.FileScanStatusClean || !s.supportsPreview(file.ContentType) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, previewTimeout)
+	defer cancel()
+
+	if err := s.createPreview(ctx, file); err != nil {
+		log.WithError(err).WithField("fileId", file.ID).Warn("Failed to generate file preview")
+	}
+}
+
+func (s *StorageService) supportsPreview(contentType string) bool {
+	switch normalizeMediaType(contentType) {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	case "application/pdf":
+		return s.pdfRenderer != nil
+	default:
+		return false
+	}
+}
+
+func (s *StorageService) createPreview(ctx context.Context, file db.File) error {
+	reader, err := s.openFileContent(ctx, file)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	defer reader.Close()
+
+	var source image.Image
+	if normalizeMediaType(file.ContentType) == "application/pdf" {
+		source, err = s.pdfRenderer.RenderFirstPage(ctx, reader)
+	} else {
+		source, err = decodeImage(reader)
+	}
+	if err != nil {
+		return err
+	}
+
+	thumbnail := createThumbnail(source, previewMaxDimension)
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, thumbnail, &jpeg.Options{Quality: previewJPEGQuality}); err != nil {
+		return fmt.Errorf("failed to encode preview: %w", err)
+	}
+
+	// previews of encrypted files show their content, so they are encrypted with the same key
+	var previewContent io.Reader = bytes.NewReader(encoded.Bytes())
+	uploadContentType := previewContentType
+	if file.EncryptionKeyID.Valid {
+		dataKey, err := s.dataKey(ctx, file.EncryptionKeyID.Bytes)
+		if err != nil {
+			return err
+		}
+		if previewContent, err = newEncryptingReader(dataKey, previewContent); err != nil {
+			return err
+		}
+		uploadContentType = encryptedContentType
+	}
+
+	storageKey := previewStorageKey(file.ID)
+	// previews are stored next to their file, which may not be migrated yet
+	uploadResult, err := s.adapterFor(file.StorageProvider).Upload(ctx, storageKey, uploadContentType, previewContent)
+	if err != nil {
+		return fmt.Errorf("failed to store preview: %w", err)
+	}
+
+	ctxWithTimeout, cancelQuery := db.GetTimeoutContext(ctx)
+	defer cancelQuery()
+
+	bounds := thumbnail.Bounds()
+	_, err = s.queries.UpsertFilePreview(ctxWithTimeout, db.UpsertFilePreviewParams{
+		FileID:      file.ID,
+		StorageKey:  uploadResult.StorageKey,
+		ContentType: previewContentType,
+		Width:       int32(bounds.Dx()),
+		Height:      int32(bounds.Dy()),
+		SizeBytes:   int64(encoded.Len()),
+	})
+	if err != nil {
+		_ = s.adapterFor(file.StorageProvider).Delete(ctx, uploadResult.StorageKey)
+		return fmt.Errorf("failed to save preview: %w", err)
+	}
+	return nil
+}
+
+// deleteFilePreview removes the preview object of a file that is deleted permanently, the record is removed by the cascade
+func (s *StorageService) deleteFilePreview(ctx context.Context, fileID uuid.UUID, storageProvider string) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	preview, err := s.queries.GetFilePreview(ctxWithTimeout, fileID)
+	cancel()
+	if err != nil {
+		// most files have no preview
+		return nil
+	}
+	return s.adapterFor(storageProvider).Delete(ctx, preview.StorageKey)
+}
+
+// getPreviewURL returns an empty URL for files without a preview
+func (s *StorageService) getPreviewURL(ctx context.Context, file db.File) string {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	preview, err := s.queries.GetFilePreview(ctxWithTimeout, file.ID)
+	cancel()
+	if err != nil {
+		return ""
+	}
+
+	if file.EncryptionKeyID.Valid {
+		if s.encryption == nil {
+			return ""
+		}
+		return s.encryption.signedURL(encryptedOperationPreview, file.ID, presignDownloadTTLSeconds())
+	}
+
+	url, err := s.adapterFor(file.StorageProvider).GetURL(ctx, preview.StorageKey, presignDownloadTTLSeconds())
+	if err != nil {
+		log.WithError(err).WithField("storageKey", preview.StorageKey).Warn("Failed to generate preview URL")
+		return ""
+	}
+	return url
+}
+
+// openPreviewContent returns the decrypted preview of an encrypted file
+func (s *StorageService) openPreviewContent(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, db.FilePreview, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	file, err := s.queries.GetFileByID(ctxWithTimeout, fileID)
+	if err != nil {
+		return nil, db.FilePreview{}, fmt.Errorf("file not found: %w", err)
+	}
+	if file.ScanStatus != db.FileScanStatusClean {
+		return nil, db.FilePreview{}, ErrFileQuarantined
+	}
+	preview, err := s.queries.GetFilePreview(ctxWithTimeout, fileID)
+	if err != nil {
+		return nil, db.FilePreview{}, fmt.Errorf("preview not found: %w", err)
+	}
+
+	reader, err := s.openStoredObject(ctx, s.adapterFor(file.StorageProvider), preview.StorageKey, file.EncryptionKeyID)
+	if err != nil {
+		return nil, db.FilePreview{}, err
+	}
+	return reader, preview, nil
+}
+
+// previewStorageKey keeps previews apart from uploaded files, whose keys never start with previews/.
+// Previews belong to a file and not to its object, which deduplicated files share.
+func previewStorageKey(fileID uuid.UUID) string {
+	return "previews/" + fileID.String() + ".jpg"
+}
+
+// decodeImage checks the dimensions before decoding, so huge images are rejected without allocating them
+func decodeImage(reader io.Reader) (image.Image, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPreviewNotSupported, err)
+	}
+	if config.Width*config.Height > previewMaxPixels {
+		return nil, fmt.Errorf("%w: the image has %dx%d pixels", ErrPreviewNotSupported, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// createThumbnail scales the image down to fit into maxDimension x maxDimension pixels.
+// Every thumbnail pixel averages a grid of source pixels, transparent areas become white.
+func createThumbnail(source image.Image, maxDimension int) *image.RGBA {
+	bounds := source.Bounds()
+	sourceWidth, sourceHeight := bounds.Dx(), bounds.Dy()
+
+	width, height := sourceWidth, sourceHeight
+	if width > maxDimension || height > maxDimension {
+		if width >= height {
+			height = max(1, height*maxDimension/width)
+			width = maxDimension
+		} else {
+			width = max(1, width*maxDimension/height)
+			height = maxDimension
+		}
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaleX := float64(sourceWidth) / float64(width)
+	scaleY := float64(sourceHeight) / float64(height)
+	samplesX := min(previewSamplesPerAxis, max(1, int(scaleX)))
+	samplesY := min(previewSamplesPerAxis, max(1, int(scaleY)))
+
+	for y := range height {
+		for x := range width {
+			var r, g, b, a uint32
+			for sy := range samplesY {
+				for sx := range samplesX {
+					sourceX := bounds.Min.X + int((float64(x)+(float64(sx)+0.5)/float64(samplesX))*scaleX)
+					sourceY := bounds.Min.Y + int((float64(y)+(float64(sy)+0.5)/float64(samplesY))*scaleY)
+					pr, pg, pb, pa := source.At(sourceX, sourceY).RGBA()
+					r, g, b, a = r+pr, g+pg, b+pb, a+pa
+				}
+			}
+
+			samples := uint32(samplesX * samplesY)
+			r, g, b, a = r/samples, g/samples, b/samples, a/samples
+			// the colors are premultiplied, so blending onto white only adds the uncovered part
+			white := 0xffff - a
+			thumbnail.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + white) >> 8),
+				G: uint8((g + white) >> 8),
+				B: uint8((b + white) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return thumbnail
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeTestPNG(t *testing.T, width, height int, fill color.Color) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, fill)
+		}
+	}
+	var buffer bytes.Buffer
+	assert.NoError(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func TestCreateThumbnail(t *testing.T) {
+	tests := []struct {
+		name           string
+		width, height  int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{"landscape", 1000, 500, 320, 160},
+		{"portrait", 500, 1000, 160, 320},
+		{"small images are not enlarged", 100, 50, 100, 50},
+		{"thin images keep a pixel", 2000, 1, 320, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			source := image.NewRGBA(image.Rect(0, 0, test.width, test.height))
+			thumbnail := createThumbnail(source, previewMaxDimension)
+			assert.Equal(t, test.expectedWidth, thumbnail.Bounds().Dx())
+			assert.Equal(t, test.expectedHeight, thumbnail.Bounds().Dy())
+		})
+	}
+}
+
+func TestCreateThumbnail_Colors(t *testing.T) {
+	red, err := decodeImage(bytes.NewReader(encodeTestPNG(t, 640, 480, color.NRGBA{R: 255, A: 255})))
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, createThumbnail(red, previewMaxDimension).RGBAAt(10, 10))
+
+	transparent, err := decodeImage(bytes.NewReader(encodeTestPNG(t, 640, 480, color.NRGBA{})))
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, createThumbnail(transparent, previewMaxDimension).RGBAAt(10, 10), "transparency becomes white")
+}
+
+func TestDecodeImage_Rejected(t *testing.T) {
+	// the header of a gif announcing 60000x60000 pixels, which must not be allocated
+	header := []byte("GIF89a")
+	header = binary.LittleEndian.AppendUint16(header, 60000)
+	header = binary.LittleEndian.AppendUint16(header, 60000)
+	header = append(header, 0, 0, 0, ',', 0, 0, 0, 0, 0x60, 0xea, 0x60, 0xea, 0)
+
+	_, err := decodeImage(bytes.NewReader(header))
+	assert.ErrorIs(t, err, ErrPreviewNotSupported)
+
+	_, err = decodeImage(bytes.NewReader([]byte("%PDF-1.7\n")))
+	assert.ErrorIs(t, err, ErrPreviewNotSupported)
+}
+
+func TestPreviewStorageKey(t *testing.T) {
+	fileID := uuid.MustParse("6f1c2a52-0b8e-4a4e-9d55-2f1a3c9d7e10")
+	assert.Equal(t, "previews/6f1c2a52-0b8e-4a4e-9d55-2f1a3c9d7e10.jpg", previewStorageKey(fileID))
+}
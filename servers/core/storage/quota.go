@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+// usageReportUploaderLimit limits the uploaders listed in the usage report to the largest ones
+const usageReportUploaderLimit = 50
+
+var ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+// StorageUsageReport aggregates the size of all files that are not deleted
+type StorageUsageReport struct {
+	TotalBytes    int64                     `json:"totalBytes"`
+	FileCount     int64                     `json:"fileCount"`
+	ByCourse      []CourseStorageUsage      `json:"byCourse"`
+	ByCoursePhase []CoursePhaseStorageUsage `json:"byCoursePhase"`
+	ByUploader    []UploaderStorageUsage    `json:"byUploader"`
+	ByContentType []ContentTypeStorageUsage `json:"byContentType"`
+}
+
+type CourseStorageUsage struct {
+	CourseID    uuid.UUID `json:"courseId"`
+	CourseName  string    `json:"courseName"`
+	SemesterTag string    `json:"semesterTag,omitempty"`
+	FileCount   int64     `json:"fileCount"`
+	UsedBytes   int64     `json:"usedBytes"`
+	// QuotaBytes is the effective quota of the course, it is omitted if the course is unlimited
+	QuotaBytes *int64 `json:"quotaBytes,omitempty"`
+}
+
+type CoursePhaseStorageUsage struct {
+	CoursePhaseID   uuid.UUID `json:"coursePhaseId"`
+	CoursePhaseName string    `json:"coursePhaseName,omitempty"`
+	CourseID        uuid.UUID `json:"courseId"`
+	CourseName      string    `json:"courseName"`
+	FileCount       int64     `json:"fileCount"`
+	UsedBytes       int64     `json:"usedBytes"`
+	QuotaBytes      *int64    `json:"quotaBytes,omitempty"`
+}
+
+type UploaderStorageUsage struct {
+	UploadedByUserID string `json:"uploadedByUserId"`
+	UploadedByEmail  string `json:"uploadedByEmail,omitempty"`
+	FileCount        int64  `json:"fileCount"`
+	UsedBytes        int64  `json:"usedBytes"`
+}
+
+type ContentTypeStorageUsage struct {
+	ContentType string `json:"contentType"`
+	FileCount   int64  `json:"fileCount"`
+	UsedBytes   int64  `json:"usedBytes"`
+}
+
+type StorageQuotaResponse struct {
+	QuotaBytes int64 `json:"quotaBytes"`
+}
+
+// checkStorageQuota verifies that a file of the given size fits into the quotas of its course phase and course.
+// Files without a course phase are not counted against any quota.
+func (s *StorageService) checkStorageQuota(ctx context.Context, coursePhaseID *uuid.UUID, sizeBytes int64) error {
+	if coursePhaseID == nil {
+		return nil
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	status, err := s.queries.GetStorageQuotaStatus(ctxWithTimeout, *coursePhaseID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get storage quota: %w", err)
+	}
+
+	if status.PhaseQuotaBytes.Valid && status.PhaseUsedBytes+sizeBytes > status.PhaseQuotaBytes.Int64 {
+		return fmt.Errorf("%w: the course phase uses %d of %d bytes", ErrStorageQuotaExceeded, status.PhaseUsedBytes, status.PhaseQuotaBytes.Int64)
+	}
+
+	courseQuota := effectiveCourseQuota(status.CourseQuotaBytes)
+	if courseQuota != nil && status.CourseUsedBytes+sizeBytes > *courseQuota {
+		return fmt.Errorf("%w: the course uses %d of %d bytes", ErrStorageQuotaExceeded, status.CourseUsedBytes, *courseQuota)
+	}
+	return nil
+}
+
+// SetCourseStorageQuota sets the quota of a course, it replaces the default quota
+func (s *StorageService) SetCourseStorageQuota(ctx context.Context, courseID uuid.UUID, quotaBytes int64) (StorageQuotaResponse, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	quota, err := s.queries.UpsertCourseStorageQuota(ctxWithTimeout, db.UpsertCourseStorageQuotaParams{
+		CourseID:   courseID,
+		QuotaBytes: quotaBytes,
+	})
+	if err != nil {
+		return StorageQuotaResponse{}, fmt.Errorf("failed to set course storage quota: %w", err)
+	}
+	return StorageQuotaResponse{QuotaBytes: quota.QuotaBytes}, nil
+}
+
+// DeleteCourseStorageQuota resets the quota of a course to the default quota
+func (s *StorageService) DeleteCourseStorageQuota(ctx context.Context, courseID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	if err := s.queries.DeleteCourseStorageQuota(ctxWithTimeout, courseID); err != nil {
+		return fmt.Errorf("failed to delete course storage quota: %w", err)
+	}
+	return nil
+}
+
+// SetCoursePhaseStorageQuota sets the quota of a course phase, which applies in addition to the course quota
+func (s *StorageService) SetCoursePhaseStorageQuota(ctx context.Context, coursePhaseID uuid.UUID, quotaBytes int64) (StorageQuotaResponse, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	quota, err := s.queries.UpsertCoursePhaseStorageQuota(ctxWithTimeout, db.UpsertCoursePhaseStorageQuotaParams{
+		CoursePhaseID: coursePhaseID,
+		QuotaBytes:    quotaBytes,
+	})
+	if err != nil {
+		return StorageQuotaResponse{}, fmt.Errorf("failed to set course phase storage quota: %w", err)
+	}
+	return StorageQuotaResponse{QuotaBytes: quota.QuotaBytes}, nil
+}
+
+// DeleteCoursePhaseStorageQuota removes the quota of a course phase
+func (s *StorageService) DeleteCoursePhaseStorageQuota(ctx context.Context, coursePhaseID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	if err := s.queries.DeleteCoursePhaseStorageQuota(ctxWithTimeout, coursePhaseID); err != nil {
+		return fmt.Errorf("failed to delete course phase storage quota: %w", err)
+	}
+	return nil
+}
+
+// GetStorageUsageReport aggregates the stored bytes by course, course phase, uploader and content type
+func (s *StorageService) GetStorageUsageReport(ctx context.Context) (*StorageUsageReport, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	byCourse, err := s.queries.GetStorageUsageByCourse(ctxWithTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage usage by course: %w", err)
+	}
+	byCoursePhase, err := s.queries.GetStorageUsageByCoursePhase(ctxWithTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage usage by course phase: %w", err)
+	}
+	byUploader, err := s.queries.GetStorageUsageByUploader(ctxWithTimeout, usageReportUploaderLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage usage by uploader: %w", err)
+	}
+	byContentType, err := s.queries.GetStorageUsageByContentType(ctxWithTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage usage by content type: %w", err)
+	}
+
+	report := &StorageUsageReport{
+		ByCourse:      make([]CourseStorageUsage, 0, len(byCourse)),
+		ByCoursePhase: make([]CoursePhaseStorageUsage, 0, len(byCoursePhase)),
+		ByUploader:    make([]UploaderStorageUsage, 0, len(byUploader)),
+		ByContentType: make([]ContentTypeStorageUsage, 0, len(byContentType)),
+	}
+
+	for _, usage := range byCourse {
+		report.ByCourse = append(report.ByCourse, CourseStorageUsage{
+			CourseID:    usage.CourseID,
+			CourseName:  usage.CourseName,
+			SemesterTag: usage.SemesterTag.String,
+			FileCount:   usage.FileCount,
+			UsedBytes:   usage.UsedBytes,
+			QuotaBytes:  effectiveCourseQuota(usage.QuotaBytes),
+		})
+	}
+
+	for _, usage := range byCoursePhase {
+		phaseUsage := CoursePhaseStorageUsage{
+			CoursePhaseID:   usage.CoursePhaseID,
+			CoursePhaseName: usage.CoursePhaseName.String,
+			CourseID:        usage.CourseID,
+			CourseName:      usage.CourseName,
+			FileCount:       usage.FileCount,
+			UsedBytes:       usage.UsedBytes,
+		}
+		if usage.QuotaBytes.Valid {
+			phaseUsage.QuotaBytes = &usage.QuotaBytes.Int64
+		}
+		report.ByCoursePhase = append(report.ByCoursePhase, phaseUsage)
+	}
+
+	for _, usage := range byUploader {
+		report.ByUploader = append(report.ByUploader, UploaderStorageUsage(usage))
+	}
+
+	// every file has a content type, so this grouping covers all files
+	for _, usage := range byContentType {
+		report.ByContentType = append(report.ByContentType, ContentTypeStorageUsage(usage))
+		report.TotalBytes += usage.UsedBytes
+		report.FileCount += usage.FileCount
+	}
+
+	return report, nil
+}
+
+// effectiveCourseQuota returns the quota set for a course or the default quota, nil means unlimited
+func effectiveCourseQuota(courseQuota pgtype.Int8) *int64 {
+	if courseQuota.Valid {
+		return &courseQuota.Int64
+	}
+	if defaultQuota := defaultCourseStorageQuotaBytes(); defaultQuota > 0 {
+		return &defaultQuota
+	}
+	return nil
+}
+
+// defaultCourseStorageQuotaBytes is the quota of courses without their own quota, 0 disables it
+func defaultCourseStorageQuotaBytes() int64 {
+	value := strings.TrimSpace(sdkUtils.GetEnv("DEFAULT_COURSE_STORAGE_QUOTA_MB", "0"))
+	quotaMB, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || quotaMB < 0 {
+		log.Warn("Invalid DEFAULT_COURSE_STORAGE_QUOTA_MB, courses without a quota are unlimited")
+		return 0
+	}
+	return quotaMB * 1024 * 1024
+}
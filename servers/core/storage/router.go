@@ -0,0 +1,878 @@
+package storage
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+func setupStorageRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionRoleMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+	garbageCollection := router.Group("/storage/garbage-collection", authMiddleware(), permissionRoleMiddleware(permissionValidation.PromptAdmin))
+	garbageCollection.GET("", getLastGarbageCollectionReport)
+	garbageCollection.POST("", collectGarbage)
+
+	router.GET("/storage/usage", authMiddleware(), permissionRoleMiddleware(permissionValidation.PromptAdmin), getStorageUsageReport)
+
+	quotas := router.Group("/storage/quotas", authMiddleware(), permissionRoleMiddleware(permissionValidation.PromptAdmin))
+	quotas.PUT("/courses/:courseID", setCourseStorageQuota)
+	quotas.DELETE("/courses/:courseID", deleteCourseStorageQuota)
+	quotas.PUT("/course-phases/:coursePhaseID", setCoursePhaseStorageQuota)
+	quotas.DELETE("/course-phases/:coursePhaseID", deleteCoursePhaseStorageQuota)
+
+	migration := router.Group("/storage/migration", authMiddleware(), permissionRoleMiddleware(permissionValidation.PromptAdmin))
+	migration.GET("", getStorageMigrationReport)
+	migration.POST("", startStorageMigration)
+}
+
+// getStorageUsageReport godoc
+// @Summary Get the storage usage
+// @Description Get the size of all stored files by course, course phase, uploader and content type together with the effective quotas. Deleted files are not counted.
+// @Tags storage
+// @Produce json
+// @Success 200 {object} StorageUsageReport
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/usage [get]
+func getStorageUsageReport(c *gin.Context) {
+	report, err := StorageServiceSingleton.GetStorageUsageReport(c)
+	if err != nil {
+		log.WithError(err).Error("Failed to get storage usage report")
+		handleError(c, http.StatusInternalServerError, errors.New("could not get the storage usage"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// setCourseStorageQuota godoc
+// @Summary Set the storage quota of a course
+// @Description Replaces the default quota of a course. Uploads to any phase of the course are rejected once the quota is reached.
+// @Tags storage
+// @Accept json
+// @Produce json
+// @Param courseID path string true "Course UUID"
+// @Param quota body StorageQuotaResponse true "Quota in bytes"
+// @Success 200 {object} StorageQuotaResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/quotas/courses/{courseID} [put]
+func setCourseStorageQuota(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course id"))
+		return
+	}
+	quotaBytes, err := bindStorageQuota(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	quota, err := StorageServiceSingleton.SetCourseStorageQuota(c, courseID, quotaBytes)
+	if err != nil {
+		log.WithError(err).Error("Failed to set course storage quota")
+		handleError(c, http.StatusInternalServerError, errors.New("could not set the storage quota"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, quota)
+}
+
+// deleteCourseStorageQuota godoc
+// @Summary Reset the storage quota of a course
+// @Description Removes the quota of a course, the default quota applies again.
+// @Tags storage
+// @Param courseID path string true "Course UUID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/quotas/courses/{courseID} [delete]
+func deleteCourseStorageQuota(c *gin.Context) {
+	courseID, err := uuid.Parse(c.Param("courseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course id"))
+		return
+	}
+
+	if err := StorageServiceSingleton.DeleteCourseStorageQuota(c, courseID); err != nil {
+		log.WithError(err).Error("Failed to delete course storage quota")
+		handleError(c, http.StatusInternalServerError, errors.New("could not delete the storage quota"))
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// setCoursePhaseStorageQuota godoc
+// @Summary Set the storage quota of a course phase
+// @Description Limits the size of the files of a course phase. The quota of the course applies in addition.
+// @Tags storage
+// @Accept json
+// @Produce json
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Param quota body StorageQuotaResponse true "Quota in bytes"
+// @Success 200 {object} StorageQuotaResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/quotas/course-phases/{coursePhaseID} [put]
+func setCoursePhaseStorageQuota(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course phase id"))
+		return
+	}
+	quotaBytes, err := bindStorageQuota(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	quota, err := StorageServiceSingleton.SetCoursePhaseStorageQuota(c, coursePhaseID, quotaBytes)
+	if err != nil {
+		log.WithError(err).Error("Failed to set course phase storage quota")
+		handleError(c, http.StatusInternalServerError, errors.New("could not set the storage quota"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, quota)
+}
+
+// deleteCoursePhaseStorageQuota godoc
+// @Summary Remove the storage quota of a course phase
+// @Description Removes the quota of a course phase, only the course quota applies.
+// @Tags storage
+// @Param coursePhaseID path string true "Course Phase UUID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/quotas/course-phases/{coursePhaseID} [delete]
+func deleteCoursePhaseStorageQuota(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("coursePhaseID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course phase id"))
+		return
+	}
+
+	if err := StorageServiceSingleton.DeleteCoursePhaseStorageQuota(c, coursePhaseID); err != nil {
+		log.WithError(err).Error("Failed to delete course phase storage quota")
+		handleError(c, http.StatusInternalServerError, errors.New("could not delete the storage quota"))
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func bindStorageQuota(c *gin.Context) (int64, error) {
+	var request StorageQuotaResponse
+	if err := c.BindJSON(&request); err != nil {
+		return 0, errors.New("invalid quota")
+	}
+	if request.QuotaBytes < 0 {
+		return 0, errors.New("quota must not be negative")
+	}
+	return request.QuotaBytes, nil
+}
+
+// getLastGarbageCollectionReport godoc
+// @Summary Get the last file garbage collection report
+// @Description Get what the last scheduled or manual garbage collection deleted. Dry runs are not included.
+// @Tags storage
+// @Produce json
+// @Success 200 {object} GarbageCollectionReport
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /storage/garbage-collection [get]
+func getLastGarbageCollectionReport(c *gin.Context) {
+	report := StorageServiceSingleton.GetLastGarbageCollectionReport()
+	if report == nil {
+		handleError(c, http.StatusNotFound, errors.New("no garbage collection has run yet"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// collectGarbage godoc
+// @Summary Run the file garbage collection
+// @Description Deletes files that were soft-deleted longer than the retention period, stored objects without a file record and file records without a stored object. Runs as dry run unless dryRun is false.
+// @Tags storage
+// @Produce json
+// @Param dryRun query bool false "Only report what would be deleted" default(true)
+// @Success 200 {object} GarbageCollectionReport
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/garbage-collection [post]
+func collectGarbage(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "true"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid dryRun parameter"))
+		return
+	}
+
+	report, err := StorageServiceSingleton.CollectGarbage(c, dryRun)
+	if errors.Is(err, ErrGarbageCollectionRunning) {
+		handleError(c, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("File garbage collection failed")
+		handleError(c, http.StatusInternalServerError, errors.New("could not collect garbage files"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// getStorageMigrationReport godoc
+// @Summary Get the progress of the storage migration
+// @Description Get the progress of the running storage migration, or the result of the last one since the server started.
+// @Tags storage
+// @Produce json
+// @Success 200 {object} StorageMigrationReport
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /storage/migration [get]
+func getStorageMigrationReport(c *gin.Context) {
+	report := StorageServiceSingleton.GetStorageMigrationReport()
+	if report == nil {
+		handleError(c, http.StatusNotFound, errors.New("no storage migration has run yet"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// startStorageMigration godoc
+// @Summary Start the storage migration
+// @Description Copies the objects of all files stored with STORAGE_MIGRATION_SOURCE_PROVIDER to the configured storage in the background. Every copy is verified by its size and SHA-256 hash before the files are switched to the configured storage provider. Starting it again retries the failed objects.
+// @Tags storage
+// @Produce json
+// @Success 202 {object} StorageMigrationReport
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/migration [post]
+func startStorageMigration(c *gin.Context) {
+	report, err := StorageServiceSingleton.StartStorageMigration()
+	if errors.Is(err, ErrStorageMigrationNotConfigured) {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if errors.Is(err, ErrStorageMigrationRunning) {
+		handleError(c, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to start storage migration")
+		handleError(c, http.StatusInternalServerError, errors.New("could not start the storage migration"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusAccepted, report)
+}
+
+// setupCoursePhaseFileRouter sets up the file endpoints of course phases
+// @Summary Course Phase File Endpoints
+// @Description Endpoints for uploading files to a course phase and referencing them from participations
+// @Tags storage
+// @Security BearerAuth
+func setupCoursePhaseFileRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionIDMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+	// students can upload files and access their own uploads
+	files := router.Group("/course_phases/:uuid/files", authMiddleware())
+	files.GET("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CourseStudent), getCoursePhaseFiles)
+	files.POST("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CourseStudent), uploadCoursePhaseFile)
+	files.GET("/:fileID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CourseStudent), getCoursePhaseFile)
+	files.GET("/:fileID/download", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CourseStudent), downloadCoursePhaseFile)
+	files.DELETE("/:fileID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor, permissionValidation.CourseStudent), deleteCoursePhaseFile)
+
+	participationFiles := router.Group("/course_phases/:uuid/participations/:course_participation_id/files", authMiddleware())
+	participationFiles.GET("", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), getCoursePhaseParticipationFiles)
+	participationFiles.PUT("/:fileID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), attachFileToCoursePhaseParticipation)
+	participationFiles.DELETE("/:fileID", permissionIDMiddleware(permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor), detachFileFromCoursePhaseParticipation)
+}
+
+// getCoursePhaseFiles godoc
+// @Summary List the files of a course phase
+// @Description Lists the files of a course phase. Students only get their own uploads.
+// @Tags storage
+// @Produce json
+// @Param uuid path string true "Course Phase UUID"
+// @Param tags query string false "Comma-separated tags, a file has to have all of them"
+// @Success 200 {array} FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/files [get]
+func getCoursePhaseFiles(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course phase id"))
+		return
+	}
+
+	uploaderUserID, err := ownFilesFilter(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	files, err := StorageServiceSingleton.GetCoursePhaseFiles(c, coursePhaseID, ParseTags(c.Query("tags")), uploaderUserID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get course phase files")
+		handleError(c, http.StatusInternalServerError, errors.New("could not get the files"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, files)
+}
+
+// uploadCoursePhaseFile godoc
+// @Summary Upload a file to a course phase
+// @Description Uploads a file to a course phase. The file counts towards the storage quota of the phase and its course.
+// @Tags storage
+// @Accept multipart/form-data
+// @Produce json
+// @Param uuid path string true "Course Phase UUID"
+// @Param file formData file true "File to upload"
+// @Param description formData string false "Description of the file"
+// @Param tags formData string false "Comma-separated tags"
+// @Success 201 {object} FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/files [post]
+func uploadCoursePhaseFile(c *gin.Context) {
+	coursePhaseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course phase id"))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("file is required"))
+		return
+	}
+
+	file, err := StorageServiceSingleton.UploadFile(c, FileUploadRequest{
+		File:           fileHeader,
+		UploaderUserID: c.GetString(keycloakTokenVerifier.CtxUserID),
+		UploaderEmail:  c.GetString(keycloakTokenVerifier.CtxUserEmail),
+		CoursePhaseID:  &coursePhaseID,
+		Description:    c.PostForm("description"),
+		Tags:           ParseTags(c.PostForm("tags")),
+	})
+	if err != nil {
+		log.WithError(err).Error("Failed to upload course phase file")
+		if status := RejectedUploadStatus(err); status != 0 {
+			handleError(c, status, err)
+			return
+		}
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, file)
+}
+
+// getCoursePhaseFile godoc
+// @Summary Get a file of a course phase
+// @Description Get a file with its download URL. Students can only get their own uploads.
+// @Tags storage
+// @Produce json
+// @Param uuid path string true "Course Phase UUID"
+// @Param fileID path string true "File UUID"
+// @Success 200 {object} FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/files/{fileID} [get]
+func getCoursePhaseFile(c *gin.Context) {
+	file, ok := getAccessibleCoursePhaseFile(c)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, file)
+}
+
+// downloadCoursePhaseFile godoc
+// @Summary Download a file of a course phase
+// @Description Streams the content of a file. Students can only download their own uploads.
+// @Tags storage
+// @Produce octet-stream
+// @Param uuid path string true "Course Phase UUID"
+// @Param fileID path string true "File UUID"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/files/{fileID}/download [get]
+func downloadCoursePhaseFile(c *gin.Context) {
+	file, ok := getAccessibleCoursePhaseFile(c)
+	if !ok {
+		return
+	}
+
+	reader, filename, err := StorageServiceSingleton.DownloadFile(c, file.ID)
+	if errors.Is(err, ErrFileQuarantined) {
+		handleError(c, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to download course phase file")
+		handleError(c, http.StatusInternalServerError, errors.New("could not download the file"))
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, file.SizeBytes, file.ContentType, reader, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+	})
+}
+
+// deleteCoursePhaseFile godoc
+// @Summary Delete a file of a course phase
+// @Description Deletes a file of a course phase. Editors and students can only delete their own uploads, files of application answers and notes cannot be deleted.
+// @Tags storage
+// @Param uuid path string true "Course Phase UUID"
+// @Param fileID path string true "File UUID"
+// @Success 204
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/files/{fileID} [delete]
+func deleteCoursePhaseFile(c *gin.Context) {
+	file, ok := getAccessibleCoursePhaseFile(c)
+	if !ok {
+		return
+	}
+
+	if file.UploadedByUserID != c.GetString(keycloakTokenVerifier.CtxUserID) {
+		isLecturer, err := hasCoursePhaseRole(c, permissionValidation.PromptAdmin, permissionValidation.CourseLecturer)
+		if err != nil {
+			handleError(c, http.StatusInternalServerError, err)
+			return
+		}
+		if !isLecturer {
+			handleError(c, http.StatusForbidden, errors.New("only the uploader or a lecturer can delete the file"))
+			return
+		}
+	}
+
+	// soft delete, the file is removed permanently by the garbage collection
+	if err := StorageServiceSingleton.DeleteFile(c, file.ID, false); err != nil {
+		log.WithError(err).Error("Failed to delete course phase file")
+		handleError(c, http.StatusInternalServerError, errors.New("could not delete the file"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getCoursePhaseParticipationFiles godoc
+// @Summary List the files of a participation
+// @Description Lists the files referenced by the data of a course phase participation
+// @Tags storage
+// @Produce json
+// @Param uuid path string true "Course Phase UUID"
+// @Param course_participation_id path string true "Course Participation UUID"
+// @Success 200 {array} FileResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/participations/{course_participation_id}/files [get]
+func getCoursePhaseParticipationFiles(c *gin.Context) {
+	coursePhaseID, courseParticipationID, ok := parseParticipationParams(c)
+	if !ok {
+		return
+	}
+
+	files, err := StorageServiceSingleton.GetCoursePhaseParticipationFiles(c, coursePhaseID, courseParticipationID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get participation files")
+		handleError(c, http.StatusInternalServerError, errors.New("could not get the files"))
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, files)
+}
+
+// attachFileToCoursePhaseParticipation godoc
+// @Summary Reference a file from a participation
+// @Description References a file of the course phase from the data of a participation, e.g. a signed contract
+// @Tags storage
+// @Param uuid path string true "Course Phase UUID"
+// @Param course_participation_id path string true "Course Participation UUID"
+// @Param fileID path string true "File UUID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/participations/{course_participation_id}/files/{fileID} [put]
+func attachFileToCoursePhaseParticipation(c *gin.Context) {
+	coursePhaseID, courseParticipationID, ok := parseParticipationParams(c)
+	if !ok {
+		return
+	}
+	fileID, err := uuid.Parse(c.Param("fileID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid file id"))
+		return
+	}
+
+	err = StorageServiceSingleton.AttachFileToCoursePhaseParticipation(c, coursePhaseID, courseParticipationID, fileID)
+	if errors.Is(err, ErrFileNotFound) || errors.Is(err, ErrFileNotInCoursePhase) || errors.Is(err, ErrParticipationNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("Failed to attach file to participation")
+		handleError(c, http.StatusInternalServerError, errors.New("could not attach the file"))
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// detachFileFromCoursePhaseParticipation godoc
+// @Summary Remove a file reference from a participation
+// @Description Removes the reference, the file stays in the course phase
+// @Tags storage
+// @Param uuid path string true "Course Phase UUID"
+// @Param course_participation_id path string true "Course Participation UUID"
+// @Param fileID path string true "File UUID"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /course_phases/{uuid}/participations/{course_participation_id}/files/{fileID} [delete]
+func detachFileFromCoursePhaseParticipation(c *gin.Context) {
+	coursePhaseID, courseParticipationID, ok := parseParticipationParams(c)
+	if !ok {
+		return
+	}
+	fileID, err := uuid.Parse(c.Param("fileID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid file id"))
+		return
+	}
+
+	if err := StorageServiceSingleton.DetachFileFromCoursePhaseParticipation(c, coursePhaseID, courseParticipationID, fileID); err != nil {
+		log.WithError(err).Error("Failed to detach file from participation")
+		handleError(c, http.StatusInternalServerError, errors.New("could not detach the file"))
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// getAccessibleCoursePhaseFile loads the file of the request and writes an error response if the user cannot access it
+func getAccessibleCoursePhaseFile(c *gin.Context) (*FileResponse, bool) {
+	coursePhaseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course phase id"))
+		return nil, false
+	}
+	fileID, err := uuid.Parse(c.Param("fileID"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid file id"))
+		return nil, false
+	}
+
+	uploaderUserID, err := ownFilesFilter(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	file, err := StorageServiceSingleton.GetCoursePhaseFile(c, coursePhaseID, fileID)
+	if err != nil && !errors.Is(err, ErrFileNotFound) && !errors.Is(err, ErrFileNotInCoursePhase) {
+		log.WithError(err).Error("Failed to get course phase file")
+		handleError(c, http.StatusInternalServerError, errors.New("could not get the file"))
+		return nil, false
+	}
+	// files of other uploaders are reported as missing, which does not reveal their existence to students
+	if err != nil || (uploaderUserID != "" && file.UploadedByUserID != uploaderUserID) {
+		handleError(c, http.StatusNotFound, ErrFileNotFound)
+		return nil, false
+	}
+	return file, true
+}
+
+// ownFilesFilter returns the user id of students, who only access their own files, and an empty string for course staff
+func ownFilesFilter(c *gin.Context) (string, error) {
+	isStaff, err := hasCoursePhaseRole(c, permissionValidation.PromptAdmin, permissionValidation.CourseLecturer, permissionValidation.CourseEditor)
+	if err != nil {
+		return "", err
+	}
+	if isStaff {
+		return "", nil
+	}
+	return c.GetString(keycloakTokenVerifier.CtxUserID), nil
+}
+
+// hasCoursePhaseRole checks whether the user has one of the allowed roles in the course phase of the request,
+// including the roles granted to custom course roles in the phase
+func hasCoursePhaseRole(c *gin.Context, allowedRoles ...string) (bool, error) {
+	userRoles, exists := c.Get(keycloakTokenVerifier.CtxUserRoles)
+	if !exists {
+		log.Error("userRoles not found in context")
+		return false, errors.New("could not get the user roles")
+	}
+
+	userRolesMap, ok := userRoles.(map[string]bool)
+	if !ok {
+		log.Error("invalid roles format in context")
+		return false, errors.New("could not get the user roles")
+	}
+
+	coursePhaseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		return false, errors.New("invalid course phase id")
+	}
+
+	hasRole, err := permissionValidation.HasCoursePhaseRole(c, userRolesMap, c.GetString("courseTokenIdentifier"), coursePhaseID, allowedRoles...)
+	if err != nil {
+		log.WithError(err).Error("Failed to check the course phase role")
+		return false, errors.New("could not check the role of the user")
+	}
+	return hasRole, nil
+}
+
+func parseParticipationParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	coursePhaseID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course phase id"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	courseParticipationID, err := uuid.Parse(c.Param("course_participation_id"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid course participation id"))
+		return uuid.Nil, uuid.Nil, false
+	}
+	return coursePhaseID, courseParticipationID, true
+}
+
+// RejectedUploadStatus returns the status code for uploads that were rejected because of the file itself,
+// e.g. an executable declared as pdf or a full course quota, or because the storage does not support multipart uploads.
+// It returns 0 for all other errors.
+func RejectedUploadStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrStorageQuotaExceeded), errors.Is(err, ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrContentTypeMismatch), errors.Is(err, ErrFileInfected), errors.Is(err, ErrInvalidMultipartUpload):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrMultipartUploadNotSupported):
+		return http.StatusNotImplemented
+	default:
+		return 0
+	}
+}
+
+// setupLocalStorageRouter serves the signed URLs of the local storage adapter
+// @Summary Local Storage Endpoints
+// @Description Endpoints for uploading and downloading files with signed URLs when files are stored on the local filesystem
+// @Tags storage
+func setupLocalStorageRouter(router *gin.RouterGroup, adapter *LocalAdapter, maxFileSize int64) {
+	// No authentication, the signature grants the access
+	localStorage := router.Group("/storage/local")
+	localStorage.GET("/"+localOperationDownload, downloadLocalFile(adapter))
+	localStorage.PUT("/"+localOperationUpload, uploadLocalFile(adapter, maxFileSize))
+}
+
+// downloadLocalFile godoc
+// @Summary Download a file with a signed URL
+// @Description Streams a file of the local storage. The URL is created by the core server and expires.
+// @Tags storage
+// @Produce octet-stream
+// @Param key query string true "Storage key"
+// @Param expires query int true "Expiry as unix timestamp"
+// @Param signature query string true "Signature of the URL"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /storage/local/download [get]
+func downloadLocalFile(adapter *LocalAdapter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		storageKey := c.Query("key")
+		err := adapter.VerifySignature(localOperationDownload, storageKey, "", c.Query("expires"), c.Query("signature"))
+		if err != nil {
+			handleError(c, http.StatusForbidden, err)
+			return
+		}
+
+		metadata, err := adapter.GetMetadata(c, storageKey)
+		if err != nil {
+			handleError(c, http.StatusNotFound, errors.New("file not found"))
+			return
+		}
+
+		reader, err := adapter.Download(c, storageKey)
+		if err != nil {
+			handleError(c, http.StatusNotFound, errors.New("file not found"))
+			return
+		}
+		defer reader.Close()
+
+		c.DataFromReader(http.StatusOK, metadata.Size, metadata.ContentType, reader, nil)
+	}
+}
+
+// uploadLocalFile godoc
+// @Summary Upload a file with a signed URL
+// @Description Stores the request body in the local storage. The URL is created by the core server and expires.
+// @Tags storage
+// @Accept octet-stream
+// @Param key query string true "Storage key"
+// @Param expires query int true "Expiry as unix timestamp"
+// @Param contentType query string true "Content type the URL was signed for"
+// @Param signature query string true "Signature of the URL"
+// @Success 200
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /storage/local/upload [put]
+func uploadLocalFile(adapter *LocalAdapter, maxFileSize int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		storageKey := c.Query("key")
+		contentType := c.Query("contentType")
+		err := adapter.VerifySignature(localOperationUpload, storageKey, contentType, c.Query("expires"), c.Query("signature"))
+		if err != nil {
+			handleError(c, http.StatusForbidden, err)
+			return
+		}
+
+		// like a presigned S3 URL, the signed content type has to be sent
+		if c.ContentType() != contentType {
+			handleError(c, http.StatusBadRequest, errors.New("content type does not match the signed content type"))
+			return
+		}
+
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, maxFileSize)
+		_, err = adapter.Upload(c, storageKey, contentType, body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				handleError(c, http.StatusRequestEntityTooLarge, errors.New("file is too large"))
+				return
+			}
+			log.WithError(err).Error("Failed to store upload in local storage")
+			handleError(c, http.StatusInternalServerError, errors.New("failed to upload file"))
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// setupEncryptedFileRouter serves the signed URLs of encrypted files, which are decrypted by the server
+// @Summary Encrypted File Endpoints
+// @Description Endpoints for downloading encrypted files and their previews with signed URLs
+// @Tags storage
+func setupEncryptedFileRouter(router *gin.RouterGroup, encryption *fileEncryption) {
+	// No authentication, the signature grants the access
+	encryptedFiles := router.Group("/storage/encrypted")
+	encryptedFiles.GET("/"+encryptedOperationDownload, downloadEncryptedFile(encryption))
+	encryptedFiles.GET("/"+encryptedOperationPreview, downloadEncryptedFilePreview(encryption))
+}
+
+// downloadEncryptedFile godoc
+// @Summary Download an encrypted file with a signed URL
+// @Description Streams the decrypted content of a file. The URL is created by the core server and expires.
+// @Tags storage
+// @Produce octet-stream
+// @Param file query string true "File UUID"
+// @Param expires query int true "Expiry as unix timestamp"
+// @Param signature query string true "Signature of the URL"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /storage/encrypted/download [get]
+func downloadEncryptedFile(encryption *fileEncryption) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileID, ok := verifyEncryptedFileURL(c, encryption, encryptedOperationDownload)
+		if !ok {
+			return
+		}
+
+		file, err := StorageServiceSingleton.GetFileByID(c, fileID)
+		if err != nil {
+			handleError(c, http.StatusNotFound, errors.New("file not found"))
+			return
+		}
+
+		reader, filename, err := StorageServiceSingleton.DownloadFile(c, fileID)
+		if errors.Is(err, ErrFileQuarantined) {
+			handleError(c, http.StatusConflict, err)
+			return
+		}
+		if err != nil {
+			log.WithError(err).WithField("fileId", fileID).Error("Failed to download encrypted file")
+			handleError(c, http.StatusNotFound, errors.New("file not found"))
+			return
+		}
+		defer reader.Close()
+
+		c.DataFromReader(http.StatusOK, file.SizeBytes, file.ContentType, reader, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+		})
+	}
+}
+
+// downloadEncryptedFilePreview godoc
+// @Summary Download the preview of an encrypted file with a signed URL
+// @Description Streams the decrypted preview of a file. The URL is created by the core server and expires.
+// @Tags storage
+// @Produce jpeg
+// @Param file query string true "File UUID"
+// @Param expires query int true "Expiry as unix timestamp"
+// @Param signature query string true "Signature of the URL"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /storage/encrypted/preview [get]
+func downloadEncryptedFilePreview(encryption *fileEncryption) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fileID, ok := verifyEncryptedFileURL(c, encryption, encryptedOperationPreview)
+		if !ok {
+			return
+		}
+
+		reader, preview, err := StorageServiceSingleton.openPreviewContent(c, fileID)
+		if err != nil {
+			handleError(c, http.StatusNotFound, errors.New("preview not found"))
+			return
+		}
+		defer reader.Close()
+
+		c.DataFromReader(http.StatusOK, preview.SizeBytes, preview.ContentType, reader, nil)
+	}
+}
+
+func verifyEncryptedFileURL(c *gin.Context, encryption *fileEncryption, operation string) (uuid.UUID, bool) {
+	err := encryption.verifySignature(operation, c.Query("file"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		handleError(c, http.StatusForbidden, err)
+		return uuid.Nil, false
+	}
+
+	fileID, err := uuid.Parse(c.Query("file"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, errors.New("invalid file id"))
+		return uuid.Nil, false
+	}
+	return fileID, true
+}
+
+func handleError(c *gin.Context, statusCode int, err error) {
+	c.JSON(statusCode, utils.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -255,3 +256,117 @@ func (s *S3Adapter) GetMetadata(ctx context.Context, storageKey string) (*FileMe
 		Size:        size,
 	}, nil
 }
+
+// ListObjects lists the objects of the bucket page by page
+func (s *S3Adapter) ListObjects(ctx context.Context, prefix string) ([]StoredObject, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+	}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+
+	var objects []StoredObject
+	paginator := s3.NewListObjectsV2Paginator(s.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.WithError(err).WithField("prefix", prefix).Error("Failed to list objects in S3")
+			return nil, fmt.Errorf("failed to list objects in S3: %w", err)
+		}
+
+		for _, object := range page.Contents {
+			storedObject := StoredObject{
+				StorageKey: aws.ToString(object.Key),
+				Size:       aws.ToInt64(object.Size),
+			}
+			if object.LastModified != nil {
+				storedObject.LastModified = *object.LastModified
+			}
+			objects = append(objects, storedObject)
+		}
+	}
+
+	return objects, nil
+}
+
+// CreateMultipartUpload starts a multipart upload
+func (s *S3Adapter) CreateMultipartUpload(ctx context.Context, storageKey string, contentType string) (string, error) {
+	output, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(storageKey),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		log.WithError(err).WithField("key", storageKey).Error("Failed to create multipart upload in S3")
+		return "", fmt.Errorf("failed to create multipart upload in S3: %w", err)
+	}
+
+	return aws.ToString(output.UploadId), nil
+}
+
+// GetUploadPartURL returns a presigned URL for uploading one part of a multipart upload
+func (s *S3Adapter) GetUploadPartURL(ctx context.Context, storageKey string, uploadID string, partNumber int32, ttl int) (string, error) {
+	duration := s.presignDuration
+	if ttl > 0 {
+		duration = time.Duration(ttl) * time.Second
+	}
+
+	request, err := s.presignClient.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(storageKey),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(partNumber),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = duration
+	})
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{"key": storageKey, "partNumber": partNumber}).Error("Failed to generate presigned upload part URL")
+		return "", fmt.Errorf("failed to generate presigned upload part URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts, S3 requires them in ascending order
+func (s *S3Adapter) CompleteMultipartUpload(ctx context.Context, storageKey string, uploadID string, parts []UploadedPart) error {
+	completedParts := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completedParts = append(completedParts, types.CompletedPart{
+			PartNumber: aws.Int32(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		})
+	}
+	sort.Slice(completedParts, func(i, j int) bool {
+		return aws.ToInt32(completedParts[i].PartNumber) < aws.ToInt32(completedParts[j].PartNumber)
+	})
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(storageKey),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+	})
+	if err != nil {
+		log.WithError(err).WithField("key", storageKey).Error("Failed to complete multipart upload in S3")
+		return fmt.Errorf("failed to complete multipart upload in S3: %w", err)
+	}
+
+	log.WithFields(log.Fields{"key": storageKey, "parts": len(parts)}).Info("Multipart upload completed in S3")
+	return nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its uploaded parts
+func (s *S3Adapter) AbortMultipartUpload(ctx context.Context, storageKey string, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(storageKey),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		log.WithError(err).WithField("key", storageKey).Error("Failed to abort multipart upload in S3")
+		return fmt.Errorf("failed to abort multipart upload in S3: %w", err)
+	}
+
+	return nil
+}
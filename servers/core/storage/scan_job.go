@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// rescanBatchSize limits the number of files scanned in one run of the rescan job
+const rescanBatchSize = 100
+
+// StartFileRescanJob scans quarantined files again every interval until the context is cancelled.
+func StartFileRescanJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				cleanCount, err := StorageServiceSingleton.ScanPendingFiles(ctx, rescanBatchSize)
+				if err != nil {
+					log.Error("rescanning quarantined files failed: ", err)
+					continue
+				}
+				if cleanCount > 0 {
+					log.Infof("rescanning released %d quarantined files", cleanCount)
+				}
+			}
+		}
+	}()
+}
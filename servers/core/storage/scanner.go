@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Malware scanners selectable via FILE_SCANNER
+const (
+	FileScannerNone   = "none"
+	FileScannerClamAV = "clamav"
+)
+
+// ScanResult is the verdict of a malware scanner
+type ScanResult struct {
+	Infected bool
+	// Signature is the name of the detected malware, empty for clean files
+	Signature string
+}
+
+// MalwareScanner checks the content of uploaded files before they can be downloaded
+type MalwareScanner interface {
+	// Scan reads the content entirely and returns the verdict
+	// An error means that no verdict could be made and the file has to stay quarantined
+	Scan(ctx context.Context, reader io.Reader) (ScanResult, error)
+
+	// Name identifies the scanner in the stored scan results
+	Name() string
+}
+
+// NoopScanner accepts every file, for setups without a malware scanner
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(ctx context.Context, reader io.Reader) (ScanResult, error) {
+	return ScanResult{}, nil
+}
+
+func (NoopScanner) Name() string {
+	return FileScannerNone
+}
+
+// clamdChunkSize is the size of the chunks streamed to clamd, which has to stay below its StreamMaxLength
+const clamdChunkSize = 64 * 1024
+
+// ClamAVScanner scans files with a clamd daemon over its TCP protocol (INSTREAM command)
+type ClamAVScanner struct {
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner creates a scanner for the clamd daemon listening on address, e.g. clamav:3310
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{
+		address: address,
+		timeout: timeout,
+	}
+}
+
+func (c *ClamAVScanner) Name() string {
+	return FileScannerClamAV
+}
+
+// Scan streams the content to clamd in length-prefixed chunks and parses its reply
+func (c *ClamAVScanner) Scan(ctx context.Context, reader io.Reader) (ScanResult, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return ScanResult{}, err
+	}
+
+	// the z prefix makes clamd use null-terminated commands and replies
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return ScanResult{}, fmt.Errorf("failed to send command to clamd: %w", err)
+	}
+
+	chunk := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := reader.Read(chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return ScanResult{}, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+			if _, err := conn.Write(chunk[:n]); err != nil {
+				// clamd closes the connection when the stream exceeds its size limit, its reply explains why
+				break
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return ScanResult{}, fmt.Errorf("failed to read file: %w", readErr)
+		}
+	}
+
+	// a zero-length chunk terminates the stream
+	binary.BigEndian.PutUint32(size, 0)
+	_, _ = conn.Write(size)
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return ScanResult{}, fmt.Errorf("failed to read reply from clamd: %w", err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply interprets replies like "stream: OK" or "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply string) (ScanResult, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return ScanResult{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return ScanResult{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		// e.g. "INSTREAM size limit exceeded. ERROR"
+		return ScanResult{}, fmt.Errorf("clamd could not scan the file: %s", reply)
+	}
+}
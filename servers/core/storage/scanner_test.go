@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeScanner struct {
+	result ScanResult
+	err    error
+}
+
+func (f fakeScanner) Scan(ctx context.Context, reader io.Reader) (ScanResult, error) {
+	_, _ = io.Copy(io.Discard, reader)
+	return f.result, f.err
+}
+
+func (f fakeScanner) Name() string {
+	return "fake"
+}
+
+// startFakeClamd accepts one INSTREAM session, passes the streamed content to reply and sends its answer
+func startFakeClamd(t *testing.T, reply func(content string) string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		command, err := reader.ReadString(0)
+		if err != nil || command != "zINSTREAM\x00" {
+			_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+			return
+		}
+
+		var content strings.Builder
+		size := make([]byte, 4)
+		for {
+			if _, err := io.ReadFull(reader, size); err != nil {
+				return
+			}
+			length := binary.BigEndian.Uint32(size)
+			if length == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, reader, int64(length)); err != nil {
+				return
+			}
+		}
+		_, _ = conn.Write([]byte(reply(content.String()) + "\x00"))
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClamAVScanner_Clean(t *testing.T) {
+	var scanned string
+	address := startFakeClamd(t, func(content string) string {
+		scanned = content
+		return "stream: OK"
+	})
+
+	// larger than a single chunk
+	content := strings.Repeat("a", clamdChunkSize+10)
+	result, err := NewClamAVScanner(address, time.Second).Scan(context.Background(), strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+	assert.Equal(t, content, scanned)
+}
+
+func TestClamAVScanner_Infected(t *testing.T) {
+	address := startFakeClamd(t, func(content string) string {
+		return "stream: Win.Test.EICAR_HDB-1 FOUND"
+	})
+
+	result, err := NewClamAVScanner(address, time.Second).Scan(context.Background(), strings.NewReader("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR"))
+	assert.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Win.Test.EICAR_HDB-1", result.Signature)
+}
+
+func TestClamAVScanner_Errors(t *testing.T) {
+	address := startFakeClamd(t, func(content string) string {
+		return "INSTREAM size limit exceeded. ERROR"
+	})
+	_, err := NewClamAVScanner(address, time.Second).Scan(context.Background(), strings.NewReader("content"))
+	assert.Error(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	unreachableAddress := listener.Addr().String()
+	listener.Close()
+	_, err = NewClamAVScanner(unreachableAddress, time.Second).Scan(context.Background(), strings.NewReader("content"))
+	assert.Error(t, err, "an unreachable scanner must not mark files as clean")
+}
+
+func TestNoopScanner(t *testing.T) {
+	result, err := NoopScanner{}.Scan(context.Background(), strings.NewReader("content"))
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"mime"
 	"mime/multipart"
 	"path/filepath"
 	"strconv"
@@ -28,10 +27,24 @@ type StorageService struct {
 	storageAdapter StorageAdapter
 	maxFileSize    int64
 	allowedTypes   []string
+	scanner        MalwareScanner
+	// pdfRenderer is nil if PDFs get no preview
+	pdfRenderer PDFRenderer
+	// encryption is nil if files are stored unencrypted
+	encryption *fileEncryption
+
+	garbageCollector garbageCollector
+	migration        storageMigrator
 }
 
 var StorageServiceSingleton *StorageService
 
+var (
+	ErrFileInfected    = errors.New("file was rejected by the malware scanner")
+	ErrFileQuarantined = errors.New("file has not passed the malware scan")
+	ErrFileTooLarge    = errors.New("file too large")
+)
+
 // FileUploadRequest represents a file upload request
 type FileUploadRequest struct {
 	File           *multipart.FileHeader
@@ -45,6 +58,7 @@ type FileUploadRequest struct {
 type PresignUploadRequest struct {
 	Filename      string
 	ContentType   string
+	SizeBytes     int64
 	CoursePhaseID *uuid.UUID
 	Description   string
 	Tags          []string
@@ -66,30 +80,38 @@ type CreateFileFromStorageKeyRequest struct {
 
 // FileResponse represents a file in API responses
 type FileResponse struct {
-	ID               uuid.UUID  `json:"id"`
-	Filename         string     `json:"filename"`
-	OriginalFilename string     `json:"originalFilename"`
-	ContentType      string     `json:"contentType"`
-	SizeBytes        int64      `json:"sizeBytes"`
-	StorageKey       string     `json:"storageKey"`
-	DownloadURL      string     `json:"downloadUrl"`
-	UploadedByUserID string     `json:"uploadedByUserId"`
-	UploadedByEmail  string     `json:"uploadedByEmail,omitempty"`
-	CoursePhaseID    *uuid.UUID `json:"coursePhaseId,omitempty"`
-	Description      string     `json:"description,omitempty"`
-	Tags             []string   `json:"tags,omitempty"`
-	CreatedAt        time.Time  `json:"createdAt"`
-	UpdatedAt        time.Time  `json:"updatedAt"`
+	ID               uuid.UUID         `json:"id"`
+	Filename         string            `json:"filename"`
+	OriginalFilename string            `json:"originalFilename"`
+	ContentType      string            `json:"contentType"`
+	SizeBytes        int64             `json:"sizeBytes"`
+	StorageKey       string            `json:"storageKey"`
+	StorageProvider  string            `json:"storageProvider"`
+	DownloadURL      string            `json:"downloadUrl"`
+	PreviewURL       string            `json:"previewUrl,omitempty"`
+	UploadedByUserID string            `json:"uploadedByUserId"`
+	UploadedByEmail  string            `json:"uploadedByEmail,omitempty"`
+	CoursePhaseID    *uuid.UUID        `json:"coursePhaseId,omitempty"`
+	Description      string            `json:"description,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	ScanStatus       db.FileScanStatus `json:"scanStatus"`
+	ContentSHA256    string            `json:"contentSha256,omitempty"`
+	Version          int32             `json:"version"`
+	ReplacedFileID   *uuid.UUID        `json:"replacedFileId,omitempty"`
+	Encrypted        bool              `json:"encrypted"`
+	CreatedAt        time.Time         `json:"createdAt"`
+	UpdatedAt        time.Time         `json:"updatedAt"`
 }
 
 // NewStorageService creates a new storage service instance
-func NewStorageService(queries db.Queries, conn *pgxpool.Pool, adapter StorageAdapter, maxFileSizeMB int64, allowedTypes []string) *StorageService {
+func NewStorageService(queries db.Queries, conn *pgxpool.Pool, adapter StorageAdapter, maxFileSizeMB int64, allowedTypes []string, scanner MalwareScanner) *StorageService {
 	return &StorageService{
 		queries:        queries,
 		conn:           conn,
 		storageAdapter: adapter,
 		maxFileSize:    maxFileSizeMB * 1024 * 1024, // Convert MB to bytes
 		allowedTypes:   allowedTypes,
+		scanner:        scanner,
 	}
 }
 
@@ -97,7 +119,7 @@ func NewStorageService(queries db.Queries, conn *pgxpool.Pool, adapter StorageAd
 func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest) (*FileResponse, error) {
 	// Validate file size
 	if req.File.Size > s.maxFileSize {
-		return nil, fmt.Errorf("file size %d bytes exceeds maximum allowed size %d bytes", req.File.Size, s.maxFileSize)
+		return nil, fmt.Errorf("%w: file size %d bytes exceeds maximum allowed size %d bytes", ErrFileTooLarge, req.File.Size, s.maxFileSize)
 	}
 
 	// Validate file type if restrictions are set
@@ -105,6 +127,10 @@ func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest)
 		return nil, fmt.Errorf("file type %s is not allowed", req.File.Header.Get("Content-Type"))
 	}
 
+	if err := s.checkStorageQuota(ctx, req.CoursePhaseID, req.File.Size); err != nil {
+		return nil, err
+	}
+
 	// Open the uploaded file
 	file, err := req.File.Open()
 	if err != nil {
@@ -117,6 +143,20 @@ func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest)
 		}
 	}()
 
+	// The declared content type is chosen by the client, the content decides
+	head, content, err := readHead(file)
+	if err != nil {
+		log.WithError(err).Error("Failed to read uploaded file")
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	contentType, err := verifyContentType(req.File.Header.Get("Content-Type"), head)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isAllowedType(contentType) {
+		return nil, fmt.Errorf("file type %s is not allowed", contentType)
+	}
+
 	// Generate a unique filename to prevent collisions
 	ext := filepath.Ext(req.File.Filename)
 	safeOriginal := sanitizeFilename(req.File.Filename)
@@ -126,15 +166,33 @@ func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest)
 	uniqueFilename := fmt.Sprintf("%s-%s", uuid.New().String(), safeOriginal)
 	storageKey := buildStorageKey(req.CoursePhaseID, uniqueFilename)
 
-	// Upload to storage backend
-	uploadResult, err := s.storageAdapter.Upload(ctx, storageKey, req.File.Header.Get("Content-Type"), file)
+	encryptionKeyID, dataKey, err := s.dataKeyForCoursePhase(ctx, req.CoursePhaseID)
+	if err != nil {
+		log.WithError(err).Error("Failed to get file encryption key")
+		return nil, err
+	}
+
+	// Upload to storage backend, files are encrypted while they are uploaded
+	plainContent := &countingReader{reader: content}
+	uploadContent, uploadContentType := io.Reader(plainContent), contentType
+	if dataKey != nil {
+		if uploadContent, err = newEncryptingReader(dataKey, plainContent); err != nil {
+			return nil, fmt.Errorf("failed to encrypt file: %w", err)
+		}
+		uploadContentType = encryptedContentType
+	}
+	uploadResult, err := s.storageAdapter.Upload(ctx, storageKey, uploadContentType, uploadContent)
 	if err != nil {
 		log.WithError(err).Error("Failed to upload file to storage backend")
 		return nil, fmt.Errorf("failed to upload file: %w", err)
 	}
+	sizeBytes := uploadResult.Size
+	if dataKey != nil {
+		sizeBytes = plainContent.count
+	}
 
 	// Get storage provider from environment
-	storageProvider := sdkUtils.GetEnv("STORAGE_PROVIDER", "seaweedfs")
+	storageProvider := configuredStorageProvider()
 
 	// Save file metadata to database
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
@@ -158,8 +216,8 @@ func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest)
 	fileRecord, err := s.queries.CreateFile(ctxWithTimeout, db.CreateFileParams{
 		Filename:         uniqueFilename,
 		OriginalFilename: req.File.Filename,
-		ContentType:      req.File.Header.Get("Content-Type"),
-		SizeBytes:        uploadResult.Size,
+		ContentType:      contentType,
+		SizeBytes:        sizeBytes,
 		StorageKey:       uploadResult.StorageKey,
 		StorageProvider:  storageProvider,
 		UploadedByUserID: req.UploaderUserID,
@@ -167,6 +225,7 @@ func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest)
 		CoursePhaseID:    coursePhaseIDPgtype,
 		Description:      descriptionPgtype,
 		Tags:             req.Tags,
+		EncryptionKeyID:  encryptionKeyID,
 	})
 
 	if err != nil {
@@ -179,35 +238,36 @@ func (s *StorageService) UploadFile(ctx context.Context, req FileUploadRequest)
 	log.WithFields(log.Fields{
 		"fileId":     fileRecord.ID,
 		"filename":   uniqueFilename,
-		"size":       uploadResult.Size,
+		"size":       sizeBytes,
 		"uploadedBy": req.UploaderUserID,
 	}).Info("File uploaded successfully")
 
-	return s.convertToFileResponse(ctx, fileRecord), nil
-}
-
-// PresignUpload creates a presigned upload URL for a file
-func (s *StorageService) PresignUpload(ctx context.Context, req PresignUploadRequest) (*PresignUploadResponse, error) {
-	if req.Filename == "" {
-		return nil, fmt.Errorf("filename is required")
+	// the stored file is scanned from the uploaded copy, which saves downloading it again
+	scanContent, err := req.File.Open()
+	if err != nil {
+		log.WithError(err).WithField("fileId", fileRecord.ID).Error("Failed to open uploaded file for scanning")
+		return s.convertToFileResponse(ctx, fileRecord), nil
 	}
+	defer scanContent.Close()
 
-	if req.ContentType == "" {
-		return nil, fmt.Errorf("content type is required")
+	fileRecord, contentSHA256 := s.scanAndHashFile(ctx, fileRecord, scanContent)
+	if fileRecord.ScanStatus == db.FileScanStatusInfected {
+		s.rejectInfectedFile(ctx, fileRecord.ID)
+		return nil, ErrFileInfected
 	}
+	fileRecord = s.deduplicateFile(ctx, fileRecord, contentSHA256)
+	s.generatePreview(ctx, fileRecord)
 
-	if len(s.allowedTypes) > 0 && !s.isAllowedType(req.ContentType) {
-		return nil, fmt.Errorf("file type %s is not allowed", req.ContentType)
-	}
+	return s.convertToFileResponse(ctx, fileRecord), nil
+}
 
-	safeOriginal := sanitizeFilename(req.Filename)
-	if safeOriginal == "" {
-		ext := filepath.Ext(req.Filename)
-		safeOriginal = "file" + ext
+// PresignUpload creates a presigned upload URL for a file
+func (s *StorageService) PresignUpload(ctx context.Context, req PresignUploadRequest) (*PresignUploadResponse, error) {
+	if err := s.validatePresignUpload(ctx, req); err != nil {
+		return nil, err
 	}
-	uniqueFilename := fmt.Sprintf("%s-%s", uuid.New().String(), safeOriginal)
-	storageKey := buildStorageKey(req.CoursePhaseID, uniqueFilename)
 
+	storageKey := newStorageKey(req.CoursePhaseID, req.Filename)
 	uploadURL, err := s.storageAdapter.GetUploadURL(ctx, storageKey, req.ContentType, presignUploadTTLSeconds())
 	if err != nil {
 		return nil, err
@@ -219,6 +279,28 @@ func (s *StorageService) PresignUpload(ctx context.Context, req PresignUploadReq
 	}, nil
 }
 
+// validatePresignUpload checks the declared file before the client uploads it directly to the storage
+func (s *StorageService) validatePresignUpload(ctx context.Context, req PresignUploadRequest) error {
+	if req.Filename == "" {
+		return fmt.Errorf("filename is required")
+	}
+
+	if req.ContentType == "" {
+		return fmt.Errorf("content type is required")
+	}
+
+	if len(s.allowedTypes) > 0 && !s.isAllowedType(req.ContentType) {
+		return fmt.Errorf("file type %s is not allowed", req.ContentType)
+	}
+
+	// the declared size is checked again against the stored object when the upload is completed
+	if req.SizeBytes > s.maxFileSize {
+		return fmt.Errorf("%w: file size %d bytes exceeds maximum allowed size %d bytes", ErrFileTooLarge, req.SizeBytes, s.maxFileSize)
+	}
+
+	return s.checkStorageQuota(ctx, req.CoursePhaseID, req.SizeBytes)
+}
+
 // CreateFileFromStorageKey stores file metadata after a presigned upload completes.
 func (s *StorageService) CreateFileFromStorageKey(ctx context.Context, req CreateFileFromStorageKeyRequest, uploaderUserID, uploaderEmail string) (*FileResponse, error) {
 	if req.StorageKey == "" {
@@ -244,15 +326,8 @@ func (s *StorageService) CreateFileFromStorageKey(ctx context.Context, req Creat
 		return nil, fmt.Errorf("failed to check existing file: %w", err)
 	}
 
-	if req.CoursePhaseID == nil {
-		if strings.HasPrefix(req.StorageKey, "course-phase/") {
-			return nil, fmt.Errorf("course phase id is required for course-phase storage keys")
-		}
-	} else {
-		expectedPrefix := fmt.Sprintf("course-phase/%s/", req.CoursePhaseID.String())
-		if !strings.HasPrefix(req.StorageKey, expectedPrefix) {
-			return nil, fmt.Errorf("storage key does not match course phase")
-		}
+	if err := validateStorageKeyScope(req.StorageKey, req.CoursePhaseID); err != nil {
+		return nil, err
 	}
 
 	metadata, err := s.storageAdapter.GetMetadata(ctx, req.StorageKey)
@@ -269,12 +344,35 @@ func (s *StorageService) CreateFileFromStorageKey(ctx context.Context, req Creat
 		return nil, fmt.Errorf("content type is required")
 	}
 
+	// the declared size cannot be trusted, a multipart upload may even consist of more parts than announced
 	if metadata.Size > s.maxFileSize {
-		return nil, fmt.Errorf("file size %d bytes exceeds maximum allowed size %d bytes", metadata.Size, s.maxFileSize)
+		s.deleteRejectedUpload(ctx, req.StorageKey)
+		return nil, fmt.Errorf("%w: file size %d bytes exceeds maximum allowed size %d bytes", ErrFileTooLarge, metadata.Size, s.maxFileSize)
 	}
 
-	if len(s.allowedTypes) > 0 && !s.isAllowedType(contentType) {
-		return nil, fmt.Errorf("file type %s is not allowed", contentType)
+	if err := s.checkStorageQuota(ctx, req.CoursePhaseID, metadata.Size); err != nil {
+		s.deleteRejectedUpload(ctx, req.StorageKey)
+		return nil, err
+	}
+
+	// presigned uploads bypass the server, so the stored content is checked before it is accepted
+	reader, err := s.storageAdapter.Download(ctx, req.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	defer reader.Close()
+
+	head, content, err := readHead(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	contentType, err = verifyContentType(contentType, head)
+	if err == nil && !s.isAllowedType(contentType) {
+		err = fmt.Errorf("file type %s is not allowed", contentType)
+	}
+	if err != nil {
+		s.deleteRejectedUpload(ctx, req.StorageKey)
+		return nil, err
 	}
 
 	uniqueFilename := filepath.Base(req.StorageKey)
@@ -283,7 +381,7 @@ func (s *StorageService) CreateFileFromStorageKey(ctx context.Context, req Creat
 		originalFilename = uniqueFilename
 	}
 
-	storageProvider := sdkUtils.GetEnv("STORAGE_PROVIDER", "seaweedfs")
+	storageProvider := configuredStorageProvider()
 
 	var coursePhaseIDPgtype pgtype.UUID
 	if req.CoursePhaseID != nil {
@@ -317,9 +415,103 @@ func (s *StorageService) CreateFileFromStorageKey(ctx context.Context, req Creat
 		return nil, fmt.Errorf("failed to save file metadata: %w", err)
 	}
 
+	fileRecord, contentSHA256 := s.scanAndHashFile(ctx, fileRecord, content)
+	if fileRecord.ScanStatus == db.FileScanStatusInfected {
+		s.rejectInfectedFile(ctx, fileRecord.ID)
+		return nil, ErrFileInfected
+	}
+	fileRecord = s.deduplicateFile(ctx, fileRecord, contentSHA256)
+	// the content is only encrypted once it was accepted, rejected uploads are deleted in plain form
+	fileRecord = s.encryptStoredFile(ctx, fileRecord)
+	s.generatePreview(ctx, fileRecord)
+
 	return s.convertToFileResponse(ctx, fileRecord), nil
 }
 
+// deleteRejectedUpload removes an uploaded object that was not accepted, a failure leaves it to the garbage collection
+func (s *StorageService) deleteRejectedUpload(ctx context.Context, storageKey string) {
+	if err := s.storageAdapter.Delete(ctx, storageKey); err != nil {
+		log.WithError(err).WithField("storageKey", storageKey).Warn("Failed to delete rejected upload")
+	}
+}
+
+// rejectInfectedFile soft-deletes an infected upload, so it neither counts towards the storage quota nor can be referenced.
+// The record with its scan result stays as audit trail until the garbage collection removes it together with its object.
+func (s *StorageService) rejectInfectedFile(ctx context.Context, fileID uuid.UUID) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	if err := s.queries.SoftDeleteFile(ctxWithTimeout, fileID); err != nil {
+		log.WithError(err).WithField("fileId", fileID).Error("Failed to delete infected file")
+	}
+}
+
+// scanFile runs the malware scanner on the content of a stored file and saves the verdict.
+// Files stay quarantined if the scanner fails, they are scanned again by the rescan job.
+func (s *StorageService) scanFile(ctx context.Context, file db.File, content io.Reader) db.File {
+	status := db.FileScanStatusClean
+	result := s.scanner.Name() + ": OK"
+
+	verdict, err := s.scanner.Scan(ctx, content)
+	if err != nil {
+		log.WithError(err).WithField("fileId", file.ID).Error("Failed to scan file")
+		status = db.FileScanStatusFailed
+		result = s.scanner.Name() + ": " + err.Error()
+	} else if verdict.Infected {
+		log.WithFields(log.Fields{
+			"fileId":     file.ID,
+			"uploadedBy": file.UploadedByUserID,
+			"signature":  verdict.Signature,
+		}).Warn("Malware detected in uploaded file")
+		status = db.FileScanStatusInfected
+		result = s.scanner.Name() + ": " + verdict.Signature + " FOUND"
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	scannedFile, err := s.queries.UpdateFileScanResult(ctxWithTimeout, db.UpdateFileScanResultParams{
+		ID:         file.ID,
+		ScanStatus: status,
+		ScanResult: pgtype.Text{String: result, Valid: true},
+	})
+	if err != nil {
+		log.WithError(err).WithField("fileId", file.ID).Error("Failed to save scan result")
+		return file
+	}
+	return scannedFile
+}
+
+// ScanPendingFiles scans files whose scan is still pending or has failed, e.g. because the scanner was unavailable.
+// It returns the number of files that are clean now.
+func (s *StorageService) ScanPendingFiles(ctx context.Context, limit int32) (int, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	files, err := s.queries.GetFilesToScan(ctxWithTimeout, limit)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get files to scan: %w", err)
+	}
+
+	cleanCount := 0
+	for _, file := range files {
+		reader, err := s.openFileContent(ctx, file)
+		if err != nil {
+			log.WithError(err).WithField("fileId", file.ID).Warn("Failed to download file for scanning")
+			continue
+		}
+		scannedFile, contentSHA256 := s.scanAndHashFile(ctx, file, reader)
+		reader.Close()
+
+		if scannedFile.ScanStatus == db.FileScanStatusClean {
+			scannedFile = s.deduplicateFile(ctx, scannedFile, contentSHA256)
+			scannedFile = s.encryptStoredFile(ctx, scannedFile)
+			s.generatePreview(ctx, scannedFile)
+			cleanCount++
+		}
+	}
+	return cleanCount, nil
+}
+
 // GetFileByID retrieves a file by its ID
 func (s *StorageService) GetFileByID(ctx context.Context, fileID uuid.UUID) (*FileResponse, error) {
 	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
@@ -333,20 +525,26 @@ func (s *StorageService) GetFileByID(ctx context.Context, fileID uuid.UUID) (*Fi
 	return s.convertToFileResponse(ctx, fileRecord), nil
 }
 
-// DownloadFile retrieves a file's content from storage
+// DownloadFile retrieves a file's content from storage, encrypted files are decrypted while they are read
 func (s *StorageService) DownloadFile(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, string, error) {
-	fileResp, err := s.GetFileByID(ctx, fileID)
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	fileRecord, err := s.queries.GetFileByID(ctxWithTimeout, fileID)
+	cancel()
 	if err != nil {
-		return nil, "", err
+		return nil, "", fmt.Errorf("file not found: %w", err)
 	}
 
-	reader, err := s.storageAdapter.Download(ctx, fileResp.StorageKey)
+	if fileRecord.ScanStatus != db.FileScanStatusClean {
+		return nil, "", ErrFileQuarantined
+	}
+
+	reader, err := s.openFileContent(ctx, fileRecord)
 	if err != nil {
 		log.WithError(err).WithField("fileId", fileID).Error("Failed to download file from storage")
 		return nil, "", fmt.Errorf("failed to download file: %w", err)
 	}
 
-	return reader, fileResp.OriginalFilename, nil
+	return reader, fileRecord.OriginalFilename, nil
 }
 
 // DeleteFile soft deletes a file and optionally removes it from storage
@@ -360,13 +558,28 @@ func (s *StorageService) DeleteFile(ctx context.Context, fileID uuid.UUID, hardD
 	defer cancel()
 
 	if hardDelete {
+		if err := s.deleteFilePreview(ctx, fileID, fileResp.StorageProvider); err != nil {
+			log.WithError(err).WithField("fileId", fileID).Warn("Failed to delete file preview - orphaned storage object")
+		}
+
 		// Hard delete from database
 		if err := s.queries.HardDeleteFile(ctxWithTimeout, fileID); err != nil {
 			return fmt.Errorf("failed to delete file record: %w", err)
 		}
 
+		// deduplicated files share their object, it is deleted together with the last file referencing it
+		references, err := s.countStorageKeyReferences(ctx, fileResp.StorageKey)
+		if err != nil {
+			log.WithError(err).WithField("fileId", fileID).Warn("File record deleted but its storage object could not be checked - left to the garbage collection")
+			return nil
+		}
+		if references > 0 {
+			log.WithField("fileId", fileID).Info("File hard deleted, its storage object is still shared")
+			return nil
+		}
+
 		// Delete from storage backend
-		if err := s.storageAdapter.Delete(ctx, fileResp.StorageKey); err != nil {
+		if err := s.adapterFor(fileResp.StorageProvider).Delete(ctx, fileResp.StorageKey); err != nil {
 			log.WithError(err).WithField("fileId", fileID).Warn("File record deleted but storage deletion failed - orphaned storage object")
 			return nil
 		}
@@ -433,6 +646,33 @@ func buildStorageKey(coursePhaseID *uuid.UUID, filename string) string {
 	return fmt.Sprintf("course-phase/%s/%s", coursePhaseID.String(), filename)
 }
 
+// newStorageKey builds a unique storage key for a file that is uploaded directly to the storage
+func newStorageKey(coursePhaseID *uuid.UUID, filename string) string {
+	safeOriginal := sanitizeFilename(filename)
+	if safeOriginal == "" {
+		ext := filepath.Ext(filename)
+		safeOriginal = "file" + ext
+	}
+	uniqueFilename := fmt.Sprintf("%s-%s", uuid.New().String(), safeOriginal)
+	return buildStorageKey(coursePhaseID, uniqueFilename)
+}
+
+// validateStorageKeyScope ensures that a storage key sent by a client belongs to the course phase of the request
+func validateStorageKeyScope(storageKey string, coursePhaseID *uuid.UUID) error {
+	if coursePhaseID == nil {
+		if strings.HasPrefix(storageKey, "course-phase/") {
+			return fmt.Errorf("course phase id is required for course-phase storage keys")
+		}
+		return nil
+	}
+
+	expectedPrefix := fmt.Sprintf("course-phase/%s/", coursePhaseID.String())
+	if !strings.HasPrefix(storageKey, expectedPrefix) {
+		return fmt.Errorf("storage key does not match course phase")
+	}
+	return nil
+}
+
 func sanitizeFilename(filename string) string {
 	sanitized := filepath.Base(filename)
 	sanitized = strings.TrimSpace(sanitized)
@@ -453,26 +693,16 @@ func (s *StorageService) isAllowedType(contentType string) bool {
 		return true // No restrictions
 	}
 
-	normalizedContentType := strings.TrimSpace(contentType)
+	normalizedContentType := normalizeMediaType(contentType)
 	if normalizedContentType == "" {
 		return false
 	}
-	if mediaType, _, err := mime.ParseMediaType(normalizedContentType); err == nil {
-		normalizedContentType = mediaType
-	} else if separatorIndex := strings.Index(normalizedContentType, ";"); separatorIndex >= 0 {
-		normalizedContentType = strings.TrimSpace(normalizedContentType[:separatorIndex])
-	}
 
 	for _, allowed := range s.allowedTypes {
-		normalizedAllowedType := strings.TrimSpace(allowed)
+		normalizedAllowedType := normalizeMediaType(allowed)
 		if normalizedAllowedType == "" {
 			continue
 		}
-		if mediaType, _, err := mime.ParseMediaType(normalizedAllowedType); err == nil {
-			normalizedAllowedType = mediaType
-		} else if separatorIndex := strings.Index(normalizedAllowedType, ";"); separatorIndex >= 0 {
-			normalizedAllowedType = strings.TrimSpace(normalizedAllowedType[:separatorIndex])
-		}
 
 		if strings.EqualFold(normalizedAllowedType, normalizedContentType) {
 			return true
@@ -483,11 +713,11 @@ func (s *StorageService) isAllowedType(contentType string) bool {
 
 // convertToFileResponse converts a database file record to an API response
 func (s *StorageService) convertToFileResponse(ctx context.Context, file db.File) *FileResponse {
-	// Generate download URL
-	downloadURL, err := s.storageAdapter.GetURL(ctx, file.StorageKey, presignDownloadTTLSeconds())
-	if err != nil {
-		log.WithError(err).WithField("storageKey", file.StorageKey).Warn("Failed to generate download URL")
-		downloadURL = ""
+	// Generate download and preview URLs, quarantined files cannot be downloaded
+	var downloadURL, previewURL string
+	if file.ScanStatus == db.FileScanStatusClean {
+		downloadURL = s.getDownloadURL(ctx, file)
+		previewURL = s.getPreviewURL(ctx, file)
 	}
 
 	response := &FileResponse{
@@ -497,12 +727,26 @@ func (s *StorageService) convertToFileResponse(ctx context.Context, file db.File
 		ContentType:      file.ContentType,
 		SizeBytes:        file.SizeBytes,
 		StorageKey:       file.StorageKey,
+		StorageProvider:  file.StorageProvider,
 		DownloadURL:      downloadURL,
 		UploadedByUserID: file.UploadedByUserID,
+		ScanStatus:       file.ScanStatus,
+		PreviewURL:       previewURL,
+		Version:          file.Version,
+		Encrypted:        file.EncryptionKeyID.Valid,
 		CreatedAt:        file.CreatedAt.Time,
 		UpdatedAt:        file.UpdatedAt.Time,
 	}
 
+	if file.ContentSha256.Valid {
+		response.ContentSHA256 = file.ContentSha256.String
+	}
+
+	if file.PreviousVersionID.Valid {
+		replacedFileID := uuid.UUID(file.PreviousVersionID.Bytes)
+		response.ReplacedFileID = &replacedFileID
+	}
+
 	if file.UploadedByEmail.Valid {
 		response.UploadedByEmail = file.UploadedByEmail.String
 	}
@@ -524,6 +768,25 @@ func (s *StorageService) convertToFileResponse(ctx context.Context, file db.File
 	return response
 }
 
+// getDownloadURL returns a presigned URL of the storage, or a signed URL of the server for encrypted files,
+// which are decrypted on download
+func (s *StorageService) getDownloadURL(ctx context.Context, file db.File) string {
+	if file.EncryptionKeyID.Valid {
+		if s.encryption == nil {
+			log.WithField("fileId", file.ID).Warn("Encrypted file cannot be downloaded without FILE_ENCRYPTION_MASTER_KEY")
+			return ""
+		}
+		return s.encryption.signedURL(encryptedOperationDownload, file.ID, presignDownloadTTLSeconds())
+	}
+
+	url, err := s.adapterFor(file.StorageProvider).GetURL(ctx, file.StorageKey, presignDownloadTTLSeconds())
+	if err != nil {
+		log.WithError(err).WithField("storageKey", file.StorageKey).Warn("Failed to generate download URL")
+		return ""
+	}
+	return url
+}
+
 func presignUploadTTLSeconds() int {
 	return resolvePresignTTLSeconds("S3_PRESIGN_UPLOAD_TTL_SECONDS", 60)
 }
@@ -3,14 +3,20 @@ package storage
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image/color"
 	"io"
 	"mime/multipart"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
-	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	"github.com/prompt-edu/prompt-sdk/testutils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -49,6 +55,7 @@ func (suite *StorageServiceTestSuite) SetupSuite() {
 		suite.mockAdapter,
 		50, // 50 MB max file size
 		[]string{"application/pdf", "image/jpeg", "image/png"},
+		NoopScanner{},
 	)
 
 	StorageServiceSingleton = suite.service
@@ -60,7 +67,7 @@ func (suite *StorageServiceTestSuite) TearDownSuite() {
 
 func (suite *StorageServiceTestSuite) TestUploadFile_Success() {
 	// Create a mock multipart file
-	fileContent := []byte("test PDF file content")
+	fileContent := []byte("%PDF-1.4\ntest PDF file content")
 	fileHeader := suite.createMultipartFileHeader("test-document.pdf", "application/pdf", fileContent)
 
 	req := FileUploadRequest{
@@ -79,6 +86,8 @@ func (suite *StorageServiceTestSuite) TestUploadFile_Success() {
 	assert.Equal(suite.T(), "test-document.pdf", result.OriginalFilename)
 	assert.Equal(suite.T(), "application/pdf", result.ContentType)
 	assert.Equal(suite.T(), int64(len(fileContent)), result.SizeBytes)
+	assert.Equal(suite.T(), db.FileScanStatusClean, result.ScanStatus)
+	assert.NotEmpty(suite.T(), result.DownloadURL)
 }
 
 func (suite *StorageServiceTestSuite) TestUploadFile_FileTooLarge() {
@@ -115,9 +124,61 @@ func (suite *StorageServiceTestSuite) TestUploadFile_InvalidFileType() {
 	assert.Contains(suite.T(), err.Error(), "not allowed")
 }
 
+func (suite *StorageServiceTestSuite) TestUploadFile_ContentDoesNotMatchType() {
+	fileContent := []byte("<html><script>alert('xss')</script></html>")
+	fileHeader := suite.createMultipartFileHeader("cv.pdf", "application/pdf", fileContent)
+
+	req := FileUploadRequest{
+		File:           fileHeader,
+		UploaderUserID: suite.testUserID,
+		UploaderEmail:  "test.user@tum.de",
+	}
+
+	_, err := suite.service.UploadFile(suite.ctx, req)
+
+	assert.Error(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "text/html is not allowed")
+}
+
+func (suite *StorageServiceTestSuite) TestUploadFile_Infected() {
+	infectedService := NewStorageService(suite.service.queries, suite.service.conn, suite.mockAdapter, 50, suite.service.allowedTypes, fakeScanner{result: ScanResult{Infected: true, Signature: "Eicar-Signature"}})
+	fileHeader := suite.createMultipartFileHeader("infected.pdf", "application/pdf", []byte("%PDF-1.4\ninfected content"))
+
+	_, err := infectedService.UploadFile(suite.ctx, FileUploadRequest{
+		File:           fileHeader,
+		UploaderUserID: suite.testUserID,
+		UploaderEmail:  "test.user@tum.de",
+	})
+	assert.ErrorIs(suite.T(), err, ErrFileInfected)
+
+	// the rejected upload is soft-deleted, so it does not count towards the quota
+	files, err := suite.service.GetFilesByUploader(suite.ctx, suite.testUserID, 100, 0)
+	assert.NoError(suite.T(), err)
+	for _, file := range files {
+		assert.NotEqual(suite.T(), "infected.pdf", file.OriginalFilename)
+	}
+}
+
+func (suite *StorageServiceTestSuite) TestUploadFile_ScannerUnavailable() {
+	failingService := NewStorageService(suite.service.queries, suite.service.conn, suite.mockAdapter, 50, suite.service.allowedTypes, fakeScanner{err: errors.New("connection refused")})
+	fileHeader := suite.createMultipartFileHeader("quarantined.pdf", "application/pdf", []byte("%PDF-1.4\nquarantined content"))
+
+	result, err := failingService.UploadFile(suite.ctx, FileUploadRequest{
+		File:           fileHeader,
+		UploaderUserID: suite.testUserID,
+		UploaderEmail:  "test.user@tum.de",
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), db.FileScanStatusFailed, result.ScanStatus)
+	assert.Empty(suite.T(), result.DownloadURL, "quarantined files cannot be downloaded")
+
+	_, _, err = failingService.DownloadFile(suite.ctx, result.ID)
+	assert.ErrorIs(suite.T(), err, ErrFileQuarantined)
+}
+
 func (suite *StorageServiceTestSuite) TestGetFileByID_Success() {
 	// First upload a file
-	fileContent := []byte("test content for retrieval")
+	fileContent := []byte("%PDF-1.4\ntest content for retrieval")
 	fileHeader := suite.createMultipartFileHeader("retrieval-test.pdf", "application/pdf", fileContent)
 
 	uploadReq := FileUploadRequest{
@@ -147,7 +208,7 @@ func (suite *StorageServiceTestSuite) TestGetFileByID_NotFound() {
 
 func (suite *StorageServiceTestSuite) TestDownloadFile_Success() {
 	// Upload a file first
-	fileContent := []byte("content to download")
+	fileContent := []byte("%PDF-1.4\ncontent to download")
 	fileHeader := suite.createMultipartFileHeader("download-test.pdf", "application/pdf", fileContent)
 
 	uploadReq := FileUploadRequest{
@@ -175,7 +236,7 @@ func (suite *StorageServiceTestSuite) TestDownloadFile_Success() {
 
 func (suite *StorageServiceTestSuite) TestDeleteFile_SoftDelete() {
 	// Upload a file first
-	fileContent := []byte("content to delete")
+	fileContent := []byte("%PDF-1.4\ncontent to delete")
 	fileHeader := suite.createMultipartFileHeader("delete-test.pdf", "application/pdf", fileContent)
 
 	uploadReq := FileUploadRequest{
@@ -200,7 +261,7 @@ func (suite *StorageServiceTestSuite) TestDeleteFile_SoftDelete() {
 func (suite *StorageServiceTestSuite) TestGetFilesByUploader() {
 	// Upload multiple files by same user
 	for i := 0; i < 3; i++ {
-		fileContent := []byte("test content")
+		fileContent := []byte("%PDF-1.4\ntest content")
 		fileHeader := suite.createMultipartFileHeader("user-file.pdf", "application/pdf", fileContent)
 
 		req := FileUploadRequest{
@@ -224,7 +285,7 @@ func (suite *StorageServiceTestSuite) TestGetFilesByCoursePhaseID() {
 
 	// Upload files for course phase
 	for i := 0; i < 2; i++ {
-		fileContent := []byte("course phase content")
+		fileContent := []byte("%PDF-1.4\ncourse phase content")
 		fileHeader := suite.createMultipartFileHeader("course-file.pdf", "application/pdf", fileContent)
 
 		req := FileUploadRequest{
@@ -244,6 +305,282 @@ func (suite *StorageServiceTestSuite) TestGetFilesByCoursePhaseID() {
 	assert.GreaterOrEqual(suite.T(), len(files), 2)
 }
 
+func (suite *StorageServiceTestSuite) TestCollectGarbage_DryRun() {
+	orphan := StoredObject{StorageKey: "course-phase/abandoned.pdf", Size: 42, LastModified: time.Now().Add(-48 * time.Hour)}
+	deleted := false
+	adapter := &MockStorageAdapter{
+		ListObjectsFunc: func(ctx context.Context, prefix string) ([]StoredObject, error) {
+			return []StoredObject{orphan}, nil
+		},
+		DeleteFunc: func(ctx context.Context, storageKey string) error {
+			deleted = true
+			return nil
+		},
+	}
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+
+	report, err := service.CollectGarbage(suite.ctx, true)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), report.DryRun)
+	assert.Equal(suite.T(), []StoredObject{orphan}, report.OrphanedObjects)
+	assert.False(suite.T(), deleted, "a dry run does not delete anything")
+	assert.Nil(suite.T(), service.GetLastGarbageCollectionReport(), "dry runs are not stored as last report")
+}
+
+func (suite *StorageServiceTestSuite) TestCollectGarbage_KeepsFilesDeduplicatedDuringCollection() {
+	objects := map[string][]byte{}
+	adapter := newInMemoryStorageAdapter(objects)
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+
+	firstPhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	secondPhaseID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	first, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("transcript.pdf", "application/pdf", []byte("%PDF-1.4\ncollected while deduplicated")),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &firstPhaseID,
+	})
+	assert.NoError(suite.T(), err)
+	second, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("transcript.pdf", "application/pdf", []byte("%PDF-1.4\nnot deduplicated yet")),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &secondPhaseID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), first.StorageKey, second.StorageKey)
+
+	// the second file is deduplicated after the collection loaded the records and before it lists the objects
+	adapter.ListObjectsFunc = func(ctx context.Context, prefix string) ([]StoredObject, error) {
+		secondRecord, err := service.queries.GetFileByID(ctx, second.ID)
+		if err != nil {
+			return nil, err
+		}
+		service.deduplicateFile(ctx, secondRecord, first.ContentSHA256)
+
+		// the objects of the files of other tests are not stored in this adapter
+		records, err := service.queries.GetFileStorageKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		listed := []StoredObject{}
+		for storageKey := range objects {
+			listed = append(listed, StoredObject{StorageKey: storageKey, LastModified: time.Now()})
+		}
+		for _, record := range records {
+			if record.ID != first.ID && record.ID != second.ID {
+				listed = append(listed, StoredObject{StorageKey: record.StorageKey, LastModified: time.Now()})
+			}
+		}
+		return listed, nil
+	}
+
+	report, err := service.CollectGarbage(suite.ctx, false)
+	assert.NoError(suite.T(), err)
+	for _, missing := range report.MissingObjects {
+		assert.NotEqual(suite.T(), second.ID, missing.ID, "the deduplicated file is not reported as missing")
+	}
+
+	deduplicated, err := service.GetFileByID(suite.ctx, second.ID)
+	assert.NoError(suite.T(), err, "the record of the deduplicated file is kept")
+	assert.Equal(suite.T(), first.StorageKey, deduplicated.StorageKey)
+	assert.NotContains(suite.T(), objects, second.StorageKey, "the duplicate object was deleted by the deduplication")
+}
+
+func (suite *StorageServiceTestSuite) TestUploadFile_StorageQuotaExceeded() {
+	coursePhaseID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	fileContent := []byte("%PDF-1.4\nquota content")
+
+	_, err := suite.service.SetCoursePhaseStorageQuota(suite.ctx, coursePhaseID, int64(len(fileContent)))
+	assert.NoError(suite.T(), err)
+	defer func() {
+		assert.NoError(suite.T(), suite.service.DeleteCoursePhaseStorageQuota(suite.ctx, coursePhaseID))
+	}()
+
+	req := FileUploadRequest{
+		File:           suite.createMultipartFileHeader("quota.pdf", "application/pdf", fileContent),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &coursePhaseID,
+	}
+	_, err = suite.service.UploadFile(suite.ctx, req)
+	assert.NoError(suite.T(), err, "the first file fits exactly into the quota")
+
+	req.File = suite.createMultipartFileHeader("quota.pdf", "application/pdf", fileContent)
+	_, err = suite.service.UploadFile(suite.ctx, req)
+	assert.ErrorIs(suite.T(), err, ErrStorageQuotaExceeded)
+
+	_, err = suite.service.PresignUpload(suite.ctx, PresignUploadRequest{
+		Filename:      "quota.pdf",
+		ContentType:   "application/pdf",
+		SizeBytes:     1,
+		CoursePhaseID: &coursePhaseID,
+	})
+	assert.ErrorIs(suite.T(), err, ErrStorageQuotaExceeded)
+}
+
+func (suite *StorageServiceTestSuite) TestGetStorageUsageReport() {
+	courseID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+
+	fileContent := []byte("%PDF-1.4\nusage content")
+	_, err := suite.service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("usage.pdf", "application/pdf", fileContent),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &coursePhaseID,
+	})
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.service.SetCourseStorageQuota(suite.ctx, courseID, 1024*1024)
+	assert.NoError(suite.T(), err)
+	defer func() {
+		assert.NoError(suite.T(), suite.service.DeleteCourseStorageQuota(suite.ctx, courseID))
+	}()
+
+	report, err := suite.service.GetStorageUsageReport(suite.ctx)
+	assert.NoError(suite.T(), err)
+	assert.GreaterOrEqual(suite.T(), report.TotalBytes, int64(len(fileContent)))
+
+	var courseUsage *CourseStorageUsage
+	for i := range report.ByCourse {
+		if report.ByCourse[i].CourseID == courseID {
+			courseUsage = &report.ByCourse[i]
+		}
+	}
+	if assert.NotNil(suite.T(), courseUsage) {
+		assert.GreaterOrEqual(suite.T(), courseUsage.UsedBytes, int64(len(fileContent)))
+		assert.Equal(suite.T(), int64(1024*1024), *courseUsage.QuotaBytes)
+	}
+	assert.NotEmpty(suite.T(), report.ByCoursePhase)
+	assert.NotEmpty(suite.T(), report.ByUploader)
+	assert.NotEmpty(suite.T(), report.ByContentType)
+}
+
+func (suite *StorageServiceTestSuite) TestGetCoursePhaseFiles_FilterByTagsAndUploader() {
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	studentID := "12121212-1212-1212-1212-121212121212"
+
+	upload := func(uploaderUserID string, tags []string) *FileResponse {
+		file, err := suite.service.UploadFile(suite.ctx, FileUploadRequest{
+			File:           suite.createMultipartFileHeader("tagged.pdf", "application/pdf", []byte("%PDF-1.4\ntagged content")),
+			UploaderUserID: uploaderUserID,
+			CoursePhaseID:  &coursePhaseID,
+			Tags:           tags,
+		})
+		assert.NoError(suite.T(), err)
+		return file
+	}
+	contract := upload(studentID, []string{"contract", "signed"})
+	unsigned := upload(studentID, []string{"contract"})
+	upload(suite.testUserID, []string{"contract", "signed"})
+
+	files, err := suite.service.GetCoursePhaseFiles(suite.ctx, coursePhaseID, []string{"contract", "signed"}, studentID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), files, 1)
+	assert.Equal(suite.T(), contract.ID, files[0].ID)
+
+	files, err = suite.service.GetCoursePhaseFiles(suite.ctx, coursePhaseID, nil, studentID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), files, 2, "no tags match every file of the uploader")
+	assert.ElementsMatch(suite.T(), []uuid.UUID{contract.ID, unsigned.ID}, []uuid.UUID{files[0].ID, files[1].ID})
+}
+
+func (suite *StorageServiceTestSuite) TestGetCoursePhaseFiles_HidesApplicationAndNoteFiles() {
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	noteID := uuid.MustParse("77777777-7777-7777-7777-777777777777")
+
+	upload := func(content string) *FileResponse {
+		file, err := suite.service.UploadFile(suite.ctx, FileUploadRequest{
+			File:           suite.createMultipartFileHeader("hidden.pdf", "application/pdf", []byte("%PDF-1.4\n"+content)),
+			UploaderUserID: suite.testUserID,
+			CoursePhaseID:  &coursePhaseID,
+			Tags:           []string{"hidden"},
+		})
+		assert.NoError(suite.T(), err)
+		return file
+	}
+	shared, replacedCV, cv, noteFile := upload("shared"), upload("old cv"), upload("cv"), upload("note")
+
+	_, err := suite.service.conn.Exec(suite.ctx,
+		"INSERT INTO application_answer_file_upload (application_question_id, course_participation_id, file_id) VALUES ($1, $2, $3)",
+		uuid.New(), uuid.New(), cv.ID)
+	assert.NoError(suite.T(), err)
+	_, err = suite.service.queries.LinkFileVersion(suite.ctx, db.LinkFileVersionParams{PreviousVersionID: replacedCV.ID, ID: cv.ID})
+	assert.NoError(suite.T(), err)
+	_, err = suite.service.conn.Exec(suite.ctx, "INSERT INTO note_file (note_id, file_id) VALUES ($1, $2)", noteID, noteFile.ID)
+	assert.NoError(suite.T(), err)
+
+	files, err := suite.service.GetCoursePhaseFiles(suite.ctx, coursePhaseID, []string{"hidden"}, "")
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), files, 1, "files of applications and notes are not listed") {
+		assert.Equal(suite.T(), shared.ID, files[0].ID)
+	}
+
+	for _, file := range []*FileResponse{replacedCV, cv, noteFile} {
+		_, err = suite.service.GetCoursePhaseFile(suite.ctx, coursePhaseID, file.ID)
+		assert.ErrorIs(suite.T(), err, ErrFileNotFound)
+	}
+	_, err = suite.service.GetCoursePhaseFile(suite.ctx, coursePhaseID, shared.ID)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *StorageServiceTestSuite) TestNoteFiles() {
+	noteID := uuid.MustParse("77777777-7777-7777-7777-777777777777")
+
+	file, err := suite.service.AttachFileToNote(suite.ctx, noteID, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("screenshot.png", "image/png", []byte("\x89PNG\x0D\x0A\x1A\x0Ascreenshot")),
+		UploaderUserID: suite.testUserID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), file.CoursePhaseID)
+
+	files, err := suite.service.GetNoteFiles(suite.ctx, noteID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), files, 1)
+
+	otherFile, err := suite.service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("other.pdf", "application/pdf", []byte("%PDF-1.4\nother")),
+		UploaderUserID: suite.testUserID,
+	})
+	assert.NoError(suite.T(), err)
+	err = suite.service.DeleteNoteFile(suite.ctx, noteID, otherFile.ID)
+	assert.ErrorIs(suite.T(), err, ErrFileNotFound, "files of other notes cannot be deleted")
+
+	assert.NoError(suite.T(), suite.service.DeleteNoteFile(suite.ctx, noteID, file.ID))
+	files, err = suite.service.GetNoteFiles(suite.ctx, noteID)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), files)
+}
+
+func (suite *StorageServiceTestSuite) TestCoursePhaseParticipationFiles() {
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	otherCoursePhaseID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	courseParticipationID := uuid.MustParse("88888888-8888-8888-8888-888888888888")
+
+	file, err := suite.service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("contract.pdf", "application/pdf", []byte("%PDF-1.4\ncontract")),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &coursePhaseID,
+	})
+	assert.NoError(suite.T(), err)
+
+	err = suite.service.AttachFileToCoursePhaseParticipation(suite.ctx, otherCoursePhaseID, courseParticipationID, file.ID)
+	assert.ErrorIs(suite.T(), err, ErrFileNotInCoursePhase)
+
+	err = suite.service.AttachFileToCoursePhaseParticipation(suite.ctx, coursePhaseID, uuid.New(), file.ID)
+	assert.ErrorIs(suite.T(), err, ErrParticipationNotFound)
+
+	assert.NoError(suite.T(), suite.service.AttachFileToCoursePhaseParticipation(suite.ctx, coursePhaseID, courseParticipationID, file.ID))
+	files, err := suite.service.GetCoursePhaseParticipationFiles(suite.ctx, coursePhaseID, courseParticipationID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), files, 1)
+
+	assert.NoError(suite.T(), suite.service.DetachFileFromCoursePhaseParticipation(suite.ctx, coursePhaseID, courseParticipationID, file.ID))
+	files, err = suite.service.GetCoursePhaseParticipationFiles(suite.ctx, coursePhaseID, courseParticipationID)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), files)
+
+	_, err = suite.service.GetCoursePhaseFile(suite.ctx, coursePhaseID, file.ID)
+	assert.NoError(suite.T(), err, "the file stays in the course phase")
+}
+
 // Helper method to create a multipart file header for testing
 func (suite *StorageServiceTestSuite) createMultipartFileHeader(filename, contentType string, content []byte) *multipart.FileHeader {
 	// Create a buffer to write multipart data
@@ -275,6 +612,322 @@ func (suite *StorageServiceTestSuite) createMultipartFileHeader(filename, conten
 	return nil
 }
 
+func (suite *StorageServiceTestSuite) TestInitiateMultipartUpload() {
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	req := PresignUploadRequest{
+		Filename:      "submission.pdf",
+		ContentType:   "application/pdf",
+		SizeBytes:     40 * 1024 * 1024,
+		CoursePhaseID: &coursePhaseID,
+	}
+
+	response, err := suite.service.InitiateMultipartUpload(suite.ctx, req)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "mock-upload-id", response.UploadID)
+	assert.True(suite.T(), strings.HasPrefix(response.StorageKey, "course-phase/"+coursePhaseID.String()+"/"))
+	assert.Equal(suite.T(), int64(16*1024*1024), response.PartSize)
+	assert.Equal(suite.T(), int32(3), response.PartCount)
+
+	req.SizeBytes = 51 * 1024 * 1024
+	_, err = suite.service.InitiateMultipartUpload(suite.ctx, req)
+	assert.ErrorIs(suite.T(), err, ErrFileTooLarge)
+
+	req.SizeBytes = 0
+	_, err = suite.service.InitiateMultipartUpload(suite.ctx, req)
+	assert.ErrorIs(suite.T(), err, ErrInvalidMultipartUpload)
+}
+
+func (suite *StorageServiceTestSuite) TestCompleteMultipartUpload() {
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	storageKey := "course-phase/" + coursePhaseID.String() + "/multipart-video.pdf"
+	var completedParts []UploadedPart
+	adapter := &MockStorageAdapter{
+		CompleteMultipartUploadFunc: func(ctx context.Context, key string, uploadID string, parts []UploadedPart) error {
+			completedParts = parts
+			return nil
+		},
+		DownloadFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("%PDF-1.4\nmultipart content")), nil
+		},
+	}
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+
+	parts := []UploadedPart{{PartNumber: 1, ETag: "\"etag-1\""}, {PartNumber: 2, ETag: "\"etag-2\""}}
+	file, err := service.CompleteMultipartUpload(suite.ctx, CompleteMultipartUploadRequest{
+		CreateFileFromStorageKeyRequest: CreateFileFromStorageKeyRequest{
+			StorageKey:       storageKey,
+			OriginalFilename: "video.pdf",
+			ContentType:      "application/pdf",
+			CoursePhaseID:    &coursePhaseID,
+		},
+		UploadID: "upload-id",
+		Parts:    parts,
+	}, suite.testUserID, "")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), parts, completedParts)
+	assert.Equal(suite.T(), storageKey, file.StorageKey)
+	assert.Equal(suite.T(), "video.pdf", file.OriginalFilename)
+
+	otherPhaseID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	_, err = service.CompleteMultipartUpload(suite.ctx, CompleteMultipartUploadRequest{
+		CreateFileFromStorageKeyRequest: CreateFileFromStorageKeyRequest{StorageKey: storageKey, CoursePhaseID: &otherPhaseID},
+		UploadID:                        "upload-id",
+		Parts:                           parts,
+	}, suite.testUserID, "")
+	assert.ErrorIs(suite.T(), err, ErrInvalidMultipartUpload, "the storage key must belong to the course phase")
+}
+
+func (suite *StorageServiceTestSuite) TestCompleteMultipartUpload_FileTooLarge() {
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	storageKey := "course-phase/" + coursePhaseID.String() + "/too-large.pdf"
+	deletedKey := ""
+	adapter := &MockStorageAdapter{
+		GetMetadataFunc: func(ctx context.Context, key string) (*FileMetadata, error) {
+			return &FileMetadata{StorageKey: key, ContentType: "application/pdf", Size: 51 * 1024 * 1024}, nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			deletedKey = key
+			return nil
+		},
+	}
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+
+	_, err := service.CompleteMultipartUpload(suite.ctx, CompleteMultipartUploadRequest{
+		CreateFileFromStorageKeyRequest: CreateFileFromStorageKeyRequest{StorageKey: storageKey, CoursePhaseID: &coursePhaseID},
+		UploadID:                        "upload-id",
+		Parts:                           []UploadedPart{{PartNumber: 1, ETag: "etag"}},
+	}, suite.testUserID, "")
+	assert.ErrorIs(suite.T(), err, ErrFileTooLarge)
+	assert.Equal(suite.T(), storageKey, deletedKey, "the assembled file is deleted when it exceeds the limit")
+}
+
+func (suite *StorageServiceTestSuite) TestUploadFile_ImagePreview() {
+	fileContent := encodeTestPNG(suite.T(), 800, 400, color.NRGBA{B: 255, A: 255})
+	uploaded := map[string][]byte{}
+	adapter := &MockStorageAdapter{
+		UploadFunc: func(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
+			content, err := io.ReadAll(reader)
+			uploaded[storageKey] = content
+			return &UploadResult{StorageKey: storageKey, Size: int64(len(content))}, err
+		},
+		DownloadFunc: func(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(uploaded[storageKey])), nil
+		},
+	}
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+
+	result, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("portfolio.png", "image/png", fileContent),
+		UploaderUserID: suite.testUserID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), result.PreviewURL, previewStorageKey(result.ID))
+
+	preview, err := service.queries.GetFilePreview(suite.ctx, result.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int32(320), preview.Width)
+	assert.Equal(suite.T(), int32(160), preview.Height)
+	assert.Equal(suite.T(), previewContentType, preview.ContentType)
+	assert.NotEmpty(suite.T(), uploaded[preview.StorageKey])
+
+	pdf, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("cv.pdf", "application/pdf", []byte("%PDF-1.4\ncv")),
+		UploaderUserID: suite.testUserID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), pdf.PreviewURL, "pdfs get no preview without a renderer")
+}
+
+func (suite *StorageServiceTestSuite) TestUploadFile_DeduplicatesWithinCourse() {
+	fileContent := []byte("%PDF-1.4\nsame content in two phases")
+	uploaded := map[string][]byte{}
+	deletedKeys := []string{}
+	adapter := &MockStorageAdapter{
+		UploadFunc: func(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
+			content, err := io.ReadAll(reader)
+			uploaded[storageKey] = content
+			return &UploadResult{StorageKey: storageKey, Size: int64(len(content))}, err
+		},
+		DeleteFunc: func(ctx context.Context, storageKey string) error {
+			deletedKeys = append(deletedKeys, storageKey)
+			return nil
+		},
+	}
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+
+	// both phases belong to the same course
+	firstPhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	secondPhaseID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	first, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("cv.pdf", "application/pdf", fileContent),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &firstPhaseID,
+	})
+	assert.NoError(suite.T(), err)
+	second, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("resume.pdf", "application/pdf", fileContent),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &secondPhaseID,
+	})
+	assert.NoError(suite.T(), err)
+
+	sum := sha256.Sum256(fileContent)
+	assert.Equal(suite.T(), hex.EncodeToString(sum[:]), first.ContentSHA256)
+	assert.Equal(suite.T(), first.ContentSHA256, second.ContentSHA256)
+	assert.Equal(suite.T(), first.StorageKey, second.StorageKey, "the second file shares the object of the first")
+	assert.Equal(suite.T(), "resume.pdf", second.OriginalFilename)
+	assert.Len(suite.T(), deletedKeys, 1, "the duplicate upload is deleted")
+	assert.NotEqual(suite.T(), first.StorageKey, deletedKeys[0])
+
+	assert.NoError(suite.T(), service.DeleteFile(suite.ctx, first.ID, true))
+	assert.Len(suite.T(), deletedKeys, 1, "the shared object is kept while another file references it")
+	assert.NoError(suite.T(), service.DeleteFile(suite.ctx, second.ID, true))
+	assert.Equal(suite.T(), first.StorageKey, deletedKeys[1], "the object is deleted with the last file referencing it")
+}
+
+func (suite *StorageServiceTestSuite) TestUploadFile_Encrypted() {
+	fileContent := []byte("%PDF-1.4\nconfidential transcript")
+	uploaded := map[string][]byte{}
+	adapter := &MockStorageAdapter{
+		UploadFunc: func(ctx context.Context, storageKey string, contentType string, reader io.Reader) (*UploadResult, error) {
+			content, err := io.ReadAll(reader)
+			uploaded[storageKey] = content
+			return &UploadResult{StorageKey: storageKey, Size: int64(len(content))}, err
+		},
+		DownloadFunc: func(ctx context.Context, storageKey string) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(uploaded[storageKey])), nil
+		},
+	}
+	service := NewStorageService(suite.service.queries, suite.service.conn, adapter, 50, suite.service.allowedTypes, NoopScanner{})
+	encryption, err := newFileEncryption(testEncryptionKey(1), nil, "http://localhost:8080/api")
+	assert.NoError(suite.T(), err)
+	service.encryption = encryption
+
+	coursePhaseID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	result, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("transcript.pdf", "application/pdf", fileContent),
+		UploaderUserID: suite.testUserID,
+		CoursePhaseID:  &coursePhaseID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Encrypted)
+	assert.Equal(suite.T(), int64(len(fileContent)), result.SizeBytes, "the size excludes the encryption overhead")
+	assert.NotContains(suite.T(), string(uploaded[result.StorageKey]), "confidential")
+	assert.Contains(suite.T(), result.DownloadURL, "/storage/encrypted/download?")
+
+	reader, _, err := service.DownloadFile(suite.ctx, result.ID)
+	assert.NoError(suite.T(), err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), fileContent, content)
+
+	// the previous master key stays available until all data keys are wrapped with the new one
+	rotatedEncryption, err := newFileEncryption(testEncryptionKey(2), [][]byte{testEncryptionKey(1)}, "http://localhost:8080/api")
+	assert.NoError(suite.T(), err)
+	service.encryption = rotatedEncryption
+	rotated, err := service.RotateEncryptionKeys(suite.ctx)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, rotated)
+
+	service.encryption, err = newFileEncryption(testEncryptionKey(2), nil, "http://localhost:8080/api")
+	assert.NoError(suite.T(), err)
+	reader, _, err = service.DownloadFile(suite.ctx, result.ID)
+	assert.NoError(suite.T(), err)
+	content, err = io.ReadAll(reader)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), fileContent, content, "the files are readable with the new master key only")
+
+	rotated, err = service.RotateEncryptionKeys(suite.ctx)
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), rotated)
+}
+
+func (suite *StorageServiceTestSuite) TestMigrateStorage() {
+	sourceObjects, targetObjects := map[string][]byte{}, map[string][]byte{}
+	service := NewStorageService(suite.service.queries, suite.service.conn, newInMemoryStorageAdapter(sourceObjects), 50, suite.service.allowedTypes, NoopScanner{})
+
+	fileContent := encodeTestPNG(suite.T(), 64, 64, color.NRGBA{G: 255, A: 255})
+	file, err := service.UploadFile(suite.ctx, FileUploadRequest{
+		File:           suite.createMultipartFileHeader("photo.png", "image/png", fileContent),
+		UploaderUserID: suite.testUserID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), StorageProviderSeaweedFS, file.StorageProvider)
+
+	// the new storage is configured, the old one stays available as migration source
+	suite.T().Setenv("STORAGE_PROVIDER", "s3")
+	service.storageAdapter = newInMemoryStorageAdapter(targetObjects)
+	service.migration.sourceProvider = StorageProviderSeaweedFS
+	service.migration.source = newInMemoryStorageAdapter(sourceObjects)
+
+	reader, _, err := service.DownloadFile(suite.ctx, file.ID)
+	assert.NoError(suite.T(), err, "files are served from the source until they are migrated")
+	reader.Close()
+
+	report, err := service.MigrateStorage(suite.ctx)
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), report.Running)
+	assert.Equal(suite.T(), "s3", report.TargetProvider)
+	assert.NotZero(suite.T(), report.MigratedObjects)
+
+	migrated, err := service.GetFileByID(suite.ctx, file.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "s3", migrated.StorageProvider)
+	assert.Equal(suite.T(), fileContent, targetObjects[file.StorageKey])
+	assert.NotEmpty(suite.T(), targetObjects[previewStorageKey(file.ID)], "the preview is migrated with its file")
+	assert.NotEmpty(suite.T(), sourceObjects[file.StorageKey], "the source objects are kept")
+
+	// files of other tests have no object in the source, they stay with the source provider
+	rerun, err := service.MigrateStorage(suite.ctx)
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), rerun.MigratedObjects)
+	assert.Equal(suite.T(), int64(len(report.FailedObjects)), rerun.TotalObjects, "only the failed objects are left")
+}
+
+func (suite *StorageServiceTestSuite) TestGetFileVersions() {
+	upload := func(content string) *FileResponse {
+		file, err := suite.service.UploadFile(suite.ctx, FileUploadRequest{
+			File:           suite.createMultipartFileHeader("cv.pdf", "application/pdf", []byte("%PDF-1.4\n"+content)),
+			UploaderUserID: suite.testUserID,
+		})
+		assert.NoError(suite.T(), err)
+		return file
+	}
+	first, second, third := upload("first version"), upload("second version"), upload("third version")
+
+	for _, link := range []db.LinkFileVersionParams{
+		{PreviousVersionID: first.ID, ID: second.ID},
+		{PreviousVersionID: second.ID, ID: third.ID},
+	} {
+		linked, err := suite.service.queries.LinkFileVersion(suite.ctx, link)
+		assert.NoError(suite.T(), err)
+		assert.Equal(suite.T(), int64(1), linked)
+	}
+
+	linked, err := suite.service.queries.LinkFileVersion(suite.ctx, db.LinkFileVersionParams{PreviousVersionID: third.ID, ID: first.ID})
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), linked, "a file of the chain cannot become its own successor")
+
+	latest, err := suite.service.GetFileByID(suite.ctx, third.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int32(3), latest.Version)
+	assert.Equal(suite.T(), second.ID, *latest.ReplacedFileID)
+
+	versions, err := suite.service.GetFileVersions(suite.ctx, third.ID)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), versions, 2) {
+		assert.Equal(suite.T(), second.ID, versions[0].ID)
+		assert.Equal(suite.T(), first.ID, versions[1].ID)
+	}
+
+	assert.NoError(suite.T(), suite.service.DeleteFile(suite.ctx, second.ID, false))
+	versions, err = suite.service.GetFileVersions(suite.ctx, third.ID)
+	assert.NoError(suite.T(), err)
+	if assert.Len(suite.T(), versions, 1, "deleted versions are skipped") {
+		assert.Equal(suite.T(), first.ID, versions[0].ID)
+	}
+}
+
 func TestStorageServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(StorageServiceTestSuite))
 }
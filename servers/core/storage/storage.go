@@ -2,9 +2,31 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"io"
+	"strings"
+	"time"
+
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+)
+
+// Storage providers selectable via STORAGE_PROVIDER, stored with every file
+// Every provider except the local one is accessed through the S3 API
+const (
+	StorageProviderLocal     = "local"
+	StorageProviderSeaweedFS = "seaweedfs"
 )
 
+// configuredStorageProvider returns STORAGE_PROVIDER as it is set, since existing files store the value unchanged
+func configuredStorageProvider() string {
+	return sdkUtils.GetEnv("STORAGE_PROVIDER", StorageProviderSeaweedFS)
+}
+
+// isLocalStorageProvider reports whether a storage provider value selects the local filesystem, ignoring its case
+func isLocalStorageProvider(storageProvider string) bool {
+	return strings.EqualFold(strings.TrimSpace(storageProvider), StorageProviderLocal)
+}
+
 // FileMetadata represents metadata about an uploaded file
 type FileMetadata struct {
 	StorageKey  string
@@ -20,6 +42,21 @@ type UploadResult struct {
 	Size       int64
 }
 
+// StoredObject describes an object in the storage backend, independent of the files table
+type StoredObject struct {
+	StorageKey   string    `json:"storageKey"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// UploadedPart identifies a part of a multipart upload by the ETag the storage returned for it
+type UploadedPart struct {
+	PartNumber int32  `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
+
+var ErrMultipartUploadNotSupported = errors.New("multipart uploads are not supported by the storage backend")
+
 // StorageAdapter defines the interface for file storage operations
 // This allows different storage backends (SeaweedFS, S3, etc.) to be used interchangeably
 type StorageAdapter interface {
@@ -43,4 +80,20 @@ type StorageAdapter interface {
 
 	// GetMetadata retrieves metadata about a stored file without downloading it
 	GetMetadata(ctx context.Context, storageKey string) (*FileMetadata, error)
+
+	// ListObjects returns all stored objects whose storage key starts with prefix
+	// An empty prefix lists the complete storage
+	ListObjects(ctx context.Context, prefix string) ([]StoredObject, error)
+
+	// CreateMultipartUpload starts an upload in parts and returns its upload id
+	CreateMultipartUpload(ctx context.Context, storageKey string, contentType string) (string, error)
+
+	// GetUploadPartURL returns a temporary/signed URL for uploading one part, part numbers start at 1
+	GetUploadPartURL(ctx context.Context, storageKey string, uploadID string, partNumber int32, ttl int) (string, error)
+
+	// CompleteMultipartUpload assembles the uploaded parts into the stored file
+	CompleteMultipartUpload(ctx context.Context, storageKey string, uploadID string, parts []UploadedPart) error
+
+	// AbortMultipartUpload discards an unfinished upload together with its uploaded parts
+	AbortMultipartUpload(ctx context.Context, storageKey string, uploadID string) error
 }
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	log "github.com/sirupsen/logrus"
+)
+
+// scanAndHashFile hashes the content while the scanner reads it, so the content is read only once.
+// The hash is empty if the content could not be read completely.
+func (s *StorageService) scanAndHashFile(ctx context.Context, file db.File, content io.Reader) (db.File, string) {
+	hash := sha256.New()
+	scannedFile := s.scanFile(ctx, file, io.TeeReader(content, hash))
+
+	// scanners may stop reading early, the rest of the content is hashed without them
+	if _, err := io.Copy(hash, content); err != nil {
+		log.WithError(err).WithField("fileId", file.ID).Warn("Failed to hash file content")
+		return scannedFile, ""
+	}
+	return scannedFile, hex.EncodeToString(hash.Sum(nil))
+}
+
+// deduplicateFile saves the content hash of a file. If a clean file of the same course has the same content,
+// the file shares the object of that file and its own object is deleted.
+// Objects are only shared if they are encrypted with the key the file would be encrypted with.
+// Deduplication only saves storage, failures are logged and the file keeps its own object.
+func (s *StorageService) deduplicateFile(ctx context.Context, file db.File, contentSHA256 string) db.File {
+	if contentSHA256 == "" {
+		return file
+	}
+
+	storageKey, encryptionKeyID := file.StorageKey, file.EncryptionKeyID
+	if file.ScanStatus == db.FileScanStatusClean && file.CoursePhaseID.Valid {
+		duplicateEncryptionKeyID := file.EncryptionKeyID
+		if !duplicateEncryptionKeyID.Valid && s.encryption != nil {
+			coursePhaseID := uuid.UUID(file.CoursePhaseID.Bytes)
+			keyID, _, err := s.dataKeyForCoursePhase(ctx, &coursePhaseID)
+			if err != nil {
+				log.WithError(err).WithField("fileId", file.ID).Warn("Failed to get encryption key for deduplication")
+			}
+			duplicateEncryptionKeyID = keyID
+		}
+
+		ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+		duplicate, err := s.queries.GetDuplicateFileInCourse(ctxWithTimeout, db.GetDuplicateFileInCourseParams{
+			CoursePhaseID:   file.CoursePhaseID.Bytes,
+			ContentSha256:   pgtype.Text{String: contentSHA256, Valid: true},
+			StorageProvider: file.StorageProvider,
+			EncryptionKeyID: duplicateEncryptionKeyID,
+			ID:              file.ID,
+		})
+		cancel()
+		if err == nil {
+			storageKey, encryptionKeyID = duplicate.StorageKey, duplicate.EncryptionKeyID
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			log.WithError(err).WithField("fileId", file.ID).Warn("Failed to look up duplicate files")
+		}
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	updatedFile, err := s.queries.UpdateFileContent(ctxWithTimeout, db.UpdateFileContentParams{
+		ID:              file.ID,
+		ContentSha256:   pgtype.Text{String: contentSHA256, Valid: true},
+		StorageKey:      storageKey,
+		EncryptionKeyID: encryptionKeyID,
+	})
+	if err != nil {
+		log.WithError(err).WithField("fileId", file.ID).Warn("Failed to save file content hash")
+		return file
+	}
+
+	if storageKey != file.StorageKey {
+		if err := s.adapterFor(file.StorageProvider).Delete(ctx, file.StorageKey); err != nil {
+			log.WithError(err).WithField("storageKey", file.StorageKey).Warn("Failed to delete duplicate upload - left to the garbage collection")
+		}
+		log.WithFields(log.Fields{
+			"fileId":     file.ID,
+			"storageKey": storageKey,
+		}).Info("File deduplicated")
+	}
+	return updatedFile
+}
+
+// countStorageKeyReferences counts the files, including soft-deleted ones, that reference a stored object
+func (s *StorageService) countStorageKeyReferences(ctx context.Context, storageKey string) (int64, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+	return s.queries.CountFilesByStorageKey(ctxWithTimeout, storageKey)
+}
+
+// GetFileVersions returns the prior versions of a file, the newest first.
+// Soft-deleted versions are skipped, the chain continues with their predecessors.
+func (s *StorageService) GetFileVersions(ctx context.Context, fileID uuid.UUID) ([]FileResponse, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	versions, err := s.queries.GetFileVersions(ctxWithTimeout, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve file versions: %w", err)
+	}
+
+	responses := make([]FileResponse, len(versions))
+	for i, version := range versions {
+		responses[i] = *s.convertToFileResponse(ctx, version)
+	}
+	return responses, nil
+}
@@ -0,0 +1,113 @@
+package student
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	"github.com/prompt-edu/prompt/servers/core/utils"
+)
+
+// identityFields are cached by the phase servers, e.g. the names of team members and tutors
+var identityFields = map[string]bool{
+	"firstName":           true,
+	"lastName":            true,
+	"email":               true,
+	"matriculationNumber": true,
+	"universityLogin":     true,
+}
+
+type profileFieldChange struct {
+	field    string
+	oldValue string
+	newValue string
+}
+
+// getProfileChanges lists the fields that differ between the two versions of a student, named like in the student DTO
+func getProfileChanges(previous, updated studentDTO.Student) []profileFieldChange {
+	fields := []profileFieldChange{
+		{"firstName", previous.FirstName, updated.FirstName},
+		{"lastName", previous.LastName, updated.LastName},
+		{"email", previous.Email, updated.Email},
+		{"matriculationNumber", previous.MatriculationNumber, updated.MatriculationNumber},
+		{"universityLogin", previous.UniversityLogin, updated.UniversityLogin},
+		{"hasUniversityAccount", strconv.FormatBool(previous.HasUniversityAccount), strconv.FormatBool(updated.HasUniversityAccount)},
+		{"gender", string(previous.Gender), string(updated.Gender)},
+		{"nationality", previous.Nationality, updated.Nationality},
+		{"studyDegree", string(previous.StudyDegree), string(updated.StudyDegree)},
+		{"studyProgram", previous.StudyProgram, updated.StudyProgram},
+		{"currentSemester", formatSemester(previous.CurrentSemester), formatSemester(updated.CurrentSemester)},
+	}
+
+	changes := make([]profileFieldChange, 0)
+	for _, field := range fields {
+		if field.oldValue != field.newValue {
+			changes = append(changes, field)
+		}
+	}
+	return changes
+}
+
+func formatSemester(semester pgtype.Int4) string {
+	if !semester.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(semester.Int32))
+}
+
+func recordProfileChanges(ctx context.Context, queries *db.Queries, previous, updated studentDTO.Student, changedBy studentDTO.ProfileChangeAuthor) error {
+	changedByID := pgtype.UUID{Bytes: changedBy.UserID, Valid: changedBy.UserID != uuid.Nil}
+
+	for _, change := range getProfileChanges(previous, updated) {
+		err := queries.CreateStudentProfileChange(ctx, db.CreateStudentProfileChangeParams{
+			ID:              uuid.New(),
+			StudentID:       updated.ID,
+			Field:           change.field,
+			OldValue:        change.oldValue,
+			NewValue:        change.newValue,
+			Source:          changedBy.Source,
+			ChangedBy:       changedByID,
+			ChangedByName:   changedBy.Name,
+			ChangedByEmail:  changedBy.Email,
+			IsIdentityField: identityFields[change.field],
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetProfileChangeAuthor returns the user of the request as author of student profile changes
+func GetProfileChangeAuthor(c *gin.Context, source db.StudentProfileChangeSource) studentDTO.ProfileChangeAuthor {
+	// the changes are recorded without user ID if the token does not carry one
+	userID, _ := utils.GetUserUUIDFromContext(c)
+
+	return studentDTO.ProfileChangeAuthor{
+		UserID: userID,
+		Name:   strings.TrimSpace(utils.GetUserNameFromContext(c)),
+		Email:  utils.GetUserEmailFromContext(c),
+		Source: source,
+	}
+}
+
+func GetStudentProfileHistory(ctx context.Context, studentID uuid.UUID) ([]studentDTO.ProfileChange, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	changes, err := StudentServiceSingleton.queries.GetStudentProfileChanges(ctxWithTimeout, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]studentDTO.ProfileChange, 0, len(changes))
+	for _, change := range changes {
+		history = append(history, studentDTO.GetProfileChangeDTOFromDBModel(change))
+	}
+	return history, nil
+}
@@ -0,0 +1,64 @@
+package student
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProfileChanges(t *testing.T) {
+	previous := studentDTO.Student{
+		FirstName:            "Niclas",
+		LastName:             "Heun",
+		Email:                "niclas.heun@tum.de",
+		MatriculationNumber:  "03711126",
+		UniversityLogin:      "ge25hok",
+		HasUniversityAccount: true,
+		Gender:               db.GenderMale,
+		Nationality:          "DE",
+		StudyDegree:          db.StudyDegreeBachelor,
+		StudyProgram:         "Computer Science",
+	}
+
+	assert.Empty(t, getProfileChanges(previous, previous))
+
+	updated := previous
+	updated.LastName = "Heun-Schmidt"
+	updated.StudyDegree = db.StudyDegreeMaster
+	updated.CurrentSemester = pgtype.Int4{Int32: 1, Valid: true}
+
+	assert.Equal(t, []profileFieldChange{
+		{field: "lastName", oldValue: "Heun", newValue: "Heun-Schmidt"},
+		{field: "studyDegree", oldValue: "bachelor", newValue: "master"},
+		{field: "currentSemester", oldValue: "", newValue: "1"},
+	}, getProfileChanges(previous, updated))
+}
+
+func TestIdentityFields(t *testing.T) {
+	assert.True(t, identityFields["firstName"])
+	assert.True(t, identityFields["universityLogin"])
+	assert.False(t, identityFields["studyProgram"], "phase servers do not cache the study program")
+}
+
+func TestUpdateStudentProfileApplyTo(t *testing.T) {
+	student := studentDTO.Student{
+		FirstName:       "Niclas",
+		LastName:        "Heun",
+		Nationality:     "DE",
+		StudyDegree:     db.StudyDegreeBachelor,
+		StudyProgram:    "Computer Science",
+		CurrentSemester: pgtype.Int4{Int32: 4, Valid: true},
+	}
+	studyProgram := "Information Systems"
+	semester := int32(5)
+
+	updated := studentDTO.UpdateStudentProfile{StudyProgram: &studyProgram, CurrentSemester: &semester}.ApplyTo(student)
+
+	assert.Equal(t, "Information Systems", updated.StudyProgram)
+	assert.Equal(t, pgtype.Int4{Int32: 5, Valid: true}, updated.CurrentSemester)
+	assert.Equal(t, "Heun", updated.LastName)
+	assert.Equal(t, db.StudyDegreeBachelor, updated.StudyDegree)
+}
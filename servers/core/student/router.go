@@ -1,11 +1,15 @@
 package student
 
 import (
+	"database/sql"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	"github.com/prompt-edu/prompt/servers/core/utils"
@@ -13,49 +17,91 @@ import (
 
 func setupStudentRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionRoleMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
 	student := router.Group("/students", authMiddleware())
-	student.GET("/with-courses", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getAllStudentsWithCourses)
-	student.GET("/search/:searchString", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), searchStudents)
+	student.GET("/self", getOwnStudent)
+	student.PUT("/self", updateOwnStudentProfile)
+	student.GET("/with-courses", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), searchStudentsWithCourses)
+	student.GET("/search", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), searchStudents)
+	student.GET("/search/:searchString", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), searchStudentsBySearchString)
 	student.GET("/:uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getStudentByID)
 	student.GET("/", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getAllStudents)
 	student.POST("/", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), createStudent)
 	student.PUT("/:uuid", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), updateStudent)
 	student.GET("/:uuid/enrollments", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getStudentEnrollments)
+	student.GET("/:uuid/history", permissionRoleMiddleware(permissionValidation.PromptAdmin, permissionValidation.PromptLecturer), getStudentProfileHistory)
 }
 
 // getAllStudents godoc
 // @Summary Get all students
-// @Description Get a list of all students
+// @Description Get a list of the students, all of them unless a limit is given. The total count is sent in the X-Total-Count header, the next page in the Link header.
 // @Tags students
 // @Produce json
+// @Param limit query int false "Maximum number of students, at most 500"
+// @Param cursor query string false "Cursor of the next page from the Link header"
+// @Param sortBy query string false "Sort field" Enums(lastName, firstName, email, matriculationNumber)
+// @Param sortOrder query string false "Sort order" Enums(asc, desc)
+// @Param passStatus query string false "Only students with a course phase participation with this pass status" Enums(passed, failed, not_assessed)
+// @Param fields query string false "Comma-separated student fields to return, e.g. id,firstName,lastName"
 // @Success 200 {array} studentDTO.Student
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /students/ [get]
 func getAllStudents(c *gin.Context) {
-	students, err := GetAllStudents(c)
+	params, err := listQuery.Parse(c, studentListOptions)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	students, total, nextCursor, err := GetAllStudents(c, params)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	c.IndentedJSON(http.StatusOK, students)
+	projectedStudents, err := listQuery.Project(students, params.Fields)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	listQuery.SetHeaders(c, total, nextCursor)
+	c.IndentedJSON(http.StatusOK, projectedStudents)
 }
 
-// getAllStudentsWithCourses() godoc
-// @Summary Get all students with courses
-// @Description Get a list of all students with the property 'courses' a list of courses that the student is taking part of or was
+// searchStudentsWithCourses godoc
+// @Summary Search students with courses
+// @Description Search students like /students/search, with the property 'courses' a list of courses that the student is taking part of or was
 // @Tags students
 // @Produce json
-// @Success 200 {array} studentDTO.StudentWithCourseParticipationsDTO
+// @Param q query string false "Search terms, each has to match the beginning of a name, email address, matriculation number or university login"
+// @Param courseID query string false "Only students that take part in this course"
+// @Param semesterTag query string false "Only students that take part in a course of this semester"
+// @Param passStatus query string false "Only students with a course phase participation with this pass status" Enums(passed, failed, not_assessed)
+// @Param studyProgram query string false "Study program"
+// @Param nationality query string false "Nationality"
+// @Param sortBy query string false "Sort field" Enums(lastName, firstName, email, matriculationNumber)
+// @Param sortOrder query string false "Sort order" Enums(asc, desc)
+// @Param limit query int false "Page size, at most 500" default(50)
+// @Param cursor query string false "The nextCursor of the previous page, also sent in the Link header"
+// @Success 200 {object} studentDTO.StudentWithCoursesSearchPage
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /students/with-courses [get]
-func getAllStudentsWithCourses(c *gin.Context) {
-	studentsWithCourses, err := GetAllStudentsWithCourses(c)
+func searchStudentsWithCourses(c *gin.Context) {
+	filter, err := parseStudentSearchFilter(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := SearchStudentsWithCourses(c, filter)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
 	}
+	listQuery.SetHeaders(c, page.TotalCount, page.NextCursor)
 
-	c.IndentedJSON(http.StatusOK, studentsWithCourses)
+	c.IndentedJSON(http.StatusOK, page)
 }
 
 // getStudentByID godoc
@@ -153,7 +199,7 @@ func updateStudent(c *gin.Context) {
 		return
 	}
 
-	student, err := UpdateStudent(c, nil, id, updateStudent)
+	student, err := UpdateStudent(c, nil, id, updateStudent, GetProfileChangeAuthor(c, db.StudentProfileChangeSourceLecturer))
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
@@ -164,22 +210,65 @@ func updateStudent(c *gin.Context) {
 
 // searchStudents godoc
 // @Summary Search students
-// @Description Search students by a search string
+// @Description Full-text search of students with filters, sorting and cursor pagination
+// @Tags students
+// @Produce json
+// @Param q query string false "Search terms, each has to match the beginning of a name, email address, matriculation number or university login"
+// @Param courseID query string false "Only students that take part in this course"
+// @Param semesterTag query string false "Only students that take part in a course of this semester"
+// @Param passStatus query string false "Only students with a course phase participation with this pass status" Enums(passed, failed, not_assessed)
+// @Param studyProgram query string false "Study program"
+// @Param nationality query string false "Nationality"
+// @Param sortBy query string false "Sort field" Enums(lastName, firstName, email, matriculationNumber)
+// @Param sortOrder query string false "Sort order" Enums(asc, desc)
+// @Param limit query int false "Page size, at most 500" default(50)
+// @Param cursor query string false "The nextCursor of the previous page, also sent in the Link header"
+// @Success 200 {object} studentDTO.StudentSearchPage
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/search [get]
+func searchStudents(c *gin.Context) {
+	filter, err := parseStudentSearchFilter(c)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	page, err := SearchStudents(c, filter)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	listQuery.SetHeaders(c, page.TotalCount, page.NextCursor)
+	c.IndentedJSON(http.StatusOK, page)
+}
+
+// searchStudentsBySearchString godoc
+// @Summary Search students by a search string
+// @Description Deprecated, use /students/search?q= instead. Returns all students matching the search string, every term has to match the beginning of a name, email address, matriculation number or university login.
 // @Tags students
 // @Produce json
 // @Param searchString path string true "Search string"
 // @Success 200 {array} studentDTO.Student
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
+// @Deprecated
 // @Router /students/search/{searchString} [get]
-func searchStudents(c *gin.Context) {
-	searchString := c.Param("searchString")
+func searchStudentsBySearchString(c *gin.Context) {
+	filter := studentDTO.StudentSearchFilter{Query: strings.TrimSpace(c.Param("searchString"))}
+	if buildSearchQuery(filter.Query) == "" {
+		handleError(c, http.StatusBadRequest, errors.New("the search does not contain any letters or digits"))
+		return
+	}
 
-	students, err := SearchStudents(c, searchString)
+	page, err := SearchStudents(c, filter)
 	if err != nil {
 		handleError(c, http.StatusInternalServerError, err)
 		return
 	}
-	c.IndentedJSON(http.StatusOK, students)
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</api/students/search>; rel="successor-version"`)
+	c.IndentedJSON(http.StatusOK, page.Students)
 }
 
 // getStudentEnrollments godoc
@@ -206,6 +295,103 @@ func getStudentEnrollments(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, studentEnrollments)
 }
 
+// getStudentProfileHistory godoc
+// @Summary Get the profile history of a student
+// @Description Get the changes of the student profile, newest first, with who changed each field
+// @Tags students
+// @Produce json
+// @Param uuid path string true "Student UUID"
+// @Success 200 {array} studentDTO.ProfileChange
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/{uuid}/history [get]
+func getStudentProfileHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	history, err := GetStudentProfileHistory(c, id)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, history)
+}
+
+// getOwnStudent godoc
+// @Summary Get own student profile
+// @Description Get the student profile of the current user
+// @Tags students
+// @Produce json
+// @Success 200 {object} studentDTO.Student
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/self [get]
+func getOwnStudent(c *gin.Context) {
+	matriculationNumber := c.GetString("matriculationNumber")
+	universityLogin := c.GetString("universityLogin")
+	if universityLogin == "" {
+		handleError(c, http.StatusNotFound, errors.New("no student profile found"))
+		return
+	}
+
+	student, err := ResolveStudentByUniversityCredentials(c, &StudentServiceSingleton.queries, matriculationNumber, universityLogin)
+	if errors.Is(err, sql.ErrNoRows) {
+		handleError(c, http.StatusNotFound, errors.New("no student profile found"))
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, student)
+}
+
+// updateOwnStudentProfile godoc
+// @Summary Update own student profile
+// @Description Correct the gender, nationality, study degree, study program or semester of the current user, omitted fields are kept
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param profile body studentDTO.UpdateStudentProfile true "Profile fields to update"
+// @Success 200 {object} studentDTO.Student
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/self [put]
+func updateOwnStudentProfile(c *gin.Context) {
+	matriculationNumber := c.GetString("matriculationNumber")
+	universityLogin := c.GetString("universityLogin")
+	if universityLogin == "" {
+		handleError(c, http.StatusNotFound, errors.New("no student profile found"))
+		return
+	}
+
+	var update studentDTO.UpdateStudentProfile
+	if err := c.BindJSON(&update); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := ValidateProfileUpdate(update); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	student, err := UpdateOwnStudentProfile(c, matriculationNumber, universityLogin, update, GetProfileChangeAuthor(c, db.StudentProfileChangeSourceSelfService))
+	if errors.Is(err, sql.ErrNoRows) {
+		handleError(c, http.StatusNotFound, errors.New("no student profile found"))
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, student)
+}
+
 func handleError(c *gin.Context, statusCode int, err error) {
 	c.JSON(statusCode, utils.ErrorResponse{
 		Error: err.Error(),
@@ -0,0 +1,173 @@
+package student
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+)
+
+const defaultStudentSearchLimit = 50
+
+// the sort keys are computed in SearchStudents
+var studentListOptions = listQuery.Options{
+	SortFields:       []string{"lastName", "firstName", "email", "matriculationNumber"},
+	PassStatusFilter: true,
+}
+
+// buildSearchQuery turns the search string into a full-text query in which every term has to match the beginning of a word.
+// Characters with a meaning in tsquery syntax are dropped, so that user input cannot produce invalid queries.
+func buildSearchQuery(search string) string {
+	terms := make([]string, 0)
+	for _, field := range strings.Fields(search) {
+		term := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("@.-_", r) {
+				return r
+			}
+			return -1
+		}, field)
+		if strings.IndexFunc(term, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		terms = append(terms, "'"+term+"':*")
+	}
+	return strings.Join(terms, " & ")
+}
+
+// parseStudentSearchFilter reads the filters of the student search and the list parameters from the query parameters
+func parseStudentSearchFilter(c *gin.Context) (studentDTO.StudentSearchFilter, error) {
+	searchOptions := studentListOptions
+	searchOptions.DefaultLimit = defaultStudentSearchLimit
+	params, err := listQuery.Parse(c, searchOptions)
+	if err != nil {
+		return studentDTO.StudentSearchFilter{}, err
+	}
+
+	filter := studentDTO.StudentSearchFilter{
+		Query:        strings.TrimSpace(c.Query("q")),
+		SemesterTag:  strings.TrimSpace(c.Query("semesterTag")),
+		StudyProgram: strings.TrimSpace(c.Query("studyProgram")),
+		Nationality:  strings.TrimSpace(c.Query("nationality")),
+		List:         params,
+	}
+
+	if courseID := c.Query("courseID"); courseID != "" {
+		id, err := uuid.Parse(courseID)
+		if err != nil {
+			return studentDTO.StudentSearchFilter{}, errors.New("courseID is invalid")
+		}
+		filter.CourseID = id
+	}
+
+	if filter.Query != "" && buildSearchQuery(filter.Query) == "" {
+		return studentDTO.StudentSearchFilter{}, errors.New("the search does not contain any letters or digits")
+	}
+	return filter, nil
+}
+
+func getSearchStudentsParams(filter studentDTO.StudentSearchFilter) db.SearchStudentsParams {
+	params := db.SearchStudentsParams{
+		SortBy:       filter.List.SortByOrDefault("lastName"),
+		StudyProgram: pgtype.Text{String: filter.StudyProgram, Valid: filter.StudyProgram != ""},
+		Nationality:  pgtype.Text{String: filter.Nationality, Valid: filter.Nationality != ""},
+		CourseID:     pgtype.UUID{Bytes: filter.CourseID, Valid: filter.CourseID != uuid.Nil},
+		SemesterTag:  pgtype.Text{String: filter.SemesterTag, Valid: filter.SemesterTag != ""},
+		PassStatus:   filter.List.PassStatusFilter(),
+		CursorKey:    filter.List.CursorKey(),
+		CursorID:     filter.List.CursorID(),
+		Descending:   filter.List.Descending,
+		PageSize:     filter.List.PageSize(),
+	}
+
+	if query := buildSearchQuery(filter.Query); query != "" {
+		params.Query = pgtype.Text{String: query, Valid: true}
+	}
+	return params
+}
+
+// searchStudentRows returns the students of the requested page, the total number of matching students and the cursor of the next page
+func searchStudentRows(ctx context.Context, filter studentDTO.StudentSearchFilter) ([]db.SearchStudentsRow, int64, string, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	rows, err := StudentServiceSingleton.queries.SearchStudents(ctxWithTimeout, getSearchStudentsParams(filter))
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	var total int64
+	if len(rows) > 0 {
+		total = rows[0].TotalCount
+	}
+	rows, nextCursor := listQuery.TrimPage(filter.List, rows, func(row db.SearchStudentsRow) (string, uuid.UUID) {
+		return row.SortKey, row.ID
+	})
+	return rows, total, nextCursor, nil
+}
+
+func SearchStudents(ctx context.Context, filter studentDTO.StudentSearchFilter) (studentDTO.StudentSearchPage, error) {
+	rows, total, nextCursor, err := searchStudentRows(ctx, filter)
+	if err != nil {
+		return studentDTO.StudentSearchPage{}, err
+	}
+
+	page := studentDTO.StudentSearchPage{
+		Students:   make([]studentDTO.Student, 0, len(rows)),
+		TotalCount: total,
+		NextCursor: nextCursor,
+	}
+	for _, row := range rows {
+		page.Students = append(page.Students, studentDTO.GetStudentDTOFromSearchRow(row))
+	}
+	return page, nil
+}
+
+// SearchStudentsWithCourses searches like SearchStudents and adds the courses and note tags of the students of the page
+func SearchStudentsWithCourses(ctx context.Context, filter studentDTO.StudentSearchFilter) (studentDTO.StudentWithCoursesSearchPage, error) {
+	rows, total, nextCursor, err := searchStudentRows(ctx, filter)
+	if err != nil {
+		return studentDTO.StudentWithCoursesSearchPage{}, err
+	}
+
+	studentIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		studentIDs = append(studentIDs, row.ID)
+	}
+
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	studentsWithCourses, err := StudentServiceSingleton.queries.GetStudentsWithCourseParticipations(ctxWithTimeout, studentIDs)
+	if err != nil {
+		return studentDTO.StudentWithCoursesSearchPage{}, err
+	}
+
+	studentsByID := make(map[uuid.UUID]studentDTO.StudentWithCourseParticipationsDTO, len(studentsWithCourses))
+	for _, studentWithCourses := range studentsWithCourses {
+		dto, err := studentDTO.GetStudentWithCoursesFromDB(studentWithCourses)
+		if err != nil {
+			return studentDTO.StudentWithCoursesSearchPage{}, err
+		}
+		studentsByID[dto.ID] = dto
+	}
+
+	page := studentDTO.StudentWithCoursesSearchPage{
+		Students:   make([]studentDTO.StudentWithCourseParticipationsDTO, 0, len(rows)),
+		TotalCount: total,
+		NextCursor: nextCursor,
+	}
+	// keep the order of the search
+	for _, studentID := range studentIDs {
+		if dto, found := studentsByID[studentID]; found {
+			page.Students = append(page.Students, dto)
+		}
+	}
+	return page, nil
+}
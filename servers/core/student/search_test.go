@@ -0,0 +1,174 @@
+package student
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestBuildSearchQuery(t *testing.T) {
+	assert.Equal(t, "'Niclas':* & 'Heun':*", buildSearchQuery("  Niclas   Heun "))
+	assert.Equal(t, "'niclas.heun@tum.de':*", buildSearchQuery("niclas.heun@tum.de"))
+	assert.Equal(t, "'oneil':* & 'x':*", buildSearchQuery("o'neil & (x)"), "tsquery operators are dropped")
+	assert.Equal(t, "", buildSearchQuery("-- ! |"))
+}
+
+func newSearchContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/students/search?"+query, nil)
+	return c
+}
+
+func TestParseStudentSearchFilter(t *testing.T) {
+	courseID := uuid.New()
+
+	filter, err := parseStudentSearchFilter(newSearchContext("q=heun&courseID=" + courseID.String() + "&semesterTag=ios2425&passStatus=passed&nationality=DE&sortBy=email&sortOrder=desc&limit=20"))
+	require.NoError(t, err)
+	assert.Equal(t, studentDTO.StudentSearchFilter{
+		Query:       "heun",
+		CourseID:    courseID,
+		SemesterTag: "ios2425",
+		Nationality: "DE",
+		List: listQuery.Params{
+			Limit:      20,
+			SortBy:     "email",
+			Descending: true,
+			PassStatus: db.PassStatusPassed,
+		},
+	}, filter)
+
+	filter, err = parseStudentSearchFilter(newSearchContext(""))
+	require.NoError(t, err)
+	assert.Equal(t, "lastName", filter.List.SortByOrDefault("lastName"))
+	assert.False(t, filter.List.Descending)
+	assert.Equal(t, int32(defaultStudentSearchLimit), filter.List.Limit)
+
+	for _, query := range []string{
+		"courseID=ios",
+		"passStatus=accepted",
+		"sortBy=gender",
+		"sortOrder=up",
+		"limit=0",
+		"limit=501",
+		"q=%26%26",
+		"metadata.score=1",
+	} {
+		_, err := parseStudentSearchFilter(newSearchContext(query))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestGetSearchStudentsParams(t *testing.T) {
+	cursor := listQuery.Cursor{SortBy: "email", SortKey: "a@tum.de", ID: uuid.New()}
+
+	params := getSearchStudentsParams(studentDTO.StudentSearchFilter{List: listQuery.Params{SortBy: "email", Limit: 10, Cursor: &cursor}})
+	assert.Equal(t, "email", params.SortBy)
+	assert.Equal(t, "a@tum.de", params.CursorKey.String)
+	assert.Equal(t, pgtype.Int4{Int32: 11, Valid: true}, params.PageSize)
+	assert.False(t, params.Query.Valid)
+
+	params = getSearchStudentsParams(studentDTO.StudentSearchFilter{Query: "heun"})
+	assert.Equal(t, "lastName", params.SortBy)
+	assert.False(t, params.PageSize.Valid, "Expected all students without limit")
+	assert.Equal(t, "'heun':*", params.Query.String)
+}
+
+type SearchTestSuite struct {
+	suite.Suite
+	ctx     context.Context
+	cleanup func()
+}
+
+func (suite *SearchTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	testDB, cleanup, err := sdkTestUtils.SetupTestDB(suite.ctx, "../database_dumps/full_db.sql", func(conn *pgxpool.Pool) *db.Queries { return db.New(conn) })
+	if err != nil {
+		log.Fatalf("Failed to set up test database: %v", err)
+	}
+
+	suite.cleanup = cleanup
+	StudentServiceSingleton = &StudentService{
+		queries: *testDB.Queries,
+		conn:    testDB.Conn,
+	}
+}
+
+func (suite *SearchTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *SearchTestSuite) TestSearchStudentsPages() {
+	filter := studentDTO.StudentSearchFilter{Query: "heun niclas", List: listQuery.Params{Limit: 2}}
+
+	studentIDs := make([]string, 0)
+	pages := 0
+	for {
+		page, err := SearchStudents(suite.ctx, filter)
+		suite.Require().NoError(err)
+		pages++
+		assert.Equal(suite.T(), int64(5), page.TotalCount)
+		for _, student := range page.Students {
+			studentIDs = append(studentIDs, student.ID.String())
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor, err := listQuery.DecodeCursor(page.NextCursor)
+		suite.Require().NoError(err)
+		filter.List.Cursor = &cursor
+	}
+
+	assert.Equal(suite.T(), 3, pages)
+	assert.Equal(suite.T(), []string{
+		"1c62c564-491b-43e3-9929-7be39509e32e",
+		"3869f209-9a21-4595-ae0e-bc6d6a3e2d63",
+		"5eb545c2-c2eb-4c77-9c0f-46ccf7c45d07",
+		"9c157166-dd37-42f6-98ab-f5fda439ced1",
+		// Heuni is sorted after Heun
+		"5939210d-5c47-446e-ba55-3da992fd7aa6",
+	}, studentIDs)
+}
+
+func (suite *SearchTestSuite) TestSearchStudentsFilters() {
+	page, err := SearchStudents(suite.ctx, studentDTO.StudentSearchFilter{Query: "heun", Nationality: "de", List: listQuery.Params{Limit: 50}})
+	suite.Require().NoError(err)
+
+	assert.Len(suite.T(), page.Students, 5)
+	assert.Equal(suite.T(), int64(5), page.TotalCount)
+	assert.Empty(suite.T(), page.NextCursor)
+	for _, student := range page.Students {
+		assert.Equal(suite.T(), "DE", student.Nationality)
+	}
+}
+
+func (suite *SearchTestSuite) TestDeprecatedSearchRoute() {
+	req, _ := http.NewRequest("GET", "/api/students/search/heun%20niclas", nil)
+	w := httptest.NewRecorder()
+	setupRouter().ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "true", w.Header().Get("Deprecation"))
+	var students []studentDTO.Student
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &students), "the deprecated route still returns a list of students")
+	assert.Len(suite.T(), students, 5, "the deprecated route returns all matching students")
+}
+
+func TestSearchTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchTestSuite))
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	"github.com/prompt-edu/prompt/servers/core/utils"
 )
@@ -20,36 +22,14 @@ type StudentService struct {
 
 var StudentServiceSingleton *StudentService
 
-func GetAllStudents(ctx context.Context) ([]studentDTO.Student, error) {
-	students, err := StudentServiceSingleton.queries.GetAllStudents(ctx)
+// GetAllStudents returns a page of the students matching the list parameters,
+// the total number of matching students and the cursor of the next page
+func GetAllStudents(ctx context.Context, params listQuery.Params) ([]studentDTO.Student, int64, string, error) {
+	page, err := SearchStudents(ctx, studentDTO.StudentSearchFilter{List: params})
 	if err != nil {
-		return nil, err
+		return nil, 0, "", err
 	}
-
-	dtoStudents := make([]studentDTO.Student, 0, len(students))
-	for _, student := range students {
-		dtoStudents = append(dtoStudents, studentDTO.GetStudentDTOFromDBModel(student))
-	}
-	return dtoStudents, nil
-}
-
-func GetAllStudentsWithCourses(ctx context.Context) ([]studentDTO.StudentWithCourseParticipationsDTO, error) {
-	studentsWithCourses, err := StudentServiceSingleton.queries.GetAllStudentsWithCourseParticipations(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	dtoStudentsWithCourses := make([]studentDTO.StudentWithCourseParticipationsDTO, 0, len(studentsWithCourses))
-
-	for _, studentWithCourse := range studentsWithCourses {
-		dto, err := studentDTO.GetStudentWithCoursesFromDB(studentWithCourse)
-		if err != nil {
-			return nil, err
-		}
-		dtoStudentsWithCourses = append(dtoStudentsWithCourses, dto)
-	}
-
-	return dtoStudentsWithCourses, nil
+	return page.Students, page.TotalCount, page.NextCursor, nil
 }
 
 func GetStudentByID(ctx context.Context, id uuid.UUID) (studentDTO.Student, error) {
@@ -132,8 +112,37 @@ func ResolveStudentByUniversityCredentials(ctx context.Context, queries *db.Quer
 	return GetStudentByUniversityLogin(ctx, queries, universityLogin)
 }
 
-func UpdateStudent(ctx context.Context, transactionQueries *db.Queries, id uuid.UUID, student studentDTO.CreateStudent) (studentDTO.Student, error) {
-	queries := utils.GetQueries(transactionQueries, &StudentServiceSingleton.queries)
+// UpdateStudent overwrites the student and records the changed fields in the profile history of the student
+func UpdateStudent(ctx context.Context, transactionQueries *db.Queries, id uuid.UUID, student studentDTO.CreateStudent, changedBy studentDTO.ProfileChangeAuthor) (studentDTO.Student, error) {
+	if transactionQueries != nil {
+		return updateStudentWithHistory(ctx, transactionQueries, id, student, changedBy)
+	}
+
+	// the student and its history are updated together
+	tx, err := StudentServiceSingleton.conn.Begin(ctx)
+	if err != nil {
+		return studentDTO.Student{}, err
+	}
+	defer sdkUtils.DeferRollback(tx, ctx)
+	qtx := StudentServiceSingleton.queries.WithTx(tx)
+
+	updatedStudent, err := updateStudentWithHistory(ctx, qtx, id, student, changedBy)
+	if err != nil {
+		return studentDTO.Student{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return studentDTO.Student{}, err
+	}
+	return updatedStudent, nil
+}
+
+func updateStudentWithHistory(ctx context.Context, queries *db.Queries, id uuid.UUID, student studentDTO.CreateStudent, changedBy studentDTO.ProfileChangeAuthor) (studentDTO.Student, error) {
+	previousStudent, err := queries.GetStudent(ctx, id)
+	if err != nil {
+		return studentDTO.Student{}, err
+	}
+
 	updateStudentParams := student.GetDBModel()
 	updateStudentParams.ID = id
 
@@ -142,10 +151,16 @@ func UpdateStudent(ctx context.Context, transactionQueries *db.Queries, id uuid.
 		return studentDTO.Student{}, err
 	}
 
-	return studentDTO.GetStudentDTOFromDBModel(updatedStudent), nil
+	updatedStudentDTO := studentDTO.GetStudentDTOFromDBModel(updatedStudent)
+	err = recordProfileChanges(ctx, queries, studentDTO.GetStudentDTOFromDBModel(previousStudent), updatedStudentDTO, changedBy)
+	if err != nil {
+		return studentDTO.Student{}, err
+	}
+
+	return updatedStudentDTO, nil
 }
 
-func CreateOrUpdateStudent(ctx context.Context, transactionQueries *db.Queries, studentInput studentDTO.CreateStudent) (studentDTO.Student, error) {
+func CreateOrUpdateStudent(ctx context.Context, transactionQueries *db.Queries, studentInput studentDTO.CreateStudent, changedBy studentDTO.ProfileChangeAuthor) (studentDTO.Student, error) {
 	queries := utils.GetQueries(transactionQueries, &StudentServiceSingleton.queries)
 
 	var existingStudent studentDTO.Student
@@ -185,21 +200,18 @@ func CreateOrUpdateStudent(ctx context.Context, transactionQueries *db.Queries,
 			StudyDegree:          studentInput.StudyDegree,
 			StudyProgram:         studentInput.StudyProgram,
 			CurrentSemester:      studentInput.CurrentSemester,
-		})
+		}, changedBy)
 	}
 }
 
-func SearchStudents(ctx context.Context, searchString string) ([]studentDTO.Student, error) {
-	students, err := StudentServiceSingleton.queries.SearchStudents(ctx, pgtype.Text{String: searchString, Valid: true})
+// UpdateOwnStudentProfile applies the changes of a student to their own profile, the student is identified by the university login
+func UpdateOwnStudentProfile(ctx context.Context, matriculationNumber, universityLogin string, update studentDTO.UpdateStudentProfile, changedBy studentDTO.ProfileChangeAuthor) (studentDTO.Student, error) {
+	existingStudent, err := ResolveStudentByUniversityCredentials(ctx, &StudentServiceSingleton.queries, matriculationNumber, universityLogin)
 	if err != nil {
-		return nil, err
+		return studentDTO.Student{}, err
 	}
 
-	dtoStudents := make([]studentDTO.Student, 0, len(students))
-	for _, student := range students {
-		dtoStudents = append(dtoStudents, studentDTO.GetStudentDTOFromDBModel(student))
-	}
-	return dtoStudents, nil
+	return UpdateStudent(ctx, nil, existingStudent.ID, update.ApplyTo(existingStudent), changedBy)
 }
 
 func GetStudentEnrollmentsByID(ctx context.Context, id uuid.UUID) (studentDTO.StudentEnrollmentsDTO, error) {
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -44,9 +45,11 @@ func (suite *ServiceTestSuite) TearDownSuite() {
 }
 
 func (suite *ServiceTestSuite) TestGetAllStudents() {
-	students, err := GetAllStudents(suite.ctx)
+	students, total, nextCursor, err := GetAllStudents(suite.ctx, listQuery.Params{})
 	assert.NoError(suite.T(), err)
 	assert.Greater(suite.T(), len(students), 0, "Expected at least one student in the initial data")
+	assert.Equal(suite.T(), int64(len(students)), total)
+	assert.Empty(suite.T(), nextCursor, "Expected all students without limit")
 }
 
 func (suite *ServiceTestSuite) TestGetStudentByID() {
@@ -104,6 +107,41 @@ func (suite *ServiceTestSuite) TestCreateStudent() {
 	assert.Equal(suite.T(), createdStudent.StudyDegree, fetchedStudent.StudyDegree, "StudyDegree should match")
 }
 
+func (suite *ServiceTestSuite) TestUpdateStudentRecordsProfileChanges() {
+	studentID := uuid.MustParse("3a774200-39a7-4656-bafb-92b7210a93c1")
+	lecturer := studentDTO.ProfileChangeAuthor{
+		UserID: uuid.New(),
+		Name:   "Test Lecturer",
+		Email:  "lecturer@tum.de",
+		Source: db.StudentProfileChangeSourceLecturer,
+	}
+
+	student, err := GetStudentByID(suite.ctx, studentID)
+	assert.NoError(suite.T(), err)
+	studyProgram := "Information Systems"
+	update := studentDTO.UpdateStudentProfile{StudyProgram: &studyProgram}.ApplyTo(student)
+	update.LastName = "Heun-Schmidt"
+
+	_, err = UpdateStudent(suite.ctx, nil, studentID, update, lecturer)
+	assert.NoError(suite.T(), err)
+
+	history, err := GetStudentProfileHistory(suite.ctx, studentID)
+	assert.NoError(suite.T(), err)
+	fields := map[string]studentDTO.ProfileChange{}
+	for _, change := range history {
+		fields[change.Field] = change
+	}
+
+	assert.Equal(suite.T(), "Heun", fields["lastName"].OldValue)
+	assert.Equal(suite.T(), "Heun-Schmidt", fields["lastName"].NewValue)
+	assert.True(suite.T(), fields["lastName"].IsIdentityField)
+	assert.False(suite.T(), fields["lastName"].Notified)
+	assert.Equal(suite.T(), "Information Systems", fields["studyProgram"].NewValue)
+	assert.False(suite.T(), fields["studyProgram"].IsIdentityField)
+	assert.Equal(suite.T(), "Test Lecturer", fields["studyProgram"].ChangedByName)
+	assert.NotContains(suite.T(), fields, "firstName", "unchanged fields are not recorded")
+}
+
 func TestServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(ServiceTestSuite))
 }
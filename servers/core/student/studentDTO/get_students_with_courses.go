@@ -36,7 +36,7 @@ type StudentWithCourseParticipationsDTO struct {
 }
 
 
-func GetStudentWithCoursesFromDB(row db.GetAllStudentsWithCourseParticipationsRow) (StudentWithCourseParticipationsDTO, error) {
+func GetStudentWithCoursesFromDB(row db.GetStudentsWithCourseParticipationsRow) (StudentWithCourseParticipationsDTO, error) {
 	var courses []StudentCourseParticipationDTO
 	if err := json.Unmarshal(row.Courses, &courses); err != nil {
 		return StudentWithCourseParticipationsDTO{}, err
@@ -0,0 +1,46 @@
+package studentDTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+// ProfileChangeAuthor is recorded in the profile history of the students it changes
+type ProfileChangeAuthor struct {
+	// the Keycloak user ID, uuid.Nil if the change was not made by a user
+	UserID uuid.UUID
+	Name   string
+	Email  string
+	Source db.StudentProfileChangeSource
+}
+
+type ProfileChange struct {
+	ID              uuid.UUID                     `json:"id"`
+	Field           string                        `json:"field"`
+	OldValue        string                        `json:"oldValue"`
+	NewValue        string                        `json:"newValue"`
+	Source          db.StudentProfileChangeSource `json:"source"`
+	ChangedByName   string                        `json:"changedByName"`
+	ChangedByEmail  string                        `json:"changedByEmail"`
+	ChangedAt       time.Time                     `json:"changedAt"`
+	IsIdentityField bool                          `json:"isIdentityField"`
+	// whether the phase servers were notified of the change, only identity fields are announced
+	Notified bool `json:"notified"`
+}
+
+func GetProfileChangeDTOFromDBModel(change db.StudentProfileChange) ProfileChange {
+	return ProfileChange{
+		ID:              change.ID,
+		Field:           change.Field,
+		OldValue:        change.OldValue,
+		NewValue:        change.NewValue,
+		Source:          change.Source,
+		ChangedByName:   change.ChangedByName,
+		ChangedByEmail:  change.ChangedByEmail,
+		ChangedAt:       change.ChangedAt.Time,
+		IsIdentityField: change.IsIdentityField,
+		Notified:        change.NotifiedAt.Valid,
+	}
+}
@@ -0,0 +1,48 @@
+package studentDTO
+
+import (
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/listQuery"
+)
+
+// StudentSearchFilter is parsed from the query parameters of the student search, empty values do not filter.
+// The pass status filter, sorting and pagination are part of the list parameters.
+type StudentSearchFilter struct {
+	Query        string
+	CourseID     uuid.UUID
+	SemesterTag  string
+	StudyProgram string
+	Nationality  string
+	List         listQuery.Params
+}
+
+// StudentSearchPage holds one page of the matching students, the next cursor is empty on the last page
+type StudentSearchPage struct {
+	Students   []Student `json:"students"`
+	TotalCount int64     `json:"totalCount"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
+
+type StudentWithCoursesSearchPage struct {
+	Students   []StudentWithCourseParticipationsDTO `json:"students"`
+	TotalCount int64                                `json:"totalCount"`
+	NextCursor string                               `json:"nextCursor,omitempty"`
+}
+
+func GetStudentDTOFromSearchRow(row db.SearchStudentsRow) Student {
+	return Student{
+		ID:                   row.ID,
+		FirstName:            row.FirstName.String,
+		LastName:             row.LastName.String,
+		Email:                row.Email.String,
+		MatriculationNumber:  row.MatriculationNumber.String,
+		UniversityLogin:      row.UniversityLogin.String,
+		HasUniversityAccount: row.HasUniversityAccount.Bool,
+		Gender:               row.Gender,
+		Nationality:          row.Nationality.String,
+		StudyDegree:          row.StudyDegree,
+		StudyProgram:         row.StudyProgram.String,
+		CurrentSemester:      row.CurrentSemester,
+	}
+}
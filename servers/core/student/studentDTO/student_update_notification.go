@@ -0,0 +1,21 @@
+package studentDTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudentUpdateNotification is sent to the phase servers when identity fields of a student changed,
+// so that they can refresh the data they cache for the course participations of the student.
+type StudentUpdateNotification struct {
+	StudentID              uuid.UUID   `json:"studentID"`
+	CourseParticipationIDs []uuid.UUID `json:"courseParticipationIDs"`
+	FirstName              string      `json:"firstName"`
+	LastName               string      `json:"lastName"`
+	Email                  string      `json:"email"`
+	MatriculationNumber    string      `json:"matriculationNumber"`
+	UniversityLogin        string      `json:"universityLogin"`
+	ChangedFields          []string    `json:"changedFields"`
+	ChangedAt              time.Time   `json:"changedAt"`
+}
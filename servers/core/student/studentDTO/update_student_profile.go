@@ -0,0 +1,51 @@
+package studentDTO
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+// UpdateStudentProfile are the profile fields students can correct themselves, omitted fields are kept.
+// Names, email and university data are managed by the university login and cannot be changed this way.
+type UpdateStudentProfile struct {
+	Gender          *db.Gender      `json:"gender,omitempty"`
+	Nationality     *string         `json:"nationality,omitempty"`
+	StudyDegree     *db.StudyDegree `json:"studyDegree,omitempty"`
+	StudyProgram    *string         `json:"studyProgram,omitempty"`
+	CurrentSemester *int32          `json:"currentSemester,omitempty"`
+}
+
+// ApplyTo returns the student with the provided fields replaced
+func (u UpdateStudentProfile) ApplyTo(student Student) CreateStudent {
+	updated := CreateStudent{
+		ID:                   student.ID,
+		FirstName:            student.FirstName,
+		LastName:             student.LastName,
+		Email:                student.Email,
+		MatriculationNumber:  student.MatriculationNumber,
+		UniversityLogin:      student.UniversityLogin,
+		HasUniversityAccount: student.HasUniversityAccount,
+		Gender:               student.Gender,
+		Nationality:          student.Nationality,
+		StudyDegree:          student.StudyDegree,
+		StudyProgram:         student.StudyProgram,
+		CurrentSemester:      student.CurrentSemester,
+	}
+
+	if u.Gender != nil {
+		updated.Gender = *u.Gender
+	}
+	if u.Nationality != nil {
+		updated.Nationality = *u.Nationality
+	}
+	if u.StudyDegree != nil {
+		updated.StudyDegree = *u.StudyDegree
+	}
+	if u.StudyProgram != nil {
+		updated.StudyProgram = *u.StudyProgram
+	}
+	if u.CurrentSemester != nil {
+		updated.CurrentSemester = pgtype.Int4{Int32: *u.CurrentSemester, Valid: true}
+	}
+	return updated
+}
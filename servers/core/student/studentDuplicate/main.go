@@ -0,0 +1,43 @@
+package studentDuplicate
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	log "github.com/sirupsen/logrus"
+)
+
+func InitStudentDuplicateModule(api *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) {
+	setupStudentDuplicateRouter(api, keycloakTokenVerifier.KeycloakMiddleware, permissionValidation.CheckAccessControlByRole)
+	StudentDuplicateServiceSingleton = &StudentDuplicateService{
+		queries: queries,
+		conn:    conn,
+	}
+}
+
+// StartDuplicateDetectionJob runs the duplicate detection every interval until the context is cancelled.
+func StartDuplicateDetectionJob(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				candidateCount, err := DetectDuplicateStudents(ctx)
+				if err != nil {
+					log.Error("duplicate student detection failed: ", err)
+					continue
+				}
+				log.Infof("duplicate student detection found %d candidates", candidateCount)
+			}
+		}
+	}()
+}
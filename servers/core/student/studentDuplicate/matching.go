@@ -0,0 +1,211 @@
+package studentDuplicate
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+)
+
+const (
+	ReasonEmail               = "email"
+	ReasonEmailLocalPart      = "emailLocalPart"
+	ReasonUniversityLogin     = "universityLogin"
+	ReasonMatriculationNumber = "matriculationNumber"
+	ReasonName                = "name"
+)
+
+// a pair is reported as duplicate candidate if its combined score reaches this value
+const minDuplicateScore = 0.6
+
+// names below this similarity are not considered as a signal
+const minNameSimilarity = 0.85
+
+type duplicateMatch struct {
+	studentID          uuid.UUID
+	duplicateStudentID uuid.UUID
+	score              float64
+	reasons            []string
+}
+
+var nameReplacer = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"á", "a", "à", "a", "â", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u",
+	"ç", "c", "ñ", "n", "ş", "s", "š", "s", "č", "c", "ć", "c", "ž", "z",
+	"-", " ", ".", " ", "'", "",
+)
+
+func normalizeName(name string) string {
+	normalized := nameReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+func normalizeIdentifier(identifier string) string {
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// matriculation numbers are entered with and without leading zeros
+func normalizeMatriculationNumber(matriculationNumber string) string {
+	return strings.TrimLeft(strings.TrimSpace(matriculationNumber), "0")
+}
+
+func emailLocalPart(email string) string {
+	localPart, _, found := strings.Cut(normalizeIdentifier(email), "@")
+	if !found {
+		return ""
+	}
+	// ignore sub-addressing, e.g. jane.doe+prompt@example.com
+	localPart, _, _ = strings.Cut(localPart, "+")
+	return localPart
+}
+
+func levenshteinDistance(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(runesA); i++ {
+		current[0] = i
+		for j := 1; j <= len(runesB); j++ {
+			substitutionCost := 1
+			if runesA[i-1] == runesB[j-1] {
+				substitutionCost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(runesB)]
+}
+
+// nameSimilarity returns a value between 0 and 1, where 1 means the normalized names are identical.
+// Swapped first and last names are treated as identical.
+func nameSimilarity(a, b db.Student) float64 {
+	fullNameA := normalizeName(a.FirstName.String + " " + a.LastName.String)
+	fullNameB := normalizeName(b.FirstName.String + " " + b.LastName.String)
+	swappedNameB := normalizeName(b.LastName.String + " " + b.FirstName.String)
+	if fullNameA == "" || fullNameB == "" {
+		return 0
+	}
+
+	similarity := func(x, y string) float64 {
+		maxLength := max(len([]rune(x)), len([]rune(y)))
+		return 1 - float64(levenshteinDistance(x, y))/float64(maxLength)
+	}
+	return math.Max(similarity(fullNameA, fullNameB), similarity(fullNameA, swappedNameB))
+}
+
+// compareStudents scores how likely two student records belong to the same person.
+// Every matching signal contributes a weight and the weights are combined as independent evidence.
+func compareStudents(a, b db.Student) (float64, []string) {
+	var reasons []string
+	remainingDoubt := 1.0
+	addSignal := func(reason string, weight float64) {
+		reasons = append(reasons, reason)
+		remainingDoubt *= 1 - weight
+	}
+
+	if matriculationA := normalizeMatriculationNumber(a.MatriculationNumber.String); matriculationA != "" &&
+		matriculationA == normalizeMatriculationNumber(b.MatriculationNumber.String) {
+		addSignal(ReasonMatriculationNumber, 1)
+	}
+
+	loginA, loginB := normalizeIdentifier(a.UniversityLogin.String), normalizeIdentifier(b.UniversityLogin.String)
+	emailA, emailB := normalizeIdentifier(a.Email.String), normalizeIdentifier(b.Email.String)
+	localPartA, localPartB := emailLocalPart(a.Email.String), emailLocalPart(b.Email.String)
+
+	if loginA != "" && loginA == loginB {
+		addSignal(ReasonUniversityLogin, 1)
+	} else if (loginA != "" && loginA == localPartB) || (loginB != "" && loginB == localPartA) {
+		// university mail addresses usually use the login as local part
+		addSignal(ReasonUniversityLogin, 0.8)
+	}
+
+	if emailA != "" && emailA == emailB {
+		addSignal(ReasonEmail, 1)
+	} else if localPartA != "" && localPartA == localPartB {
+		addSignal(ReasonEmailLocalPart, 0.5)
+	}
+
+	if similarity := nameSimilarity(a, b); similarity >= minNameSimilarity {
+		addSignal(ReasonName, 0.5*similarity)
+	}
+
+	return 1 - remainingDoubt, reasons
+}
+
+// blockingKeys groups students that are worth comparing, so that not every pair has to be scored.
+func blockingKeys(student db.Student) []string {
+	var keys []string
+	if matriculationNumber := normalizeMatriculationNumber(student.MatriculationNumber.String); matriculationNumber != "" {
+		keys = append(keys, "matriculation:"+matriculationNumber)
+	}
+	if login := normalizeIdentifier(student.UniversityLogin.String); login != "" {
+		keys = append(keys, "login:"+login)
+	}
+	if localPart := emailLocalPart(student.Email.String); localPart != "" {
+		// shares the namespace with the login, since mail addresses often contain it
+		keys = append(keys, "login:"+localPart)
+	}
+	for _, name := range []string{student.FirstName.String, student.LastName.String} {
+		if normalized := []rune(strings.ReplaceAll(normalizeName(name), " ", "")); len(normalized) >= 3 {
+			keys = append(keys, "name:"+string(normalized[:3]))
+		}
+	}
+	return keys
+}
+
+// findDuplicateMatches returns all pairs of students scoring at least minDuplicateScore.
+// The student with the smaller id is always reported first.
+func findDuplicateMatches(students []db.Student) []duplicateMatch {
+	blocks := make(map[string][]int)
+	for index, student := range students {
+		for _, key := range blockingKeys(student) {
+			blocks[key] = append(blocks[key], index)
+		}
+	}
+
+	type studentPair struct{ first, second int }
+	compared := make(map[studentPair]bool)
+	matches := make([]duplicateMatch, 0)
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				pair := studentPair{min(block[i], block[j]), max(block[i], block[j])}
+				if pair.first == pair.second || compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				a, b := students[pair.first], students[pair.second]
+				score, reasons := compareStudents(a, b)
+				if score < minDuplicateScore {
+					continue
+				}
+				if strings.Compare(a.ID.String(), b.ID.String()) > 0 {
+					a, b = b, a
+				}
+				matches = append(matches, duplicateMatch{
+					studentID:          a.ID,
+					duplicateStudentID: b.ID,
+					score:              score,
+					reasons:            reasons,
+				})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches
+}
@@ -0,0 +1,88 @@
+package studentDuplicate
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStudent(firstName, lastName, email, matriculationNumber, universityLogin string) db.Student {
+	return db.Student{
+		ID:                  uuid.New(),
+		FirstName:           pgtype.Text{String: firstName, Valid: true},
+		LastName:            pgtype.Text{String: lastName, Valid: true},
+		Email:               pgtype.Text{String: email, Valid: true},
+		MatriculationNumber: pgtype.Text{String: matriculationNumber, Valid: true},
+		UniversityLogin:     pgtype.Text{String: universityLogin, Valid: true},
+	}
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	assert.Equal(t, 0, levenshteinDistance("mueller", "mueller"))
+	assert.Equal(t, 1, levenshteinDistance("meier", "meyer"))
+	assert.Equal(t, 3, levenshteinDistance("", "abc"))
+	assert.Equal(t, 1, levenshteinDistance("müller", "muller"))
+}
+
+func TestCompareStudents_PrivateAndUniversityMail(t *testing.T) {
+	privateApplication := newStudent("Jürgen", "Müller", "juergen.mueller@gmail.com", "", "")
+	universityApplication := newStudent("Juergen", "Mueller", "ab12cde@mytum.de", "03712345", "ab12cde")
+
+	// identical names alone are not enough
+	score, reasons := compareStudents(privateApplication, universityApplication)
+	assert.Less(t, score, minDuplicateScore)
+	assert.Equal(t, []string{ReasonName}, reasons)
+
+	// the same local part on another domain adds enough evidence
+	universityApplication.Email = pgtype.Text{String: "Juergen.Mueller@tum.de", Valid: true}
+	score, reasons = compareStudents(privateApplication, universityApplication)
+	assert.GreaterOrEqual(t, score, minDuplicateScore)
+	assert.ElementsMatch(t, []string{ReasonEmailLocalPart, ReasonName}, reasons)
+}
+
+func TestCompareStudents_LoginUsedAsMailLocalPart(t *testing.T) {
+	a := newStudent("Jane", "Doe", "ab12cde@mytum.de", "", "")
+	b := newStudent("Jane", "Smith", "jane@example.com", "", "AB12CDE")
+
+	score, reasons := compareStudents(a, b)
+	assert.GreaterOrEqual(t, score, minDuplicateScore)
+	assert.Equal(t, []string{ReasonUniversityLogin}, reasons)
+}
+
+func TestCompareStudents_MatriculationNumberWithoutLeadingZeros(t *testing.T) {
+	a := newStudent("Max", "Mustermann", "max@example.com", "03711111", "")
+	b := newStudent("Maximilian", "Mustermann", "maxi@example.com", "3711111", "")
+
+	score, reasons := compareStudents(a, b)
+	assert.Equal(t, 1.0, score)
+	assert.Contains(t, reasons, ReasonMatriculationNumber)
+}
+
+func TestCompareStudents_SwappedNames(t *testing.T) {
+	a := newStudent("Wei", "Zhang", "wei@example.com", "", "")
+	b := newStudent("Zhang", "Wei", "wei@example.org", "", "")
+
+	score, reasons := compareStudents(a, b)
+	assert.GreaterOrEqual(t, score, minDuplicateScore)
+	assert.ElementsMatch(t, []string{ReasonEmailLocalPart, ReasonName}, reasons)
+}
+
+func TestFindDuplicateMatches(t *testing.T) {
+	students := []db.Student{
+		newStudent("Anna", "Schmidt", "anna.schmidt@gmail.com", "", ""),
+		newStudent("Anna", "Schmitt", "anna.schmidt@tum.de", "03700001", "ge12abc"),
+		newStudent("Bernd", "Schmidt", "bernd@example.com", "03700002", "ge34def"),
+		newStudent("Clara", "Weber", "clara@example.com", "", ""),
+	}
+
+	matches := findDuplicateMatches(students)
+	assert.Len(t, matches, 1)
+
+	match := matches[0]
+	assert.ElementsMatch(t, []uuid.UUID{students[0].ID, students[1].ID}, []uuid.UUID{match.studentID, match.duplicateStudentID})
+	assert.Less(t, match.studentID.String(), match.duplicateStudentID.String())
+	assert.ElementsMatch(t, []string{ReasonEmailLocalPart, ReasonName}, match.reasons)
+}
@@ -0,0 +1,147 @@
+package studentDuplicate
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prompt-edu/prompt/servers/core/permissionValidation"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDuplicate/studentDuplicateDTO"
+	"github.com/prompt-edu/prompt/servers/core/utils"
+)
+
+func setupStudentDuplicateRouter(router *gin.RouterGroup, authMiddleware func() gin.HandlerFunc, permissionRoleMiddleware func(allowedRoles ...string) gin.HandlerFunc) {
+	duplicates := router.Group("/students/duplicates", authMiddleware(), permissionRoleMiddleware(permissionValidation.PromptAdmin))
+	duplicates.GET("", getOpenDuplicateCandidates)
+	duplicates.POST("/detect", detectDuplicateStudents)
+	duplicates.PUT("/:uuid/dismiss", dismissDuplicateCandidate)
+	duplicates.POST("/:uuid/merge", mergeDuplicateCandidate)
+}
+
+// getOpenDuplicateCandidates godoc
+// @Summary Get duplicate student candidates
+// @Description Get all pairs of students that likely belong to the same person and are not reviewed yet
+// @Tags students
+// @Produce json
+// @Success 200 {array} studentDuplicateDTO.DuplicateCandidate
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/duplicates [get]
+func getOpenDuplicateCandidates(c *gin.Context) {
+	candidates, err := GetOpenDuplicateCandidates(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, candidates)
+}
+
+// detectDuplicateStudents godoc
+// @Summary Detect duplicate students
+// @Description Run the duplicate detection immediately instead of waiting for the next scheduled run
+// @Tags students
+// @Produce json
+// @Success 200 {array} studentDuplicateDTO.DuplicateCandidate
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/duplicates/detect [post]
+func detectDuplicateStudents(c *gin.Context) {
+	_, err := DetectDuplicateStudents(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	candidates, err := GetOpenDuplicateCandidates(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, candidates)
+}
+
+// dismissDuplicateCandidate godoc
+// @Summary Dismiss a duplicate student candidate
+// @Description Mark a candidate as distinct persons, so it is not reported again
+// @Tags students
+// @Param uuid path string true "Duplicate Candidate UUID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/duplicates/{uuid}/dismiss [put]
+func dismissDuplicateCandidate(c *gin.Context) {
+	candidateID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	userID, err := utils.GetUserUUIDFromContext(c)
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = DismissDuplicateCandidate(c, candidateID, userID)
+	if errors.Is(err, ErrCandidateNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "duplicate candidate dismissed"})
+}
+
+// mergeDuplicateCandidate godoc
+// @Summary Merge duplicate students
+// @Description Merge the student records of a candidate. Course participations, answers and notes are moved to the surviving student and the other record is deleted.
+// @Tags students
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Duplicate Candidate UUID"
+// @Param merge body studentDuplicateDTO.MergeStudents true "Student to keep"
+// @Success 200 {object} studentDTO.Student
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /students/duplicates/{uuid}/merge [post]
+func mergeDuplicateCandidate(c *gin.Context) {
+	candidateID, err := uuid.Parse(c.Param("uuid"))
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var merge studentDuplicateDTO.MergeStudents
+	if err := c.BindJSON(&merge); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	student, err := MergeDuplicateCandidate(c, candidateID, merge.SurvivingStudentID)
+	if errors.Is(err, ErrCandidateNotFound) {
+		handleError(c, http.StatusNotFound, err)
+		return
+	}
+	if errors.Is(err, ErrInvalidSurvivingStudent) {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, student)
+}
+
+func handleError(c *gin.Context, statusCode int, err error) {
+	c.JSON(statusCode, utils.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,342 @@
+package studentDuplicate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkUtils "github.com/prompt-edu/prompt-sdk/utils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/keycloakTokenVerifier"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDuplicate/studentDuplicateDTO"
+	log "github.com/sirupsen/logrus"
+)
+
+type StudentDuplicateService struct {
+	queries db.Queries
+	conn    *pgxpool.Pool
+}
+
+var StudentDuplicateServiceSingleton *StudentDuplicateService
+
+var ErrCandidateNotFound = errors.New("duplicate candidate was not found")
+var ErrInvalidSurvivingStudent = errors.New("the surviving student must be one of the candidate students")
+
+// DetectDuplicateStudents scans all students and stores the likely duplicates as open candidates.
+// Dismissed candidates stay dismissed, open candidates that are no longer detected are removed.
+func DetectDuplicateStudents(ctx context.Context) (int, error) {
+	scanStart := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	students, err := StudentDuplicateServiceSingleton.queries.GetAllStudents(ctx)
+	if err != nil {
+		log.Error(err)
+		return 0, errors.New("could not get the students")
+	}
+
+	matches := findDuplicateMatches(students)
+
+	tx, err := StudentDuplicateServiceSingleton.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer sdkUtils.DeferRollback(tx, ctx)
+	qtx := StudentDuplicateServiceSingleton.queries.WithTx(tx)
+
+	for _, match := range matches {
+		err = qtx.UpsertStudentDuplicateCandidate(ctx, db.UpsertStudentDuplicateCandidateParams{
+			ID:                 uuid.New(),
+			StudentID:          match.studentID,
+			DuplicateStudentID: match.duplicateStudentID,
+			Score:              match.score,
+			Reasons:            match.reasons,
+			DetectedAt:         scanStart,
+		})
+		if err != nil {
+			log.Error(err)
+			return 0, errors.New("could not store the duplicate candidates")
+		}
+	}
+
+	err = qtx.DeleteOpenStudentDuplicateCandidatesDetectedBefore(ctx, scanStart)
+	if err != nil {
+		log.Error(err)
+		return 0, errors.New("could not remove outdated duplicate candidates")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error(err)
+		return 0, errors.New("could not store the duplicate candidates")
+	}
+
+	return len(matches), nil
+}
+
+func GetOpenDuplicateCandidates(ctx context.Context) ([]studentDuplicateDTO.DuplicateCandidate, error) {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	candidates, err := StudentDuplicateServiceSingleton.queries.GetStudentDuplicateCandidatesByStatus(ctxWithTimeout, db.StudentDuplicateStatusOpen)
+	if err != nil {
+		log.Error(err)
+		return nil, errors.New("could not get the duplicate candidates")
+	}
+
+	studentIDs := make([]uuid.UUID, 0, 2*len(candidates))
+	for _, candidate := range candidates {
+		studentIDs = append(studentIDs, candidate.StudentID, candidate.DuplicateStudentID)
+	}
+
+	students, err := StudentDuplicateServiceSingleton.queries.GetStudentsByIDs(ctxWithTimeout, studentIDs)
+	if err != nil {
+		log.Error(err)
+		return nil, errors.New("could not get the duplicate candidates")
+	}
+
+	studentsByID := make(map[uuid.UUID]db.Student, len(students))
+	for _, student := range students {
+		studentsByID[student.ID] = student
+	}
+
+	candidateDTOs := make([]studentDuplicateDTO.DuplicateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidateDTOs = append(candidateDTOs, studentDuplicateDTO.GetDuplicateCandidateDTOFromDBModel(
+			candidate,
+			studentsByID[candidate.StudentID],
+			studentsByID[candidate.DuplicateStudentID],
+		))
+	}
+	return candidateDTOs, nil
+}
+
+func DismissDuplicateCandidate(ctx context.Context, candidateID uuid.UUID, userID uuid.UUID) error {
+	ctxWithTimeout, cancel := db.GetTimeoutContext(ctx)
+	defer cancel()
+
+	_, err := StudentDuplicateServiceSingleton.queries.DismissStudentDuplicateCandidate(ctxWithTimeout, db.DismissStudentDuplicateCandidateParams{
+		ID:         candidateID,
+		ResolvedBy: pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCandidateNotFound
+	}
+	if err != nil {
+		log.Error(err)
+		return errors.New("could not dismiss the duplicate candidate")
+	}
+	return nil
+}
+
+// MergeDuplicateCandidate merges the other student of the candidate into the surviving student.
+// Course participations, answers, assessments and notes are moved to the surviving student and
+// the merged student record is deleted. If both students participated in the same course, the
+// surviving participation wins and only data it does not have yet is taken over.
+func MergeDuplicateCandidate(ctx context.Context, candidateID uuid.UUID, survivingStudentID uuid.UUID) (studentDTO.Student, error) {
+	tx, err := StudentDuplicateServiceSingleton.conn.Begin(ctx)
+	if err != nil {
+		return studentDTO.Student{}, err
+	}
+	defer sdkUtils.DeferRollback(tx, ctx)
+	qtx := StudentDuplicateServiceSingleton.queries.WithTx(tx)
+
+	candidate, err := qtx.GetStudentDuplicateCandidate(ctx, candidateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return studentDTO.Student{}, ErrCandidateNotFound
+	}
+	if err != nil {
+		log.Error(err)
+		return studentDTO.Student{}, errors.New("could not get the duplicate candidate")
+	}
+
+	var mergedStudentID uuid.UUID
+	switch survivingStudentID {
+	case candidate.StudentID:
+		mergedStudentID = candidate.DuplicateStudentID
+	case candidate.DuplicateStudentID:
+		mergedStudentID = candidate.StudentID
+	default:
+		return studentDTO.Student{}, ErrInvalidSurvivingStudent
+	}
+
+	mergedStudent, err := mergeStudents(ctx, qtx, survivingStudentID, mergedStudentID)
+	if err != nil {
+		return studentDTO.Student{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error(err)
+		return studentDTO.Student{}, errors.New("could not merge the students")
+	}
+
+	keycloakTokenVerifier.InvalidateRoleCache()
+
+	log.Infof("merged student %s into student %s", mergedStudentID, survivingStudentID)
+	return studentDTO.GetStudentDTOFromDBModel(mergedStudent), nil
+}
+
+func mergeStudents(ctx context.Context, qtx *db.Queries, survivingStudentID, mergedStudentID uuid.UUID) (db.Student, error) {
+	survivingStudent, err := qtx.GetStudent(ctx, survivingStudentID)
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not get the surviving student")
+	}
+
+	mergedStudent, err := qtx.GetStudent(ctx, mergedStudentID)
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not get the merged student")
+	}
+
+	// 1. Move the course participations
+	participations, err := qtx.GetAllCourseParticipationsForStudent(ctx, mergedStudentID)
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not get the course participations")
+	}
+
+	for _, participation := range participations {
+		err = mergeCourseParticipation(ctx, qtx, participation, survivingStudentID)
+		if err != nil {
+			log.Error(err)
+			return db.Student{}, errors.New("could not merge the course participations")
+		}
+	}
+
+	// 2. Move the instructor notes
+	err = qtx.ReassignNotesToStudent(ctx, db.ReassignNotesToStudentParams{
+		TargetStudentID: survivingStudentID,
+		SourceStudentID: mergedStudentID,
+	})
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not move the instructor notes")
+	}
+
+	// 3. Move the lecturer and editor assignments stored in the database
+	err = qtx.ReassignCourseStaffToStudent(ctx, db.ReassignCourseStaffToStudentParams{
+		TargetStudentID: survivingStudentID,
+		SourceStudentID: mergedStudentID,
+	})
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not move the course staff assignments")
+	}
+
+	// 4. Move the profile history, pending identity changes are announced for the surviving student
+	err = qtx.ReassignStudentProfileChangesToStudent(ctx, db.ReassignStudentProfileChangesToStudentParams{
+		TargetStudentID: survivingStudentID,
+		SourceStudentID: mergedStudentID,
+	})
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not move the profile history")
+	}
+
+	// 5. Delete the merged student before taking over its unique identifiers
+	err = qtx.DeleteStudent(ctx, mergedStudentID)
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not delete the merged student")
+	}
+
+	updatedStudent, err := qtx.UpdateStudent(ctx, fillMissingStudentDetails(survivingStudent, mergedStudent))
+	if err != nil {
+		log.Error(err)
+		return db.Student{}, errors.New("could not update the surviving student")
+	}
+	return updatedStudent, nil
+}
+
+func mergeCourseParticipation(ctx context.Context, qtx *db.Queries, participation db.CourseParticipation, survivingStudentID uuid.UUID) error {
+	survivingParticipation, err := qtx.GetCourseParticipationByStudentAndCourseID(ctx, db.GetCourseParticipationByStudentAndCourseIDParams{
+		StudentID: survivingStudentID,
+		CourseID:  participation.CourseID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// the surviving student did not participate in this course, so the participation is simply handed over
+		return qtx.ReassignCourseParticipationToStudent(ctx, db.ReassignCourseParticipationToStudentParams{
+			ID:        participation.ID,
+			StudentID: survivingStudentID,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	err = qtx.CopyMissingCoursePhaseParticipations(ctx, db.CopyMissingCoursePhaseParticipationsParams{
+		TargetCourseParticipationID: survivingParticipation.ID,
+		SourceCourseParticipationID: participation.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.MoveApplicationAssessments(ctx, db.MoveApplicationAssessmentsParams{
+		TargetCourseParticipationID: survivingParticipation.ID,
+		SourceCourseParticipationID: participation.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.MoveApplicationAnswersText(ctx, db.MoveApplicationAnswersTextParams{
+		TargetCourseParticipationID: survivingParticipation.ID,
+		SourceCourseParticipationID: participation.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.MoveApplicationAnswersMultiSelect(ctx, db.MoveApplicationAnswersMultiSelectParams{
+		TargetCourseParticipationID: survivingParticipation.ID,
+		SourceCourseParticipationID: participation.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = qtx.MoveApplicationAnswersFileUpload(ctx, db.MoveApplicationAnswersFileUploadParams{
+		TargetCourseParticipationID: survivingParticipation.ID,
+		SourceCourseParticipationID: participation.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// everything that was not moved is superseded by the surviving participation
+	return qtx.DeleteCourseParticipation(ctx, participation.ID)
+}
+
+// fillMissingStudentDetails keeps the details of the surviving student and only fills in empty fields.
+func fillMissingStudentDetails(surviving, merged db.Student) db.UpdateStudentParams {
+	preferText := func(preferred, fallback pgtype.Text) pgtype.Text {
+		if preferred.Valid && preferred.String != "" {
+			return preferred
+		}
+		return fallback
+	}
+
+	currentSemester := surviving.CurrentSemester
+	if !currentSemester.Valid {
+		currentSemester = merged.CurrentSemester
+	}
+
+	return db.UpdateStudentParams{
+		ID:                   surviving.ID,
+		FirstName:            preferText(surviving.FirstName, merged.FirstName),
+		LastName:             preferText(surviving.LastName, merged.LastName),
+		Email:                preferText(surviving.Email, merged.Email),
+		MatriculationNumber:  preferText(surviving.MatriculationNumber, merged.MatriculationNumber),
+		UniversityLogin:      preferText(surviving.UniversityLogin, merged.UniversityLogin),
+		HasUniversityAccount: pgtype.Bool{Bool: surviving.HasUniversityAccount.Bool || merged.HasUniversityAccount.Bool, Valid: true},
+		Gender:               surviving.Gender,
+		Nationality:          preferText(surviving.Nationality, merged.Nationality),
+		StudyProgram:         preferText(surviving.StudyProgram, merged.StudyProgram),
+		StudyDegree:          surviving.StudyDegree,
+		CurrentSemester:      currentSemester,
+	}
+}
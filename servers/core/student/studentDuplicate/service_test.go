@@ -0,0 +1,178 @@
+package studentDuplicate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDuplicate/studentDuplicateDTO"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	annaUniversityID = uuid.MustParse("1b6a0f4e-7c1d-4c1e-9a43-6f0e4b6a2d11")
+	annaPrivateID    = uuid.MustParse("c3d94a2b-5e8f-4b70-8d21-0a9e7f3c4b22")
+	weiID            = uuid.MustParse("2e8f1c3d-9a4b-4d5e-8f6a-1b2c3d4e5f33")
+	weiSwappedID     = uuid.MustParse("d4e5f6a7-b8c9-4d0e-9f1a-2b3c4d5e6f44")
+)
+
+type ServiceTestSuite struct {
+	suite.Suite
+	ctx                     context.Context
+	cleanup                 func()
+	studentDuplicateService StudentDuplicateService
+}
+
+func (suite *ServiceTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	// Set up PostgreSQL container
+	testDB, cleanup, err := sdkTestUtils.SetupTestDB(suite.ctx, "../../database_dumps/student_duplicates_test.sql", func(conn *pgxpool.Pool) *db.Queries { return db.New(conn) })
+	if err != nil {
+		log.Fatalf("Failed to set up test database: %v", err)
+	}
+
+	suite.cleanup = cleanup
+	suite.studentDuplicateService = StudentDuplicateService{
+		queries: *testDB.Queries,
+		conn:    testDB.Conn,
+	}
+	StudentDuplicateServiceSingleton = &suite.studentDuplicateService
+}
+
+func (suite *ServiceTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *ServiceTestSuite) findOpenCandidate(studentID uuid.UUID) (studentDuplicateDTO.DuplicateCandidate, bool) {
+	candidates, err := GetOpenDuplicateCandidates(suite.ctx)
+	assert.NoError(suite.T(), err)
+	for _, candidate := range candidates {
+		if candidate.Student.ID == studentID || candidate.DuplicateStudent.ID == studentID {
+			return candidate, true
+		}
+	}
+	return studentDuplicateDTO.DuplicateCandidate{}, false
+}
+
+func (suite *ServiceTestSuite) TestDetectDuplicateStudents() {
+	candidateCount, err := DetectDuplicateStudents(suite.ctx)
+	assert.NoError(suite.T(), err)
+	assert.GreaterOrEqual(suite.T(), candidateCount, 1)
+
+	candidate, found := suite.findOpenCandidate(annaPrivateID)
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), annaUniversityID, candidate.Student.ID, "the smaller id is reported first")
+	assert.Equal(suite.T(), "anna.schmidt@gmail.com", candidate.DuplicateStudent.Email)
+	assert.ElementsMatch(suite.T(), []string{ReasonEmailLocalPart, ReasonName}, candidate.Reasons)
+
+	// running the detection again does not report the pair twice
+	_, err = DetectDuplicateStudents(suite.ctx)
+	assert.NoError(suite.T(), err)
+	candidates, err := GetOpenDuplicateCandidates(suite.ctx)
+	assert.NoError(suite.T(), err)
+	occurrences := 0
+	for _, c := range candidates {
+		if c.Student.ID == annaUniversityID && c.DuplicateStudent.ID == annaPrivateID {
+			occurrences++
+		}
+	}
+	assert.Equal(suite.T(), 1, occurrences)
+}
+
+func (suite *ServiceTestSuite) TestDismissDuplicateCandidate() {
+	_, err := DetectDuplicateStudents(suite.ctx)
+	assert.NoError(suite.T(), err)
+
+	candidate, found := suite.findOpenCandidate(weiID)
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), weiSwappedID, candidate.DuplicateStudent.ID)
+
+	err = DismissDuplicateCandidate(suite.ctx, candidate.ID, uuid.New())
+	assert.NoError(suite.T(), err)
+
+	// a dismissed pair is not reported again
+	_, err = DetectDuplicateStudents(suite.ctx)
+	assert.NoError(suite.T(), err)
+	_, found = suite.findOpenCandidate(weiID)
+	assert.False(suite.T(), found)
+
+	err = DismissDuplicateCandidate(suite.ctx, uuid.New(), uuid.New())
+	assert.ErrorIs(suite.T(), err, ErrCandidateNotFound)
+}
+
+func (suite *ServiceTestSuite) TestMergeDuplicateCandidate() {
+	_, err := DetectDuplicateStudents(suite.ctx)
+	assert.NoError(suite.T(), err)
+
+	candidate, found := suite.findOpenCandidate(annaPrivateID)
+	assert.True(suite.T(), found)
+
+	_, err = MergeDuplicateCandidate(suite.ctx, candidate.ID, uuid.New())
+	assert.ErrorIs(suite.T(), err, ErrInvalidSurvivingStudent)
+
+	merged, err := MergeDuplicateCandidate(suite.ctx, candidate.ID, annaUniversityID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), annaUniversityID, merged.ID)
+	assert.Equal(suite.T(), "anna.schmidt@tum.de", merged.Email, "details of the surviving student are kept")
+	assert.Equal(suite.T(), "DE", merged.Nationality, "missing details are taken over")
+	assert.Equal(suite.T(), "Informatics", merged.StudyProgram)
+
+	queries := suite.studentDuplicateService.queries
+	_, err = queries.GetStudent(suite.ctx, annaPrivateID)
+	assert.Error(suite.T(), err, "the merged student is deleted")
+
+	participations, err := queries.GetAllCourseParticipationsForStudent(suite.ctx, annaUniversityID)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), participations, 2)
+
+	survivingParticipationID := uuid.MustParse("c3c3c3c3-0000-4000-8000-000000000001")
+	var phaseCount int
+	err = suite.studentDuplicateService.conn.QueryRow(suite.ctx,
+		"SELECT COUNT(*) FROM course_phase_participation WHERE course_participation_id = $1", survivingParticipationID).Scan(&phaseCount)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, phaseCount, "the team phase participation is taken over")
+
+	var assessmentScore int
+	err = suite.studentDuplicateService.conn.QueryRow(suite.ctx,
+		"SELECT score FROM application_assessment WHERE course_participation_id = $1", survivingParticipationID).Scan(&assessmentScore)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 42, assessmentScore)
+
+	rows, err := suite.studentDuplicateService.conn.Query(suite.ctx,
+		"SELECT answer FROM application_answer_text WHERE course_participation_id = $1 ORDER BY answer", survivingParticipationID)
+	assert.NoError(suite.T(), err)
+	var answers []string
+	for rows.Next() {
+		var answer string
+		assert.NoError(suite.T(), rows.Scan(&answer))
+		answers = append(answers, answer)
+	}
+	rows.Close()
+	assert.Equal(suite.T(), []string{"Answer of the university account", "Only answered with the private account"}, answers)
+
+	var noteStudentID uuid.UUID
+	err = suite.studentDuplicateService.conn.QueryRow(suite.ctx,
+		"SELECT for_student FROM note WHERE id = $1", uuid.MustParse("a7a7a7a7-0000-4000-8000-000000000001")).Scan(&noteStudentID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), annaUniversityID, noteStudentID)
+
+	var staffStudentID uuid.UUID
+	err = suite.studentDuplicateService.conn.QueryRow(suite.ctx,
+		"SELECT student_id FROM course_staff WHERE course_id = $1 AND role = 'Editor'", uuid.MustParse("a1a1a1a1-0000-4000-8000-000000000002")).Scan(&staffStudentID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), annaUniversityID, staffStudentID)
+
+	// the candidate is removed together with the merged student
+	_, err = MergeDuplicateCandidate(suite.ctx, candidate.ID, annaUniversityID)
+	assert.ErrorIs(suite.T(), err, ErrCandidateNotFound)
+}
+
+func TestStudentDuplicateServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceTestSuite))
+}
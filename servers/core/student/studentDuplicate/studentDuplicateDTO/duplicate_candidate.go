@@ -0,0 +1,31 @@
+package studentDuplicateDTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+)
+
+type DuplicateCandidate struct {
+	ID               uuid.UUID                 `json:"id"`
+	Student          studentDTO.Student        `json:"student"`
+	DuplicateStudent studentDTO.Student        `json:"duplicateStudent"`
+	Score            float64                   `json:"score"`
+	Reasons          []string                  `json:"reasons"`
+	Status           db.StudentDuplicateStatus `json:"status"`
+	DetectedAt       time.Time                 `json:"detectedAt"`
+}
+
+func GetDuplicateCandidateDTOFromDBModel(candidate db.StudentDuplicateCandidate, student, duplicateStudent db.Student) DuplicateCandidate {
+	return DuplicateCandidate{
+		ID:               candidate.ID,
+		Student:          studentDTO.GetStudentDTOFromDBModel(student),
+		DuplicateStudent: studentDTO.GetStudentDTOFromDBModel(duplicateStudent),
+		Score:            candidate.Score,
+		Reasons:          candidate.Reasons,
+		Status:           candidate.Status,
+		DetectedAt:       candidate.DetectedAt.Time,
+	}
+}
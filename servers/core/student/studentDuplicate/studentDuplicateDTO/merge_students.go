@@ -0,0 +1,8 @@
+package studentDuplicateDTO
+
+import "github.com/google/uuid"
+
+type MergeStudents struct {
+	// the student record that is kept, the other record of the candidate is merged into it
+	SurvivingStudentID uuid.UUID `json:"survivingStudentID"`
+}
@@ -0,0 +1,254 @@
+package student
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	promptSDK "github.com/prompt-edu/prompt-sdk"
+	"github.com/prompt-edu/prompt/servers/core/coursePhase/resolution"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// changes are announced again in the following runs until all phase servers accepted them
+	studentUpdateNotificationMaxAttempts = 10
+	studentUpdateNotificationBatchSize   = 200
+
+	StudentUpdateTimestampHeader = "X-Prompt-Timestamp"
+	StudentUpdateSignatureHeader = "X-Prompt-Signature"
+)
+
+var studentUpdateClient = &http.Client{Timeout: 10 * time.Second}
+
+// studentDataCachingPaths are the API paths of the phase servers known to cache student names.
+// They must implement the student-updates endpoint, a 404 from them is retried instead of skipped.
+var studentDataCachingPaths = []string{
+	"/team-allocation/api",
+	"/self-team-allocation/api",
+}
+
+type studentUpdateEndpoint struct {
+	url               string
+	cachesStudentData bool
+}
+
+// StartStudentUpdateNotificationJob announces changed identity fields to the phase servers every interval until the context is cancelled.
+func StartStudentUpdateNotificationJob(ctx context.Context, interval time.Duration, secret string) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				studentCount, err := NotifyPhaseServersOfStudentUpdates(ctx, secret)
+				if err != nil {
+					log.Error("notifying the phase servers of student updates failed: ", err)
+					continue
+				}
+				if studentCount > 0 {
+					log.Infof("notified the phase servers of %d updated students", studentCount)
+				}
+			}
+		}
+	}()
+}
+
+// NotifyPhaseServersOfStudentUpdates sends the pending identity changes of each student to the student-updates endpoint of all phase servers.
+// Phase servers without this endpoint are skipped, unless they are known to cache student data. It returns the number of students whose changes were delivered.
+func NotifyPhaseServersOfStudentUpdates(ctx context.Context, secret string) (int, error) {
+	queries := StudentServiceSingleton.queries
+	changes, err := queries.GetPendingStudentIdentityChanges(ctx, db.GetPendingStudentIdentityChangesParams{
+		MaxAttempts: studentUpdateNotificationMaxAttempts,
+		BatchSize:   studentUpdateNotificationBatchSize,
+	})
+	if err != nil || len(changes) == 0 {
+		return 0, err
+	}
+
+	baseURLs, err := queries.GetPhaseServerBaseURLs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	endpoints, err := getStudentUpdateEndpoints(baseURLs)
+	if err != nil {
+		return 0, err
+	}
+
+	notifiedCount := 0
+	for _, studentChanges := range groupChangesByStudent(changes) {
+		changeIDs := make([]uuid.UUID, 0, len(studentChanges))
+		for _, change := range studentChanges {
+			changeIDs = append(changeIDs, change.ID)
+		}
+
+		err := notifyPhaseServers(ctx, endpoints, secret, studentChanges)
+		if err != nil {
+			log.Warnf("could not notify the phase servers of the update of student %s: %v", studentChanges[0].StudentID, err)
+			if err := queries.IncrementStudentProfileChangeNotificationAttempts(ctx, changeIDs); err != nil {
+				return notifiedCount, err
+			}
+			continue
+		}
+
+		if err := queries.MarkStudentProfileChangesNotified(ctx, changeIDs); err != nil {
+			return notifiedCount, err
+		}
+		notifiedCount++
+	}
+	return notifiedCount, nil
+}
+
+func getStudentUpdateEndpoints(baseURLs []string) ([]studentUpdateEndpoint, error) {
+	coreHost := resolution.NormaliseHost(promptSDK.GetEnv("CORE_HOST", "http://localhost:8080"))
+
+	endpoints := make([]studentUpdateEndpoint, 0, len(baseURLs))
+	for _, baseURL := range baseURLs {
+		resolvedBaseURL := strings.ReplaceAll(baseURL, "{CORE_HOST}", coreHost)
+		endpoint, err := url.JoinPath(resolvedBaseURL, "student-updates")
+		if err != nil {
+			return nil, fmt.Errorf("failed to join student update path: %w", err)
+		}
+		endpoints = append(endpoints, studentUpdateEndpoint{
+			url:               endpoint,
+			cachesStudentData: cachesStudentData(resolvedBaseURL),
+		})
+	}
+	return endpoints, nil
+}
+
+func cachesStudentData(baseURL string) bool {
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	path := strings.TrimSuffix(parsedURL.Path, "/")
+	for _, cachingPath := range studentDataCachingPaths {
+		if strings.HasSuffix(path, cachingPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupChangesByStudent keeps the order of the changes within and across students
+func groupChangesByStudent(changes []db.StudentProfileChange) [][]db.StudentProfileChange {
+	groups := make([][]db.StudentProfileChange, 0)
+	groupIndex := make(map[uuid.UUID]int)
+	for _, change := range changes {
+		index, exists := groupIndex[change.StudentID]
+		if !exists {
+			index = len(groups)
+			groupIndex[change.StudentID] = index
+			groups = append(groups, nil)
+		}
+		groups[index] = append(groups[index], change)
+	}
+	return groups
+}
+
+func notifyPhaseServers(ctx context.Context, endpoints []studentUpdateEndpoint, secret string, changes []db.StudentProfileChange) error {
+	queries := StudentServiceSingleton.queries
+	studentID := changes[0].StudentID
+
+	participations, err := queries.GetAllCourseParticipationsForStudent(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if len(participations) == 0 {
+		// phase servers only cache data of course participants
+		return nil
+	}
+
+	student, err := queries.GetStudent(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	notification := studentDTO.StudentUpdateNotification{
+		StudentID:              studentID,
+		CourseParticipationIDs: make([]uuid.UUID, 0, len(participations)),
+		FirstName:              student.FirstName.String,
+		LastName:               student.LastName.String,
+		Email:                  student.Email.String,
+		MatriculationNumber:    student.MatriculationNumber.String,
+		UniversityLogin:        student.UniversityLogin.String,
+		ChangedFields:          make([]string, 0, len(changes)),
+	}
+	for _, participation := range participations {
+		notification.CourseParticipationIDs = append(notification.CourseParticipationIDs, participation.ID)
+	}
+	for _, change := range changes {
+		if !slices.Contains(notification.ChangedFields, change.Field) {
+			notification.ChangedFields = append(notification.ChangedFields, change.Field)
+		}
+		if change.ChangedAt.Time.After(notification.ChangedAt) {
+			notification.ChangedAt = change.ChangedAt.Time
+		}
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		if err := sendStudentUpdateNotification(ctx, endpoint, secret, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendStudentUpdateNotification(ctx context.Context, endpoint studentUpdateEndpoint, secret string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(StudentUpdateTimestampHeader, timestamp)
+	req.Header.Set(StudentUpdateSignatureHeader, signStudentUpdateNotification(secret, timestamp, body))
+
+	resp, err := studentUpdateClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send student update to %s: %w", endpoint.url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound && !endpoint.cachesStudentData {
+		// the phase server does not cache student data
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// e.g. a phase server caching student data that is not updated yet, the notification is sent again in the next runs
+		return fmt.Errorf("received non-OK response from %s: %s", endpoint.url, resp.Status)
+	}
+	return nil
+}
+
+// signStudentUpdateNotification lets the phase servers verify that a notification was sent by the core server.
+// The timestamp is signed as well, so that phase servers can reject replayed notifications.
+func signStudentUpdateNotification(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package student
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignStudentUpdateNotification(t *testing.T) {
+	signature := signStudentUpdateNotification("secret", "1760000000", []byte(`{"studentID":"x"}`))
+
+	assert.Equal(t, "sha256=61d68751973a81e911cf67889004cbbfd43de925f28136b90b16ebcebe378fa8", signature)
+	assert.NotEqual(t, signature, signStudentUpdateNotification("secret", "1760000001", []byte(`{"studentID":"x"}`)))
+}
+
+func TestGroupChangesByStudent(t *testing.T) {
+	anna, wei := uuid.New(), uuid.New()
+	changes := []db.StudentProfileChange{
+		{ID: uuid.New(), StudentID: anna, Field: "lastName"},
+		{ID: uuid.New(), StudentID: wei, Field: "firstName"},
+		{ID: uuid.New(), StudentID: anna, Field: "email"},
+	}
+
+	groups := groupChangesByStudent(changes)
+
+	assert.Equal(t, [][]db.StudentProfileChange{{changes[0], changes[2]}, {changes[1]}}, groups)
+}
+
+func TestGetStudentUpdateEndpoints(t *testing.T) {
+	t.Setenv("CORE_HOST", "prompt.example.com")
+
+	endpoints, err := getStudentUpdateEndpoints([]string{
+		"{CORE_HOST}/team-allocation/api",
+		"http://localhost:8084/self-team-allocation/api/",
+		"http://assessment:8085/api/",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []studentUpdateEndpoint{
+		{url: "https://prompt.example.com/team-allocation/api/student-updates", cachesStudentData: true},
+		{url: "http://localhost:8084/self-team-allocation/api/student-updates", cachesStudentData: true},
+		{url: "http://assessment:8085/api/student-updates", cachesStudentData: false},
+	}, endpoints)
+}
+
+func TestSendStudentUpdateNotification(t *testing.T) {
+	body := []byte(`{"studentID":"x"}`)
+
+	tests := []struct {
+		name              string
+		status            int
+		cachesStudentData bool
+		expectError       bool
+	}{
+		{"accepted", http.StatusOK, false, false},
+		{"phase server without student data", http.StatusNotFound, false, false},
+		{"phase server caching student data without the endpoint", http.StatusNotFound, true, true},
+		{"phase server failure", http.StatusInternalServerError, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				timestamp := r.Header.Get(StudentUpdateTimestampHeader)
+				assert.NotEmpty(t, timestamp)
+				assert.Equal(t, signStudentUpdateNotification("secret", timestamp, body), r.Header.Get(StudentUpdateSignatureHeader))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			endpoint := studentUpdateEndpoint{url: server.URL + "/student-updates", cachesStudentData: tt.cachesStudentData}
+			err := sendStudentUpdateNotification(context.Background(), endpoint, "secret", body)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package student
 import (
 	"errors"
 	"regexp"
+	"slices"
 
 	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
@@ -39,6 +40,33 @@ func Validate(c studentDTO.CreateStudent) error {
 	return nil
 }
 
+// ValidateProfileUpdate checks the fields a student provided to update their own profile
+func ValidateProfileUpdate(u studentDTO.UpdateStudentProfile) error {
+	if u.Gender != nil && !slices.Contains(validGenders, *u.Gender) {
+		log.Error("gender is invalid")
+		return errors.New("gender is invalid")
+	}
+	if u.Nationality != nil && *u.Nationality == "" {
+		log.Error("nationality is missing")
+		return errors.New("nationality is missing")
+	}
+	if u.CurrentSemester != nil && *u.CurrentSemester < 1 {
+		log.Error("semester is invalid")
+		return errors.New("semester is invalid")
+	}
+	if u.StudyProgram != nil && *u.StudyProgram == "" {
+		log.Error("study program is invalid")
+		return errors.New("study program is invalid")
+	}
+	if u.StudyDegree != nil && *u.StudyDegree != db.StudyDegreeBachelor && *u.StudyDegree != db.StudyDegreeMaster {
+		log.Error("study degree is invalid")
+		return errors.New("study degree is invalid")
+	}
+	return nil
+}
+
+var validGenders = []db.Gender{db.GenderMale, db.GenderFemale, db.GenderDiverse, db.GenderPreferNotToSay}
+
 func validateName(firstName, lastName string) error {
 	if firstName == "" {
 		log.Error("first name is required")
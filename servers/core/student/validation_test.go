@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/prompt-edu/prompt/servers/core/db/sqlc"
 	"github.com/prompt-edu/prompt/servers/core/student/studentDTO"
 	"github.com/stretchr/testify/assert"
 )
@@ -168,3 +169,39 @@ func TestValidateUniversityData(t *testing.T) {
 	err = validateUniversityData(false, "", "")
 	assert.NoError(t, err)
 }
+
+func TestValidateProfileUpdate(t *testing.T) {
+	gender := db.GenderDiverse
+	invalidGender := db.Gender("unknown")
+	emptyText := ""
+	studyProgram := "Information Systems"
+	masterDegree := db.StudyDegreeMaster
+	invalidDegree := db.StudyDegree("diploma")
+	semester := int32(3)
+	invalidSemester := int32(0)
+
+	tests := []struct {
+		name          string
+		input         studentDTO.UpdateStudentProfile
+		expectedError string
+	}{
+		{"nothing to update", studentDTO.UpdateStudentProfile{}, ""},
+		{"valid update", studentDTO.UpdateStudentProfile{Gender: &gender, StudyProgram: &studyProgram, StudyDegree: &masterDegree, CurrentSemester: &semester}, ""},
+		{"invalid gender", studentDTO.UpdateStudentProfile{Gender: &invalidGender}, "gender is invalid"},
+		{"empty nationality", studentDTO.UpdateStudentProfile{Nationality: &emptyText}, "nationality is missing"},
+		{"invalid semester", studentDTO.UpdateStudentProfile{CurrentSemester: &invalidSemester}, "semester is invalid"},
+		{"empty study program", studentDTO.UpdateStudentProfile{StudyProgram: &emptyText}, "study program is invalid"},
+		{"invalid study degree", studentDTO.UpdateStudentProfile{StudyDegree: &invalidDegree}, "study degree is invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProfileUpdate(tt.input)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -46,4 +46,11 @@ SELECT id,
        updated_at
 FROM assignments
 WHERE course_participation_id = $1
-  AND course_phase_id = $2;
\ No newline at end of file
+  AND course_phase_id = $2;
+
+-- name: UpdateStudentNameForParticipations :exec
+UPDATE assignments
+SET student_first_name = $1,
+    student_last_name = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE course_participation_id = ANY(sqlc.arg(course_participation_ids)::uuid[]);
@@ -30,3 +30,9 @@ WHERE course_participation_id = $1
 DELETE FROM tutor
 WHERE team_id = $1
   AND course_phase_id = $2;
+
+-- name: UpdateTutorNameForParticipations :exec
+UPDATE tutor
+SET first_name = $1,
+    last_name = $2
+WHERE course_participation_id = ANY(sqlc.arg(course_participation_ids)::uuid[]);
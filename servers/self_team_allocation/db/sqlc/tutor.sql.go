@@ -163,3 +163,21 @@ func (q *Queries) GetTutorsByTeamID(ctx context.Context, arg GetTutorsByTeamIDPa
 	}
 	return items, nil
 }
+
+const updateTutorNameForParticipations = `-- name: UpdateTutorNameForParticipations :exec
+UPDATE tutor
+SET first_name = $1,
+    last_name = $2
+WHERE course_participation_id = ANY($3::uuid[])
+`
+
+type UpdateTutorNameForParticipationsParams struct {
+	FirstName              string      `json:"first_name"`
+	LastName               string      `json:"last_name"`
+	CourseParticipationIds []uuid.UUID `json:"course_participation_ids"`
+}
+
+func (q *Queries) UpdateTutorNameForParticipations(ctx context.Context, arg UpdateTutorNameForParticipationsParams) error {
+	_, err := q.db.Exec(ctx, updateTutorNameForParticipations, arg.FirstName, arg.LastName, arg.CourseParticipationIds)
+	return err
+}
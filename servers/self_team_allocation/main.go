@@ -19,6 +19,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/self_team_allocation/config"
 	"github.com/prompt-edu/prompt/servers/self_team_allocation/copy"
 	db "github.com/prompt-edu/prompt/servers/self_team_allocation/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/self_team_allocation/studentUpdate"
 	teams "github.com/prompt-edu/prompt/servers/self_team_allocation/team"
 	"github.com/prompt-edu/prompt/servers/self_team_allocation/timeframe"
 
@@ -166,6 +167,9 @@ func main() {
 
 	config.InitConfigModule(api, *query, conn)
 
+	// the core server sends the identity changes of students to <baseURL>/student-updates
+	studentUpdate.InitStudentUpdateModule(router.Group("self-team-allocation/api"), *query, conn)
+
 	serverAddress := promptSDK.GetEnv("SERVER_ADDRESS", "localhost:8084")
 	log.Info("Self Team Allocation Server started")
 	err = router.Run(serverAddress)
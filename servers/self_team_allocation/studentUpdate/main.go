@@ -0,0 +1,17 @@
+package studentUpdate
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	promptSDK "github.com/prompt-edu/prompt-sdk"
+	db "github.com/prompt-edu/prompt/servers/self_team_allocation/db/sqlc"
+)
+
+// InitStudentUpdateModule receives the student updates of the core server, which are signed with STUDENT_UPDATE_WEBHOOK_SECRET
+func InitStudentUpdateModule(routerGroup *gin.RouterGroup, queries db.Queries, conn *pgxpool.Pool) {
+	setupStudentUpdateRouter(routerGroup, promptSDK.GetEnv("STUDENT_UPDATE_WEBHOOK_SECRET", ""))
+	StudentUpdateServiceSingleton = &StudentUpdateService{
+		queries: queries,
+		conn:    conn,
+	}
+}
@@ -0,0 +1,125 @@
+package studentUpdate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	db "github.com/prompt-edu/prompt/servers/self_team_allocation/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/self_team_allocation/studentUpdate/studentUpdateDTO"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "test-secret"
+
+type StudentUpdateRouterTestSuite struct {
+	suite.Suite
+	router               *gin.Engine
+	suiteCtx             context.Context
+	cleanup              func()
+	queries              db.Queries
+	studentUpdateService StudentUpdateService
+}
+
+func (suite *StudentUpdateRouterTestSuite) SetupSuite() {
+	suite.suiteCtx = context.Background()
+	testDB, cleanup, err := sdkTestUtils.SetupTestDB(suite.suiteCtx, "../database_dumps/base.sql", func(conn *pgxpool.Pool) *db.Queries { return db.New(conn) })
+	if err != nil {
+		suite.T().Fatalf("Failed to set up test database: %v", err)
+	}
+	suite.cleanup = cleanup
+	suite.queries = *testDB.Queries
+	suite.studentUpdateService = StudentUpdateService{
+		queries: *testDB.Queries,
+		conn:    testDB.Conn,
+	}
+	StudentUpdateServiceSingleton = &suite.studentUpdateService
+	suite.router = gin.Default()
+	setupStudentUpdateRouter(suite.router.Group("/api"), testSecret)
+}
+
+func (suite *StudentUpdateRouterTestSuite) TearDownSuite() {
+	if suite.cleanup != nil {
+		suite.cleanup()
+	}
+}
+
+func (suite *StudentUpdateRouterTestSuite) sendUpdate(notification studentUpdateDTO.StudentUpdateNotification, sentAt time.Time, secret string) *httptest.ResponseRecorder {
+	body, err := json.Marshal(notification)
+	assert.NoError(suite.T(), err)
+
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	req, _ := http.NewRequest("POST", "/api/student-updates", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(studentUpdateTimestampHeader, timestamp)
+	req.Header.Set(studentUpdateSignatureHeader, signStudentUpdate(secret, timestamp, body))
+	resp := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *StudentUpdateRouterTestSuite) TestUpdateStudentRenamesAssignmentsAndTutors() {
+	coursePhaseID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	assignedParticipationID := uuid.MustParse("aaaa1111-1111-1111-1111-111111111111")
+	tutorParticipationID := uuid.MustParse("eeee1111-1111-1111-1111-111111111111")
+
+	resp := suite.sendUpdate(studentUpdateDTO.StudentUpdateNotification{
+		StudentID:              uuid.New(),
+		CourseParticipationIDs: []uuid.UUID{assignedParticipationID, tutorParticipationID},
+		FirstName:              "Johanna",
+		LastName:               "Doe-Smith",
+		ChangedFields:          []string{"first_name", "last_name"},
+		ChangedAt:              time.Now(),
+	}, time.Now(), testSecret)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	assignments, err := suite.queries.GetAssignmentsByCoursePhase(suite.suiteCtx, coursePhaseID)
+	assert.NoError(suite.T(), err)
+	for _, assignment := range assignments {
+		if assignment.CourseParticipationID == assignedParticipationID {
+			assert.Equal(suite.T(), "Johanna", assignment.StudentFirstName)
+			assert.Equal(suite.T(), "Doe-Smith", assignment.StudentLastName)
+		} else {
+			assert.NotEqual(suite.T(), "Johanna", assignment.StudentFirstName, "Other students must not be renamed")
+		}
+	}
+
+	tutor, err := suite.queries.GetTutorByCourseParticipationID(suite.suiteCtx, db.GetTutorByCourseParticipationIDParams{
+		CourseParticipationID: tutorParticipationID,
+		CoursePhaseID:         coursePhaseID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Johanna", tutor.FirstName)
+	assert.Equal(suite.T(), "Doe-Smith", tutor.LastName)
+}
+
+func (suite *StudentUpdateRouterTestSuite) TestUpdateStudentInvalidSignature() {
+	resp := suite.sendUpdate(studentUpdateDTO.StudentUpdateNotification{
+		CourseParticipationIDs: []uuid.UUID{uuid.MustParse("bbbb1111-1111-1111-1111-111111111111")},
+		FirstName:              "Mallory",
+	}, time.Now(), "wrong-secret")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+}
+
+func (suite *StudentUpdateRouterTestSuite) TestUpdateStudentExpiredTimestamp() {
+	resp := suite.sendUpdate(studentUpdateDTO.StudentUpdateNotification{
+		CourseParticipationIDs: []uuid.UUID{uuid.MustParse("bbbb1111-1111-1111-1111-111111111111")},
+		FirstName:              "Mallory",
+	}, time.Now().Add(-time.Hour), testSecret)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+}
+
+func TestStudentUpdateRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(StudentUpdateRouterTestSuite))
+}
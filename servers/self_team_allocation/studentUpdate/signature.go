@@ -0,0 +1,44 @@
+package studentUpdate
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	studentUpdateTimestampHeader = "X-Prompt-Timestamp"
+	studentUpdateSignatureHeader = "X-Prompt-Signature"
+
+	// notifications older than this are rejected, so that captured requests cannot be replayed later
+	studentUpdateMaxAge = 5 * time.Minute
+)
+
+var ErrInvalidSignature = errors.New("invalid or expired student update signature")
+
+// verifyStudentUpdateSignature checks the HMAC-SHA256 of "<timestamp>.<body>" the core server signs every notification with
+func verifyStudentUpdateSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(sentAt, 0))
+	if age > studentUpdateMaxAge || age < -studentUpdateMaxAge {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(signStudentUpdate(secret, timestamp, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func signStudentUpdate(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
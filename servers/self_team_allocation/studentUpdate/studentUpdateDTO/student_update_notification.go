@@ -0,0 +1,21 @@
+package studentUpdateDTO
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudentUpdateNotification is sent by the core server when identity fields of a student changed.
+// It contains the current data of the student and all its course participations.
+type StudentUpdateNotification struct {
+	StudentID              uuid.UUID   `json:"studentID"`
+	CourseParticipationIDs []uuid.UUID `json:"courseParticipationIDs"`
+	FirstName              string      `json:"firstName"`
+	LastName               string      `json:"lastName"`
+	Email                  string      `json:"email"`
+	MatriculationNumber    string      `json:"matriculationNumber"`
+	UniversityLogin        string      `json:"universityLogin"`
+	ChangedFields          []string    `json:"changedFields"`
+	ChangedAt              time.Time   `json:"changedAt"`
+}
@@ -114,3 +114,10 @@ SET student_first_name = $1,
     updated_at = CURRENT_TIMESTAMP
 WHERE course_participation_id = $3
   AND course_phase_id = $4;
+
+-- name: UpdateStudentNameForParticipations :exec
+UPDATE allocations
+SET student_first_name = $1,
+    student_last_name = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE course_participation_id = ANY(sqlc.arg(course_participation_ids)::uuid[]);
@@ -13,3 +13,9 @@ SELECT t.*
 FROM tutor t
 WHERE t.team_id = $1
   AND t.course_phase_id = $2;
+
+-- name: UpdateTutorNameForParticipations :exec
+UPDATE tutor
+SET first_name = $1,
+    last_name = $2
+WHERE course_participation_id = ANY(sqlc.arg(course_participation_ids)::uuid[]);
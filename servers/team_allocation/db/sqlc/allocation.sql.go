@@ -344,3 +344,22 @@ func (q *Queries) UpdateStudentNameForAllocation(ctx context.Context, arg Update
 	)
 	return err
 }
+
+const updateStudentNameForParticipations = `-- name: UpdateStudentNameForParticipations :exec
+UPDATE allocations
+SET student_first_name = $1,
+    student_last_name = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE course_participation_id = ANY($3::uuid[])
+`
+
+type UpdateStudentNameForParticipationsParams struct {
+	StudentFirstName       string      `json:"student_first_name"`
+	StudentLastName        string      `json:"student_last_name"`
+	CourseParticipationIds []uuid.UUID `json:"course_participation_ids"`
+}
+
+func (q *Queries) UpdateStudentNameForParticipations(ctx context.Context, arg UpdateStudentNameForParticipationsParams) error {
+	_, err := q.db.Exec(ctx, updateStudentNameForParticipations, arg.StudentFirstName, arg.StudentLastName, arg.CourseParticipationIds)
+	return err
+}
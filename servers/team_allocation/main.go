@@ -20,6 +20,7 @@ import (
 	"github.com/prompt-edu/prompt/servers/team_allocation/copy"
 	db "github.com/prompt-edu/prompt/servers/team_allocation/db/sqlc"
 	"github.com/prompt-edu/prompt/servers/team_allocation/skills"
+	"github.com/prompt-edu/prompt/servers/team_allocation/studentUpdate"
 	"github.com/prompt-edu/prompt/servers/team_allocation/survey"
 	teams "github.com/prompt-edu/prompt/servers/team_allocation/team"
 	"github.com/prompt-edu/prompt/servers/team_allocation/tease"
@@ -168,6 +169,9 @@ func main() {
 
 	config.InitConfigModule(api, *query, conn)
 
+	// the core server sends the identity changes of students to <baseURL>/student-updates
+	studentUpdate.InitStudentUpdateModule(router.Group("team-allocation/api"), *query, conn)
+
 	serverAddress := promptSDK.GetEnv("SERVER_ADDRESS", "localhost:8083")
 	log.Info("Team Allocation Server started")
 	err = router.Run(serverAddress)
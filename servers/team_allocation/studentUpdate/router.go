@@ -0,0 +1,72 @@
+package studentUpdate
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prompt-edu/prompt/servers/team_allocation/studentUpdate/studentUpdateDTO"
+	log "github.com/sirupsen/logrus"
+)
+
+func setupStudentUpdateRouter(routerGroup *gin.RouterGroup, secret string) {
+	// not authenticated with a keycloak token, the core server signs the requests instead
+	routerGroup.POST("/student-updates", func(c *gin.Context) {
+		updateStudent(c, secret)
+	})
+}
+
+// updateStudent godoc
+// @Summary Update the cached data of a student
+// @Description Receives the identity changes of a student from the core server and updates the names of its allocations and tutors. The request is signed with the shared STUDENT_UPDATE_WEBHOOK_SECRET.
+// @Tags student_updates
+// @Accept json
+// @Param X-Prompt-Timestamp header string true "Unix time of the request in seconds"
+// @Param X-Prompt-Signature header string true "sha256= followed by the hex encoded HMAC-SHA256 of <timestamp>.<body>"
+// @Param request body studentUpdateDTO.StudentUpdateNotification true "Current data of the student"
+// @Success 200
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /student-updates [post]
+func updateStudent(c *gin.Context, secret string) {
+	if secret == "" {
+		// answered with an error, so the core server retries the update once the secret is configured
+		handleError(c, http.StatusServiceUnavailable, errors.New("student updates are not configured"))
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = verifyStudentUpdateSignature(secret, c.GetHeader(studentUpdateTimestampHeader), c.GetHeader(studentUpdateSignatureHeader), body, time.Now())
+	if err != nil {
+		handleError(c, http.StatusUnauthorized, err)
+		return
+	}
+
+	var notification studentUpdateDTO.StudentUpdateNotification
+	if err := json.Unmarshal(body, &notification); err != nil {
+		handleError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := UpdateStudent(c, notification); err != nil {
+		log.Error("Error updating the student data: ", err)
+		handleError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func handleError(c *gin.Context, statusCode int, err error) {
+	c.JSON(statusCode, gin.H{"error": err.Error()})
+}
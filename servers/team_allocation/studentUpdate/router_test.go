@@ -0,0 +1,125 @@
+package studentUpdate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	sdkTestUtils "github.com/prompt-edu/prompt-sdk/testutils"
+	db "github.com/prompt-edu/prompt/servers/team_allocation/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/team_allocation/studentUpdate/studentUpdateDTO"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSecret = "test-secret"
+
+type StudentUpdateRouterTestSuite struct {
+	suite.Suite
+	router               *gin.Engine
+	suiteCtx             context.Context
+	cleanup              func()
+	queries              db.Queries
+	studentUpdateService StudentUpdateService
+}
+
+func (suite *StudentUpdateRouterTestSuite) SetupSuite() {
+	suite.suiteCtx = context.Background()
+	testDB, cleanup, err := sdkTestUtils.SetupTestDB(suite.suiteCtx, "../database_dumps/teams.sql", func(conn *pgxpool.Pool) *db.Queries { return db.New(conn) })
+	if err != nil {
+		suite.T().Fatalf("Failed to set up test database: %v", err)
+	}
+	suite.cleanup = cleanup
+	suite.queries = *testDB.Queries
+	suite.studentUpdateService = StudentUpdateService{
+		queries: *testDB.Queries,
+		conn:    testDB.Conn,
+	}
+	StudentUpdateServiceSingleton = &suite.studentUpdateService
+	suite.router = gin.Default()
+	setupStudentUpdateRouter(suite.router.Group("/api"), testSecret)
+}
+
+func (suite *StudentUpdateRouterTestSuite) TearDownSuite() {
+	if suite.cleanup != nil {
+		suite.cleanup()
+	}
+}
+
+func (suite *StudentUpdateRouterTestSuite) sendUpdate(notification studentUpdateDTO.StudentUpdateNotification, sentAt time.Time, secret string) *httptest.ResponseRecorder {
+	body, err := json.Marshal(notification)
+	assert.NoError(suite.T(), err)
+
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	req, _ := http.NewRequest("POST", "/api/student-updates", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(studentUpdateTimestampHeader, timestamp)
+	req.Header.Set(studentUpdateSignatureHeader, signStudentUpdate(secret, timestamp, body))
+	resp := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(resp, req)
+	return resp
+}
+
+func (suite *StudentUpdateRouterTestSuite) TestUpdateStudentRenamesAllocationsAndTutors() {
+	coursePhaseID := uuid.MustParse("4179d58a-d00d-4fa7-94a5-397bc69fab02")
+	allocatedParticipationID := uuid.MustParse("99999999-9999-9999-9999-999999999991")
+	tutorParticipationID := uuid.MustParse("99999999-9999-9999-9999-999999999993")
+
+	resp := suite.sendUpdate(studentUpdateDTO.StudentUpdateNotification{
+		StudentID:              uuid.New(),
+		CourseParticipationIDs: []uuid.UUID{allocatedParticipationID, tutorParticipationID},
+		FirstName:              "Johanna",
+		LastName:               "Doe-Smith",
+		ChangedFields:          []string{"first_name", "last_name"},
+		ChangedAt:              time.Now(),
+	}, time.Now(), testSecret)
+	assert.Equal(suite.T(), http.StatusOK, resp.Code)
+
+	allocations, err := suite.queries.GetAllocationsByCoursePhase(suite.suiteCtx, coursePhaseID)
+	assert.NoError(suite.T(), err)
+	for _, allocation := range allocations {
+		if allocation.CourseParticipationID == allocatedParticipationID {
+			assert.Equal(suite.T(), "Johanna", allocation.StudentFirstName)
+			assert.Equal(suite.T(), "Doe-Smith", allocation.StudentLastName)
+		} else {
+			assert.NotEqual(suite.T(), "Johanna", allocation.StudentFirstName, "Other students must not be renamed")
+		}
+	}
+
+	tutor, err := suite.queries.GetTutorByCourseParticipationID(suite.suiteCtx, db.GetTutorByCourseParticipationIDParams{
+		CourseParticipationID: tutorParticipationID,
+		CoursePhaseID:         coursePhaseID,
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Johanna", tutor.FirstName)
+	assert.Equal(suite.T(), "Doe-Smith", tutor.LastName)
+}
+
+func (suite *StudentUpdateRouterTestSuite) TestUpdateStudentInvalidSignature() {
+	resp := suite.sendUpdate(studentUpdateDTO.StudentUpdateNotification{
+		CourseParticipationIDs: []uuid.UUID{uuid.MustParse("99999999-9999-9999-9999-999999999992")},
+		FirstName:              "Mallory",
+	}, time.Now(), "wrong-secret")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+}
+
+func (suite *StudentUpdateRouterTestSuite) TestUpdateStudentExpiredTimestamp() {
+	resp := suite.sendUpdate(studentUpdateDTO.StudentUpdateNotification{
+		CourseParticipationIDs: []uuid.UUID{uuid.MustParse("99999999-9999-9999-9999-999999999992")},
+		FirstName:              "Mallory",
+	}, time.Now().Add(-time.Hour), testSecret)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.Code)
+}
+
+func TestStudentUpdateRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(StudentUpdateRouterTestSuite))
+}
@@ -0,0 +1,56 @@
+package studentUpdate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	promptSDK "github.com/prompt-edu/prompt-sdk"
+	db "github.com/prompt-edu/prompt/servers/team_allocation/db/sqlc"
+	"github.com/prompt-edu/prompt/servers/team_allocation/studentUpdate/studentUpdateDTO"
+)
+
+type StudentUpdateService struct {
+	queries db.Queries
+	conn    *pgxpool.Pool
+}
+
+var StudentUpdateServiceSingleton *StudentUpdateService
+
+// UpdateStudent refreshes the names cached for the course participations of the student in the allocations and tutors.
+// The notification contains the current names, so applying it again has no further effect.
+func UpdateStudent(ctx context.Context, notification studentUpdateDTO.StudentUpdateNotification) error {
+	if len(notification.CourseParticipationIDs) == 0 {
+		return nil
+	}
+
+	tx, err := StudentUpdateServiceSingleton.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer promptSDK.DeferDBRollback(tx, ctx)
+	qtx := StudentUpdateServiceSingleton.queries.WithTx(tx)
+
+	err = qtx.UpdateStudentNameForParticipations(ctx, db.UpdateStudentNameForParticipationsParams{
+		StudentFirstName:       notification.FirstName,
+		StudentLastName:        notification.LastName,
+		CourseParticipationIds: notification.CourseParticipationIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the names of the allocations: %w", err)
+	}
+
+	err = qtx.UpdateTutorNameForParticipations(ctx, db.UpdateTutorNameForParticipationsParams{
+		FirstName:              notification.FirstName,
+		LastName:               notification.LastName,
+		CourseParticipationIds: notification.CourseParticipationIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the names of the tutors: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}